@@ -1,24 +1,49 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"time"
+	_ "time/tzdata" // Nhúng dữ liệu múi giờ, image alpine không có sẵn (dùng cho Venue.Timezone)
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/shopspring/decimal"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 
 	"github.com/yourname/ticketing-system/internal/adapter/handler"
+	"github.com/yourname/ticketing-system/internal/adapter/mailer"
+	"github.com/yourname/ticketing-system/internal/adapter/notifier"
+	"github.com/yourname/ticketing-system/internal/adapter/payout"
 	"github.com/yourname/ticketing-system/internal/adapter/repository"
+	"github.com/yourname/ticketing-system/internal/core/port"
 	"github.com/yourname/ticketing-system/internal/core/service"
+	"github.com/yourname/ticketing-system/pkg/auth"
 )
 
 func main() {
 	// 1. Cấu hình (Lấy từ Environment hoặc mặc định)
-	jwtSecret := getEnv("JWT_SECRET", "my-super-secret-key-2026")
+	jwtKeysFile := getEnv("JWT_KEYS_FILE", "")                                      // Manifest khóa ký JWT (RS256/EdDSA, lịch xoay vòng)
+	cursorSecret := getEnv("CURSOR_SECRET", "")                                     // Khóa ký cursor phân trang
+	orderTTL := getEnvDuration("ORDER_TTL", 15*time.Minute)                         // Thời hạn thanh toán đơn PENDING
+	waitlistOfferTTL := getEnvDuration("WAITLIST_OFFER_TTL", 10*time.Minute)        // Thời gian giữ vé cho người trong hàng chờ
+	resaleFeePercent := getEnvDecimal("RESALE_FEE_PERCENT", decimal.NewFromInt(10)) // Phí sàn bán lại (% giá bán)
+	accessTokenTTL := getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute)            // Access token ngắn hạn
+	refreshTokenTTL := getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour)         // Refresh token (xoay vòng mỗi lần dùng)
+	appBaseURL := getEnv("APP_BASE_URL", "http://localhost:8080")                   // Gốc URL frontend cho link trong email
+	requireVerifiedEmail := getEnvBool("REQUIRE_EMAIL_VERIFIED", false)             // Chặn đặt vé khi chưa xác thực email
+	requireAdminMFA := getEnvBool("REQUIRE_ADMIN_MFA", false)                       // Admin bắt buộc bật xác thực 2 bước (TOTP)
+	jwtKeys := loadJWTKeys(jwtKeysFile, accessTokenTTL)
+	if cursorSecret == "" {
+		cursorSecret = randomSecret()
+		log.Printf("CẢNH BÁO: chưa đặt CURSOR_SECRET, dùng khóa ngẫu nhiên (cursor phân trang mất hiệu lực khi khởi động lại)")
+	}
 	dbConnStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		getEnv("DB_HOST", "postgres"),
 		getEnv("DB_PORT", "5432"),
@@ -50,18 +75,112 @@ func main() {
 	// User module
 	sqlDB, _ := db.DB()
 	userRepo := repository.NewUserRepository(sqlDB)
-	authService := service.NewAuthService(userRepo, jwtSecret)
+	tokenRepo := repository.NewTokenRepository(sqlDB)
+	loginThrottleRepo := repository.NewLoginThrottleRepository(sqlDB)
+	mfaRepo := repository.NewMFARepository(sqlDB)
+	authService := service.NewAuthService(userRepo, tokenRepo, loginThrottleRepo, mfaRepo, newMailer(), jwtKeys, service.AuthConfig{
+		AccessTTL:       accessTokenTTL,
+		RefreshTTL:      refreshTokenTTL,
+		AppBaseURL:      appBaseURL,
+		MFAIssuer:       getEnv("MFA_ISSUER", "Ticketing"),
+		RequireAdminMFA: requireAdminMFA,
+	})
 	authHandler := handler.NewAuthHandler(authService)
 
+	// Access module (vai trò theo phạm vi: organizer, scanner, box_office, support)
+	roleRepo := repository.NewRoleRepository(sqlDB)
+	accessService := service.NewAccessService(roleRepo, userRepo)
+	accessHandler := handler.NewAccessHandler(accessService)
+
+	// API key module (tích hợp máy-máy cho website đối tác, kiosk)
+	apiKeyRepo := repository.NewAPIKeyRepository(sqlDB)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, accessService)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+
+	// Venue module
+	venueRepo := repository.NewVenueRepository(db)
+	venueService := service.NewVenueService(venueRepo)
+	venueHandler := handler.NewVenueHandler(venueService)
+
 	// Event module
 	eventRepo := repository.NewEventRepository(db)
-	eventService := service.NewEventService(eventRepo)
-	eventHandler := handler.NewEventHandler(eventService)
+	eventService := service.NewEventService(eventRepo, venueRepo)
+	eventHandler := handler.NewEventHandler(eventService, cursorSecret)
+
+	// Organization module (ban tổ chức sở hữu event, thành viên và cấu hình riêng)
+	organizationRepo := repository.NewOrganizationRepository(db)
+	organizationService := service.NewOrganizationService(organizationRepo, eventRepo, venueRepo, roleRepo, userRepo, accessService)
+	organizationHandler := handler.NewOrganizationHandler(organizationService, cursorSecret)
+
+	// Seat module (vé ngồi theo số)
+	seatRepo := repository.NewSeatRepository(db)
+	seatService := service.NewSeatService(seatRepo, eventRepo, venueRepo)
+	seatHandler := handler.NewSeatHandler(seatService)
+
+	// Capacity pool module
+	poolRepo := repository.NewPoolRepository(db)
+	poolService := service.NewPoolService(poolRepo, eventRepo)
+	poolHandler := handler.NewPoolHandler(poolService)
 
-	// Order module
+	// Session module (event nhiều suất diễn)
+	sessionRepo := repository.NewSessionRepository(db)
+	sessionService := service.NewSessionService(sessionRepo, eventRepo, venueRepo)
+	sessionHandler := handler.NewSessionHandler(sessionService)
+
+	// Bundle module (gói combo nhiều loại vé)
+	bundleRepo := repository.NewBundleRepository(db)
+	bundleService := service.NewBundleService(bundleRepo, eventRepo)
+	bundleHandler := handler.NewBundleHandler(bundleService)
+
+	// Add-on module (hàng bán kèm)
+	addOnRepo := repository.NewAddOnRepository(db)
+	addOnService := service.NewAddOnService(addOnRepo, eventRepo)
+	addOnHandler := handler.NewAddOnHandler(addOnService)
+
+	// Presale module (mã truy cập cho loại vé ẩn)
+	presaleRepo := repository.NewPresaleRepository(db)
+	presaleService := service.NewPresaleService(presaleRepo, eventRepo)
+	presaleHandler := handler.NewPresaleHandler(presaleService)
+
+	// Order module (kèm hàng chờ cho loại vé đã hết và sàn bán lại vé)
+	notify := notifier.NewLogNotifier()
 	orderRepo := repository.NewOrderRepository(db)
-	orderService := service.NewOrderService(db, orderRepo)
-	orderHandler := handler.NewOrderHandler(orderService)
+	waitlistRepo := repository.NewWaitlistRepository(db)
+	waitlistService := service.NewWaitlistService(db, waitlistRepo, orderRepo, notify, waitlistOfferTTL)
+	waitlistHandler := handler.NewWaitlistHandler(waitlistService)
+	transferRepo := repository.NewTransferRepository(db)
+	resaleRepo := repository.NewResaleRepository(db)
+	resaleService := service.NewResaleService(db, resaleRepo, transferRepo, notify, payout.NewLogPayout(), resaleFeePercent)
+	resaleHandler := handler.NewResaleHandler(resaleService)
+	orderService := service.NewOrderService(db, orderRepo, waitlistService, resaleService)
+	orderHandler := handler.NewOrderHandler(orderService, cursorSecret)
+	boxOfficeHandler := handler.NewBoxOfficeHandler(orderService)
+
+	// Allocation module (vé giữ lại và vé mời)
+	allocationRepo := repository.NewAllocationRepository(db)
+	allocationService := service.NewAllocationService(db, allocationRepo, orderRepo, userRepo, waitlistService, notify)
+	allocationHandler := handler.NewAllocationHandler(allocationService)
+
+	// Transfer module (chuyển nhượng vé)
+	transferService := service.NewTransferService(db, transferRepo, userRepo, notify)
+	transferHandler := handler.NewTransferHandler(transferService)
+
+	// Check-in module (soát vé tại cổng)
+	checkInService := service.NewCheckInService(db, transferRepo)
+	checkInHandler := handler.NewCheckInHandler(checkInService)
+
+	// Attendee module (câu hỏi và thông tin người tham dự từng vé)
+	attendeeRepo := repository.NewAttendeeRepository(db)
+	attendeeService := service.NewAttendeeService(db, attendeeRepo)
+	attendeeHandler := handler.NewAttendeeHandler(attendeeService)
+
+	// Ballot module (bán vé bốc thăm)
+	ballotRepo := repository.NewBallotRepository(db)
+	ballotService := service.NewBallotService(db, ballotRepo, orderRepo, orderService, notify)
+	ballotHandler := handler.NewBallotHandler(ballotService)
+
+	// Job nền: hủy đơn quá hạn thanh toán, thu hồi vé giữ cho hàng chờ quá hạn và chi lại tiền bán lại lỗi
+	go runExpiryJobs(orderService, waitlistService, resaleService, authService, orderTTL)
 
 	// 4. Khởi tạo Fiber
 	app := fiber.New(fiber.Config{
@@ -72,7 +191,7 @@ func main() {
 	app.Use(logger.New())
 
 	// 5. GỌI ROUTER CỦA BẠN Ở ĐÂY
-	handler.SetupRoutes(app, authHandler, eventHandler, orderHandler, jwtSecret)
+	handler.SetupRoutes(app, authHandler, eventHandler, orderHandler, venueHandler, seatHandler, poolHandler, sessionHandler, bundleHandler, addOnHandler, waitlistHandler, ballotHandler, presaleHandler, allocationHandler, boxOfficeHandler, transferHandler, checkInHandler, resaleHandler, attendeeHandler, accessHandler, organizationHandler, apiKeyHandler, jwtKeys, requireVerifiedEmail)
 
 	// 6. Chạy Server
 	port := getEnv("SERVER_PORT", "8080")
@@ -86,3 +205,105 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// loadJWTKeys đọc bộ khóa ký JWT từ manifest. Không có manifest thì sinh khóa tạm (chỉ dùng khi dev).
+func loadJWTKeys(manifestPath string, accessTTL time.Duration) *auth.KeySet {
+	if manifestPath != "" {
+		keys, err := auth.LoadKeySet(manifestPath, accessTTL)
+		if err != nil {
+			log.Fatalf("Không đọc được khóa ký JWT: %v", err)
+		}
+		return keys
+	}
+
+	keys, err := auth.GenerateEphemeralKeySet(accessTTL)
+	if err != nil {
+		log.Fatalf("Không sinh được khóa ký JWT: %v", err)
+	}
+	log.Printf("CẢNH BÁO: chưa đặt JWT_KEYS_FILE, dùng khóa ký tạm (token mất hiệu lực khi khởi động lại)")
+	return keys
+}
+
+// newMailer gửi qua SMTP nếu có SMTP_HOST, ngược lại ghi email ra file trong MAIL_DIR (dev).
+func newMailer() port.MailerPort {
+	from := getEnv("MAIL_FROM", "no-reply@ticketing.local")
+	if host := getEnv("SMTP_HOST", ""); host != "" {
+		return mailer.NewSMTPMailer(host, getEnv("SMTP_PORT", "587"), getEnv("SMTP_USER", ""), getEnv("SMTP_PASS", ""), from)
+	}
+
+	dir := getEnv("MAIL_DIR", "tmp/mail")
+	m, err := mailer.NewFileMailer(dir, from)
+	if err != nil {
+		log.Fatalf("Không tạo được thư mục email %s: %v", dir, err)
+	}
+	log.Printf("Chưa cấu hình SMTP_HOST, email được ghi vào %s", dir)
+	return m
+}
+
+func randomSecret() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatalf("Không sinh được khóa ngẫu nhiên: %v", err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	if value, ok := os.LookupEnv(key); ok {
+		if d, err := time.ParseDuration(value); err == nil {
+			return d
+		}
+		log.Printf("%s không hợp lệ, dùng mặc định %s", key, fallback)
+	}
+	return fallback
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	if value, ok := os.LookupEnv(key); ok {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+		log.Printf("%s không hợp lệ, dùng mặc định %t", key, fallback)
+	}
+	return fallback
+}
+
+func getEnvDecimal(key string, fallback decimal.Decimal) decimal.Decimal {
+	if value, ok := os.LookupEnv(key); ok {
+		if d, err := decimal.NewFromString(value); err == nil && !d.IsNegative() {
+			return d
+		}
+		log.Printf("%s không hợp lệ, dùng mặc định %s", key, fallback)
+	}
+	return fallback
+}
+
+// runExpiryJobs chạy mỗi phút: hủy đơn quá hạn thanh toán (trả vé về kho/hàng chờ),
+// chuyển vé giữ quá hạn cho người kế tiếp trong hàng chờ, chi lại tiền bán lại bị lỗi và dọn
+// token đã hết hạn.
+func runExpiryJobs(orderService *service.OrderService, waitlistService *service.WaitlistService, resaleService *service.ResaleService, authService port.AuthServicePort, orderTTL time.Duration) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx := context.Background()
+		if n, err := orderService.ExpirePendingOrders(ctx, orderTTL); err != nil {
+			log.Printf("Hủy đơn quá hạn lỗi: %v", err)
+		} else if n > 0 {
+			log.Printf("Đã hủy %d đơn quá hạn thanh toán", n)
+		}
+		if n, err := waitlistService.ExpireOffers(ctx); err != nil {
+			log.Printf("Thu hồi vé giữ cho hàng chờ lỗi: %v", err)
+		} else if n > 0 {
+			log.Printf("Đã thu hồi %d lượt giữ vé quá hạn", n)
+		}
+		if n, err := resaleService.RetryPayouts(ctx); err != nil {
+			log.Printf("Chi lại tiền bán lại lỗi: %v", err)
+		} else if n > 0 {
+			log.Printf("Đã chi lại tiền cho %d vé bán lại", n)
+		}
+		if _, err := authService.PurgeExpiredTokens(ctx); err != nil {
+			log.Printf("Dọn token hết hạn lỗi: %v", err)
+		}
+	}
+}
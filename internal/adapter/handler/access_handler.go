@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+type AccessHandler struct {
+	svc port.AccessServicePort
+}
+
+func NewAccessHandler(svc port.AccessServicePort) *AccessHandler {
+	return &AccessHandler{svc: svc}
+}
+
+// Grant cấp thêm vai trò cho user trong một phạm vi (toàn hệ thống hoặc một event)
+func (h *AccessHandler) Grant(c *fiber.Ctx) error {
+	grantedBy, err := currentUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.GrantRoleRequest
+	if err := c.BodyParser(&req); err != nil || req.UserID == uuid.Nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Dữ liệu không hợp lệ",
+		})
+	}
+
+	assignment, err := h.svc.GrantRole(c.Context(), grantedBy, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(assignment)
+}
+
+// Revoke thu hồi một vai trò đã cấp
+func (h *AccessHandler) Revoke(c *fiber.Ctx) error {
+	assignmentID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	if err := h.svc.RevokeRole(c.Context(), assignmentID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{"message": "Đã thu hồi vai trò"})
+}
+
+// ListUserRoles trả về role gốc và các vai trò được cấp thêm của user
+func (h *AccessHandler) ListUserRoles(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	roles, err := h.svc.ListUserRoles(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(roles)
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+type AddOnHandler struct {
+	svc port.AddOnServicePort
+}
+
+func NewAddOnHandler(svc port.AddOnServicePort) *AddOnHandler {
+	return &AddOnHandler{svc: svc}
+}
+
+func (h *AddOnHandler) CreateAddOn(c *fiber.Ctx) error {
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	var req entity.CreateAddOnRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Dữ liệu không hợp lệ",
+		})
+	}
+
+	addOn, err := h.svc.CreateAddOn(c.Context(), eventID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(addOn)
+}
+
+func (h *AddOnHandler) ListAddOns(c *fiber.Ctx) error {
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	addOns, err := h.svc.ListAddOns(c.Context(), eventID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{"data": addOns})
+}
+
+// GetSalesReport trả về doanh số event, tách theo vé / combo / add-on
+func (h *AddOnHandler) GetSalesReport(c *fiber.Ctx) error {
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	report, err := h.svc.GetSalesReport(c.Context(), eventID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(report)
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+type AllocationHandler struct {
+	svc *service.AllocationService
+}
+
+func NewAllocationHandler(svc *service.AllocationService) *AllocationHandler {
+	return &AllocationHandler{svc: svc}
+}
+
+// CreateAllocation giữ lại một phần vé của loại vé khỏi bán công khai
+func (h *AllocationHandler) CreateAllocation(c *fiber.Ctx) error {
+	ticketTypeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid ticket type ID"})
+	}
+
+	var req entity.CreateAllocationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	allocation, err := h.svc.CreateAllocation(c.Context(), ticketTypeID, req)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(http.StatusCreated).JSON(allocation)
+}
+
+// ListAllocations trả về các phần vé giữ lại của loại vé
+func (h *AllocationHandler) ListAllocations(c *fiber.Ctx) error {
+	ticketTypeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid ticket type ID"})
+	}
+
+	allocations, err := h.svc.ListAllocations(c.Context(), ticketTypeID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": allocations})
+}
+
+// IssueComp phát vé mời từ phần vé giữ lại
+func (h *AllocationHandler) IssueComp(c *fiber.Ctx) error {
+	allocationID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid allocation ID"})
+	}
+
+	var req entity.IssueCompRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	order, err := h.svc.IssueComp(c.Context(), allocationID, req)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(http.StatusCreated).JSON(order)
+}
+
+// Release trả vé chưa phát về bán công khai
+func (h *AllocationHandler) Release(c *fiber.Ctx) error {
+	allocationID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid allocation ID"})
+	}
+
+	var req entity.ReleaseAllocationRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		}
+	}
+
+	allocation, err := h.svc.ReleaseAllocation(c.Context(), allocationID, req.Quantity)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(allocation)
+}
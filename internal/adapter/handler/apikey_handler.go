@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+type APIKeyHandler struct {
+	svc port.APIKeyServicePort
+}
+
+func NewAPIKeyHandler(svc port.APIKeyServicePort) *APIKeyHandler {
+	return &APIKeyHandler{svc: svc}
+}
+
+// Create tạo API key cho user đang đăng nhập, khóa thô chỉ trả về một lần
+func (h *APIKeyHandler) Create(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var req entity.CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dữ liệu không hợp lệ"})
+	}
+
+	key, err := h.svc.CreateKey(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(key)
+}
+
+// List liệt kê API key của user (không kèm khóa thô)
+func (h *APIKeyHandler) List(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	keys, err := h.svc.ListKeys(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": keys})
+}
+
+// Rotate cấp khóa mới thay khóa cũ (khóa cũ thu hồi ngay hoặc sau thời gian chuyển tiếp)
+func (h *APIKeyHandler) Rotate(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	keyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID không hợp lệ"})
+	}
+
+	// Body không bắt buộc: bỏ trống = thu hồi khóa cũ ngay
+	var req entity.RotateAPIKeyRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dữ liệu không hợp lệ"})
+		}
+	}
+
+	role, _ := c.Locals("role").(string)
+	key, err := h.svc.RotateKey(c.Context(), userID, role, keyID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(key)
+}
+
+// Revoke thu hồi API key (chủ khóa hoặc admin)
+func (h *APIKeyHandler) Revoke(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	keyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID không hợp lệ"})
+	}
+
+	role, _ := c.Locals("role").(string)
+	if err := h.svc.RevokeKey(c.Context(), userID, role, keyID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "Đã thu hồi API key"})
+}
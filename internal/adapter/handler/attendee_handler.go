@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+type AttendeeHandler struct {
+	svc *service.AttendeeService
+}
+
+func NewAttendeeHandler(svc *service.AttendeeService) *AttendeeHandler {
+	return &AttendeeHandler{svc: svc}
+}
+
+// SetQuestions đặt bộ câu hỏi người tham dự và hạn trả lời của event (admin)
+func (h *AttendeeHandler) SetQuestions(c *fiber.Ctx) error {
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid event ID"})
+	}
+
+	var req entity.SetAttendeeQuestionsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	event, err := h.svc.SetQuestions(c.Context(), eventID, req)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{
+		"attendee_questions": event.AttendeeQuestions,
+		"attendee_deadline":  event.AttendeeDeadline,
+	})
+}
+
+// UpdateAttendee điền/sửa tên và câu trả lời người tham dự của vé đang giữ
+func (h *AttendeeHandler) UpdateAttendee(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	ticketID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid ticket ID"})
+	}
+
+	var req entity.AttendeeDetails
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	ticket, err := h.svc.UpdateAttendee(c.Context(), userID, ticketID, req)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(ticket)
+}
+
+// Export xuất danh sách người tham dự của event (admin). ?format=csv để tải file CSV, mỗi câu hỏi một cột.
+func (h *AttendeeHandler) Export(c *fiber.Ctx) error {
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid event ID"})
+	}
+
+	export, err := h.svc.Export(c.Context(), eventID)
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if c.Query("format") != "csv" {
+		return c.JSON(export)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	header := []string{"ticket_id", "order_id", "ticket_type", "status", "name", "complete"}
+	for _, q := range export.Questions {
+		header = append(header, q.Label)
+	}
+	if err := w.Write(header); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+	for _, a := range export.Attendees {
+		complete := "false"
+		if a.Complete {
+			complete = "true"
+		}
+		row := []string{a.TicketID.String(), a.OrderID.String(), a.TicketTypeName, string(a.Status), a.Name, complete}
+		for _, q := range export.Questions {
+			row = append(row, a.Answers[q.ID])
+		}
+		if err := w.Write(row); err != nil {
+			return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="attendees-`+eventID.String()+`.csv"`)
+	return c.Send(buf.Bytes())
+}
@@ -1,9 +1,17 @@
 package handler
 
 import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/yourname/ticketing-system/internal/core/entity"
 	"github.com/yourname/ticketing-system/internal/core/port"
+	"github.com/yourname/ticketing-system/internal/core/service"
+	"github.com/yourname/ticketing-system/pkg/auth"
 )
 
 type AuthHandler struct {
@@ -36,10 +44,247 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dữ liệu không hợp lệ"})
 	}
 
-	token, err := h.svc.Login(c.Context(), req)
+	req.IP = c.IP()
+	result, err := h.svc.Login(c.Context(), req)
+	if err != nil {
+		return loginError(c, err)
+	}
+
+	return c.JSON(result)
+}
+
+// VerifyMFA là bước 2 của đăng nhập: challenge token + mã TOTP (hoặc mã khôi phục)
+func (h *AuthHandler) VerifyMFA(c *fiber.Ctx) error {
+	var req entity.MFALoginRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dữ liệu không hợp lệ"})
+	}
+
+	req.IP = c.IP()
+	result, err := h.svc.VerifyMFALogin(c.Context(), req)
+	if err != nil {
+		return loginError(c, err)
+	}
+
+	return c.JSON(result)
+}
+
+// EnrollMFAWithChallenge cấp secret TOTP trong lúc đăng nhập (role bắt buộc 2FA mà chưa đăng ký)
+func (h *AuthHandler) EnrollMFAWithChallenge(c *fiber.Ctx) error {
+	var req entity.MFAChallengeRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dữ liệu không hợp lệ"})
+	}
+
+	enrollment, err := h.svc.BeginMFAEnrollmentWithChallenge(c.Context(), req.ChallengeToken)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(enrollment)
+}
+
+// EnrollMFA cấp secret TOTP (kèm otpauth URI và mã QR) cho user đang đăng nhập
+func (h *AuthHandler) EnrollMFA(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	enrollment, err := h.svc.BeginMFAEnrollment(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(enrollment)
+}
+
+// ConfirmMFA bật 2FA bằng mã đầu tiên từ ứng dụng xác thực, trả về mã khôi phục
+func (h *AuthHandler) ConfirmMFA(c *fiber.Ctx) error {
+	return h.withMFACode(c, func(userID uuid.UUID, code string) error {
+		codes, err := h.svc.ConfirmMFAEnrollment(c.Context(), userID, code)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.JSON(fiber.Map{"recovery_codes": codes})
+	})
+}
+
+// RegenerateRecoveryCodes cấp bộ mã khôi phục mới (cần mã TOTP hiện tại)
+func (h *AuthHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	return h.withMFACode(c, func(userID uuid.UUID, code string) error {
+		codes, err := h.svc.RegenerateRecoveryCodes(c.Context(), userID, code)
+		if err != nil {
+			return loginError(c, err)
+		}
+		return c.JSON(fiber.Map{"recovery_codes": codes})
+	})
+}
+
+// DisableMFA tắt 2FA (cần mã TOTP hiện tại)
+func (h *AuthHandler) DisableMFA(c *fiber.Ctx) error {
+	return h.withMFACode(c, func(userID uuid.UUID, code string) error {
+		if err := h.svc.DisableMFA(c.Context(), userID, code); err != nil {
+			return loginError(c, err)
+		}
+		return c.JSON(fiber.Map{"message": "Đã tắt xác thực 2 bước"})
+	})
+}
+
+// withMFACode đọc user đang đăng nhập và mã TOTP trong body rồi gọi fn
+func (h *AuthHandler) withMFACode(c *fiber.Ctx, fn func(userID uuid.UUID, code string) error) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var req entity.MFACodeRequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Thiếu mã xác thực"})
+	}
+
+	return fn(userID, req.Code)
+}
+
+// loginError trả 429 kèm Retry-After khi bị khóa do sai nhiều lần, còn lại 401
+func loginError(c *fiber.Ctx, err error) error {
+	var locked *service.LoginLockedError
+	if errors.As(err, &locked) {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(locked.RetryAfter.Seconds()))))
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+}
+
+// Refresh đổi refresh token lấy cặp token mới (refresh token cũ hết hiệu lực)
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var req entity.RefreshTokenRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dữ liệu không hợp lệ"})
+	}
+
+	tokens, err := h.svc.Refresh(c.Context(), req.RefreshToken)
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	return c.JSON(fiber.Map{"token": token})
+	return c.JSON(tokens)
+}
+
+// Logout thu hồi access token hiện tại và refresh token của phiên (gửi kèm trong body, nếu có)
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var req entity.RefreshTokenRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dữ liệu không hợp lệ"})
+		}
+	}
+
+	jti, _ := c.Locals("jti").(string)
+	expiresAt, _ := c.Locals("token_expires_at").(time.Time)
+	if err := h.svc.Logout(c.Context(), userID, jti, expiresAt, req.RefreshToken); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "Đã đăng xuất"})
+}
+
+// ForgotPassword gửi link đặt lại mật khẩu. Luôn trả 200 để không lộ email nào đã đăng ký.
+func (h *AuthHandler) ForgotPassword(c *fiber.Ctx) error {
+	var req entity.ForgotPasswordRequest
+
+	if err := c.BodyParser(&req); err != nil || req.Email == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dữ liệu không hợp lệ"})
+	}
+
+	if err := h.svc.ForgotPassword(c.Context(), req.Email); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "Nếu email đã đăng ký, hướng dẫn đặt lại mật khẩu đã được gửi"})
+}
+
+// ResetPassword đặt mật khẩu mới bằng token trong email (mọi phiên đăng nhập cũ bị thu hồi)
+func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	var req entity.ResetPasswordRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dữ liệu không hợp lệ"})
+	}
+
+	if err := h.svc.ResetPassword(c.Context(), req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "Đã đặt lại mật khẩu, vui lòng đăng nhập lại"})
+}
+
+// VerifyEmail xác thực email bằng token trong email
+func (h *AuthHandler) VerifyEmail(c *fiber.Ctx) error {
+	var req entity.VerifyEmailRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dữ liệu không hợp lệ"})
+	}
+
+	if err := h.svc.VerifyEmail(c.Context(), req.Token); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "Đã xác thực email"})
+}
+
+// ResendVerification gửi lại link xác thực email cho user đang đăng nhập
+func (h *AuthHandler) ResendVerification(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	if err := h.svc.ResendVerification(c.Context(), userID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "Đã gửi lại email xác thực"})
+}
+
+// ListLockouts liệt kê email / IP đang bị khóa đăng nhập (admin)
+func (h *AuthHandler) ListLockouts(c *fiber.Ctx) error {
+	lockouts, err := h.svc.ListLockouts(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(lockouts)
+}
+
+// ClearLockout mở khóa đăng nhập cho một email / IP: DELETE ?kind=ACCOUNT|IP&key=... (admin)
+func (h *AuthHandler) ClearLockout(c *fiber.Ctx) error {
+	kind := entity.LoginThrottleKind(c.Query("kind"))
+	key := c.Query("key")
+	if key == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Thiếu key (email hoặc IP)"})
+	}
+
+	if err := h.svc.ClearLockout(c.Context(), kind, key); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "Đã mở khóa đăng nhập"})
+}
+
+// JWKSHandler công bố public key của các khóa ký JWT còn hiệu lực (kể cả khóa sắp tới lượt)
+func JWKSHandler(keys *auth.KeySet) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+		return c.JSON(keys.JWKS(time.Now()))
+	}
 }
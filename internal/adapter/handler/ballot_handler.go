@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+type BallotHandler struct {
+	svc *service.BallotService
+}
+
+func NewBallotHandler(svc *service.BallotService) *BallotHandler {
+	return &BallotHandler{svc: svc}
+}
+
+// CreateBallot mở đợt bốc thăm cho một loại vé (admin)
+func (h *BallotHandler) CreateBallot(c *fiber.Ctx) error {
+	var req entity.CreateBallotRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	ballot, err := h.svc.CreateBallot(c.Context(), req)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(http.StatusCreated).JSON(ballot)
+}
+
+func (h *BallotHandler) GetBallot(c *fiber.Ctx) error {
+	ballotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid ballot ID"})
+	}
+
+	ballot, err := h.svc.GetBallot(c.Context(), ballotID)
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": "Ballot not found"})
+	}
+
+	return c.JSON(ballot)
+}
+
+// Enter đăng ký tham gia bốc thăm
+func (h *BallotHandler) Enter(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	ballotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid ballot ID"})
+	}
+
+	var req entity.EnterBallotRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	entry, err := h.svc.Enter(c.Context(), userID, ballotID, req)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(http.StatusCreated).JSON(entry)
+}
+
+// MyEntry trả về lượt đăng ký và kết quả của user
+func (h *BallotHandler) MyEntry(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	ballotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid ballot ID"})
+	}
+
+	entry, err := h.svc.GetMyEntry(c.Context(), userID, ballotID)
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": "Entry not found"})
+	}
+
+	return c.JSON(entry)
+}
+
+// Results công bố thứ tự bốc (seed + rank từng entry) để ai cũng kiểm chứng được
+func (h *BallotHandler) Results(c *fiber.Ctx) error {
+	ballotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid ballot ID"})
+	}
+
+	entries, err := h.svc.Results(c.Context(), ballotID)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": entries})
+}
+
+// Draw bốc thăm lần đầu (admin)
+func (h *BallotHandler) Draw(c *fiber.Ctx) error {
+	ballotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid ballot ID"})
+	}
+
+	result, err := h.svc.Draw(c.Context(), ballotID)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(result)
+}
+
+// Redraw bốc bổ sung cho các suất không thanh toán (admin)
+func (h *BallotHandler) Redraw(c *fiber.Ctx) error {
+	ballotID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid ballot ID"})
+	}
+
+	result, err := h.svc.Redraw(c.Context(), ballotID)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+// BoxOfficeHandler phục vụ nhân viên bán vé tại quầy (role box_office hoặc admin).
+type BoxOfficeHandler struct {
+	svc *service.OrderService
+}
+
+func NewBoxOfficeHandler(svc *service.OrderService) *BoxOfficeHandler {
+	return &BoxOfficeHandler{svc: svc}
+}
+
+type BoxOfficeOrderRequest struct {
+	CreateOrderRequest
+	entity.BoxOfficeSale
+}
+
+// Sell bán vé cho khách vãng lai: đơn PAID ngay, trả về vé để in
+func (h *BoxOfficeHandler) Sell(c *fiber.Ctx) error {
+	cashierID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var req BoxOfficeOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+	if len(req.Items) == 0 {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Items cannot be empty"})
+	}
+
+	serviceItems, err := toServiceItems(req.CreateOrderRequest)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	order, err := h.svc.PlaceBoxOfficeOrder(c.Context(), cashierID, req.BoxOfficeSale, serviceItems)
+	if err != nil {
+		return placeOrderError(c, err)
+	}
+
+	return c.Status(http.StatusCreated).JSON(order)
+}
+
+// ShiftReport đối soát cuối ca: ?from=&to= (RFC3339, mặc định từ 0h hôm nay tới hiện tại).
+// Nhân viên quầy chỉ xem được ca của mình, admin xem tất cả hoặc lọc bằng ?cashier_id=
+func (h *BoxOfficeHandler) ShiftReport(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	to := now
+	if raw := c.Query("from"); raw != "" {
+		if from, err = time.Parse(time.RFC3339, raw); err != nil {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid from"})
+		}
+	}
+	if raw := c.Query("to"); raw != "" {
+		if to, err = time.Parse(time.RFC3339, raw); err != nil {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid to"})
+		}
+	}
+
+	cashierID := &userID
+	if c.Locals("role") == entity.RoleAdmin {
+		cashierID = nil
+		if raw := c.Query("cashier_id"); raw != "" {
+			id, err := uuid.Parse(raw)
+			if err != nil {
+				return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid cashier_id"})
+			}
+			cashierID = &id
+		}
+	}
+
+	report, err := h.svc.ShiftReport(c.Context(), from, to, cashierID)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(report)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+type BundleHandler struct {
+	svc port.BundleServicePort
+}
+
+func NewBundleHandler(svc port.BundleServicePort) *BundleHandler {
+	return &BundleHandler{svc: svc}
+}
+
+func (h *BundleHandler) CreateBundle(c *fiber.Ctx) error {
+	var req entity.CreateBundleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Dữ liệu không hợp lệ",
+		})
+	}
+
+	bundle, err := h.svc.CreateBundle(c.Context(), req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(bundle)
+}
+
+func (h *BundleHandler) GetBundle(c *fiber.Ctx) error {
+	bundleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	bundle, err := h.svc.GetBundle(c.Context(), bundleID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Gói vé không tìm thấy",
+		})
+	}
+
+	return c.JSON(bundle)
+}
+
+func (h *BundleHandler) ListBundles(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 10)
+	offset := c.QueryInt("offset", 0)
+
+	bundles, err := h.svc.ListBundles(c.Context(), limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data":   bundles,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
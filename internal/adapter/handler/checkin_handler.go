@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+type CheckInHandler struct {
+	svc *service.CheckInService
+}
+
+func NewCheckInHandler(svc *service.CheckInService) *CheckInHandler {
+	return &CheckInHandler{svc: svc}
+}
+
+// CheckIn soát vé tại cổng của event (nhân viên soát vé), trả về vé và tên người tham dự
+func (h *CheckInHandler) CheckIn(c *fiber.Ctx) error {
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid event ID"})
+	}
+
+	var req entity.CheckInRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	ticket, err := h.svc.CheckIn(c.Context(), eventID, req)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(ticket)
+}
@@ -1,22 +1,35 @@
 package handler
 
 import (
+	"errors"
+	"strings"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/yourname/ticketing-system/internal/core/entity"
 	"github.com/yourname/ticketing-system/internal/core/port"
+	"github.com/yourname/ticketing-system/internal/core/service"
 )
 
 type EventHandler struct {
-	svc port.EventServicePort
+	svc          port.EventServicePort
+	cursorSecret string // Khóa ký cursor phân trang
+}
+
+func NewEventHandler(svc port.EventServicePort, cursorSecret string) *EventHandler {
+	return &EventHandler{svc: svc, cursorSecret: cursorSecret}
 }
 
-func NewEventHandler(svc port.EventServicePort) *EventHandler {
-	return &EventHandler{svc: svc}
+// createEventBody cho phép gửi kèm danh sách loại vé khi tạo event
+type createEventBody struct {
+	entity.CreateEventRequest
+	TicketTypes []entity.CreateTicketTypeRequest `json:"ticket_types"`
 }
 
 func (h *EventHandler) CreateEvent(c *fiber.Ctx) error {
-	var req entity.CreateEventRequest
+	var req createEventBody
 
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -24,7 +37,13 @@ func (h *EventHandler) CreateEvent(c *fiber.Ctx) error {
 		})
 	}
 
-	event, err := h.svc.CreateEvent(c.Context(), req)
+	var event *entity.Event
+	var err error
+	if len(req.TicketTypes) > 0 {
+		event, err = h.svc.CreateEventWithTickets(c.Context(), req.CreateEventRequest, req.TicketTypes)
+	} else {
+		event, err = h.svc.CreateEvent(c.Context(), req.CreateEventRequest)
+	}
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
@@ -69,19 +88,87 @@ func (h *EventHandler) GetEventBySlug(c *fiber.Ctx) error {
 }
 
 func (h *EventHandler) ListEvents(c *fiber.Ctx) error {
-	limit := c.QueryInt("limit", 10)
-	offset := c.QueryInt("offset", 0)
-
-	events, err := h.svc.ListEvents(c.Context(), limit, offset)
+	filter, err := parseEventFilter(c, h.cursorSecret)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
+	page, err := h.svc.ListEvents(c.Context(), filter)
+	if err != nil {
+		return listEventsError(c, err)
+	}
+
 	return c.JSON(fiber.Map{
-		"data":   events,
-		"limit":  limit,
-		"offset": offset,
+		"data":        page.Events,
+		"total":       page.Total,
+		"limit":       filter.Limit,
+		"offset":      filter.Offset,
+		"next_cursor": encodePageCursor(page.NextCursor, h.cursorSecret),
+		"prev_cursor": encodePageCursor(page.PrevCursor, h.cursorSecret),
 	})
 }
+
+// listEventsError trả 400 khi bộ lọc không hợp lệ, còn lại (lỗi DB...) 500
+func listEventsError(c *fiber.Ctx, err error) error {
+	var filterErr *service.FilterError
+	if errors.As(err, &filterErr) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
+
+// parseEventFilter đọc các query param tìm kiếm:
+// ?q=&status=&location=&start_from=&start_to=&min_price=&max_price=&sort=&limit=&offset=&cursor=
+// Thời gian theo định dạng RFC3339, giá là số thập phân. sort=popularity chỉ phân trang bằng offset.
+func parseEventFilter(c *fiber.Ctx, cursorSecret string) (entity.EventFilter, error) {
+	filter := entity.EventFilter{
+		Query:    strings.TrimSpace(c.Query("q")),
+		Status:   entity.EventStatus(strings.ToUpper(c.Query("status"))),
+		Location: strings.TrimSpace(c.Query("location")),
+		Sort:     entity.EventSort(c.Query("sort")),
+		Limit:    c.QueryInt("limit", 10),
+		Offset:   c.QueryInt("offset", 0),
+	}
+
+	if v := c.Query("start_from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, errors.New("start_from không hợp lệ (định dạng RFC3339)")
+		}
+		filter.StartFrom = &t
+	}
+	if v := c.Query("start_to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, errors.New("start_to không hợp lệ (định dạng RFC3339)")
+		}
+		filter.StartTo = &t
+	}
+	if v := c.Query("min_price"); v != "" {
+		p, err := decimal.NewFromString(v)
+		if err != nil {
+			return filter, errors.New("min_price không hợp lệ")
+		}
+		filter.MinPrice = &p
+	}
+	if v := c.Query("max_price"); v != "" {
+		p, err := decimal.NewFromString(v)
+		if err != nil {
+			return filter, errors.New("max_price không hợp lệ")
+		}
+		filter.MaxPrice = &p
+	}
+
+	cur, err := decodePageCursor(c.Query("cursor"), cursorSecret)
+	if err != nil {
+		return filter, err
+	}
+	// Cursor đã xác định vị trí trang, offset đi kèm sẽ bị bỏ qua nên báo lỗi thay vì trả về trang khác ý
+	if cur != nil && c.Query("offset") != "" {
+		return filter, errors.New("không dùng offset cùng cursor")
+	}
+	filter.Cursor = cur
+	return filter, nil
+}
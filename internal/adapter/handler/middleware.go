@@ -1,14 +1,22 @@
 package handler
 
 import (
+	"errors"
+	"math"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+	"github.com/yourname/ticketing-system/internal/core/service"
 	"github.com/yourname/ticketing-system/pkg/auth"
 )
 
-// AuthMiddleware nhận vào secret key để giải mã JWT
-func AuthMiddleware(secret string) fiber.Handler {
+// AuthMiddleware nhận vào bộ khóa để kiểm tra chữ ký JWT và authSvc để chặn token đã thu hồi (logout)
+func AuthMiddleware(keys *auth.KeySet, authSvc port.AuthServicePort) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// 1. Lấy giá trị từ Header Authorization
 		// Thông thường có dạng: Bearer <chuỗi_token>
@@ -30,24 +38,75 @@ func AuthMiddleware(secret string) fiber.Handler {
 		tokenString := tokenParts[1]
 
 		// 2. Kiểm tra Token bằng bộ công cụ pkg/auth chúng ta đã viết
-		claims, err := auth.ValidateToken(tokenString, secret)
-		if err != nil {
+		claims, err := auth.ValidateToken(tokenString, keys)
+		if err != nil || claims.ID == "" || claims.ExpiresAt == nil {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"error": "Token hết hạn hoặc không hợp lệ",
 			})
 		}
 
+		// 2b. Token đã logout (jti nằm trong danh sách thu hồi) thì chặn dù chưa hết hạn
+		revoked, err := authSvc.IsTokenRevoked(c.Context(), claims.ID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Không kiểm tra được token",
+			})
+		}
+		if revoked {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Token đã bị thu hồi",
+			})
+		}
+
 		// 3. "Đánh dấu" thông tin User vào Request
 		// c.Locals giúp truyền dữ liệu từ Middleware vào Handler chính
 		c.Locals("user_id", claims.UserID)
 		c.Locals("role", claims.Role)
+		c.Locals("jti", claims.ID)
+		c.Locals("token_expires_at", claims.ExpiresAt.Time)
 
 		// Cho phép đi tiếp vào Handler tiếp theo
 		return c.Next()
 	}
 }
 
-// AdminMiddleware kiểm tra user có role admin không (phải chạy sau AuthMiddleware)
+// AuthOrAPIKeyMiddleware chấp nhận JWT (Authorization: Bearer) hoặc API key (header X-API-Key).
+// Request dùng API key mang danh tính chủ khóa, kèm api_key_id và api_key_scopes để RequirePermission giới hạn quyền.
+func AuthOrAPIKeyMiddleware(keys *auth.KeySet, authSvc port.AuthServicePort, apiKeySvc port.APIKeyServicePort) fiber.Handler {
+	requireJWT := AuthMiddleware(keys, authSvc)
+
+	return func(c *fiber.Ctx) error {
+		apiKey := c.Get(entity.APIKeyHeader)
+		if apiKey == "" {
+			return requireJWT(c)
+		}
+
+		identity, err := apiKeySvc.Authenticate(c.Context(), apiKey, c.IP())
+		if err != nil {
+			var limited *service.APIKeyRateLimitedError
+			if errors.As(err, &limited) {
+				c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(limited.RetryAfter.Seconds()))))
+				c.Set("X-RateLimit-Limit", strconv.Itoa(limited.Limit))
+				c.Set("X-RateLimit-Remaining", "0")
+				return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": err.Error()})
+			}
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+		}
+
+		c.Set("X-RateLimit-Limit", strconv.Itoa(identity.RateLimit))
+		c.Set("X-RateLimit-Remaining", strconv.Itoa(identity.Remaining))
+
+		c.Locals("user_id", identity.UserID.String())
+		c.Locals("role", identity.Role)
+		c.Locals("api_key_id", identity.KeyID.String())
+		c.Locals("api_key_scopes", identity.Scopes)
+
+		return c.Next()
+	}
+}
+
+// AdminMiddleware kiểm tra user có role admin không (phải chạy sau AuthMiddleware).
+// Thao tác admin luôn cần đăng nhập, không dùng API key.
 func AdminMiddleware(c *fiber.Ctx) error {
 	role := c.Locals("role")
 	if role == nil {
@@ -56,7 +115,13 @@ func AdminMiddleware(c *fiber.Ctx) error {
 		})
 	}
 
-	if role != "admin" {
+	if c.Locals("api_key_id") != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Không dùng API key cho thao tác admin",
+		})
+	}
+
+	if role != entity.RoleAdmin {
 		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 			"error": "Chỉ admin mới có quyền thực hiện thao tác này",
 		})
@@ -64,3 +129,294 @@ func AdminMiddleware(c *fiber.Ctx) error {
 
 	return c.Next()
 }
+
+// RejectAPIKeyMiddleware chặn API key trên route không khai báo quyền (phải chạy sau AuthOrAPIKeyMiddleware).
+// Thao tác của chính người dùng (đặt vé, chuyển nhượng, bán lại...) chỉ nhận JWT, khóa phạm vi nào cũng không dùng thay chủ được.
+func RejectAPIKeyMiddleware(c *fiber.Ctx) error {
+	if c.Locals("api_key_id") != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "API key không được dùng cho thao tác này",
+		})
+	}
+	return c.Next()
+}
+
+// ScopeResolver xác định phạm vi (event...) của thao tác từ request để RequirePermission kiểm tra
+type ScopeResolver func(c *fiber.Ctx) (entity.PermissionScope, error)
+
+// GlobalScope dùng cho thao tác không gắn với event cụ thể (tạo event, quản lý venue...)
+func GlobalScope(c *fiber.Ctx) (entity.PermissionScope, error) {
+	return entity.PermissionScope{Type: entity.ScopeGlobal}, nil
+}
+
+// EventScope lấy event từ path param (VD: /events/:id)
+func EventScope(param string) ScopeResolver {
+	return func(c *fiber.Ctx) (entity.PermissionScope, error) {
+		eventID, err := uuid.Parse(c.Params(param))
+		if err != nil {
+			return entity.PermissionScope{}, err
+		}
+		return entity.PermissionScope{Type: entity.ScopeEvent, ID: eventID}, nil
+	}
+}
+
+// OrganizationScope lấy tổ chức từ path param (VD: /organizations/:id)
+func OrganizationScope(param string) ScopeResolver {
+	return func(c *fiber.Ctx) (entity.PermissionScope, error) {
+		orgID, err := uuid.Parse(c.Params(param))
+		if err != nil {
+			return entity.PermissionScope{}, err
+		}
+		return entity.PermissionScope{Type: entity.ScopeOrganization, ID: orgID}, nil
+	}
+}
+
+// TicketTypeEventScope lấy event chứa loại vé trong path param (VD: /ticket-types/:id)
+func TicketTypeEventScope(access port.AccessServicePort, param string) ScopeResolver {
+	return func(c *fiber.Ctx) (entity.PermissionScope, error) {
+		ticketTypeID, err := uuid.Parse(c.Params(param))
+		if err != nil {
+			return entity.PermissionScope{}, err
+		}
+		eventID, err := access.EventIDForTicketType(c.Context(), ticketTypeID)
+		if err != nil {
+			return entity.PermissionScope{}, err
+		}
+		return entity.PermissionScope{Type: entity.ScopeEvent, ID: eventID}, nil
+	}
+}
+
+// AllocationEventScope lấy event của phần vé giữ lại trong path param (VD: /allocations/:id)
+func AllocationEventScope(access port.AccessServicePort, param string) ScopeResolver {
+	return func(c *fiber.Ctx) (entity.PermissionScope, error) {
+		allocationID, err := uuid.Parse(c.Params(param))
+		if err != nil {
+			return entity.PermissionScope{}, err
+		}
+		eventID, err := access.EventIDForAllocation(c.Context(), allocationID)
+		if err != nil {
+			return entity.PermissionScope{}, err
+		}
+		return entity.PermissionScope{Type: entity.ScopeEvent, ID: eventID}, nil
+	}
+}
+
+// BallotEventScope lấy event của loại vé mở bốc thăm trong path param (VD: /ballots/:id)
+func BallotEventScope(access port.AccessServicePort, param string) ScopeResolver {
+	return func(c *fiber.Ctx) (entity.PermissionScope, error) {
+		ballotID, err := uuid.Parse(c.Params(param))
+		if err != nil {
+			return entity.PermissionScope{}, err
+		}
+		eventID, err := access.EventIDForBallot(c.Context(), ballotID)
+		if err != nil {
+			return entity.PermissionScope{}, err
+		}
+		return entity.PermissionScope{Type: entity.ScopeEvent, ID: eventID}, nil
+	}
+}
+
+// NewBallotEventScope lấy event của loại vé trong body tạo đợt bốc thăm
+func NewBallotEventScope(access port.AccessServicePort) ScopeResolver {
+	return func(c *fiber.Ctx) (entity.PermissionScope, error) {
+		var req entity.CreateBallotRequest
+		if err := c.BodyParser(&req); err != nil {
+			return entity.PermissionScope{}, err
+		}
+		eventID, err := access.EventIDForTicketType(c.Context(), req.TicketTypeID)
+		if err != nil {
+			return entity.PermissionScope{}, err
+		}
+		return entity.PermissionScope{Type: entity.ScopeEvent, ID: eventID}, nil
+	}
+}
+
+// ScopesResolver dùng cho thao tác chạm nhiều event cùng lúc (VD: bán gói combo tại quầy), user phải
+// có quyền ở mọi phạm vi trả về
+type ScopesResolver func(c *fiber.Ctx) ([]entity.PermissionScope, error)
+
+// SaleEventScopes lấy các event của loại vé / gói / add-on trong body đơn bán tại quầy
+func SaleEventScopes(access port.AccessServicePort) ScopesResolver {
+	return func(c *fiber.Ctx) ([]entity.PermissionScope, error) {
+		var req CreateOrderRequest
+		if err := c.BodyParser(&req); err != nil {
+			return nil, err
+		}
+		items, err := toServiceItems(req)
+		if err != nil {
+			return nil, err
+		}
+
+		var eventIDs []uuid.UUID
+		for _, item := range items {
+			switch {
+			case item.ResaleID != nil:
+				continue // quầy không bán vé bán lại, service sẽ từ chối
+			case item.AddOnID != nil:
+				eventID, err := access.EventIDForAddOn(c.Context(), *item.AddOnID)
+				if err != nil {
+					return nil, err
+				}
+				eventIDs = append(eventIDs, eventID)
+			case item.BundleID != nil:
+				ids, err := access.EventIDsForBundle(c.Context(), *item.BundleID)
+				if err != nil {
+					return nil, err
+				}
+				eventIDs = append(eventIDs, ids...)
+			default:
+				eventID, err := access.EventIDForTicketType(c.Context(), item.TicketTypeID)
+				if err != nil {
+					return nil, err
+				}
+				eventIDs = append(eventIDs, eventID)
+			}
+		}
+		return eventScopes(eventIDs)
+	}
+}
+
+// NewBundleEventScopes lấy các event của loại vé thành phần trong body tạo gói combo
+func NewBundleEventScopes(access port.AccessServicePort) ScopesResolver {
+	return func(c *fiber.Ctx) ([]entity.PermissionScope, error) {
+		var req entity.CreateBundleRequest
+		if err := c.BodyParser(&req); err != nil {
+			return nil, err
+		}
+
+		var eventIDs []uuid.UUID
+		for _, item := range req.Items {
+			eventID, err := access.EventIDForTicketType(c.Context(), item.TicketTypeID)
+			if err != nil {
+				return nil, err
+			}
+			eventIDs = append(eventIDs, eventID)
+		}
+		return eventScopes(eventIDs)
+	}
+}
+
+// OrderEventScopes lấy các event có hàng trong đơn ở path param (VD: /orders/:id)
+func OrderEventScopes(access port.AccessServicePort, param string) ScopesResolver {
+	return func(c *fiber.Ctx) ([]entity.PermissionScope, error) {
+		orderID, err := uuid.Parse(c.Params(param))
+		if err != nil {
+			return nil, err
+		}
+		eventIDs, err := access.EventIDsForOrder(c.Context(), orderID)
+		if err != nil {
+			return nil, err
+		}
+		return eventScopes(eventIDs)
+	}
+}
+
+// eventScopes bỏ trùng event; không có event nào thì coi như không xác định được phạm vi
+func eventScopes(eventIDs []uuid.UUID) ([]entity.PermissionScope, error) {
+	var scopes []entity.PermissionScope
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range eventIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		scopes = append(scopes, entity.PermissionScope{Type: entity.ScopeEvent, ID: id})
+	}
+	if len(scopes) == 0 {
+		return nil, errors.New("không xác định được sự kiện")
+	}
+	return scopes, nil
+}
+
+// RequirePermission chỉ cho user có quyền perm trong phạm vi của request đi tiếp (phải chạy sau AuthMiddleware)
+func RequirePermission(access port.AccessServicePort, perm entity.Permission, scope ScopeResolver) fiber.Handler {
+	return RequirePermissionAll(access, perm, func(c *fiber.Ctx) ([]entity.PermissionScope, error) {
+		s, err := scope(c)
+		if err != nil {
+			return nil, err
+		}
+		return []entity.PermissionScope{s}, nil
+	})
+}
+
+// RequirePermissionAll như RequirePermission nhưng user phải có quyền perm ở mọi phạm vi của request
+func RequirePermissionAll(access port.AccessServicePort, perm entity.Permission, scopes ScopesResolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, _ := c.Locals("role").(string)
+		userID, err := currentUserID(c)
+		if err != nil || role == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Yêu cầu xác thực trước",
+			})
+		}
+
+		// API key chỉ được các quyền ghi trên khóa (và chủ khóa vẫn phải còn quyền đó)
+		if keyScopes, ok := c.Locals("api_key_scopes").([]entity.Permission); ok && !slices.Contains(keyScopes, perm) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":      "API key không có quyền thực hiện thao tác này",
+				"permission": perm,
+			})
+		}
+
+		// Không xác định được phạm vi (ID sai, tài nguyên không tồn tại) thì coi như không có quyền,
+		// tránh lộ tài nguyên nào có thật
+		resolved, err := scopes(c)
+		if err != nil {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Bạn không có quyền thực hiện thao tác này",
+			})
+		}
+
+		for _, s := range resolved {
+			allowed, err := access.HasPermission(c.Context(), userID, role, perm, s)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": "Không kiểm tra được quyền",
+				})
+			}
+			if !allowed {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":      "Bạn không có quyền thực hiện thao tác này",
+					"permission": perm,
+				})
+			}
+		}
+
+		return c.Next()
+	}
+}
+
+// VerifiedEmailMiddleware chỉ cho user đã xác thực email đi tiếp (phải chạy sau AuthMiddleware)
+func VerifiedEmailMiddleware(authSvc port.AuthServicePort) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, err := currentUserID(c)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Yêu cầu xác thực trước",
+			})
+		}
+
+		verified, err := authSvc.IsEmailVerified(c.Context(), userID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Không kiểm tra được trạng thái email",
+			})
+		}
+		if !verified {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Vui lòng xác thực email trước khi đặt vé",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// currentUserID lấy user_id mà AuthMiddleware đã gắn vào request
+func currentUserID(c *fiber.Ctx) (uuid.UUID, error) {
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return uuid.Nil, errors.New("missing user_id")
+	}
+	return uuid.Parse(userIDStr)
+}
@@ -1,28 +1,38 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
+	"github.com/yourname/ticketing-system/internal/core/entity"
 	"github.com/yourname/ticketing-system/internal/core/service"
 )
 
 type OrderHandler struct {
-	svc *service.OrderService
+	svc          *service.OrderService
+	cursorSecret string // Khóa ký cursor phân trang
 }
 
-func NewOrderHandler(svc *service.OrderService) *OrderHandler {
-	return &OrderHandler{svc: svc}
+func NewOrderHandler(svc *service.OrderService, cursorSecret string) *OrderHandler {
+	return &OrderHandler{svc: svc, cursorSecret: cursorSecret}
 }
 
 type CreateOrderRequest struct {
 	Items []struct {
-		TicketTypeID string `json:"ticket_type_id"`
-		Quantity     int    `json:"quantity"`
+		TicketTypeID string                   `json:"ticket_type_id"`
+		BundleID     string                   `json:"bundle_id"` // Mua gói combo thay cho ticket_type_id
+		AddOnID      string                   `json:"add_on_id"` // Mua hàng bán kèm thay cho ticket_type_id
+		ResaleID     string                   `json:"resale_id"` // Mua vé bán lại trên sàn thay cho ticket_type_id
+		Quantity     int                      `json:"quantity"`
+		SeatIDs      []string                 `json:"seat_ids"`    // Chỉ dùng cho vé ngồi theo số
+		AccessCode   string                   `json:"access_code"` // Mã presale riêng cho item (ưu tiên hơn access_code của đơn)
+		Attendees    []entity.AttendeeDetails `json:"attendees"`   // Người tham dự từng vé (tùy chọn, điền sau được)
 	} `json:"items"`
+	AccessCode string `json:"access_code"` // Mã presale dùng chung cho cả đơn
 }
 
 func (h *OrderHandler) PlaceOrder(c *fiber.Ctx) error {
@@ -48,33 +58,212 @@ func (h *OrderHandler) PlaceOrder(c *fiber.Ctx) error {
 	}
 
 	// Du lieu tu client
+	serviceItems, err := toServiceItems(req)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	// goi Service
+	order, err := h.svc.PlaceOrder(c.Context(), userID, serviceItems)
+	if err != nil {
+		return placeOrderError(c, err)
+	}
+
+	// Tra ve response
+	return c.Status(http.StatusCreated).JSON(order)
+}
+
+// placeOrderError trả lỗi đặt vé cho client
+func placeOrderError(c *fiber.Ctx, err error) error {
+	// Hết vé: gợi ý client cho user vào hàng chờ của loại vé đó
+	var soldOut *service.SoldOutError
+	if errors.As(err, &soldOut) {
+		return c.Status(http.StatusConflict).JSON(fiber.Map{
+			"error":          err.Error(),
+			"ticket_type_id": soldOut.TicketTypeID,
+			"waitlist":       true,
+		})
+	}
+	// Goi Service bi loi
+	return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
+
+// toServiceItems chuyển items từ request sang service.RequestItem, dùng chung cho đặt online và bán tại quầy.
+func toServiceItems(req CreateOrderRequest) ([]service.RequestItem, error) {
 	var serviceItems []service.RequestItem
 	for _, item := range req.Items {
-		ticketID, err := uuid.Parse(item.TicketTypeID)
-		if err != nil {
-			return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-				"error": fmt.Sprintf("Invalid ticket_type_id: %s", item.TicketTypeID),
-			})
+		var ticketID uuid.UUID
+		var bundleID, addOnID, resaleID *uuid.UUID
+		if item.ResaleID != "" {
+			id, err := uuid.Parse(item.ResaleID)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid resale_id: %s", item.ResaleID)
+			}
+			resaleID = &id
+			if item.Quantity == 0 {
+				item.Quantity = 1 // mỗi tin bán lại là một vé
+			}
+		} else if item.AddOnID != "" {
+			id, err := uuid.Parse(item.AddOnID)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid add_on_id: %s", item.AddOnID)
+			}
+			addOnID = &id
+		} else if item.BundleID != "" {
+			id, err := uuid.Parse(item.BundleID)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid bundle_id: %s", item.BundleID)
+			}
+			bundleID = &id
+		} else {
+			id, err := uuid.Parse(item.TicketTypeID)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid ticket_type_id: %s", item.TicketTypeID)
+			}
+			ticketID = id
 		}
+
+		// Chọn ghế thì số lượng chính là số ghế
+		seatIDs := make([]uuid.UUID, 0, len(item.SeatIDs))
+		for _, raw := range item.SeatIDs {
+			seatID, err := uuid.Parse(raw)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid seat_id: %s", raw)
+			}
+			seatIDs = append(seatIDs, seatID)
+		}
+		if len(seatIDs) > 0 && item.Quantity == 0 {
+			item.Quantity = len(seatIDs)
+		}
+
 		if item.Quantity <= 0 {
-			return c.Status(http.StatusBadRequest).JSON(fiber.Map{
-				"error": fmt.Sprintf("Quantity must be greater than 0 for ticket: %s", item.TicketTypeID),
-			})
+			return nil, fmt.Errorf("Quantity must be greater than 0 for ticket: %s", item.TicketTypeID)
+		}
+
+		accessCode := item.AccessCode
+		if accessCode == "" {
+			accessCode = req.AccessCode
 		}
 
 		serviceItems = append(serviceItems, service.RequestItem{
 			TicketTypeID: ticketID,
+			BundleID:     bundleID,
+			AddOnID:      addOnID,
+			ResaleID:     resaleID,
 			Quantity:     item.Quantity,
+			SeatIDs:      seatIDs,
+			AccessCode:   accessCode,
+			Attendees:    item.Attendees,
 		})
 	}
 
-	// goi Service
-	order, err := h.svc.PlaceOrder(c.Context(), userID, serviceItems)
+	return serviceItems, nil
+}
+
+// ListOrders trả về đơn hàng của user đang đăng nhập, phân trang bằng ?cursor=&limit=
+func (h *OrderHandler) ListOrders(c *fiber.Ctx) error {
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		// Goi Service bi loi
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	cursor, err := decodePageCursor(c.Query("cursor"), h.cursorSecret)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid cursor"})
+	}
+
+	limit := c.QueryInt("limit", 10)
+	page, err := h.svc.ListOrders(c.Context(), userID, cursor, limit)
+	if err != nil {
+		var filterErr *service.FilterError
+		if errors.As(err, &filterErr) {
+			return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	// Tra ve response
-	return c.Status(http.StatusCreated).JSON(order)
+	return c.JSON(fiber.Map{
+		"data":        page.Orders,
+		"limit":       limit,
+		"next_cursor": encodePageCursor(page.NextCursor, h.cursorSecret),
+		"prev_cursor": encodePageCursor(page.PrevCursor, h.cursorSecret),
+	})
+}
+
+// CancelOrder cho user hủy đơn đang chờ thanh toán, vé trả lại được cấp cho hàng chờ
+func (h *OrderHandler) CancelOrder(c *fiber.Ctx) error {
+	userIDStr, ok := c.Locals("user_id").(string)
+	if !ok {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	orderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid order ID"})
+	}
+
+	if err := h.svc.CancelOrder(c.Context(), userID, orderID); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "Order cancelled"})
+}
+
+// RefundOrder hoàn đơn đã thanh toán (quyền orders:refund trên các event của đơn), vé bị thu hồi
+func (h *OrderHandler) RefundOrder(c *fiber.Ctx) error {
+	orderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid order ID"})
+	}
+
+	if err := h.svc.RefundOrder(c.Context(), orderID); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "Order refunded"})
+}
+
+// ConfirmPayment cho admin đánh dấu đơn đã thanh toán sau khi đối soát với cổng thanh toán
+func (h *OrderHandler) ConfirmPayment(c *fiber.Ctx) error {
+	orderID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid order ID"})
+	}
+
+	if err := h.svc.ConfirmPayment(c.Context(), orderID); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "Order paid"})
+}
+
+// TopUpStock cho admin mở bán thêm vé của một loại vé
+func (h *OrderHandler) TopUpStock(c *fiber.Ctx) error {
+	ticketTypeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid ticket type ID"})
+	}
+
+	var req struct {
+		Quantity int `json:"quantity"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.svc.TopUpStock(c.Context(), ticketTypeID, req.Quantity); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "Stock added"})
 }
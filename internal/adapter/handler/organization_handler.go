@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+type OrganizationHandler struct {
+	svc          port.OrganizationServicePort
+	cursorSecret string // Khóa ký cursor phân trang
+}
+
+func NewOrganizationHandler(svc port.OrganizationServicePort, cursorSecret string) *OrganizationHandler {
+	return &OrganizationHandler{svc: svc, cursorSecret: cursorSecret}
+}
+
+// Create tạo tổ chức mới (admin)
+func (h *OrganizationHandler) Create(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.CreateOrganizationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Dữ liệu không hợp lệ",
+		})
+	}
+
+	org, err := h.svc.CreateOrganization(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(org)
+}
+
+// List trả về các tổ chức của user hiện tại (admin thấy tất cả)
+func (h *OrganizationHandler) List(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+	role, _ := c.Locals("role").(string)
+
+	orgs, err := h.svc.ListOrganizations(c.Context(), userID, role)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{"data": orgs})
+}
+
+// Get trả về thông tin công khai của tổ chức (tên, tiền tệ, phí, thương hiệu)
+func (h *OrganizationHandler) Get(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	org, err := h.svc.GetOrganization(c.Context(), orgID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Không tìm thấy tổ chức",
+		})
+	}
+
+	return c.JSON(org)
+}
+
+// UpdateSettings cập nhật tiền tệ, phí và thương hiệu của tổ chức
+func (h *OrganizationHandler) UpdateSettings(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	var settings entity.OrganizationSettings
+	if err := c.BodyParser(&settings); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Dữ liệu không hợp lệ",
+		})
+	}
+
+	org, err := h.svc.UpdateSettings(c.Context(), orgID, settings)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(org)
+}
+
+func (h *OrganizationHandler) ListMembers(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	members, err := h.svc.ListMembers(c.Context(), orgID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{"data": members})
+}
+
+// AddMember cấp vai trò trong tổ chức cho user theo email
+func (h *OrganizationHandler) AddMember(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+	grantedBy, err := currentUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req entity.AddMemberRequest
+	if err := c.BodyParser(&req); err != nil || req.Email == "" || req.Role == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Dữ liệu không hợp lệ",
+		})
+	}
+
+	assignment, err := h.svc.AddMember(c.Context(), grantedBy, orgID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(assignment)
+}
+
+// RemoveMember thu hồi vai trò của user trong tổ chức (?role= để chỉ thu hồi một vai trò)
+func (h *OrganizationHandler) RemoveMember(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+	userID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID user không hợp lệ",
+		})
+	}
+
+	if err := h.svc.RemoveMember(c.Context(), orgID, userID, c.Query("role")); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{"message": "Đã thu hồi vai trò trong tổ chức"})
+}
+
+// CreateEvent tạo event thuộc tổ chức (có thể gửi kèm danh sách loại vé)
+func (h *OrganizationHandler) CreateEvent(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	var req createEventBody
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Dữ liệu không hợp lệ",
+		})
+	}
+
+	event, err := h.svc.CreateEvent(c.Context(), orgID, req.CreateEventRequest, req.TicketTypes)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(event)
+}
+
+// ListEvents liệt kê event của tổ chức, kể cả bản nháp (cùng bộ lọc với GET /events)
+func (h *OrganizationHandler) ListEvents(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	filter, err := parseEventFilter(c, h.cursorSecret)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	page, err := h.svc.ListEvents(c.Context(), orgID, filter)
+	if err != nil {
+		return listEventsError(c, err)
+	}
+
+	return c.JSON(fiber.Map{
+		"data":        page.Events,
+		"total":       page.Total,
+		"limit":       filter.Limit,
+		"offset":      filter.Offset,
+		"next_cursor": encodePageCursor(page.NextCursor, h.cursorSecret),
+		"prev_cursor": encodePageCursor(page.PrevCursor, h.cursorSecret),
+	})
+}
+
+// ListEventOrders liệt kê đơn hàng của một event thuộc tổ chức (cursor pagination)
+func (h *OrganizationHandler) ListEventOrders(c *fiber.Ctx) error {
+	orgID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+	eventID, err := uuid.Parse(c.Params("eventId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID sự kiện không hợp lệ",
+		})
+	}
+
+	cursor, err := decodePageCursor(c.Query("cursor"), h.cursorSecret)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid cursor",
+		})
+	}
+
+	limit := c.QueryInt("limit", 10)
+	page, err := h.svc.ListEventOrders(c.Context(), orgID, eventID, cursor, limit)
+	if err != nil {
+		var filterErr *service.FilterError
+		if errors.As(err, &filterErr) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data":        page.Orders,
+		"limit":       limit,
+		"next_cursor": encodePageCursor(page.NextCursor, h.cursorSecret),
+		"prev_cursor": encodePageCursor(page.PrevCursor, h.cursorSecret),
+	})
+}
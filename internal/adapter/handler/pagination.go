@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/pkg/cursor"
+)
+
+// decodePageCursor giải mã cursor client gửi lên (?cursor=...). Chuỗi rỗng = trang đầu.
+func decodePageCursor(raw string, secret string) (*entity.PageCursor, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var cur entity.PageCursor
+	if err := cursor.Decode(raw, secret, &cur); err != nil {
+		return nil, err
+	}
+	return &cur, nil
+}
+
+// encodePageCursor ký cursor để trả về client, nil khi không còn trang.
+func encodePageCursor(cur *entity.PageCursor, secret string) *string {
+	if cur == nil {
+		return nil
+	}
+
+	token, err := cursor.Encode(cur, secret)
+	if err != nil {
+		return nil
+	}
+	return &token
+}
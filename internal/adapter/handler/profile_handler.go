@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+// GetMe trả về thông tin đầy đủ của user đang đăng nhập
+func (h *AuthHandler) GetMe(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	user, err := h.svc.GetProfile(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(user)
+}
+
+// UpdateMe cập nhật các trường hồ sơ (họ tên, số điện thoại, ngày sinh, avatar)
+func (h *AuthHandler) UpdateMe(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var req entity.UpdateProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dữ liệu không hợp lệ"})
+	}
+
+	user, err := h.svc.UpdateProfile(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(user)
+}
+
+// ChangePassword đổi mật khẩu, yêu cầu mật khẩu hiện tại
+func (h *AuthHandler) ChangePassword(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var req entity.ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dữ liệu không hợp lệ"})
+	}
+
+	if err := h.svc.ChangePassword(c.Context(), userID, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "Đã đổi mật khẩu, vui lòng đăng nhập lại trên các thiết bị khác"})
+}
+
+// ChangeEmail gửi link xác nhận tới email mới, email chỉ đổi sau khi xác nhận
+func (h *AuthHandler) ChangeEmail(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var req entity.ChangeEmailRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dữ liệu không hợp lệ"})
+	}
+
+	if err := h.svc.RequestEmailChange(c.Context(), userID, req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{"message": "Đã gửi link xác nhận tới email mới"})
+}
+
+// ConfirmEmailChange xác nhận đổi email bằng token trong email
+func (h *AuthHandler) ConfirmEmailChange(c *fiber.Ctx) error {
+	var req entity.VerifyEmailRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Dữ liệu không hợp lệ"})
+	}
+
+	if err := h.svc.ConfirmEmailChange(c.Context(), req.Token); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "Đã đổi email"})
+}
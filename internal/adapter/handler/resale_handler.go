@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+type ResaleHandler struct {
+	svc *service.ResaleService
+}
+
+func NewResaleHandler(svc *service.ResaleService) *ResaleHandler {
+	return &ResaleHandler{svc: svc}
+}
+
+// CreateListing rao bán lại một vé đang giữ với giá không vượt trần của event
+func (h *ResaleHandler) CreateListing(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	var req entity.CreateResaleListingRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	listing, err := h.svc.CreateListing(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(http.StatusCreated).JSON(listing)
+}
+
+// ListMyListings trả về các tin bán lại của user (kèm phí và tiền hoàn của tin đã bán)
+func (h *ResaleHandler) ListMyListings(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	listings, err := h.svc.ListMyListings(c.Context(), userID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": listings})
+}
+
+// CancelListing gỡ tin bán lại đang rao
+func (h *ResaleHandler) CancelListing(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	listingID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid listing ID"})
+	}
+
+	if err := h.svc.CancelListing(c.Context(), userID, listingID); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "Listing cancelled"})
+}
+
+// ListEventListings trả về các vé đang rao bán lại của event (public)
+func (h *ResaleHandler) ListEventListings(c *fiber.Ctx) error {
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid event ID"})
+	}
+
+	listings, err := h.svc.ListEventListings(c.Context(), eventID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": listings})
+}
+
+// SetEventCap đặt trần giá bán lại của event, 0 = tắt bán lại (admin)
+func (h *ResaleHandler) SetEventCap(c *fiber.Ctx) error {
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid event ID"})
+	}
+
+	var req entity.SetResaleCapRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.svc.SetEventCap(c.Context(), eventID, req.CapPercent); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"resale_cap_percent": req.CapPercent})
+}
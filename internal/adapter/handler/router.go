@@ -2,34 +2,223 @@ package handler
 
 import (
 	"github.com/gofiber/fiber/v2"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/pkg/auth"
 )
 
 // SetupRoutes tập trung tất cả định nghĩa API vào một chỗ
-func SetupRoutes(app *fiber.App, authHandler *AuthHandler, eventHandler *EventHandler, orderHandler *OrderHandler, jwtSecret string) {
+func SetupRoutes(app *fiber.App, authHandler *AuthHandler, eventHandler *EventHandler, orderHandler *OrderHandler, venueHandler *VenueHandler, seatHandler *SeatHandler, poolHandler *PoolHandler, sessionHandler *SessionHandler, bundleHandler *BundleHandler, addOnHandler *AddOnHandler, waitlistHandler *WaitlistHandler, ballotHandler *BallotHandler, presaleHandler *PresaleHandler, allocationHandler *AllocationHandler, boxOfficeHandler *BoxOfficeHandler, transferHandler *TransferHandler, checkInHandler *CheckInHandler, resaleHandler *ResaleHandler, attendeeHandler *AttendeeHandler, accessHandler *AccessHandler, organizationHandler *OrganizationHandler, apiKeyHandler *APIKeyHandler, jwtKeys *auth.KeySet, requireVerifiedEmail bool) {
 	api := app.Group("/api/v1")
 
+	// Route quản lý tài khoản / admin / API key chỉ nhận JWT (kiểm tra cả token đã thu hồi).
+	// Các route khác nhận thêm API key cho tích hợp máy-máy, quyền giới hạn theo khóa.
+	// Route không khai báo quyền (thao tác của chính người dùng) chặn API key bằng ownerOnly.
+	requireLogin := AuthMiddleware(jwtKeys, authHandler.svc)
+	requireAuth := AuthOrAPIKeyMiddleware(jwtKeys, authHandler.svc, apiKeyHandler.svc)
+	ownerOnly := RejectAPIKeyMiddleware
+
+	// Chặn đặt vé khi user chưa xác thực email (bật bằng cấu hình)
+	requireVerified := func(c *fiber.Ctx) error { return c.Next() }
+	if requireVerifiedEmail {
+		requireVerified = VerifiedEmailMiddleware(authHandler.svc)
+	}
+
+	// Kiểm tra quyền theo vai trò + phạm vi (role gốc hoặc vai trò được cấp cho event)
+	access := accessHandler.svc
+	can := func(perm entity.Permission, scope ScopeResolver) fiber.Handler {
+		return RequirePermission(access, perm, scope)
+	}
+	canAll := func(perm entity.Permission, scopes ScopesResolver) fiber.Handler {
+		return RequirePermissionAll(access, perm, scopes)
+	}
+	eventScope := EventScope("id")
+	orgScope := OrganizationScope("id")
+	ticketTypeScope := TicketTypeEventScope(access, "id")
+	allocationScope := AllocationEventScope(access, "id")
+	ballotScope := BallotEventScope(access, "id")
+	bundleScopes := NewBundleEventScopes(access)  // event của các loại vé thành phần trong body
+	newBallotScope := NewBallotEventScope(access) // event của loại vé trong body
+
+	// Public key để service khác tự kiểm tra token của hệ thống (RFC 7517)
+	app.Get("/.well-known/jwks.json", JWKSHandler(jwtKeys))
+
 	// Auth routes
 	auth := api.Group("/auth")
 	auth.Post("/register", authHandler.Register)
 	auth.Post("/login", authHandler.Login)
+	auth.Post("/refresh", authHandler.Refresh)                                      // Đổi refresh token lấy cặp token mới
+	auth.Post("/logout", requireLogin, authHandler.Logout)                          // Thu hồi access + refresh token của phiên
+	auth.Post("/forgot-password", authHandler.ForgotPassword)                       // Gửi link đặt lại mật khẩu qua email
+	auth.Post("/reset-password", authHandler.ResetPassword)                         // Đặt mật khẩu mới bằng token trong email
+	auth.Post("/verify-email", authHandler.VerifyEmail)                             // Xác thực email bằng token trong email
+	auth.Post("/verify-email/resend", requireLogin, authHandler.ResendVerification) // Gửi lại link xác thực
+	auth.Post("/confirm-email", authHandler.ConfirmEmailChange)                     // Xác nhận đổi email bằng token gửi tới email mới
+
+	// Xác thực 2 bước (TOTP): bước 2 khi đăng nhập và quản lý ứng dụng xác thực
+	auth.Post("/login/mfa", authHandler.VerifyMFA)                                      // Challenge token + mã TOTP / mã khôi phục
+	auth.Post("/login/mfa/enroll", authHandler.EnrollMFAWithChallenge)                  // Đăng ký bắt buộc ngay lúc đăng nhập
+	auth.Post("/mfa/enroll", requireLogin, authHandler.EnrollMFA)                       // Secret + otpauth URI + QR
+	auth.Post("/mfa/enroll/confirm", requireLogin, authHandler.ConfirmMFA)              // Nhập mã đầu tiên để bật, nhận mã khôi phục
+	auth.Post("/mfa/recovery-codes", requireLogin, authHandler.RegenerateRecoveryCodes) // Cấp lại mã khôi phục
+	auth.Delete("/mfa", requireLogin, authHandler.DisableMFA)                           // Tắt 2FA
+
+	// Admin: xem / mở khóa đăng nhập bị khóa do sai mật khẩu nhiều lần
+	admin := api.Group("/admin", requireLogin, AdminMiddleware)
+	admin.Get("/login-lockouts", authHandler.ListLockouts)
+	admin.Delete("/login-lockouts", authHandler.ClearLockout) // ?kind=ACCOUNT|IP&key=...
+
+	// Admin: cấp / thu hồi vai trò (organizer, scanner, box_office, support) theo phạm vi
+	admin.Post("/role-assignments", accessHandler.Grant)        // Cấp vai trò cho user (GLOBAL hoặc một EVENT)
+	admin.Delete("/role-assignments/:id", accessHandler.Revoke) // Thu hồi vai trò đã cấp
+	admin.Get("/users/:id/roles", accessHandler.ListUserRoles)  // Role gốc + vai trò được cấp
+
+	// Admin: xác nhận đơn đã thanh toán sau khi đối soát (vé bán lại sang tên, chi tiền người bán)
+	admin.Post("/orders/:id/paid", orderHandler.ConfirmPayment)
+
+	// API key cho website đối tác / kiosk (organizer, admin), gửi kèm header X-API-Key
+	apiKeys := api.Group("/api-keys", requireLogin)
+	apiKeys.Post("", apiKeyHandler.Create)            // Tạo khóa (quyền, giới hạn request/phút, hạn dùng), khóa chỉ hiện một lần
+	apiKeys.Get("", apiKeyHandler.List)               // Khóa của tôi, kèm lần dùng cuối
+	apiKeys.Post("/:id/rotate", apiKeyHandler.Rotate) // Cấp khóa mới, khóa cũ thu hồi ngay hoặc sau grace_period_minutes
+	apiKeys.Delete("/:id", apiKeyHandler.Revoke)      // Thu hồi khóa (chủ khóa hoặc admin)
 
 	// User routes
-	user := api.Group("/user", AuthMiddleware(jwtSecret))
-	user.Get("/me", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"user_id": c.Locals("user_id"),
-			"role":    c.Locals("role"),
-		})
-	})
+	user := api.Group("/user", requireLogin)
+	user.Get("/me", authHandler.GetMe)                   // Thông tin đầy đủ từ DB
+	user.Patch("/me", authHandler.UpdateMe)              // Cập nhật hồ sơ (họ tên, SĐT, ngày sinh, avatar)
+	user.Put("/me/password", authHandler.ChangePassword) // Đổi mật khẩu, cần mật khẩu hiện tại
+	user.Post("/me/email", authHandler.ChangeEmail)      // Gửi link xác nhận tới email mới
 
 	// Event routes
 	events := api.Group("/events")
-	events.Post("/", AuthMiddleware(jwtSecret), AdminMiddleware, eventHandler.CreateEvent) // Create event (admin only)
-	events.Get("/:id", eventHandler.GetEvent)                                              // Get event by ID
-	events.Get("/slug/:slug", eventHandler.GetEventBySlug)                                 // Get event by slug
-	events.Get("", eventHandler.ListEvents)                                                // List all events
+	events.Post("/", requireAuth, can(entity.PermEventsWrite, GlobalScope), eventHandler.CreateEvent) // Create event
+	events.Get("/:id", eventHandler.GetEvent)                                                         // Get event by ID
+	events.Get("/slug/:slug", eventHandler.GetEventBySlug)                                            // Get event by slug
+	events.Get("", eventHandler.ListEvents)                                                           // List all events
+
+	// Organization routes (ban tổ chức sở hữu event; thành viên là vai trò cấp theo tổ chức)
+	orgs := api.Group("/organizations")
+	orgs.Post("/", requireAuth, AdminMiddleware, organizationHandler.Create) // Tạo tổ chức (admin only)
+	orgs.Get("", requireAuth, ownerOnly, organizationHandler.List)           // Tổ chức của tôi (admin: tất cả)
+	orgs.Get("/:id", organizationHandler.Get)                                // Tên, tiền tệ, phí, thương hiệu
+
+	orgs.Put("/:id/settings", requireAuth, can(entity.PermOrgManage, orgScope), organizationHandler.UpdateSettings)         // Cấu hình tiền tệ / phí / thương hiệu
+	orgs.Get("/:id/members", requireAuth, can(entity.PermOrgManage, orgScope), organizationHandler.ListMembers)             // Thành viên và vai trò
+	orgs.Post("/:id/members", requireAuth, can(entity.PermOrgManage, orgScope), organizationHandler.AddMember)              // Cấp vai trò theo email
+	orgs.Delete("/:id/members/:userId", requireAuth, can(entity.PermOrgManage, orgScope), organizationHandler.RemoveMember) // Thu hồi vai trò (?role=)
+
+	orgs.Post("/:id/events", requireAuth, can(entity.PermEventsWrite, orgScope), organizationHandler.CreateEvent)                   // Tạo event của tổ chức
+	orgs.Get("/:id/events", requireAuth, can(entity.PermEventsWrite, orgScope), organizationHandler.ListEvents)                     // Event của tổ chức (kể cả nháp)
+	orgs.Get("/:id/events/:eventId/orders", requireAuth, can(entity.PermOrdersRead, orgScope), organizationHandler.ListEventOrders) // Đơn hàng của event
+
+	// Seat map routes (vé ngồi theo số)
+	events.Get("/:id/seats", seatHandler.GetSeatMap)                                                         // Sơ đồ ghế + trạng thái
+	events.Get("/:id/seats/best", seatHandler.BestAvailable)                                                 // Gợi ý N ghế liền nhau
+	events.Post("/:id/seats", requireAuth, can(entity.PermEventsWrite, eventScope), seatHandler.CreateSeats) // Thêm ghế
+
+	// Capacity pool routes
+	events.Post("/:id/pools", requireAuth, can(entity.PermEventsWrite, eventScope), poolHandler.CreatePool)          // Tạo pool dùng chung
+	events.Get("/:id/pools/report", requireAuth, can(entity.PermReportsRead, eventScope), poolHandler.GetPoolReport) // Báo cáo sử dụng pool
+
+	// Add-on routes (hàng bán kèm: gửi xe, áo...)
+	events.Get("/:id/addons", addOnHandler.ListAddOns)                                                          // Add-on của event
+	events.Post("/:id/addons", requireAuth, can(entity.PermEventsWrite, eventScope), addOnHandler.CreateAddOn)  // Tạo add-on
+	events.Get("/:id/sales", requireAuth, can(entity.PermReportsRead, eventScope), addOnHandler.GetSalesReport) // Doanh số theo loại item
+
+	// Presale routes (vé ẩn mở khóa bằng mã)
+	events.Post("/:id/presale-codes", requireAuth, can(entity.PermEventsWrite, eventScope), presaleHandler.CreateCode) // Tạo mã presale
+	events.Get("/:id/presale-codes", requireAuth, can(entity.PermEventsWrite, eventScope), presaleHandler.ListCodes)   // Danh sách mã
+	events.Post("/:id/presale/unlock", requireAuth, ownerOnly, presaleHandler.Unlock)                                  // Nhập mã để xem loại vé ẩn
+
+	// Session routes (event nhiều suất diễn)
+	events.Get("/:id/sessions", sessionHandler.ListEventSessions)                                                     // Các suất của event
+	events.Post("/:id/sessions", requireAuth, can(entity.PermEventsWrite, eventScope), sessionHandler.CreateSessions) // Sinh suất theo luật lặp
+	api.Get("/sessions", sessionHandler.ListUpcomingSessions)                                                         // Suất sắp diễn của mọi event
+
+	// Venue routes
+	venues := api.Group("/venues")
+	venues.Get("", venueHandler.ListVenues)                                                                      // List venues
+	venues.Get("/:id", venueHandler.GetVenue)                                                                    // Get venue (kèm sections)
+	venues.Post("/", requireAuth, can(entity.PermVenuesWrite, GlobalScope), venueHandler.CreateVenue)            // Create venue
+	venues.Put("/:id", requireAuth, can(entity.PermVenuesWrite, GlobalScope), venueHandler.UpdateVenue)          // Update venue
+	venues.Delete("/:id", requireAuth, can(entity.PermVenuesWrite, GlobalScope), venueHandler.DeleteVenue)       // Delete venue
+	venues.Post("/:id/sections", requireAuth, can(entity.PermVenuesWrite, GlobalScope), venueHandler.AddSection) // Add section
+
+	// Bundle routes (gói combo nhiều loại vé)
+	bundles := api.Group("/bundles")
+	bundles.Get("", bundleHandler.ListBundles)                                                               // List bundles
+	bundles.Get("/:id", bundleHandler.GetBundle)                                                             // Get bundle (kèm loại vé thành phần)
+	bundles.Post("/", requireAuth, canAll(entity.PermEventsWrite, bundleScopes), bundleHandler.CreateBundle) // Create bundle
 
 	// Order routes
-	orders := api.Group("/orders", AuthMiddleware(jwtSecret))
-	orders.Post("/", orderHandler.PlaceOrder)
+	orders := api.Group("/orders", requireAuth)
+	orders.Post("/", ownerOnly, requireVerified, orderHandler.PlaceOrder)
+	orders.Get("", ownerOnly, orderHandler.ListOrders)              // Lịch sử đơn hàng của user (cursor pagination)
+	orders.Post("/:id/cancel", ownerOnly, orderHandler.CancelOrder) // Hủy đơn chờ thanh toán (trả vé cho hàng chờ)
+
+	// Hoàn đơn đã thanh toán (CSKH / ban tổ chức), cần orders:refund trên mọi event có hàng trong đơn
+	orders.Post("/:id/refund", canAll(entity.PermOrdersRefund, OrderEventScopes(access, "id")), orderHandler.RefundOrder)
+
+	// Box office routes (bán tại quầy, cần quyền box_office:sell trên mọi event có vé trong đơn)
+	boxOffice := api.Group("/box-office", requireAuth)
+	boxOffice.Post("/orders", canAll(entity.PermBoxOfficeSell, SaleEventScopes(access)), boxOfficeHandler.Sell) // Bán cho khách vãng lai, đơn PAID + vé ngay
+	boxOffice.Get("/shift-report", ownerOnly, boxOfficeHandler.ShiftReport)                                     // Đối soát tiền cuối ca của chính mình (admin: mọi thu ngân)
+
+	// Ticket type stock
+	api.Post("/ticket-types/:id/top-up", requireAuth, can(entity.PermEventsWrite, ticketTypeScope), orderHandler.TopUpStock) // Mở bán thêm vé
+
+	// Allocation routes (vé giữ lại cho nhà tài trợ / vé mời)
+	api.Post("/ticket-types/:id/allocations", requireAuth, can(entity.PermEventsWrite, ticketTypeScope), allocationHandler.CreateAllocation) // Giữ lại vé khỏi bán công khai
+	api.Get("/ticket-types/:id/allocations", requireAuth, can(entity.PermEventsWrite, ticketTypeScope), allocationHandler.ListAllocations)   // Các phần vé đang giữ
+	api.Post("/allocations/:id/comps", requireAuth, can(entity.PermEventsWrite, allocationScope), allocationHandler.IssueComp)               // Phát vé mời (đơn PAID 0 đồng)
+	api.Post("/allocations/:id/release", requireAuth, can(entity.PermEventsWrite, allocationScope), allocationHandler.Release)               // Trả vé chưa phát về bán công khai
+
+	// Ticket & transfer routes (chuyển nhượng vé cho người khác qua email)
+	tickets := api.Group("/tickets", requireAuth, ownerOnly)
+	tickets.Get("", transferHandler.ListMyTickets)               // Vé đang giữ (kể cả vé được chuyển tới)
+	tickets.Post("/:id/transfers", transferHandler.Initiate)     // Chuyển vé tới email người nhận
+	tickets.Get("/:id/transfers", transferHandler.TicketHistory) // Lịch sử chuyển nhượng của vé
+
+	// Soát vé tại cổng (nhân viên soát vé của event)
+	events.Post("/:id/check-in", requireAuth, can(entity.PermTicketsScan, eventScope), checkInHandler.CheckIn) // Quét mã vé, đánh dấu đã dùng
+
+	transfers := api.Group("/transfers", requireAuth, ownerOnly)
+	transfers.Get("", transferHandler.ListTransfers)      // Lượt chuyển đã gửi / được gửi tới
+	transfers.Post("/:id/accept", transferHandler.Accept) // Nhận vé, cấp mã mới
+	transfers.Post("/:id/cancel", transferHandler.Cancel) // Rút lại / từ chối
+
+	api.Put("/events/:id/transfers", requireAuth, can(entity.PermEventsWrite, eventScope), transferHandler.SetEventTransfers)                 // Bật/tắt chuyển nhượng cả event
+	api.Put("/ticket-types/:id/transfers", requireAuth, can(entity.PermEventsWrite, ticketTypeScope), transferHandler.SetTicketTypeTransfers) // Bật/tắt chuyển nhượng loại vé
+
+	// Resale routes (sàn bán lại vé có trần giá, người mua đặt qua POST /orders với resale_id, vé sang tên khi đơn thanh toán)
+	resale := api.Group("/resale", requireAuth, ownerOnly)
+	resale.Post("/listings", resaleHandler.CreateListing)       // Rao bán lại vé đang giữ
+	resale.Get("/listings/me", resaleHandler.ListMyListings)    // Tin của tôi, kèm phí và tiền hoàn
+	resale.Delete("/listings/:id", resaleHandler.CancelListing) // Gỡ tin đang rao
+
+	api.Get("/events/:id/resale", resaleHandler.ListEventListings)                                                 // Vé đang rao bán lại của event
+	api.Put("/events/:id/resale", requireAuth, can(entity.PermEventsWrite, eventScope), resaleHandler.SetEventCap) // Trần giá bán lại (% giá gốc)
+
+	// Attendee routes (thông tin người tham dự từng vé theo câu hỏi của event)
+	tickets.Put("/:id/attendee", attendeeHandler.UpdateAttendee)                                                                  // Điền/sửa người tham dự trước hạn
+	api.Put("/events/:id/attendee-questions", requireAuth, can(entity.PermEventsWrite, eventScope), attendeeHandler.SetQuestions) // Bộ câu hỏi + hạn trả lời
+	api.Get("/events/:id/attendees", requireAuth, can(entity.PermAttendeesRead, eventScope), attendeeHandler.Export)              // Xuất danh sách (?format=csv)
+
+	// Waitlist routes (hàng chờ loại vé đã hết)
+	waitlist := api.Group("/waitlist", requireAuth, ownerOnly)
+	waitlist.Post("/", waitlistHandler.Join)
+	waitlist.Get("", waitlistHandler.List)
+	waitlist.Delete("/:id", waitlistHandler.Leave)
+	waitlist.Post("/:id/claim", requireVerified, waitlistHandler.Claim) // Nhận vé đang được giữ
+
+	// Ballot routes (bán vé bốc thăm)
+	ballots := api.Group("/ballots")
+	ballots.Post("/", requireAuth, can(entity.PermEventsWrite, newBallotScope), ballotHandler.CreateBallot)  // Mở đợt bốc thăm
+	ballots.Get("/:id", ballotHandler.GetBallot)                                                             // Thông tin đợt (seed_hash, seed sau khi bốc)
+	ballots.Get("/:id/results", ballotHandler.Results)                                                       // Thứ tự bốc để kiểm chứng
+	ballots.Post("/:id/entries", requireAuth, ownerOnly, ballotHandler.Enter)                                // Đăng ký tham gia
+	ballots.Get("/:id/entries/me", requireAuth, ownerOnly, ballotHandler.MyEntry)                            // Kết quả của tôi
+	ballots.Post("/:id/draw", requireAuth, can(entity.PermEventsWrite, ballotScope), ballotHandler.Draw)     // Bốc lần đầu
+	ballots.Post("/:id/redraw", requireAuth, can(entity.PermEventsWrite, ballotScope), ballotHandler.Redraw) // Bốc bổ sung
 }
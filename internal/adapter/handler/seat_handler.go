@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+type SeatHandler struct {
+	svc port.SeatServicePort
+}
+
+func NewSeatHandler(svc port.SeatServicePort) *SeatHandler {
+	return &SeatHandler{svc: svc}
+}
+
+// CreateSeats nhận danh sách ghế {"seats": [...]} cho sơ đồ của event
+func (h *SeatHandler) CreateSeats(c *fiber.Ctx) error {
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	var req struct {
+		Seats []entity.CreateSeatRequest `json:"seats"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Dữ liệu không hợp lệ",
+		})
+	}
+
+	seats, err := h.svc.CreateSeats(c.Context(), eventID, req.Seats)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"data":  seats,
+		"count": len(seats),
+	})
+}
+
+func (h *SeatHandler) GetSeatMap(c *fiber.Ctx) error {
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	seatMap, err := h.svc.GetSeatMap(c.Context(), eventID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(seatMap)
+}
+
+// BestAvailable gợi ý N ghế liền nhau: ?ticket_type_id=&quantity=
+func (h *SeatHandler) BestAvailable(c *fiber.Ctx) error {
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	ticketTypeID, err := uuid.Parse(c.Query("ticket_type_id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ticket_type_id không hợp lệ",
+		})
+	}
+
+	seats, err := h.svc.FindBestAvailable(c.Context(), eventID, ticketTypeID, c.QueryInt("quantity", 1))
+	if err != nil {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{"data": seats})
+}
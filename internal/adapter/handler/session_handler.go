@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+type SessionHandler struct {
+	svc port.SessionServicePort
+}
+
+func NewSessionHandler(svc port.SessionServicePort) *SessionHandler {
+	return &SessionHandler{svc: svc}
+}
+
+func (h *SessionHandler) CreateSessions(c *fiber.Ctx) error {
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	var req entity.CreateSessionsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Dữ liệu không hợp lệ",
+		})
+	}
+
+	sessions, err := h.svc.CreateSessions(c.Context(), eventID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"data":  sessions,
+		"count": len(sessions),
+	})
+}
+
+func (h *SessionHandler) ListEventSessions(c *fiber.Ctx) error {
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	filter, err := parseSessionFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	sessions, err := h.svc.ListEventSessions(c.Context(), eventID, filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data":   sessions,
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	})
+}
+
+// ListUpcomingSessions liệt kê các suất sắp diễn của mọi event: ?from=&to=&limit=&offset=
+func (h *SessionHandler) ListUpcomingSessions(c *fiber.Ctx) error {
+	filter, err := parseSessionFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	sessions, err := h.svc.ListUpcomingSessions(c.Context(), filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data":   sessions,
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	})
+}
+
+func parseSessionFilter(c *fiber.Ctx) (entity.SessionFilter, error) {
+	filter := entity.SessionFilter{
+		Limit:  c.QueryInt("limit", 20),
+		Offset: c.QueryInt("offset", 0),
+	}
+
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, errors.New("from không hợp lệ (định dạng RFC3339)")
+		}
+		filter.From = &t
+	}
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return filter, errors.New("to không hợp lệ (định dạng RFC3339)")
+		}
+		filter.To = &t
+	}
+	return filter, nil
+}
@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+type TransferHandler struct {
+	svc *service.TransferService
+}
+
+func NewTransferHandler(svc *service.TransferService) *TransferHandler {
+	return &TransferHandler{svc: svc}
+}
+
+// ListMyTickets trả về các vé user đang giữ (kể cả vé được chuyển nhượng tới)
+func (h *TransferHandler) ListMyTickets(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	tickets, err := h.svc.ListMyTickets(c.Context(), userID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": tickets})
+}
+
+// Initiate chuyển vé cho người nhận theo email
+func (h *TransferHandler) Initiate(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	ticketID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid ticket ID"})
+	}
+
+	var req entity.CreateTicketTransferRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	transfer, err := h.svc.Initiate(c.Context(), userID, ticketID, req)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.Status(http.StatusCreated).JSON(transfer)
+}
+
+// TicketHistory trả về lịch sử chuyển nhượng của vé
+func (h *TransferHandler) TicketHistory(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	ticketID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid ticket ID"})
+	}
+
+	transfers, err := h.svc.TicketHistory(c.Context(), userID, ticketID)
+	if err != nil {
+		return c.Status(http.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": transfers})
+}
+
+// ListTransfers trả về các lượt chuyển user đã gửi hoặc được gửi tới
+func (h *TransferHandler) ListTransfers(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+
+	transfers, err := h.svc.ListTransfers(c.Context(), userID)
+	if err != nil {
+		return c.Status(http.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"data": transfers})
+}
+
+// Accept nhận vé được chuyển nhượng, trả về vé với mã mới
+func (h *TransferHandler) Accept(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	transferID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid transfer ID"})
+	}
+
+	ticket, err := h.svc.Accept(c.Context(), userID, transferID)
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(ticket)
+}
+
+// Cancel rút lại (người gửi) hoặc từ chối (người nhận) lượt chuyển đang chờ
+func (h *TransferHandler) Cancel(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return c.Status(http.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	}
+	transferID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid transfer ID"})
+	}
+
+	if err := h.svc.Cancel(c.Context(), userID, transferID); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"message": "Transfer cancelled"})
+}
+
+// SetEventTransfers bật/tắt chuyển nhượng cho cả event (admin)
+func (h *TransferHandler) SetEventTransfers(c *fiber.Ctx) error {
+	eventID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid event ID"})
+	}
+
+	var req entity.SetTransfersRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.svc.SetEventTransfers(c.Context(), eventID, req.Disabled); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"transfers_disabled": req.Disabled})
+}
+
+// SetTicketTypeTransfers bật/tắt chuyển nhượng cho một loại vé (admin)
+func (h *TransferHandler) SetTicketTypeTransfers(c *fiber.Ctx) error {
+	ticketTypeID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid ticket type ID"})
+	}
+
+	var req entity.SetTransfersRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	if err := h.svc.SetTicketTypeTransfers(c.Context(), ticketTypeID, req.Disabled); err != nil {
+		return c.Status(http.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(fiber.Map{"transfers_disabled": req.Disabled})
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+type VenueHandler struct {
+	svc port.VenueServicePort
+}
+
+func NewVenueHandler(svc port.VenueServicePort) *VenueHandler {
+	return &VenueHandler{svc: svc}
+}
+
+func (h *VenueHandler) CreateVenue(c *fiber.Ctx) error {
+	var req entity.CreateVenueRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Dữ liệu không hợp lệ",
+		})
+	}
+
+	venue, err := h.svc.CreateVenue(c.Context(), req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(venue)
+}
+
+func (h *VenueHandler) GetVenue(c *fiber.Ctx) error {
+	venueID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	venue, err := h.svc.GetVenue(c.Context(), venueID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Địa điểm không tìm thấy",
+		})
+	}
+
+	return c.JSON(venue)
+}
+
+func (h *VenueHandler) ListVenues(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 10)
+	offset := c.QueryInt("offset", 0)
+
+	venues, err := h.svc.ListVenues(c.Context(), limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"data":   venues,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+func (h *VenueHandler) UpdateVenue(c *fiber.Ctx) error {
+	venueID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	var req entity.UpdateVenueRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Dữ liệu không hợp lệ",
+		})
+	}
+
+	venue, err := h.svc.UpdateVenue(c.Context(), venueID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(venue)
+}
+
+func (h *VenueHandler) DeleteVenue(c *fiber.Ctx) error {
+	venueID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	if err := h.svc.DeleteVenue(c.Context(), venueID); err != nil {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Không thể xóa địa điểm đang có sự kiện",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *VenueHandler) AddSection(c *fiber.Ctx) error {
+	venueID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID không hợp lệ",
+		})
+	}
+
+	var req entity.CreateVenueSectionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Dữ liệu không hợp lệ",
+		})
+	}
+
+	section, err := h.svc.AddSection(c.Context(), venueID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(section)
+}
@@ -0,0 +1,49 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+// fileMailer ghi mỗi email thành một file .eml trong dir thay vì gửi thật (dùng khi dev/test).
+type fileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir, from string) (port.MailerPort, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &fileMailer{dir: dir, from: from}, nil
+}
+
+func (m *fileMailer) Send(ctx context.Context, to string, subject string, body string) error {
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405"), uuid.New().String())
+	return os.WriteFile(filepath.Join(m.dir, name), buildMessage(m.from, to, subject, body), 0o600)
+}
+
+// headerSanitizer bỏ xuống dòng trong header để email người dùng nhập không chèn thêm header được.
+var headerSanitizer = strings.NewReplacer("\r", "", "\n", "")
+
+// buildMessage dựng email dạng văn bản thuần (RFC 5322) dùng chung cho các adapter.
+func buildMessage(from, to, subject, body string) []byte {
+	from, to, subject = headerSanitizer.Replace(from), headerSanitizer.Replace(to), headerSanitizer.Replace(subject)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,29 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+// smtpMailer gửi email qua SMTP server (STARTTLS nếu server hỗ trợ).
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSMTPMailer tạo mailer gửi qua host:port. username rỗng thì gửi không xác thực (VD: relay nội bộ, MailHog).
+func NewSMTPMailer(host, port, username, password, from string) port.MailerPort {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &smtpMailer{addr: net.JoinHostPort(host, port), auth: auth, from: from}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, to string, subject string, body string) error {
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{to}, buildMessage(m.from, to, subject, body))
+}
@@ -0,0 +1,21 @@
+package notifier
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+// logNotifier chỉ ghi thông báo ra log, dùng khi chưa cấu hình kênh gửi thật.
+type logNotifier struct{}
+
+func NewLogNotifier() port.NotifierPort {
+	return &logNotifier{}
+}
+
+func (n *logNotifier) Notify(ctx context.Context, userID uuid.UUID, subject string, message string) error {
+	log.Printf("[notify] user=%s subject=%q message=%q", userID, subject, message)
+	return nil
+}
@@ -0,0 +1,22 @@
+package payout
+
+import (
+	"context"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+// logPayout chỉ ghi lệnh chi ra log, dùng khi chưa tích hợp cổng chi tiền thật.
+type logPayout struct{}
+
+func NewLogPayout() port.PayoutPort {
+	return &logPayout{}
+}
+
+func (p *logPayout) Payout(ctx context.Context, userID uuid.UUID, reference uuid.UUID, amount decimal.Decimal) error {
+	log.Printf("[payout] user=%s reference=%s amount=%s", userID, reference, amount.StringFixed(2))
+	return nil
+}
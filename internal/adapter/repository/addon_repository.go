@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+	"gorm.io/gorm"
+)
+
+type addOnRepository struct {
+	db *gorm.DB
+}
+
+func NewAddOnRepository(db *gorm.DB) port.AddOnRepositoryPort {
+	return &addOnRepository{db: db}
+}
+
+func (r *addOnRepository) CreateAddOn(ctx context.Context, addOn *entity.AddOn) error {
+	// GORM tạo luôn add_on_restrictions (association)
+	return r.db.WithContext(ctx).Create(addOn).Error
+}
+
+func (r *addOnRepository) ListAddOnsByEvent(ctx context.Context, eventID uuid.UUID) ([]entity.AddOn, error) {
+	var addOns []entity.AddOn
+	err := r.db.WithContext(ctx).Preload("Restrictions").
+		Where("event_id = ?", eventID).
+		Order("name").Order("variant").
+		Find(&addOns).Error
+	return addOns, err
+}
+
+func (r *addOnRepository) SalesByKind(ctx context.Context, eventID uuid.UUID) ([]entity.ItemKindSales, error) {
+	// Item thuộc event nếu: loại vé của event, add-on của event, hoặc bundle có loại vé của event
+	var sales []entity.ItemKindSales
+	err := r.db.WithContext(ctx).
+		Table("order_items").
+		Select("order_items.kind, SUM(order_items.quantity) AS quantity, SUM(order_items.quantity * order_items.price) AS revenue").
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Joins("LEFT JOIN ticket_types ON ticket_types.id = order_items.ticket_type_id").
+		Joins("LEFT JOIN add_ons ON add_ons.id = order_items.add_on_id").
+		Where("orders.status <> ?", entity.OrderStatusCancelled).
+		Where(`ticket_types.event_id = ? OR add_ons.event_id = ? OR order_items.bundle_id IN (
+			SELECT bundle_items.bundle_id FROM bundle_items
+			JOIN ticket_types bt ON bt.id = bundle_items.ticket_type_id
+			WHERE bt.event_id = ?)`, eventID, eventID, eventID).
+		Group("order_items.kind").
+		Order("order_items.kind").
+		Scan(&sales).Error
+	return sales, err
+}
+
+func (r *addOnRepository) AddOnSales(ctx context.Context, eventID uuid.UUID) ([]entity.AddOnSales, error) {
+	var sales []entity.AddOnSales
+	err := r.db.WithContext(ctx).
+		Table("add_ons").
+		Select(`add_ons.id AS add_on_id, add_ons.name, add_ons.variant,
+			add_ons.initial_quantity - add_ons.remaining_quantity AS sold,
+			add_ons.remaining_quantity AS remaining,
+			COALESCE(SUM(order_items.quantity * order_items.price) FILTER (WHERE orders.status <> ?), 0) AS revenue`,
+			entity.OrderStatusCancelled).
+		Joins("LEFT JOIN order_items ON order_items.add_on_id = add_ons.id").
+		Joins("LEFT JOIN orders ON orders.id = order_items.order_id").
+		Where("add_ons.event_id = ?", eventID).
+		Group("add_ons.id").
+		Order("add_ons.name").Order("add_ons.variant").
+		Scan(&sales).Error
+	return sales, err
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+// AllocationRepository nhận tx như OrderRepository vì giữ vé / phát vé mời đi cùng trừ kho và tạo đơn.
+type AllocationRepository struct {
+	db *gorm.DB
+}
+
+func NewAllocationRepository(db *gorm.DB) *AllocationRepository {
+	return &AllocationRepository{db: db}
+}
+
+func (r *AllocationRepository) CreateAllocation(ctx context.Context, tx *gorm.DB, allocation *entity.Allocation) error {
+	return tx.WithContext(ctx).Create(allocation).Error
+}
+
+func (r *AllocationRepository) ListByTicketType(ctx context.Context, ticketTypeID uuid.UUID) ([]entity.Allocation, error) {
+	var allocations []entity.Allocation
+	err := r.db.WithContext(ctx).
+		Where("ticket_type_id = ?", ticketTypeID).
+		Order("created_at").
+		Find(&allocations).Error
+	return allocations, err
+}
+
+// GetAllocationForUpdate khóa allocation để phát vé mời / trả vé không vượt số đang giữ.
+func (r *AllocationRepository) GetAllocationForUpdate(ctx context.Context, tx *gorm.DB, id uuid.UUID) (*entity.Allocation, error) {
+	var allocation entity.Allocation
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&allocation, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &allocation, nil
+}
+
+func (r *AllocationRepository) SaveAllocation(ctx context.Context, tx *gorm.DB, allocation *entity.Allocation) error {
+	return tx.WithContext(ctx).Save(allocation).Error
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+type apiKeyRepository struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) port.APIKeyRepositoryPort {
+	return &apiKeyRepository{db: db}
+}
+
+// apiKeyColumns là các cột đọc ra entity.APIKey, dùng chung với scanAPIKey
+const apiKeyColumns = `k.id, k.user_id, k.name, k.prefix, k.key_hash, k.scopes, k.rate_limit_per_minute,
+       k.expires_at, k.last_used_at, k.last_used_ip, k.revoked_at, k.rotated_from, k.created_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAPIKey(row rowScanner, extra ...interface{}) (*entity.APIKey, error) {
+	key := &entity.APIKey{}
+	var scopes []byte
+	dest := append([]interface{}{
+		&key.ID, &key.UserID, &key.Name, &key.Prefix, &key.KeyHash, &scopes, &key.RateLimitPerMinute,
+		&key.ExpiresAt, &key.LastUsedAt, &key.LastUsedIP, &key.RevokedAt, &key.RotatedFrom, &key.CreatedAt,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(scopes, &key.Scopes); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (r *apiKeyRepository) CreateKey(ctx context.Context, key *entity.APIKey) error {
+	scopes, err := json.Marshal(key.Scopes)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO api_keys (id, user_id, name, prefix, key_hash, scopes, rate_limit_per_minute, expires_at, rotated_from, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		key.ID, key.UserID, key.Name, key.Prefix, key.KeyHash, scopes, key.RateLimitPerMinute,
+		key.ExpiresAt, key.RotatedFrom, key.CreatedAt,
+	)
+	return err
+}
+
+func (r *apiKeyRepository) GetKey(ctx context.Context, id uuid.UUID) (*entity.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys k WHERE k.id = $1`
+	return scanAPIKey(r.db.QueryRowContext(ctx, query, id))
+}
+
+func (r *apiKeyRepository) ListKeysByUser(ctx context.Context, userID uuid.UUID) ([]entity.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+apiKeyColumns+` FROM api_keys k WHERE k.user_id = $1 ORDER BY k.created_at DESC`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []entity.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *apiKeyRepository) CountActiveKeys(ctx context.Context, userID uuid.UUID, now time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM api_keys
+         WHERE user_id = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > $2)`, userID, now).Scan(&count)
+	return count, err
+}
+
+func (r *apiKeyRepository) RevokeKey(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE api_keys SET revoked_at = $2 WHERE id = $1 AND revoked_at IS NULL`, id, at)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (r *apiKeyRepository) ExpireKey(ctx context.Context, id uuid.UUID, at time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE api_keys SET expires_at = LEAST(COALESCE(expires_at, $2), $2) WHERE id = $1 AND revoked_at IS NULL`, id, at)
+	return err
+}
+
+func (r *apiKeyRepository) UseKey(ctx context.Context, keyHash string, ip string, windowStart time.Time) (*entity.APIKeyUsage, error) {
+	// Một câu lệnh vừa kiểm tra hiệu lực, vừa ghi lần dùng cuối, vừa đếm request: cửa sổ cũ hơn windowStart thì mở cửa sổ mới
+	query := `UPDATE api_keys k SET
+                  rate_window_count = CASE WHEN k.rate_window_start IS NULL OR k.rate_window_start <= $3 THEN 1 ELSE k.rate_window_count + 1 END,
+                  rate_window_start = CASE WHEN k.rate_window_start IS NULL OR k.rate_window_start <= $3 THEN NOW() ELSE k.rate_window_start END,
+                  last_used_at = NOW(),
+                  last_used_ip = $2
+              FROM users u
+              WHERE u.id = k.user_id AND k.key_hash = $1
+                AND k.revoked_at IS NULL AND (k.expires_at IS NULL OR k.expires_at > NOW())
+              RETURNING ` + apiKeyColumns + `, u.role, k.rate_window_start, k.rate_window_count`
+
+	usage := &entity.APIKeyUsage{}
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, query, keyHash, ip, windowStart),
+		&usage.OwnerRole, &usage.WindowStart, &usage.WindowCount)
+	if err != nil {
+		return nil, err
+	}
+	usage.Key = key
+	return usage, nil
+}
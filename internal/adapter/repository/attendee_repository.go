@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+// AttendeeRepository nhận tx như TransferRepository vì sửa thông tin người tham dự phải khóa vé.
+type AttendeeRepository struct {
+	db *gorm.DB
+}
+
+func NewAttendeeRepository(db *gorm.DB) *AttendeeRepository {
+	return &AttendeeRepository{db: db}
+}
+
+func (r *AttendeeRepository) GetEvent(ctx context.Context, eventID uuid.UUID) (*entity.Event, error) {
+	var event entity.Event
+	if err := r.db.WithContext(ctx).First(&event, "id = ?", eventID).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// SetEventQuestions ghi đè bộ câu hỏi và hạn trả lời của event.
+func (r *AttendeeRepository) SetEventQuestions(ctx context.Context, eventID uuid.UUID, questions []entity.AttendeeQuestion, deadline *time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.Event{ID: eventID}).
+		Select("AttendeeQuestions", "AttendeeDeadline").
+		Updates(&entity.Event{AttendeeQuestions: questions, AttendeeDeadline: deadline}).Error
+}
+
+// GetTicketForUpdate khóa vé để không ghi đè câu trả lời khi vé đang được chuyển nhượng.
+func (r *AttendeeRepository) GetTicketForUpdate(ctx context.Context, tx *gorm.DB, id uuid.UUID) (*entity.Ticket, error) {
+	var ticket entity.Ticket
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&ticket, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// GetEventByTicketType trả về event của loại vé (kèm bộ câu hỏi người tham dự).
+func (r *AttendeeRepository) GetEventByTicketType(ctx context.Context, tx *gorm.DB, ticketTypeID uuid.UUID) (*entity.Event, error) {
+	var event entity.Event
+	if err := tx.WithContext(ctx).
+		Joins("JOIN ticket_types ON ticket_types.event_id = events.id").
+		Where("ticket_types.id = ?", ticketTypeID).
+		First(&event).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r *AttendeeRepository) SaveAttendee(ctx context.Context, tx *gorm.DB, ticket *entity.Ticket) error {
+	return tx.WithContext(ctx).
+		Model(ticket).
+		Select("OwnerName", "Answers").
+		Updates(ticket).Error
+}
+
+// ListAttendees trả về các vé của event thuộc đơn đã thanh toán, kèm tên loại vé.
+func (r *AttendeeRepository) ListAttendees(ctx context.Context, eventID uuid.UUID) ([]entity.AttendeeRecord, error) {
+	var rows []struct {
+		entity.Ticket
+		TicketTypeName string
+	}
+	err := r.db.WithContext(ctx).
+		Model(&entity.Ticket{}).
+		Select("tickets.*, ticket_types.name AS ticket_type_name").
+		Joins("JOIN ticket_types ON ticket_types.id = tickets.ticket_type_id").
+		Joins("JOIN orders ON orders.id = tickets.order_id").
+		Where("ticket_types.event_id = ? AND orders.status = ?", eventID, entity.OrderStatusPaid).
+		Order("ticket_types.name").Order("tickets.created_at").Order("tickets.id").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]entity.AttendeeRecord, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, entity.AttendeeRecord{
+			TicketID:       row.ID,
+			OrderID:        row.OrderID,
+			TicketTypeID:   row.TicketTypeID,
+			TicketTypeName: row.TicketTypeName,
+			Status:         row.Status,
+			Name:           row.OwnerName,
+			Answers:        row.Answers,
+		})
+	}
+	return records, nil
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+// BallotRepository nhận tx như OrderRepository vì bốc thăm tạo đơn và trừ kho trong cùng transaction.
+type BallotRepository struct {
+	db *gorm.DB
+}
+
+func NewBallotRepository(db *gorm.DB) *BallotRepository {
+	return &BallotRepository{db: db}
+}
+
+// CreateBallot tạo đợt bốc thăm và đánh dấu loại vé chỉ bán qua bốc thăm.
+func (r *BallotRepository) CreateBallot(ctx context.Context, tx *gorm.DB, ballot *entity.Ballot) error {
+	if err := tx.WithContext(ctx).Create(ballot).Error; err != nil {
+		return err
+	}
+	return tx.WithContext(ctx).
+		Model(&entity.TicketType{}).
+		Where("id = ?", ballot.TicketTypeID).
+		Update("ballot", true).Error
+}
+
+func (r *BallotRepository) GetBallot(ctx context.Context, id uuid.UUID) (*entity.Ballot, error) {
+	var ballot entity.Ballot
+	if err := r.db.WithContext(ctx).First(&ballot, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &ballot, nil
+}
+
+// GetBallotForUpdate khóa ballot, để hai admin bấm bốc cùng lúc không bốc hai lần.
+func (r *BallotRepository) GetBallotForUpdate(ctx context.Context, tx *gorm.DB, id uuid.UUID) (*entity.Ballot, error) {
+	var ballot entity.Ballot
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&ballot, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &ballot, nil
+}
+
+func (r *BallotRepository) SaveBallot(ctx context.Context, tx *gorm.DB, ballot *entity.Ballot) error {
+	return tx.WithContext(ctx).Save(ballot).Error
+}
+
+func (r *BallotRepository) CreateEntry(ctx context.Context, entry *entity.BallotEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *BallotRepository) GetEntryByUser(ctx context.Context, ballotID, userID uuid.UUID) (*entity.BallotEntry, error) {
+	var entry entity.BallotEntry
+	err := r.db.WithContext(ctx).
+		Where("ballot_id = ? AND user_id = ?", ballotID, userID).
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// ListEntriesForUpdate khóa các entry có status thuộc statuses, theo thứ tự bốc (position) rồi id.
+func (r *BallotRepository) ListEntriesForUpdate(ctx context.Context, tx *gorm.DB, ballotID uuid.UUID, statuses []entity.BallotEntryStatus) ([]entity.BallotEntry, error) {
+	var entries []entity.BallotEntry
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("ballot_id = ? AND status IN ?", ballotID, statuses).
+		Order("position NULLS LAST").Order("id").
+		Find(&entries).Error
+	return entries, err
+}
+
+// ListResults trả về toàn bộ entry theo thứ tự bốc, dùng để công bố/kiểm chứng kết quả.
+func (r *BallotRepository) ListResults(ctx context.Context, ballotID uuid.UUID) ([]entity.BallotEntry, error) {
+	var entries []entity.BallotEntry
+	err := r.db.WithContext(ctx).
+		Where("ballot_id = ?", ballotID).
+		Order("position NULLS LAST").Order("id").
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *BallotRepository) SaveEntry(ctx context.Context, tx *gorm.DB, entry *entity.BallotEntry) error {
+	return tx.WithContext(ctx).Save(entry).Error
+}
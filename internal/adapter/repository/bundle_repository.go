@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+	"gorm.io/gorm"
+)
+
+type bundleRepository struct {
+	db *gorm.DB
+}
+
+func NewBundleRepository(db *gorm.DB) port.BundleRepositoryPort {
+	return &bundleRepository{db: db}
+}
+
+func (r *bundleRepository) CreateBundle(ctx context.Context, bundle *entity.Bundle) error {
+	// GORM tạo luôn bundle_items (association)
+	return r.db.WithContext(ctx).Create(bundle).Error
+}
+
+func (r *bundleRepository) GetBundleByID(ctx context.Context, id uuid.UUID) (*entity.Bundle, error) {
+	var bundle entity.Bundle
+	err := r.db.WithContext(ctx).Preload("Items").First(&bundle, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+func (r *bundleRepository) ListBundles(ctx context.Context, limit int, offset int) ([]entity.Bundle, error) {
+	var bundles []entity.Bundle
+	err := r.db.WithContext(ctx).Preload("Items").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&bundles).Error
+	return bundles, err
+}
@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourname/ticketing-system/internal/core/entity"
@@ -10,53 +13,302 @@ import (
 )
 
 type eventRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	orgID *uuid.UUID // Khác nil: chỉ thấy event của tổ chức này (xem ForOrganization)
 }
 
 func NewEventRepository(db *gorm.DB) port.EventRepositoryPort {
 	return &eventRepository{db: db}
 }
 
+func (r *eventRepository) ForOrganization(orgID uuid.UUID) port.EventRepositoryPort {
+	return &eventRepository{db: r.db, orgID: &orgID}
+}
+
+// tenantEvents lọc bảng events theo tổ chức của repository (không làm gì nếu không giới hạn tổ chức)
+func (r *eventRepository) tenantEvents(db *gorm.DB) *gorm.DB {
+	if r.orgID == nil {
+		return db
+	}
+	return db.Where("events.organization_id = ?", *r.orgID)
+}
+
+// tenantEventColumn lọc bảng con (ticket_types...) theo tổ chức qua cột event_id của bảng đó
+func (r *eventRepository) tenantEventColumn(column string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if r.orgID == nil {
+			return db
+		}
+		return db.Where(column+" IN (SELECT id FROM events WHERE organization_id = ?)", *r.orgID)
+	}
+}
+
+// checkEventInTenant trả về gorm.ErrRecordNotFound nếu event không thuộc tổ chức của repository
+func (r *eventRepository) checkEventInTenant(ctx context.Context, eventID uuid.UUID) error {
+	if r.orgID == nil {
+		return nil
+	}
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.Event{}).
+		Scopes(r.tenantEvents).
+		Where("events.id = ?", eventID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
 func (r *eventRepository) CreateEvent(ctx context.Context, event *entity.Event) error {
+	if r.orgID != nil {
+		if event.OrganizationID != nil && *event.OrganizationID != *r.orgID {
+			return errors.New("sự kiện không thuộc tổ chức")
+		}
+		event.OrganizationID = r.orgID
+	}
 	return r.db.WithContext(ctx).Create(event).Error
 }
 
 func (r *eventRepository) GetEventByID(ctx context.Context, id uuid.UUID) (*entity.Event, error) {
 	var event entity.Event
-	err := r.db.WithContext(ctx).First(&event, "id = ?", id).Error
+	err := r.db.WithContext(ctx).Scopes(r.tenantEvents).First(&event, "events.id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
-	return &event, nil
+	return &event, r.loadOrganization(ctx, &event)
 }
 
 func (r *eventRepository) GetEventBySlug(ctx context.Context, slug string) (*entity.Event, error) {
 	var event entity.Event
-	err := r.db.WithContext(ctx).First(&event, "slug = ?", slug).Error
+	err := r.db.WithContext(ctx).Scopes(r.tenantEvents).First(&event, "events.slug = ?", slug).Error
 	if err != nil {
 		return nil, err
 	}
-	return &event, nil
+	return &event, r.loadOrganization(ctx, &event)
+}
+
+// loadOrganization điền tổ chức sở hữu (tiền tệ, phí, thương hiệu) vào chi tiết event
+func (r *eventRepository) loadOrganization(ctx context.Context, event *entity.Event) error {
+	if event.OrganizationID == nil {
+		return nil
+	}
+	var org entity.Organization
+	if err := r.db.WithContext(ctx).First(&org, "id = ?", *event.OrganizationID).Error; err != nil {
+		return err
+	}
+	event.Organization = &org
+	return nil
 }
 
-func (r *eventRepository) ListEvents(ctx context.Context, limit int, offset int) ([]entity.Event, error) {
+// soldCountExpr là tổng số vé đã bán của một event, dùng cho sort "popularity".
+// Giá trị đổi theo từng đơn nên không làm keyset được: sort này chỉ phân trang bằng offset
+// (service từ chối cursor), trang sau vẫn có thể lặp / sót event khi số vé bán thay đổi.
+const soldCountExpr = "(SELECT COALESCE(SUM(tt.initial_quantity - tt.remaining_quantity), 0) FROM ticket_types tt WHERE tt.event_id = events.id)"
+
+func (r *eventRepository) ListEvents(ctx context.Context, filter entity.EventFilter) ([]entity.Event, int64, error) {
+	// Session mới để Count và Find dùng chung điều kiện mà không dính state của nhau
+	query := r.applyEventFilter(r.db.WithContext(ctx).Model(&entity.Event{}).Scopes(r.tenantEvents), filter).Session(&gorm.Session{})
+
+	// Đếm tổng trước khi phân trang để client biết còn bao nhiêu trang (không tính cursor)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	page := query.Select("events.*, " + soldCountExpr + " AS sold_count")
+	if filter.Cursor != nil {
+		cond, args, err := eventKeyset(filter)
+		if err != nil {
+			return nil, 0, err
+		}
+		page = page.Where(cond, args...)
+	} else {
+		page = page.Offset(filter.Offset)
+	}
+
 	var events []entity.Event
-	err := r.db.WithContext(ctx).Limit(limit).Offset(offset).Find(&events).Error
-	return events, err
+	err := page.Scopes(orderEvents(filter)).
+		Limit(filter.Limit).
+		Find(&events).Error
+	return events, total, err
+}
+
+// applyEventFilter thêm các điều kiện WHERE tương ứng với filter.
+// Tìm kiếm toàn văn dùng cột search_vector (xem scripts/db/init.sql), đã được
+// bỏ dấu bằng f_unaccent nên "my dinh" vẫn khớp "Mỹ Đình".
+func (r *eventRepository) applyEventFilter(query *gorm.DB, filter entity.EventFilter) *gorm.DB {
+	if filter.Query != "" {
+		query = query.Where("events.search_vector @@ plainto_tsquery('simple', f_unaccent(?))", filter.Query)
+	}
+	if filter.Status != "" {
+		query = query.Where("events.status = ?", filter.Status)
+	}
+	if filter.Location != "" {
+		query = query.Where("f_unaccent(events.location) ILIKE f_unaccent(?)", "%"+filter.Location+"%")
+	}
+	if filter.StartFrom != nil {
+		query = query.Where("events.start_time >= ?", *filter.StartFrom)
+	}
+	if filter.StartTo != nil {
+		query = query.Where("events.start_time <= ?", *filter.StartTo)
+	}
+
+	// Khoảng giá: event hợp lệ nếu có ít nhất một loại vé nằm trong khoảng
+	if filter.MinPrice != nil || filter.MaxPrice != nil {
+		sub := r.db.Table("ticket_types tt").Select("1").Where("tt.event_id = events.id")
+		if filter.MinPrice != nil {
+			sub = sub.Where("tt.price >= ?", *filter.MinPrice)
+		}
+		if filter.MaxPrice != nil {
+			sub = sub.Where("tt.price <= ?", *filter.MaxPrice)
+		}
+		query = query.Where("EXISTS (?)", sub)
+	}
+	return query
+}
+
+// eventSortColumn trả về biểu thức cột sắp xếp và chiều giảm dần hay không.
+// id luôn đi cùng chiều với cột chính để so sánh tuple (col, id) được.
+func eventSortColumn(sort entity.EventSort) (string, bool) {
+	switch sort {
+	case entity.EventSortNewest:
+		return "events.created_at", true
+	case entity.EventSortPopularity:
+		return soldCountExpr, true
+	default:
+		return "events.start_time", false
+	}
+}
+
+// orderEvents sắp xếp theo filter.Sort, luôn kèm id để thứ tự ổn định giữa các trang.
+// Khi đi lùi (cursor.Backward) thì đảo chiều, service sẽ đảo lại kết quả.
+func orderEvents(filter entity.EventFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		column, desc := eventSortColumn(filter.Sort)
+		if filter.Cursor != nil && filter.Cursor.Backward {
+			desc = !desc
+		}
+
+		dir := "ASC"
+		if desc {
+			dir = "DESC"
+		}
+		return db.Order(column + " " + dir).Order("events.id " + dir)
+	}
+}
+
+// eventKeyset tạo điều kiện "(col, id) > (v, id)" (hoặc <) từ cursor.
+func eventKeyset(filter entity.EventFilter) (string, []interface{}, error) {
+	cur := filter.Cursor
+	column, desc := eventSortColumn(filter.Sort)
+	if cur.Backward {
+		desc = !desc
+	}
+
+	// Cột keyset luôn là thời gian: popularity không cấp cursor (xem soldCountExpr)
+	value, err := time.Parse(time.RFC3339Nano, cur.Value)
+	if err != nil {
+		return "", nil, err
+	}
+
+	op := ">"
+	if desc {
+		op = "<"
+	}
+	return "(" + column + ", events.id) " + op + " (?, ?)", []interface{}{value, cur.ID}, nil
 }
 
 func (r *eventRepository) UpdateEvent(ctx context.Context, event *entity.Event) error {
+	// Không cho đổi event sang tổ chức khác hoặc sửa event ngoài tổ chức
+	if r.orgID != nil && (event.OrganizationID == nil || *event.OrganizationID != *r.orgID) {
+		return gorm.ErrRecordNotFound
+	}
+	if err := r.checkEventInTenant(ctx, event.ID); err != nil {
+		return err
+	}
 	return r.db.WithContext(ctx).Save(event).Error
 }
 
 func (r *eventRepository) DeleteEvent(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&entity.Event{}, "id = ?", id).Error
+	return r.db.WithContext(ctx).Scopes(r.tenantEvents).Delete(&entity.Event{}, "events.id = ?", id).Error
 }
 
 func (r *eventRepository) CreateTicketType(ctx context.Context, ticketType *entity.TicketType) error {
+	if err := r.checkEventInTenant(ctx, ticketType.EventID); err != nil {
+		return err
+	}
 	return r.db.WithContext(ctx).Create(ticketType).Error
 }
 
 func (r *eventRepository) CreateTicketTypes(ctx context.Context, ticketTypes []entity.TicketType) error {
+	checked := make(map[uuid.UUID]bool)
+	for _, tt := range ticketTypes {
+		if checked[tt.EventID] {
+			continue
+		}
+		if err := r.checkEventInTenant(ctx, tt.EventID); err != nil {
+			return err
+		}
+		checked[tt.EventID] = true
+	}
 	return r.db.WithContext(ctx).Create(ticketTypes).Error
 }
+
+func (r *eventRepository) ListTicketTypesByEvent(ctx context.Context, eventID uuid.UUID) ([]entity.TicketType, error) {
+	var ticketTypes []entity.TicketType
+	err := r.db.WithContext(ctx).
+		Scopes(r.tenantEventColumn("ticket_types.event_id")).
+		Where("event_id = ?", eventID).Order("price DESC").Find(&ticketTypes).Error
+	return ticketTypes, err
+}
+
+func (r *eventRepository) GetTicketTypesByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.TicketType, error) {
+	var ticketTypes []entity.TicketType
+	err := r.db.WithContext(ctx).
+		Scopes(r.tenantEventColumn("ticket_types.event_id")).
+		Where("id IN ?", ids).Find(&ticketTypes).Error
+	return ticketTypes, err
+}
+
+// orderItemOfEvent khớp order_items (alias oi) thuộc event: vé lẻ / vé bán lại theo loại vé,
+// add-on của event, hoặc gói combo có loại vé của event
+const orderItemOfEvent = `(oi.ticket_type_id IN (SELECT id FROM ticket_types WHERE event_id = @event)
+	OR oi.add_on_id IN (SELECT id FROM add_ons WHERE event_id = @event)
+	OR oi.bundle_id IN (SELECT bi.bundle_id FROM bundle_items bi JOIN ticket_types tt ON tt.id = bi.ticket_type_id WHERE tt.event_id = @event))`
+
+func (r *eventRepository) ListOrdersByEvent(ctx context.Context, eventID uuid.UUID, cursor *entity.PageCursor, limit int) ([]entity.Order, error) {
+	if err := r.checkEventInTenant(ctx, eventID); err != nil {
+		return nil, err
+	}
+
+	// Đơn có thể gồm item của event khác (kể cả của tổ chức khác), nên chỉ nạp item và vé của event này
+	event := sql.Named("event", eventID)
+	query := r.db.WithContext(ctx).
+		Preload("Items", "order_items.id IN (SELECT oi.id FROM order_items oi WHERE "+orderItemOfEvent+")", event).
+		Preload("Tickets", "tickets.ticket_type_id IN (SELECT id FROM ticket_types WHERE event_id = ?)", eventID).
+		Where("EXISTS (SELECT 1 FROM order_items oi WHERE oi.order_id = orders.id AND "+orderItemOfEvent+")", event)
+
+	dir := "DESC"
+	if cursor != nil {
+		createdAt, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		if cursor.Backward {
+			query = query.Where("(orders.created_at, orders.id) > (?, ?)", createdAt, cursor.ID)
+			dir = "ASC"
+		} else {
+			query = query.Where("(orders.created_at, orders.id) < (?, ?)", createdAt, cursor.ID)
+		}
+	}
+
+	var orders []entity.Order
+	err := query.Order("orders.created_at " + dir).Order("orders.id " + dir).
+		Limit(limit).
+		Find(&orders).Error
+	return orders, err
+}
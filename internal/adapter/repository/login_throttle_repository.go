@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+type loginThrottleRepository struct {
+	db *sql.DB
+}
+
+func NewLoginThrottleRepository(db *sql.DB) port.LoginThrottleRepositoryPort {
+	return &loginThrottleRepository{db: db}
+}
+
+func (r *loginThrottleRepository) GetThrottle(ctx context.Context, kind entity.LoginThrottleKind, key string) (*entity.LoginThrottle, error) {
+	throttle := &entity.LoginThrottle{}
+	query := `SELECT kind, key, failures, last_failure_at, locked_until FROM login_throttles WHERE kind = $1 AND key = $2`
+
+	err := r.db.QueryRowContext(ctx, query, kind, key).Scan(
+		&throttle.Kind, &throttle.Key, &throttle.Failures, &throttle.LastFailureAt, &throttle.LockedUntil,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return throttle, nil
+}
+
+func (r *loginThrottleRepository) RecordFailure(ctx context.Context, kind entity.LoginThrottleKind, key string, windowStart time.Time) (*entity.LoginThrottle, error) {
+	throttle := &entity.LoginThrottle{}
+	// Upsert nguyên tử để các request sai đồng thời đều được đếm
+	query := `INSERT INTO login_throttles (kind, key, failures, last_failure_at) VALUES ($1, $2, 1, NOW())
+              ON CONFLICT (kind, key) DO UPDATE SET
+                  failures = CASE WHEN login_throttles.last_failure_at < $3 THEN 1 ELSE login_throttles.failures + 1 END,
+                  last_failure_at = NOW()
+              RETURNING kind, key, failures, last_failure_at, locked_until`
+
+	err := r.db.QueryRowContext(ctx, query, kind, key, windowStart).Scan(
+		&throttle.Kind, &throttle.Key, &throttle.Failures, &throttle.LastFailureAt, &throttle.LockedUntil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return throttle, nil
+}
+
+func (r *loginThrottleRepository) SetLockedUntil(ctx context.Context, kind entity.LoginThrottleKind, key string, until time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE login_throttles SET locked_until = $3 WHERE kind = $1 AND key = $2`, kind, key, until)
+	return err
+}
+
+func (r *loginThrottleRepository) ResetThrottle(ctx context.Context, kind entity.LoginThrottleKind, key string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM login_throttles WHERE kind = $1 AND key = $2`, kind, key)
+	return err
+}
+
+func (r *loginThrottleRepository) ListLocked(ctx context.Context, now time.Time) ([]entity.LoginThrottle, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT kind, key, failures, last_failure_at, locked_until FROM login_throttles
+         WHERE locked_until > $1 ORDER BY locked_until DESC`, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var throttles []entity.LoginThrottle
+	for rows.Next() {
+		var throttle entity.LoginThrottle
+		if err := rows.Scan(&throttle.Kind, &throttle.Key, &throttle.Failures, &throttle.LastFailureAt, &throttle.LockedUntil); err != nil {
+			return nil, err
+		}
+		throttles = append(throttles, throttle)
+	}
+	return throttles, rows.Err()
+}
+
+// PurgeThrottles xóa bộ đếm không còn tác dụng: lần sai cuối và thời hạn khóa đều trước before.
+func (r *loginThrottleRepository) PurgeThrottles(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM login_throttles WHERE last_failure_at < $1 AND (locked_until IS NULL OR locked_until < $1)`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+type mfaRepository struct {
+	db *sql.DB
+}
+
+func NewMFARepository(db *sql.DB) port.MFARepositoryPort {
+	return &mfaRepository{db: db}
+}
+
+func (r *mfaRepository) GetTOTP(ctx context.Context, userID uuid.UUID) (*entity.UserTOTP, error) {
+	totp := &entity.UserTOTP{}
+	query := `SELECT user_id, secret, enabled_at, last_used_step, created_at FROM user_totp WHERE user_id = $1`
+
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&totp.UserID, &totp.Secret, &totp.EnabledAt, &totp.LastUsedStep, &totp.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return totp, nil
+}
+
+func (r *mfaRepository) SaveTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) (bool, error) {
+	// Đăng ký lại khi đang dở thì thay secret; đã bật rồi thì không đụng tới
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO user_totp (user_id, secret, last_used_step, created_at) VALUES ($1, $2, 0, NOW())
+         ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, last_used_step = 0, created_at = NOW()
+         WHERE user_totp.enabled_at IS NULL`,
+		userID, secret,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (r *mfaRepository) EnableTOTP(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE user_totp SET enabled_at = NOW(), last_used_step = $2 WHERE user_id = $1 AND enabled_at IS NULL`,
+		userID, step,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = errors.New("2FA đã được bật hoặc chưa bắt đầu đăng ký")
+		}
+		return err
+	}
+	if err := replaceRecoveryCodes(ctx, tx, userID, recoveryCodeHashes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *mfaRepository) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE user_totp SET last_used_step = $2 WHERE user_id = $1 AND enabled_at IS NOT NULL AND last_used_step < $2`,
+		userID, step,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (r *mfaRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE mfa_recovery_codes SET used_at = NOW() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`,
+		userID, codeHash,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (r *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := replaceRecoveryCodes(ctx, tx, userID, codeHashes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *mfaRepository) DeleteTOTP(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// replaceRecoveryCodes xóa mã khôi phục cũ (kể cả mã chưa dùng) và lưu bộ mã mới.
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID uuid.UUID, codeHashes []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+	for _, hash := range codeHashes {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO mfa_recovery_codes (id, user_id, code_hash) VALUES ($1, $2, $3)`,
+			uuid.New(), userID, hash,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
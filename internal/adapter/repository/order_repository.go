@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourname/ticketing-system/internal/core/entity"
@@ -48,9 +49,408 @@ func (r *OrderRepository) DecreaseStock(ctx context.Context, tx *gorm.DB, id uui
 		Error
 }
 
+// IncreaseStock cộng trả remaining_quantity khi đơn bị hủy/hết hạn hoặc hết hạn giữ vé.
+func (r *OrderRepository) IncreaseStock(ctx context.Context, tx *gorm.DB, id uuid.UUID, quantity int) error {
+	return tx.WithContext(ctx).
+		Model(&entity.TicketType{}).
+		Where("id = ?", id).
+		Update("remaining_quantity", gorm.Expr("remaining_quantity + ?", quantity)).
+		Error
+}
+
+// TopUpStock mở bán thêm vé: tăng cả initial_quantity lẫn remaining_quantity.
+func (r *OrderRepository) TopUpStock(ctx context.Context, tx *gorm.DB, id uuid.UUID, quantity int) error {
+	return tx.WithContext(ctx).
+		Model(&entity.TicketType{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"initial_quantity":   gorm.Expr("initial_quantity + ?", quantity),
+			"remaining_quantity": gorm.Expr("remaining_quantity + ?", quantity),
+		}).Error
+}
+
+// GetEventForUpdate khóa event (FOR UPDATE) để các lần mở bán thêm vé của cùng event chạy lần lượt,
+// tránh hai loại vé cùng lúc đều lọt qua kiểm tra sức chứa. Gọi sau khi đã khóa loại vé.
+func (r *OrderRepository) GetEventForUpdate(ctx context.Context, tx *gorm.DB, id uuid.UUID) (*entity.Event, error) {
+	var event entity.Event
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "venue_id").
+		First(&event, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// GetVenueWithSections lấy venue kèm các khu trong transaction.
+func (r *OrderRepository) GetVenueWithSections(ctx context.Context, tx *gorm.DB, id uuid.UUID) (*entity.Venue, error) {
+	var venue entity.Venue
+	if err := tx.WithContext(ctx).Preload("Sections").First(&venue, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &venue, nil
+}
+
+// ListSessionTicketTypes lấy các loại vé dùng chung sức chứa venue: cùng event và cùng suất diễn
+// (sessionID nil = loại vé của event không chia suất).
+func (r *OrderRepository) ListSessionTicketTypes(ctx context.Context, tx *gorm.DB, eventID uuid.UUID, sessionID *uuid.UUID) ([]entity.TicketType, error) {
+	query := tx.WithContext(ctx).Where("event_id = ?", eventID)
+	if sessionID != nil {
+		query = query.Where("session_id = ?", *sessionID)
+	} else {
+		query = query.Where("session_id IS NULL")
+	}
+
+	var ticketTypes []entity.TicketType
+	if err := query.Find(&ticketTypes).Error; err != nil {
+		return nil, err
+	}
+	return ticketTypes, nil
+}
+
+// GetPoolForUpdate khóa pool sức chứa dùng chung (FOR UPDATE), gọi sau khi đã khóa loại vé.
+// Nhiều item cùng pool trong một đơn thì khóa lại lần nữa cũng không sao (cùng transaction).
+func (r *OrderRepository) GetPoolForUpdate(ctx context.Context, tx *gorm.DB, id uuid.UUID) (*entity.CapacityPool, error) {
+	var pool entity.CapacityPool
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&pool, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &pool, nil
+}
+
+// DecreasePoolStock trừ remaining_quantity của pool, giống DecreaseStock của loại vé.
+func (r *OrderRepository) DecreasePoolStock(ctx context.Context, tx *gorm.DB, id uuid.UUID, quantity int) error {
+	return tx.WithContext(ctx).
+		Model(&entity.CapacityPool{}).
+		Where("id = ?", id).
+		Update("remaining_quantity", gorm.Expr("remaining_quantity - ?", quantity)).
+		Error
+}
+
+// IncreasePoolStock cộng trả remaining_quantity của pool.
+func (r *OrderRepository) IncreasePoolStock(ctx context.Context, tx *gorm.DB, id uuid.UUID, quantity int) error {
+	return tx.WithContext(ctx).
+		Model(&entity.CapacityPool{}).
+		Where("id = ?", id).
+		Update("remaining_quantity", gorm.Expr("remaining_quantity + ?", quantity)).
+		Error
+}
+
+// GetBundleForUpdate khóa gói combo (FOR UPDATE) kèm danh sách loại vé thành phần.
+func (r *OrderRepository) GetBundleForUpdate(ctx context.Context, tx *gorm.DB, id uuid.UUID) (*entity.Bundle, error) {
+	var bundle entity.Bundle
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&bundle, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.WithContext(ctx).
+		Where("bundle_id = ?", bundle.ID).
+		Find(&bundle.Items).Error; err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+// DecreaseBundleStock trừ remaining_quantity của bundle, giống DecreaseStock của loại vé.
+func (r *OrderRepository) DecreaseBundleStock(ctx context.Context, tx *gorm.DB, id uuid.UUID, quantity int) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Bundle{}).
+		Where("id = ?", id).
+		Update("remaining_quantity", gorm.Expr("remaining_quantity - ?", quantity)).
+		Error
+}
+
+// IncreaseBundleStock cộng trả remaining_quantity của bundle.
+func (r *OrderRepository) IncreaseBundleStock(ctx context.Context, tx *gorm.DB, id uuid.UUID, quantity int) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Bundle{}).
+		Where("id = ?", id).
+		Update("remaining_quantity", gorm.Expr("remaining_quantity + ?", quantity)).
+		Error
+}
+
+// GetAddOnForUpdate khóa add-on (FOR UPDATE) kèm điều kiện loại vé.
+func (r *OrderRepository) GetAddOnForUpdate(ctx context.Context, tx *gorm.DB, id uuid.UUID) (*entity.AddOn, error) {
+	var addOn entity.AddOn
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&addOn, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.WithContext(ctx).
+		Where("add_on_id = ?", addOn.ID).
+		Find(&addOn.Restrictions).Error; err != nil {
+		return nil, err
+	}
+	return &addOn, nil
+}
+
+// DecreaseAddOnStock trừ remaining_quantity của add-on, giống DecreaseStock của loại vé.
+func (r *OrderRepository) DecreaseAddOnStock(ctx context.Context, tx *gorm.DB, id uuid.UUID, quantity int) error {
+	return tx.WithContext(ctx).
+		Model(&entity.AddOn{}).
+		Where("id = ?", id).
+		Update("remaining_quantity", gorm.Expr("remaining_quantity - ?", quantity)).
+		Error
+}
+
+// IncreaseAddOnStock cộng trả remaining_quantity của add-on.
+func (r *OrderRepository) IncreaseAddOnStock(ctx context.Context, tx *gorm.DB, id uuid.UUID, quantity int) error {
+	return tx.WithContext(ctx).
+		Model(&entity.AddOn{}).
+		Where("id = ?", id).
+		Update("remaining_quantity", gorm.Expr("remaining_quantity + ?", quantity)).
+		Error
+}
+
+// UserHasTicketTypes kiểm tra user đã có vé (đơn chưa hủy) thuộc một trong các loại vé ticketTypeIDs chưa.
+func (r *OrderRepository) UserHasTicketTypes(ctx context.Context, tx *gorm.DB, userID uuid.UUID, ticketTypeIDs []uuid.UUID) (bool, error) {
+	var count int64
+	err := tx.WithContext(ctx).
+		Model(&entity.Ticket{}).
+		Joins("JOIN orders ON orders.id = tickets.order_id").
+		Where("orders.user_id = ? AND orders.status <> ?", userID, entity.OrderStatusCancelled).
+		Where("tickets.ticket_type_id IN ?", ticketTypeIDs).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetSeatsForUpdate khóa (FOR UPDATE) các ghế được chọn, theo thứ tự id để hai đơn
+// cùng chọn mấy ghế giống nhau không bị deadlock. Gọi trong transaction (tx).
+func (r *OrderRepository) GetSeatsForUpdate(ctx context.Context, tx *gorm.DB, ids []uuid.UUID) ([]entity.Seat, error) {
+	var seats []entity.Seat
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).
+		Order("id").
+		Find(&seats).Error
+	return seats, err
+}
+
+// MarkSeatsSold gắn ghế vào OrderItem và chuyển sang SOLD.
+// Phải gọi sau CreateOrder (khóa ngoại order_item_id) và trong cùng transaction.
+func (r *OrderRepository) MarkSeatsSold(ctx context.Context, tx *gorm.DB, ids []uuid.UUID, orderItemID uuid.UUID) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Seat{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{
+			"status":        entity.SeatStatusSold,
+			"order_item_id": orderItemID,
+		}).Error
+}
+
+// ReleaseSeats trả ghế của các order item về AVAILABLE (đơn bị hủy/hết hạn).
+func (r *OrderRepository) ReleaseSeats(ctx context.Context, tx *gorm.DB, orderItemIDs []uuid.UUID) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Seat{}).
+		Where("order_item_id IN ?", orderItemIDs).
+		Updates(map[string]interface{}{
+			"status":        entity.SeatStatusAvailable,
+			"order_item_id": nil,
+		}).Error
+}
+
 // CreateOrder tạo mới một đơn hàng kèm theo các OrderItem bên trong.
 // Gọi trong transaction để đảm bảo: hoặc tạo hết, hoặc không tạo cái nào cả (atomic).
 func (r *OrderRepository) CreateOrder(ctx context.Context, tx *gorm.DB, order *entity.Order) error {
 	// GORM tự động tạo cả order và các order_items liên quan (nếu có association)
 	return tx.WithContext(ctx).Create(order).Error
 }
+
+// CreateTickets lưu các vé đã phát hành cho đơn. Gọi sau CreateOrder, trong cùng transaction.
+func (r *OrderRepository) CreateTickets(ctx context.Context, tx *gorm.DB, tickets []entity.Ticket) error {
+	if len(tickets) == 0 {
+		return nil
+	}
+	return tx.WithContext(ctx).Create(&tickets).Error
+}
+
+// GetOrderForUpdate khóa đơn (FOR UPDATE) kèm các item, dùng khi hủy đơn hoặc xác nhận thanh toán.
+func (r *OrderRepository) GetOrderForUpdate(ctx context.Context, tx *gorm.DB, id uuid.UUID) (*entity.Order, error) {
+	var order entity.Order
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&order, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.WithContext(ctx).
+		Where("order_id = ?", order.ID).
+		Find(&order.Items).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// UpdateOrderStatus đổi trạng thái đơn trong transaction.
+func (r *OrderRepository) UpdateOrderStatus(ctx context.Context, tx *gorm.DB, id uuid.UUID, status entity.OrderStatus) error {
+	return tx.WithContext(ctx).
+		Model(&entity.Order{}).
+		Where("id = ?", id).
+		Update("status", status).Error
+}
+
+// GetTicketsByOrderForUpdate khóa các vé đã phát hành của đơn (khi hoàn tiền), để vé không bị soát
+// hay chuyển nhượng giữa chừng.
+func (r *OrderRepository) GetTicketsByOrderForUpdate(ctx context.Context, tx *gorm.DB, orderID uuid.UUID) ([]entity.Ticket, error) {
+	var tickets []entity.Ticket
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ?", orderID).
+		Order("id").
+		Find(&tickets).Error
+	return tickets, err
+}
+
+// OrderTicketsInResale kiểm tra vé của đơn có đang rao bán lại (hoặc chờ người mua thanh toán)
+// hay đang chờ chuyển nhượng không.
+func (r *OrderRepository) OrderTicketsInResale(ctx context.Context, tx *gorm.DB, orderID uuid.UUID) (bool, error) {
+	var count int64
+	err := tx.WithContext(ctx).
+		Model(&entity.Ticket{}).
+		Where("order_id = ?", orderID).
+		Where(`EXISTS (SELECT 1 FROM resale_listings rl WHERE rl.ticket_id = tickets.id AND rl.status IN ?)
+		    OR EXISTS (SELECT 1 FROM ticket_transfers tr WHERE tr.ticket_id = tickets.id AND tr.status = ?)`,
+			[]entity.ResaleListingStatus{entity.ResaleListingStatusActive, entity.ResaleListingStatusReserved},
+			entity.TicketTransferStatusPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteTicketsByOrder thu hồi vé đã phát hành của đơn bị hủy / hoàn tiền.
+func (r *OrderRepository) DeleteTicketsByOrder(ctx context.Context, tx *gorm.DB, orderID uuid.UUID) error {
+	return tx.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Delete(&entity.Ticket{}).Error
+}
+
+// GetAttendeeQuestions trả về bộ câu hỏi người tham dự của event.
+func (r *OrderRepository) GetAttendeeQuestions(ctx context.Context, tx *gorm.DB, eventID uuid.UUID) ([]entity.AttendeeQuestion, error) {
+	var event entity.Event
+	if err := tx.WithContext(ctx).Select("attendee_questions").First(&event, "id = ?", eventID).Error; err != nil {
+		return nil, err
+	}
+	return event.AttendeeQuestions, nil
+}
+
+// GetPresaleCodeForUpdate khóa mã presale của event (FOR UPDATE) để kiểm tra và tiêu lượt dùng an toàn.
+func (r *OrderRepository) GetPresaleCodeForUpdate(ctx context.Context, tx *gorm.DB, eventID uuid.UUID, code string) (*entity.PresaleCode, error) {
+	var presale entity.PresaleCode
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&presale, "event_id = ? AND code = ?", eventID, code).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.WithContext(ctx).
+		Where("presale_code_id = ?", presale.ID).
+		Find(&presale.TicketTypes).Error; err != nil {
+		return nil, err
+	}
+	return &presale, nil
+}
+
+// HasRedeemedPresaleCode kiểm tra user đã dùng mã presale cho đơn nào chưa (đơn hủy đã được trả lượt).
+func (r *OrderRepository) HasRedeemedPresaleCode(ctx context.Context, tx *gorm.DB, codeID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := tx.WithContext(ctx).Model(&entity.PresaleRedemption{}).
+		Where("presale_code_id = ? AND user_id = ?", codeID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// RedeemPresaleCode ghi nhận lượt dùng mã cho đơn và tăng used_count.
+func (r *OrderRepository) RedeemPresaleCode(ctx context.Context, tx *gorm.DB, codeID, userID, orderID uuid.UUID) error {
+	redemption := &entity.PresaleRedemption{
+		ID:            uuid.New(),
+		PresaleCodeID: codeID,
+		UserID:        userID,
+		OrderID:       orderID,
+		CreatedAt:     time.Now(),
+	}
+	if err := tx.WithContext(ctx).Create(redemption).Error; err != nil {
+		return err
+	}
+	return tx.WithContext(ctx).Model(&entity.PresaleCode{}).
+		Where("id = ?", codeID).
+		Update("used_count", gorm.Expr("used_count + 1")).Error
+}
+
+// ReleasePresaleRedemptions trả lại lượt dùng mã presale của đơn bị hủy.
+func (r *OrderRepository) ReleasePresaleRedemptions(ctx context.Context, tx *gorm.DB, orderID uuid.UUID) error {
+	if err := tx.WithContext(ctx).Model(&entity.PresaleCode{}).
+		Where("id IN (?)", tx.Model(&entity.PresaleRedemption{}).Select("presale_code_id").Where("order_id = ?", orderID)).
+		Update("used_count", gorm.Expr("used_count - 1")).Error; err != nil {
+		return err
+	}
+	return tx.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Delete(&entity.PresaleRedemption{}).Error
+}
+
+// CashierSales gộp đơn bán tại quầy (chưa hủy) trong [from, to) theo thu ngân và phương thức thanh toán.
+func (r *OrderRepository) CashierSales(ctx context.Context, from, to time.Time, cashierID *uuid.UUID) ([]entity.CashierSales, error) {
+	query := r.db.WithContext(ctx).
+		Table("orders").
+		Select(`orders.cashier_id, orders.payment_method, COUNT(*) AS orders,
+			COALESCE(SUM(issued.tickets), 0) AS tickets, SUM(orders.total_amount) AS amount`).
+		Joins("LEFT JOIN (SELECT order_id, COUNT(*) AS tickets FROM tickets GROUP BY order_id) issued ON issued.order_id = orders.id").
+		Where("orders.channel = ? AND orders.status <> ?", entity.OrderChannelBoxOffice, entity.OrderStatusCancelled).
+		Where("orders.created_at >= ? AND orders.created_at < ?", from, to)
+	if cashierID != nil {
+		query = query.Where("orders.cashier_id = ?", *cashierID)
+	}
+
+	var sales []entity.CashierSales
+	err := query.
+		Group("orders.cashier_id, orders.payment_method").
+		Order("orders.cashier_id").Order("orders.payment_method").
+		Scan(&sales).Error
+	return sales, err
+}
+
+// ListExpiredPendingOrders trả về id các đơn PENDING quá hạn thanh toán: đơn có payment_deadline
+// thì xét theo deadline, còn lại là đơn tạo trước thời điểm before.
+func (r *OrderRepository) ListExpiredPendingOrders(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&entity.Order{}).
+		Where("status = ?", entity.OrderStatusPending).
+		Where("(payment_deadline IS NULL AND created_at < ?) OR payment_deadline < ?", before, time.Now()).
+		Order("created_at").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// ListOrdersByUser lấy đơn hàng của user, mới nhất trước, phân trang theo keyset (created_at, id).
+// cursor = nil là trang đầu. Không cần transaction vì chỉ đọc.
+func (r *OrderRepository) ListOrdersByUser(ctx context.Context, userID uuid.UUID, cursor *entity.PageCursor, limit int) ([]entity.Order, error) {
+	query := r.db.WithContext(ctx).
+		Preload("Items").
+		Preload("Tickets", "owner_id = ?", userID). // vé đã chuyển nhượng / bán lại không còn hiện ở đơn gốc
+		Where("user_id = ?", userID)
+
+	dir := "DESC"
+	if cursor != nil {
+		createdAt, err := time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		if cursor.Backward {
+			// Đi lùi về trang mới hơn: lấy theo chiều tăng rồi service đảo lại
+			query = query.Where("(created_at, id) > (?, ?)", createdAt, cursor.ID)
+			dir = "ASC"
+		} else {
+			query = query.Where("(created_at, id) < (?, ?)", createdAt, cursor.ID)
+		}
+	}
+
+	var orders []entity.Order
+	err := query.Order("created_at " + dir).Order("id " + dir).
+		Limit(limit).
+		Find(&orders).Error
+	return orders, err
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+	"gorm.io/gorm"
+)
+
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) port.OrganizationRepositoryPort {
+	return &organizationRepository{db: db}
+}
+
+func (r *organizationRepository) CreateOrganization(ctx context.Context, org *entity.Organization) error {
+	return r.db.WithContext(ctx).Create(org).Error
+}
+
+func (r *organizationRepository) GetOrganizationByID(ctx context.Context, id uuid.UUID) (*entity.Organization, error) {
+	var org entity.Organization
+	err := r.db.WithContext(ctx).First(&org, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (r *organizationRepository) GetOrganizationBySlug(ctx context.Context, slug string) (*entity.Organization, error) {
+	var org entity.Organization
+	err := r.db.WithContext(ctx).First(&org, "slug = ?", slug).Error
+	if err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
+
+func (r *organizationRepository) ListOrganizations(ctx context.Context) ([]entity.Organization, error) {
+	var orgs []entity.Organization
+	err := r.db.WithContext(ctx).Order("name").Find(&orgs).Error
+	return orgs, err
+}
+
+func (r *organizationRepository) ListOrganizationsByMember(ctx context.Context, userID uuid.UUID) ([]entity.Organization, error) {
+	var orgs []entity.Organization
+	err := r.db.WithContext(ctx).
+		Where("id IN (SELECT scope_id FROM role_assignments WHERE user_id = ? AND scope_type = ?)", userID, entity.ScopeOrganization).
+		Order("name").
+		Find(&orgs).Error
+	return orgs, err
+}
+
+func (r *organizationRepository) UpdateOrganization(ctx context.Context, org *entity.Organization) error {
+	return r.db.WithContext(ctx).Save(org).Error
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type poolRepository struct {
+	db *gorm.DB
+}
+
+func NewPoolRepository(db *gorm.DB) port.PoolRepositoryPort {
+	return &poolRepository{db: db}
+}
+
+func (r *poolRepository) CreatePool(ctx context.Context, pool *entity.CapacityPool, ticketTypeIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Khóa các loại vé để số đã bán không đổi trong lúc tính remaining của pool
+		var ticketTypes []entity.TicketType
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND event_id = ?", ticketTypeIDs, pool.EventID).
+			Order("id").
+			Find(&ticketTypes).Error; err != nil {
+			return err
+		}
+		if len(ticketTypes) != len(ticketTypeIDs) {
+			return errors.New("có loại vé không thuộc sự kiện")
+		}
+
+		sold := 0
+		for _, tt := range ticketTypes {
+			if tt.PoolID != nil {
+				return errors.New("loại vé " + tt.Name + " đã thuộc một pool khác")
+			}
+			sold += tt.InitialQuantity - tt.RemainingQuantity
+		}
+		if sold > pool.Capacity {
+			return errors.New("sức chứa pool nhỏ hơn số vé đã bán")
+		}
+		pool.RemainingQuantity = pool.Capacity - sold
+
+		if err := tx.Create(pool).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.TicketType{}).
+			Where("id IN ?", ticketTypeIDs).
+			Update("pool_id", pool.ID).Error
+	})
+}
+
+func (r *poolRepository) ListPoolsByEvent(ctx context.Context, eventID uuid.UUID) ([]entity.CapacityPool, error) {
+	var pools []entity.CapacityPool
+	err := r.db.WithContext(ctx).Where("event_id = ?", eventID).Order("name").Find(&pools).Error
+	return pools, err
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+	"gorm.io/gorm"
+)
+
+type presaleRepository struct {
+	db *gorm.DB
+}
+
+func NewPresaleRepository(db *gorm.DB) port.PresaleRepositoryPort {
+	return &presaleRepository{db: db}
+}
+
+func (r *presaleRepository) CreateCode(ctx context.Context, code *entity.PresaleCode) error {
+	// GORM tạo luôn presale_code_ticket_types (association)
+	return r.db.WithContext(ctx).Create(code).Error
+}
+
+func (r *presaleRepository) ListCodesByEvent(ctx context.Context, eventID uuid.UUID) ([]entity.PresaleCode, error) {
+	var codes []entity.PresaleCode
+	err := r.db.WithContext(ctx).Preload("TicketTypes").
+		Where("event_id = ?", eventID).
+		Order("created_at").
+		Find(&codes).Error
+	return codes, err
+}
+
+func (r *presaleRepository) GetCodeByValue(ctx context.Context, eventID uuid.UUID, code string) (*entity.PresaleCode, error) {
+	var presale entity.PresaleCode
+	err := r.db.WithContext(ctx).Preload("TicketTypes").
+		Where("event_id = ? AND code = ?", eventID, code).
+		First(&presale).Error
+	if err != nil {
+		return nil, err
+	}
+	return &presale, nil
+}
+
+func (r *presaleRepository) HasRedeemed(ctx context.Context, codeID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.PresaleRedemption{}).
+		Where("presale_code_id = ? AND user_id = ?", codeID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+// ResaleRepository nhận tx như OrderRepository vì mua vé bán lại đi chung transaction với checkout.
+type ResaleRepository struct {
+	db *gorm.DB
+}
+
+func NewResaleRepository(db *gorm.DB) *ResaleRepository {
+	return &ResaleRepository{db: db}
+}
+
+func (r *ResaleRepository) CreateListing(ctx context.Context, tx *gorm.DB, listing *entity.ResaleListing) error {
+	return tx.WithContext(ctx).Create(listing).Error
+}
+
+func (r *ResaleRepository) GetListing(ctx context.Context, id uuid.UUID) (*entity.ResaleListing, error) {
+	var listing entity.ResaleListing
+	if err := r.db.WithContext(ctx).First(&listing, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &listing, nil
+}
+
+// GetListingForUpdate khóa tin bán lại để một vé không bao giờ bán được cho hai người.
+func (r *ResaleRepository) GetListingForUpdate(ctx context.Context, tx *gorm.DB, id uuid.UUID) (*entity.ResaleListing, error) {
+	var listing entity.ResaleListing
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&listing, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &listing, nil
+}
+
+func (r *ResaleRepository) SaveListing(ctx context.Context, tx *gorm.DB, listing *entity.ResaleListing) error {
+	return tx.WithContext(ctx).Save(listing).Error
+}
+
+func (r *ResaleRepository) ListActiveByEvent(ctx context.Context, eventID uuid.UUID) ([]entity.ResaleListing, error) {
+	var listings []entity.ResaleListing
+	err := r.db.WithContext(ctx).
+		Where("event_id = ? AND status = ?", eventID, entity.ResaleListingStatusActive).
+		Order("price").Order("created_at").
+		Find(&listings).Error
+	return listings, err
+}
+
+func (r *ResaleRepository) ListBySeller(ctx context.Context, sellerID uuid.UUID) ([]entity.ResaleListing, error) {
+	var listings []entity.ResaleListing
+	err := r.db.WithContext(ctx).
+		Where("seller_id = ?", sellerID).
+		Order("created_at DESC").
+		Find(&listings).Error
+	return listings, err
+}
+
+// ListUnpaidPayouts trả về id các tin đã bán mà người bán chưa được chi tiền (lần chi trước lỗi).
+func (r *ResaleRepository) ListUnpaidPayouts(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&entity.ResaleListing{}).
+		Where("status = ? AND paid_out_at IS NULL", entity.ResaleListingStatusSold).
+		Order("sold_at").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// GetOrderItem lấy item gốc của vé để biết giá mua ban đầu.
+func (r *ResaleRepository) GetOrderItem(ctx context.Context, tx *gorm.DB, id uuid.UUID) (*entity.OrderItem, error) {
+	var item entity.OrderItem
+	if err := tx.WithContext(ctx).First(&item, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// ResaleCap trả về event của loại vé và trần giá bán lại (% giá gốc) của event đó.
+func (r *ResaleRepository) ResaleCap(ctx context.Context, tx *gorm.DB, ticketTypeID uuid.UUID) (uuid.UUID, int, error) {
+	var (
+		eventID    uuid.UUID
+		capPercent int
+	)
+	err := tx.WithContext(ctx).
+		Table("ticket_types").
+		Select("events.id, events.resale_cap_percent").
+		Joins("JOIN events ON events.id = ticket_types.event_id").
+		Where("ticket_types.id = ?", ticketTypeID).
+		Row().Scan(&eventID, &capPercent)
+	return eventID, capPercent, err
+}
+
+func (r *ResaleRepository) SetEventResaleCap(ctx context.Context, eventID uuid.UUID, capPercent int) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.Event{}).
+		Where("id = ?", eventID).
+		Update("resale_cap_percent", capPercent)
+	return result.RowsAffected, result.Error
+}
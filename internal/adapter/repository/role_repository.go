@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+type roleRepository struct {
+	db *sql.DB
+}
+
+func NewRoleRepository(db *sql.DB) port.RoleRepositoryPort {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) CreateAssignment(ctx context.Context, assignment *entity.RoleAssignment) error {
+	query := `INSERT INTO role_assignments (id, user_id, role, scope_type, scope_id, granted_by, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6, $7)
+              ON CONFLICT DO NOTHING`
+
+	result, err := r.db.ExecContext(ctx, query,
+		assignment.ID, assignment.UserID, assignment.Role, assignment.ScopeType, assignment.ScopeID,
+		assignment.GrantedBy, assignment.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return errors.New("user đã có vai trò này trong phạm vi đã chọn")
+	}
+	return nil
+}
+
+func (r *roleRepository) DeleteAssignment(ctx context.Context, id uuid.UUID) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM role_assignments WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (r *roleRepository) ListAssignmentsByUser(ctx context.Context, userID uuid.UUID) ([]entity.RoleAssignment, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, user_id, role, scope_type, scope_id, granted_by, created_at
+         FROM role_assignments WHERE user_id = $1 ORDER BY created_at`, userID)
+	if err != nil {
+		return nil, err
+	}
+	return scanRoleAssignments(rows)
+}
+
+func (r *roleRepository) ListScopeAssignments(ctx context.Context, scopeType entity.ScopeType, scopeID uuid.UUID) ([]entity.RoleAssignment, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, user_id, role, scope_type, scope_id, granted_by, created_at
+         FROM role_assignments WHERE scope_type = $1 AND scope_id = $2 ORDER BY created_at`, scopeType, scopeID)
+	if err != nil {
+		return nil, err
+	}
+	return scanRoleAssignments(rows)
+}
+
+func scanRoleAssignments(rows *sql.Rows) ([]entity.RoleAssignment, error) {
+	defer rows.Close()
+
+	assignments := []entity.RoleAssignment{}
+	for rows.Next() {
+		var a entity.RoleAssignment
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Role, &a.ScopeType, &a.ScopeID, &a.GrantedBy, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		assignments = append(assignments, a)
+	}
+	return assignments, rows.Err()
+}
+
+func (r *roleRepository) DeleteScopeAssignments(ctx context.Context, userID uuid.UUID, scopeType entity.ScopeType, scopeID uuid.UUID, role string) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM role_assignments
+         WHERE user_id = $1 AND scope_type = $2 AND scope_id = $3 AND ($4 = '' OR role = $4)`,
+		userID, scopeType, scopeID, role)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *roleRepository) EventExists(ctx context.Context, eventID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM events WHERE id = $1)`, eventID).Scan(&exists)
+	return exists, err
+}
+
+func (r *roleRepository) OrganizationExists(ctx context.Context, orgID uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM organizations WHERE id = $1)`, orgID).Scan(&exists)
+	return exists, err
+}
+
+func (r *roleRepository) OrganizationIDForEvent(ctx context.Context, eventID uuid.UUID) (*uuid.UUID, error) {
+	var orgID *uuid.UUID
+	err := r.db.QueryRowContext(ctx, `SELECT organization_id FROM events WHERE id = $1`, eventID).Scan(&orgID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return orgID, err
+}
+
+func (r *roleRepository) EventIDForTicketType(ctx context.Context, ticketTypeID uuid.UUID) (uuid.UUID, error) {
+	var eventID uuid.UUID
+	err := r.db.QueryRowContext(ctx, `SELECT event_id FROM ticket_types WHERE id = $1`, ticketTypeID).Scan(&eventID)
+	return eventID, err
+}
+
+func (r *roleRepository) EventIDForAllocation(ctx context.Context, allocationID uuid.UUID) (uuid.UUID, error) {
+	var eventID uuid.UUID
+	err := r.db.QueryRowContext(ctx,
+		`SELECT tt.event_id FROM allocations a JOIN ticket_types tt ON tt.id = a.ticket_type_id WHERE a.id = $1`,
+		allocationID).Scan(&eventID)
+	return eventID, err
+}
+
+func (r *roleRepository) EventIDForAddOn(ctx context.Context, addOnID uuid.UUID) (uuid.UUID, error) {
+	var eventID uuid.UUID
+	err := r.db.QueryRowContext(ctx, `SELECT event_id FROM add_ons WHERE id = $1`, addOnID).Scan(&eventID)
+	return eventID, err
+}
+
+func (r *roleRepository) EventIDForBallot(ctx context.Context, ballotID uuid.UUID) (uuid.UUID, error) {
+	var eventID uuid.UUID
+	err := r.db.QueryRowContext(ctx,
+		`SELECT tt.event_id FROM ballots b JOIN ticket_types tt ON tt.id = b.ticket_type_id WHERE b.id = $1`,
+		ballotID).Scan(&eventID)
+	return eventID, err
+}
+
+func (r *roleRepository) EventIDsForBundle(ctx context.Context, bundleID uuid.UUID) ([]uuid.UUID, error) {
+	return r.queryEventIDs(ctx,
+		`SELECT DISTINCT tt.event_id FROM bundle_items bi JOIN ticket_types tt ON tt.id = bi.ticket_type_id WHERE bi.bundle_id = $1`,
+		bundleID)
+}
+
+// EventIDsForOrder gom event từ vé lẻ / vé bán lại (ticket_type_id), loại vé trong gói và add-on của đơn.
+func (r *roleRepository) EventIDsForOrder(ctx context.Context, orderID uuid.UUID) ([]uuid.UUID, error) {
+	return r.queryEventIDs(ctx, `
+		SELECT tt.event_id FROM order_items oi JOIN ticket_types tt ON tt.id = oi.ticket_type_id WHERE oi.order_id = $1
+		UNION
+		SELECT tt.event_id FROM order_items oi
+		  JOIN bundle_items bi ON bi.bundle_id = oi.bundle_id
+		  JOIN ticket_types tt ON tt.id = bi.ticket_type_id
+		 WHERE oi.order_id = $1
+		UNION
+		SELECT a.event_id FROM order_items oi JOIN add_ons a ON a.id = oi.add_on_id WHERE oi.order_id = $1`,
+		orderID)
+}
+
+func (r *roleRepository) queryEventIDs(ctx context.Context, query string, args ...interface{}) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
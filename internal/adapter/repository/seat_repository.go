@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+	"gorm.io/gorm"
+)
+
+type seatRepository struct {
+	db *gorm.DB
+}
+
+func NewSeatRepository(db *gorm.DB) port.SeatRepositoryPort {
+	return &seatRepository{db: db}
+}
+
+func (r *seatRepository) CreateSeats(ctx context.Context, seats []entity.Seat) error {
+	ticketTypeIDs := make([]uuid.UUID, 0)
+	seen := make(map[uuid.UUID]bool)
+	for _, seat := range seats {
+		if !seen[seat.TicketTypeID] {
+			seen[seat.TicketTypeID] = true
+			ticketTypeIDs = append(ticketTypeIDs, seat.TicketTypeID)
+		}
+	}
+
+	// Tạo ghế + bật cờ reserved trong cùng transaction, tránh loại vé có ghế mà vẫn bán kiểu đứng
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(seats, 500).Error; err != nil {
+			return err
+		}
+		return tx.Model(&entity.TicketType{}).
+			Where("id IN ?", ticketTypeIDs).
+			Update("reserved", true).Error
+	})
+}
+
+func (r *seatRepository) ListSeatsByEvent(ctx context.Context, eventID uuid.UUID) ([]entity.Seat, error) {
+	var seats []entity.Seat
+	err := r.db.WithContext(ctx).
+		Where("event_id = ?", eventID).
+		Order("y, row_label, seat_number").
+		Find(&seats).Error
+	return seats, err
+}
+
+func (r *seatRepository) CountSeatsByTicketType(ctx context.Context, ticketTypeID uuid.UUID) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&entity.Seat{}).Where("ticket_type_id = ?", ticketTypeID).Count(&count).Error
+	return int(count), err
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+	"gorm.io/gorm"
+)
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) port.SessionRepositoryPort {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) CreateSessions(ctx context.Context, event *entity.Event, sessions []entity.EventSession) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// GORM tạo luôn TicketTypes của từng suất (association)
+		if err := tx.Omit("Event").CreateInBatches(sessions, 100).Error; err != nil {
+			return err
+		}
+		return tx.Model(event).
+			Select("start_time", "end_time", "recurrence").
+			Updates(event).Error
+	})
+}
+
+func (r *sessionRepository) ListSessions(ctx context.Context, filter entity.SessionFilter) ([]entity.EventSession, error) {
+	query := r.db.WithContext(ctx).
+		Preload("Event").
+		Preload("TicketTypes").
+		Where("event_sessions.status = ?", entity.SessionStatusScheduled)
+
+	if filter.EventID != nil {
+		query = query.Where("event_sessions.event_id = ?", *filter.EventID)
+	} else {
+		// Danh sách chung chỉ hiện suất của event đã publish
+		query = query.Joins("JOIN events ON events.id = event_sessions.event_id").
+			Where("events.status = ?", entity.EventStatusPublished)
+	}
+	if filter.From != nil {
+		query = query.Where("event_sessions.start_time >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("event_sessions.start_time <= ?", *filter.To)
+	}
+
+	var sessions []entity.EventSession
+	err := query.Order("event_sessions.start_time").Order("event_sessions.id").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&sessions).Error
+	return sessions, err
+}
@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+type tokenRepository struct {
+	db *sql.DB
+}
+
+func NewTokenRepository(db *sql.DB) port.TokenRepositoryPort {
+	return &tokenRepository{db: db}
+}
+
+func (r *tokenRepository) CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+              VALUES ($1, $2, $3, $4, $5, $6)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		token.ID, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt, token.CreatedAt,
+	)
+	return err
+}
+
+func (r *tokenRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*entity.RefreshToken, error) {
+	token := &entity.RefreshToken{}
+	query := `SELECT id, user_id, family_id, token_hash, expires_at, revoked_at, replaced_by, created_at
+              FROM refresh_tokens WHERE token_hash = $1`
+
+	err := r.db.QueryRowContext(ctx, query, hash).Scan(
+		&token.ID, &token.UserID, &token.FamilyID, &token.TokenHash, &token.ExpiresAt,
+		&token.RevokedAt, &token.ReplacedBy, &token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (r *tokenRepository) RotateRefreshToken(ctx context.Context, oldID uuid.UUID, next *entity.RefreshToken) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// Chỉ thu hồi khi token cũ còn hiệu lực: hai request refresh cùng lúc thì chỉ một bên thắng
+	result, err := tx.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = NOW(), replaced_by = $2 WHERE id = $1 AND revoked_at IS NULL`,
+		oldID, next.ID,
+	)
+	if err != nil {
+		return false, err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at) VALUES ($1, $2, $3, $4, $5, $6)`,
+		next.ID, next.UserID, next.FamilyID, next.TokenHash, next.ExpiresAt, next.CreatedAt,
+	); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (r *tokenRepository) RevokeRefreshFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL`, familyID)
+	return err
+}
+
+func (r *tokenRepository) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO revoked_tokens (jti, expires_at) VALUES ($1, $2) ON CONFLICT (jti) DO NOTHING`, jti, expiresAt)
+	return err
+}
+
+func (r *tokenRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)`, jti).Scan(&exists)
+	return exists, err
+}
+
+func (r *tokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`, userID)
+	return err
+}
+
+func (r *tokenRepository) CreateUserToken(ctx context.Context, token *entity.UserToken) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Chỉ link mới nhất còn dùng được: vô hiệu các token chưa dùng cùng mục đích
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE user_tokens SET used_at = NOW() WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`,
+		token.UserID, token.Purpose,
+	); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO user_tokens (id, user_id, purpose, token_hash, expires_at, created_at) VALUES ($1, $2, $3, $4, $5, $6)`,
+		token.ID, token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt, token.CreatedAt,
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *tokenRepository) GetUserToken(ctx context.Context, hash string, purpose entity.UserTokenPurpose) (*entity.UserToken, error) {
+	token := &entity.UserToken{}
+	query := `SELECT id, user_id, purpose, token_hash, expires_at, used_at, created_at FROM user_tokens
+              WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()`
+
+	err := r.db.QueryRowContext(ctx, query, hash, purpose).Scan(
+		&token.ID, &token.UserID, &token.Purpose, &token.TokenHash, &token.ExpiresAt, &token.UsedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (r *tokenRepository) ConsumeUserToken(ctx context.Context, hash string, purpose entity.UserTokenPurpose) (*entity.UserToken, error) {
+	token := &entity.UserToken{}
+	// Một câu UPDATE duy nhất: hai request dùng cùng token thì chỉ một bên nhận được dòng trả về
+	query := `UPDATE user_tokens SET used_at = NOW()
+              WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+              RETURNING id, user_id, purpose, token_hash, expires_at, used_at, created_at`
+
+	err := r.db.QueryRowContext(ctx, query, hash, purpose).Scan(
+		&token.ID, &token.UserID, &token.Purpose, &token.TokenHash, &token.ExpiresAt, &token.UsedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// PurgeExpiredTokens xóa jti đã thu hồi, refresh token và token email đã hết hạn trước before (không còn dùng được nữa).
+func (r *tokenRepository) PurgeExpiredTokens(ctx context.Context, before time.Time) (int64, error) {
+	var total int64
+	for _, query := range []string{
+		`DELETE FROM revoked_tokens WHERE expires_at < $1`,
+		`DELETE FROM refresh_tokens WHERE expires_at < $1`,
+		`DELETE FROM user_tokens WHERE expires_at < $1`,
+	} {
+		result, err := r.db.ExecContext(ctx, query, before)
+		if err != nil {
+			return total, err
+		}
+		n, _ := result.RowsAffected()
+		total += n
+	}
+	return total, nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+// TransferRepository nhận tx như OrderRepository vì sang tên vé phải khóa vé và lượt chuyển cùng lúc.
+type TransferRepository struct {
+	db *gorm.DB
+}
+
+func NewTransferRepository(db *gorm.DB) *TransferRepository {
+	return &TransferRepository{db: db}
+}
+
+// GetTicketForUpdate khóa vé để không bị chuyển hai lần hoặc vừa soát vé vừa chuyển.
+func (r *TransferRepository) GetTicketForUpdate(ctx context.Context, tx *gorm.DB, id uuid.UUID) (*entity.Ticket, error) {
+	var ticket entity.Ticket
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&ticket, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// GetTicketByCodeForUpdate khóa vé theo mã QR khi soát vé, để một vé không qua cổng hai lần.
+func (r *TransferRepository) GetTicketByCodeForUpdate(ctx context.Context, tx *gorm.DB, code string) (*entity.Ticket, error) {
+	var ticket entity.Ticket
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&ticket, "ticket_code = ?", code).Error; err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// TicketTypeEventID trả về event của loại vé.
+func (r *TransferRepository) TicketTypeEventID(ctx context.Context, tx *gorm.DB, ticketTypeID uuid.UUID) (uuid.UUID, error) {
+	var eventID uuid.UUID
+	err := tx.WithContext(ctx).
+		Table("ticket_types").
+		Select("event_id").
+		Where("id = ?", ticketTypeID).
+		Row().Scan(&eventID)
+	return eventID, err
+}
+
+func (r *TransferRepository) SaveTicket(ctx context.Context, tx *gorm.DB, ticket *entity.Ticket) error {
+	return tx.WithContext(ctx).Save(ticket).Error
+}
+
+// ListTicketsByOwner trả về các vé user đang giữ (kể cả vé được chuyển nhượng tới).
+func (r *TransferRepository) ListTicketsByOwner(ctx context.Context, ownerID uuid.UUID) ([]entity.Ticket, error) {
+	var tickets []entity.Ticket
+	err := r.db.WithContext(ctx).
+		Where("owner_id = ?", ownerID).
+		Order("created_at DESC").
+		Find(&tickets).Error
+	return tickets, err
+}
+
+// OrderStatus trả về trạng thái đơn của vé; chỉ vé thuộc đơn đã thanh toán mới được chuyển.
+func (r *TransferRepository) OrderStatus(ctx context.Context, tx *gorm.DB, orderID uuid.UUID) (entity.OrderStatus, error) {
+	var order entity.Order
+	if err := tx.WithContext(ctx).Select("status").First(&order, "id = ?", orderID).Error; err != nil {
+		return "", err
+	}
+	return order.Status, nil
+}
+
+// TransfersDisabled kiểm tra ban tổ chức có tắt chuyển nhượng cho loại vé hoặc cả event không.
+func (r *TransferRepository) TransfersDisabled(ctx context.Context, tx *gorm.DB, ticketTypeID uuid.UUID) (bool, error) {
+	var disabled bool
+	err := tx.WithContext(ctx).
+		Table("ticket_types").
+		Select("ticket_types.transfers_disabled OR events.transfers_disabled").
+		Joins("JOIN events ON events.id = ticket_types.event_id").
+		Where("ticket_types.id = ?", ticketTypeID).
+		Row().Scan(&disabled)
+	return disabled, err
+}
+
+func (r *TransferRepository) SetEventTransfersDisabled(ctx context.Context, eventID uuid.UUID, disabled bool) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.Event{}).
+		Where("id = ?", eventID).
+		Update("transfers_disabled", disabled)
+	return result.RowsAffected, result.Error
+}
+
+func (r *TransferRepository) SetTicketTypeTransfersDisabled(ctx context.Context, ticketTypeID uuid.UUID, disabled bool) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.TicketType{}).
+		Where("id = ?", ticketTypeID).
+		Update("transfers_disabled", disabled)
+	return result.RowsAffected, result.Error
+}
+
+// HasPendingTransfer kiểm tra vé đang có lượt chuyển chờ chấp nhận chưa.
+func (r *TransferRepository) HasPendingTransfer(ctx context.Context, tx *gorm.DB, ticketID uuid.UUID) (bool, error) {
+	var count int64
+	err := tx.WithContext(ctx).
+		Model(&entity.TicketTransfer{}).
+		Where("ticket_id = ? AND status = ?", ticketID, entity.TicketTransferStatusPending).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// HasActiveListing kiểm tra vé đang được rao bán lại trên sàn, hoặc đã có người đặt mua và đang chờ
+// người mua thanh toán (vé vẫn của người bán tới lúc đó).
+func (r *TransferRepository) HasActiveListing(ctx context.Context, tx *gorm.DB, ticketID uuid.UUID) (bool, error) {
+	var count int64
+	err := tx.WithContext(ctx).
+		Model(&entity.ResaleListing{}).
+		Where("ticket_id = ? AND status IN ?", ticketID, []entity.ResaleListingStatus{entity.ResaleListingStatusActive, entity.ResaleListingStatusReserved}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *TransferRepository) CreateTransfer(ctx context.Context, tx *gorm.DB, transfer *entity.TicketTransfer) error {
+	return tx.WithContext(ctx).Create(transfer).Error
+}
+
+func (r *TransferRepository) GetTransferForUpdate(ctx context.Context, tx *gorm.DB, id uuid.UUID) (*entity.TicketTransfer, error) {
+	var transfer entity.TicketTransfer
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&transfer, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+func (r *TransferRepository) SaveTransfer(ctx context.Context, tx *gorm.DB, transfer *entity.TicketTransfer) error {
+	return tx.WithContext(ctx).Save(transfer).Error
+}
+
+// ListByUser trả về các lượt chuyển user đã gửi hoặc được gửi tới email của user, mới nhất trước.
+func (r *TransferRepository) ListByUser(ctx context.Context, userID uuid.UUID, email string) ([]entity.TicketTransfer, error) {
+	var transfers []entity.TicketTransfer
+	err := r.db.WithContext(ctx).
+		Where("from_user_id = ? OR to_user_id = ? OR to_email = ?", userID, userID, email).
+		Order("created_at DESC").
+		Find(&transfers).Error
+	return transfers, err
+}
+
+// ListByTicket trả về lịch sử chuyển nhượng của vé theo thứ tự thời gian.
+func (r *TransferRepository) ListByTicket(ctx context.Context, ticketID uuid.UUID) ([]entity.TicketTransfer, error) {
+	var transfers []entity.TicketTransfer
+	err := r.db.WithContext(ctx).
+		Where("ticket_id = ?", ticketID).
+		Order("created_at").
+		Find(&transfers).Error
+	return transfers, err
+}
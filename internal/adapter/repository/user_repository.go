@@ -3,6 +3,9 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
 
 	"github.com/yourname/ticketing-system/internal/core/entity"
 	"github.com/yourname/ticketing-system/internal/core/port"
@@ -26,28 +29,74 @@ func (r *userRepository) CreateUser(ctx context.Context, user *entity.User) erro
 	return err
 }
 
-func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
-	user := &entity.User{}
-	query := `SELECT id, username, email, password_hash, role FROM users WHERE email = $1 LIMIT 1`
+// userColumns là các cột đọc ra entity.User, dùng chung với scanUser
+const userColumns = `id, username, email, password_hash, role, profile_data, email_verified_at, pending_email, created_at, updated_at`
 
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.Role,
+func scanUser(row *sql.Row) (*entity.User, error) {
+	user := &entity.User{}
+	var profile []byte
+	err := row.Scan(
+		&user.ID, &user.Username, &user.Email, &user.PasswordHash, &user.Role, &profile,
+		&user.EmailVerifiedAt, &user.PendingEmail, &user.CreatedAt, &user.UpdatedAt,
 	)
 	if err != nil {
 		return nil, err
 	}
+
+	user.ProfileData = map[string]interface{}{}
+	if len(profile) > 0 {
+		if err := json.Unmarshal(profile, &user.ProfileData); err != nil {
+			return nil, err
+		}
+	}
 	return user, nil
 }
 
+func (r *userRepository) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1 LIMIT 1`
+	return scanUser(r.db.QueryRowContext(ctx, query, email))
+}
+
 func (r *userRepository) GetUserByID(ctx context.Context, id string) (*entity.User, error) {
-	user := &entity.User{}
-	query := `SELECT id, username, email, role FROM users WHERE id = $1`
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+	return scanUser(r.db.QueryRowContext(ctx, query, id))
+}
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&user.ID, &user.Username, &user.Email, &user.Role,
-	)
+func (r *userRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE users SET password_hash = $2, updated_at = NOW() WHERE id = $1`, userID, passwordHash)
+	return err
+}
+
+func (r *userRepository) MarkEmailVerified(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE users SET email_verified_at = NOW(), updated_at = NOW() WHERE id = $1 AND email_verified_at IS NULL`, userID)
+	return err
+}
+
+func (r *userRepository) UpdateProfileData(ctx context.Context, userID uuid.UUID, data map[string]interface{}) error {
+	profile, err := json.Marshal(data)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return user, nil
+	_, err = r.db.ExecContext(ctx,
+		`UPDATE users SET profile_data = $2, updated_at = NOW() WHERE id = $1`, userID, profile)
+	return err
+}
+
+func (r *userRepository) SetPendingEmail(ctx context.Context, userID uuid.UUID, email string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE users SET pending_email = $2, updated_at = NOW() WHERE id = $1`, userID, email)
+	return err
+}
+
+func (r *userRepository) ApplyPendingEmail(ctx context.Context, userID uuid.UUID) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE users SET email = pending_email, pending_email = NULL, email_verified_at = NOW(), updated_at = NOW()
+		 WHERE id = $1 AND pending_email IS NOT NULL`, userID)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
 }
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+	"gorm.io/gorm"
+)
+
+type venueRepository struct {
+	db *gorm.DB
+}
+
+func NewVenueRepository(db *gorm.DB) port.VenueRepositoryPort {
+	return &venueRepository{db: db}
+}
+
+func (r *venueRepository) CreateVenue(ctx context.Context, venue *entity.Venue) error {
+	// GORM tạo luôn các section đi kèm (association)
+	return r.db.WithContext(ctx).Create(venue).Error
+}
+
+func (r *venueRepository) GetVenueByID(ctx context.Context, id uuid.UUID) (*entity.Venue, error) {
+	var venue entity.Venue
+	err := r.db.WithContext(ctx).Preload("Sections").First(&venue, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &venue, nil
+}
+
+func (r *venueRepository) ListVenues(ctx context.Context, limit int, offset int) ([]entity.Venue, error) {
+	var venues []entity.Venue
+	err := r.db.WithContext(ctx).Preload("Sections").
+		Order("name").
+		Limit(limit).
+		Offset(offset).
+		Find(&venues).Error
+	return venues, err
+}
+
+func (r *venueRepository) UpdateVenue(ctx context.Context, venue *entity.Venue) error {
+	// Omit association để không ghi đè section khi chỉ sửa thông tin venue
+	return r.db.WithContext(ctx).Omit("Sections").Save(venue).Error
+}
+
+func (r *venueRepository) DeleteVenue(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entity.Venue{}, "id = ?", id).Error
+}
+
+func (r *venueRepository) CreateSection(ctx context.Context, section *entity.VenueSection) error {
+	return r.db.WithContext(ctx).Create(section).Error
+}
+
+func (r *venueRepository) MaxEventAllocation(ctx context.Context, venueID uuid.UUID, sectionID *uuid.UUID) (int, error) {
+	sub := r.db.Table("ticket_types tt").
+		Select("SUM(tt.initial_quantity) AS total").
+		Joins("JOIN events e ON e.id = tt.event_id").
+		Where("e.venue_id = ?", venueID).
+		Group("e.id")
+	if sectionID != nil {
+		sub = sub.Where("tt.section_id = ?", *sectionID)
+	}
+
+	var max int
+	err := r.db.WithContext(ctx).
+		Table("(?) AS allocations", sub).
+		Select("COALESCE(MAX(total), 0)").
+		Scan(&max).Error
+	return max, err
+}
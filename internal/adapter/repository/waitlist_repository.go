@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+// WaitlistRepository giống OrderRepository: các hàm nhận tx để chạy chung transaction
+// với việc trừ/cộng kho.
+type WaitlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWaitlistRepository(db *gorm.DB) *WaitlistRepository {
+	return &WaitlistRepository{db: db}
+}
+
+// CreateEntry thêm user vào cuối hàng chờ.
+func (r *WaitlistRepository) CreateEntry(ctx context.Context, tx *gorm.DB, entry *entity.WaitlistEntry) error {
+	return tx.WithContext(ctx).Create(entry).Error
+}
+
+// HasActiveEntry kiểm tra user đã đang chờ (hoặc đang được giữ vé) loại vé này chưa.
+func (r *WaitlistRepository) HasActiveEntry(ctx context.Context, tx *gorm.DB, userID, ticketTypeID uuid.UUID) (bool, error) {
+	var count int64
+	err := tx.WithContext(ctx).
+		Model(&entity.WaitlistEntry{}).
+		Where("user_id = ? AND ticket_type_id = ?", userID, ticketTypeID).
+		Where("status IN ?", []entity.WaitlistStatus{entity.WaitlistStatusWaiting, entity.WaitlistStatusOffered}).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetEntryForUpdate khóa một entry (FOR UPDATE).
+func (r *WaitlistRepository) GetEntryForUpdate(ctx context.Context, tx *gorm.DB, id uuid.UUID) (*entity.WaitlistEntry, error) {
+	var entry entity.WaitlistEntry
+	if err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&entry, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// NextWaiting lấy entry đang chờ lâu nhất (FIFO) của loại vé, khóa lại để cấp offer.
+// Trả về nil nếu hàng chờ trống.
+func (r *WaitlistRepository) NextWaiting(ctx context.Context, tx *gorm.DB, ticketTypeID uuid.UUID) (*entity.WaitlistEntry, error) {
+	var entry entity.WaitlistEntry
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("ticket_type_id = ? AND status = ?", ticketTypeID, entity.WaitlistStatusWaiting).
+		Order("created_at").Order("id").
+		First(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// SaveEntry lưu trạng thái mới của entry.
+func (r *WaitlistRepository) SaveEntry(ctx context.Context, tx *gorm.DB, entry *entity.WaitlistEntry) error {
+	return tx.WithContext(ctx).Save(entry).Error
+}
+
+// ListByUser trả về các lượt chờ của user, mới nhất trước.
+func (r *WaitlistRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]entity.WaitlistEntry, error) {
+	var entries []entity.WaitlistEntry
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// ListExpiredOffers trả về id các offer đã quá hạn giữ vé.
+func (r *WaitlistRepository) ListExpiredOffers(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&entity.WaitlistEntry{}).
+		Where("status = ? AND offer_expires_at < ?", entity.WaitlistStatusOffered, now).
+		Order("offer_expires_at").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
@@ -1,2 +1,3 @@
 package entity
+
 // Domain entities go here
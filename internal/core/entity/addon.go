@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// AddOn là sản phẩm không phải vé bán kèm đơn (vé gửi xe, áo thun theo size...).
+// Có kho riêng, không phát hành vé. Nếu có Restrictions thì chỉ người mua
+// một trong các loại vé đó (trong cùng đơn hoặc đơn trước) mới được mua.
+type AddOn struct {
+	ID                uuid.UUID          `gorm:"type:uuid;primary_key;" json:"id"`
+	EventID           uuid.UUID          `gorm:"type:uuid;not null" json:"event_id"`
+	Name              string             `gorm:"not null" json:"name"`
+	Variant           string             `gorm:"type:varchar(50)" json:"variant,omitempty"` // VD: size S/M/L
+	Price             decimal.Decimal    `gorm:"type:decimal(10,2);not null" json:"price"`
+	InitialQuantity   int                `gorm:"not null" json:"initial_quantity"`
+	RemainingQuantity int                `gorm:"not null" json:"remaining_quantity"`
+	Restrictions      []AddOnRestriction `gorm:"foreignKey:AddOnID;constraint:OnDelete:CASCADE;" json:"restrictions,omitempty"`
+	CreatedAt         time.Time          `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time          `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// AddOnRestriction giới hạn add-on cho người mua loại vé TicketTypeID.
+type AddOnRestriction struct {
+	AddOnID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	TicketTypeID uuid.UUID `gorm:"type:uuid;primaryKey" json:"ticket_type_id"`
+}
+
+type CreateAddOnRequest struct {
+	Name                  string          `json:"name" validate:"required"`
+	Variant               string          `json:"variant"`
+	Price                 decimal.Decimal `json:"price" validate:"required"`
+	InitialQuantity       int             `json:"initial_quantity" validate:"required,min=1"`
+	RequiredTicketTypeIDs []uuid.UUID     `json:"required_ticket_type_ids"` // Rỗng = ai cũng mua được
+}
+
+// ItemKindSales là doanh số của một event gộp theo loại item (vé, combo, add-on).
+type ItemKindSales struct {
+	Kind     OrderItemKind   `json:"kind"`
+	Quantity int             `json:"quantity"`
+	Revenue  decimal.Decimal `json:"revenue"`
+}
+
+// AddOnSales là doanh số của một add-on.
+type AddOnSales struct {
+	AddOnID   uuid.UUID       `json:"add_on_id"`
+	Name      string          `json:"name"`
+	Variant   string          `json:"variant,omitempty"`
+	Sold      int             `json:"sold"`
+	Remaining int             `json:"remaining"`
+	Revenue   decimal.Decimal `json:"revenue"`
+}
+
+// SalesReport là báo cáo bán hàng của event, tách riêng vé và hàng bán kèm.
+type SalesReport struct {
+	EventID uuid.UUID       `json:"event_id"`
+	ByKind  []ItemKindSales `json:"by_kind"`
+	AddOns  []AddOnSales    `json:"add_ons"`
+}
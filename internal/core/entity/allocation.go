@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Allocation là phần vé ban tổ chức giữ lại khỏi bán công khai (nhà tài trợ, khách mời...).
+// Quantity là số vé đang giữ (gồm cả vé mời đã phát), IssuedQuantity là số vé mời đã phát.
+type Allocation struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;" json:"id"`
+	TicketTypeID   uuid.UUID `gorm:"type:uuid;not null" json:"ticket_type_id"`
+	Name           string    `gorm:"type:varchar(255);not null" json:"name"`
+	Quantity       int       `gorm:"not null" json:"quantity"`
+	IssuedQuantity int       `gorm:"not null;default:0" json:"issued_quantity"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// Available là số vé còn lại trong allocation, chưa phát thành vé mời.
+func (a Allocation) Available() int {
+	return a.Quantity - a.IssuedQuantity
+}
+
+type CreateAllocationRequest struct {
+	Name     string `json:"name" validate:"required"`
+	Quantity int    `json:"quantity" validate:"required,min=1"`
+}
+
+// IssueCompRequest phát vé mời cho một user, xác định bằng user_id hoặc email tài khoản.
+type IssueCompRequest struct {
+	UserID   *uuid.UUID `json:"user_id"`
+	Email    string     `json:"email"`
+	Quantity int        `json:"quantity" validate:"required,min=1"`
+}
+
+type ReleaseAllocationRequest struct {
+	Quantity int `json:"quantity"` // 0 = trả lại toàn bộ vé chưa phát
+}
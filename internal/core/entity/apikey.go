@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyHeader là header chứa API key khi hệ thống đối tác / kiosk gọi API không qua đăng nhập.
+const APIKeyHeader = "X-API-Key"
+
+// APIKey là khóa cho tích hợp máy-máy, thuộc về một organizer hoặc admin (chỉ lưu SHA-256 của khóa).
+// Request dùng API key mang danh tính của chủ khóa nhưng chỉ được các quyền trong Scopes
+// mà chủ khóa vẫn còn giữ tại thời điểm gọi.
+type APIKey struct {
+	ID                 uuid.UUID    `json:"id"`
+	UserID             uuid.UUID    `json:"user_id"` // Chủ khóa
+	Name               string       `json:"name"`
+	Prefix             string       `json:"prefix"` // Vài ký tự đầu của khóa để nhận diện, không đủ để dùng
+	KeyHash            string       `json:"-"`
+	Scopes             []Permission `json:"scopes"`
+	RateLimitPerMinute int          `json:"rate_limit_per_minute"`
+	ExpiresAt          *time.Time   `json:"expires_at,omitempty"`
+	LastUsedAt         *time.Time   `json:"last_used_at,omitempty"`
+	LastUsedIP         *string      `json:"last_used_ip,omitempty"`
+	RevokedAt          *time.Time   `json:"revoked_at,omitempty"`
+	RotatedFrom        *uuid.UUID   `json:"rotated_from,omitempty"` // Khóa cũ mà khóa này thay thế
+	CreatedAt          time.Time    `json:"created_at"`
+}
+
+// Active cho biết khóa còn dùng được (chưa thu hồi, chưa hết hạn).
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || k.ExpiresAt.After(now))
+}
+
+type CreateAPIKeyRequest struct {
+	Name               string       `json:"name" validate:"required"`
+	Scopes             []Permission `json:"scopes" validate:"required"`
+	RateLimitPerMinute int          `json:"rate_limit_per_minute"` // 0 = mặc định
+	ExpiresAt          *time.Time   `json:"expires_at"`
+}
+
+// RotateAPIKeyRequest: khóa cũ còn dùng được thêm GracePeriodMinutes phút để đối tác kịp đổi (0 = thu hồi ngay).
+type RotateAPIKeyRequest struct {
+	GracePeriodMinutes int `json:"grace_period_minutes"`
+}
+
+// CreatedAPIKey là khóa vừa tạo, Key chỉ trả về đúng một lần này.
+type CreatedAPIKey struct {
+	*APIKey
+	Key string `json:"key"`
+}
+
+// APIKeyUsage là kết quả dùng khóa trong cửa sổ giới hạn tần suất hiện tại.
+type APIKeyUsage struct {
+	Key         *APIKey
+	OwnerRole   string    // Role gốc hiện tại của chủ khóa
+	WindowStart time.Time // Đầu cửa sổ 1 phút đang đếm
+	WindowCount int       // Số request trong cửa sổ, kể cả request này
+}
+
+// APIKeyIdentity là danh tính của request đã xác thực bằng API key.
+type APIKeyIdentity struct {
+	KeyID     uuid.UUID
+	UserID    uuid.UUID
+	Role      string
+	Scopes    []Permission
+	RateLimit int
+	Remaining int
+}
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AttendeeQuestionType string
+
+const (
+	AttendeeQuestionText        AttendeeQuestionType = "TEXT"          // Trả lời tự do
+	AttendeeQuestionChoice      AttendeeQuestionType = "CHOICE"        // Chọn một trong Options
+	AttendeeQuestionDateOfBirth AttendeeQuestionType = "DATE_OF_BIRTH" // Ngày sinh dạng YYYY-MM-DD
+	AttendeeQuestionIDNumber    AttendeeQuestionType = "ID_NUMBER"     // Số CCCD / hộ chiếu
+)
+
+// AttendeeQuestion là một câu hỏi ban tổ chức đặt cho từng người tham dự (mỗi vé một bộ câu trả lời).
+// ID là khóa của câu trả lời trong Ticket.Answers nên giữ nguyên khi sửa câu hỏi.
+type AttendeeQuestion struct {
+	ID       string               `json:"id"`
+	Label    string               `json:"label"`
+	Type     AttendeeQuestionType `json:"type"`
+	Options  []string             `json:"options,omitempty"` // Chỉ dùng cho CHOICE
+	Required bool                 `json:"required"`
+}
+
+// AttendeeDetails là thông tin người tham dự của một vé: tên in trên vé và câu trả lời theo ID câu hỏi.
+type AttendeeDetails struct {
+	Name    string            `json:"name"`
+	Answers map[string]string `json:"answers"`
+}
+
+// SetAttendeeQuestionsRequest đặt bộ câu hỏi của event. Deadline = nil thì cho sửa tới giờ event bắt đầu.
+type SetAttendeeQuestionsRequest struct {
+	Questions []AttendeeQuestion `json:"questions"`
+	Deadline  *time.Time         `json:"deadline"`
+}
+
+// AttendeeRecord là một dòng danh sách người tham dự xuất cho ban tổ chức.
+type AttendeeRecord struct {
+	TicketID       uuid.UUID         `json:"ticket_id"`
+	OrderID        uuid.UUID         `json:"order_id"`
+	TicketTypeID   uuid.UUID         `json:"ticket_type_id"`
+	TicketTypeName string            `json:"ticket_type_name"`
+	Status         TicketStatus      `json:"status"`
+	Name           string            `json:"name"`
+	Answers        map[string]string `json:"answers"`
+	Complete       bool              `json:"complete"` // Đã có tên và trả lời đủ câu bắt buộc
+}
+
+// AttendeeExport là danh sách người tham dự của event kèm bộ câu hỏi để đọc các cột câu trả lời.
+type AttendeeExport struct {
+	Questions []AttendeeQuestion `json:"questions"`
+	Attendees []AttendeeRecord   `json:"attendees"`
+}
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BallotStatus string
+
+const (
+	BallotStatusOpen  BallotStatus = "OPEN"  // Đang/chuẩn bị nhận đăng ký
+	BallotStatusDrawn BallotStatus = "DRAWN" // Đã bốc thăm (có thể bốc bổ sung)
+)
+
+type BallotEntryStatus string
+
+const (
+	BallotEntryStatusEntered     BallotEntryStatus = "ENTERED"      // Đã đăng ký, chờ bốc thăm
+	BallotEntryStatusWon         BallotEntryStatus = "WON"          // Trúng, có đơn PENDING chờ thanh toán
+	BallotEntryStatusNotSelected BallotEntryStatus = "NOT_SELECTED" // Chưa trúng, còn cơ hội ở lượt bốc bổ sung
+	BallotEntryStatusUnpaid      BallotEntryStatus = "UNPAID"       // Trúng nhưng không thanh toán kịp, mất suất
+)
+
+// Ballot là đợt bán vé theo hình thức bốc thăm cho một loại vé.
+// Seed được sinh lúc tạo, chỉ công bố SeedHash; sau khi bốc mới lộ Seed để ai cũng kiểm chứng
+// được thứ tự: rank của entry = sha256(seed + ":" + entry_id), rank nhỏ xếp trước.
+type Ballot struct {
+	ID                   uuid.UUID    `gorm:"type:uuid;primary_key;" json:"id"`
+	TicketTypeID         uuid.UUID    `gorm:"type:uuid;not null;uniqueIndex" json:"ticket_type_id"`
+	EntryOpensAt         time.Time    `gorm:"not null" json:"entry_opens_at"`
+	EntryClosesAt        time.Time    `gorm:"not null" json:"entry_closes_at"`
+	MaxPerEntry          int          `gorm:"not null" json:"max_per_entry"`
+	PaymentWindowMinutes int          `gorm:"not null" json:"payment_window_minutes"` // Thời gian người trúng được thanh toán
+	Status               BallotStatus `gorm:"type:varchar(20);not null;default:'OPEN'" json:"status"`
+	Seed                 string       `gorm:"type:varchar(64);not null" json:"seed,omitempty"` // Chỉ trả ra sau khi bốc
+	SeedHash             string       `gorm:"type:varchar(64);not null" json:"seed_hash"`
+	DrawnAt              *time.Time   `json:"drawn_at,omitempty"`
+	CreatedAt            time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt            time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type BallotEntry struct {
+	ID              uuid.UUID         `gorm:"type:uuid;primary_key;" json:"id"`
+	BallotID        uuid.UUID         `gorm:"type:uuid;not null" json:"ballot_id"`
+	UserID          uuid.UUID         `gorm:"type:uuid;not null" json:"-"`
+	Quantity        int               `gorm:"not null" json:"quantity"`
+	Status          BallotEntryStatus `gorm:"type:varchar(20);not null;default:'ENTERED'" json:"status"`
+	DrawRank        string            `gorm:"type:varchar(64)" json:"draw_rank,omitempty"` // sha256(seed:id), có sau khi bốc
+	Position        *int              `json:"position,omitempty"`                          // Thứ tự sau khi bốc, bắt đầu từ 1
+	OrderID         *uuid.UUID        `gorm:"type:uuid" json:"order_id,omitempty"`
+	PaymentDeadline *time.Time        `json:"payment_deadline,omitempty"`
+	CreatedAt       time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type CreateBallotRequest struct {
+	TicketTypeID         uuid.UUID `json:"ticket_type_id" validate:"required"`
+	EntryOpensAt         time.Time `json:"entry_opens_at" validate:"required"`
+	EntryClosesAt        time.Time `json:"entry_closes_at" validate:"required"`
+	MaxPerEntry          int       `json:"max_per_entry"`
+	PaymentWindowMinutes int       `json:"payment_window_minutes"`
+}
+
+type EnterBallotRequest struct {
+	Quantity int `json:"quantity" validate:"required,min=1"`
+}
+
+// BallotDrawResult là kết quả một lượt bốc (lần đầu hoặc bổ sung).
+type BallotDrawResult struct {
+	Ballot      *Ballot `json:"ballot"`
+	Winners     int     `json:"winners"`      // Số entry trúng ở lượt này
+	Allocated   int     `json:"allocated"`    // Số vé đã phân bổ ở lượt này
+	Unpaid      int     `json:"unpaid"`       // Số suất bị thu hồi vì không thanh toán (lượt bổ sung)
+	NotSelected int     `json:"not_selected"` // Số entry còn chờ
+}
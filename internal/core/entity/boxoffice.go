@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// PaymentMethod là cách khách trả tiền tại quầy.
+type PaymentMethod string
+
+const (
+	PaymentMethodCash PaymentMethod = "CASH"
+	PaymentMethodCard PaymentMethod = "CARD" // Quẹt thẻ qua máy POS
+)
+
+// BoxOfficeSale là thông tin bán tại quầy đi kèm đơn: phương thức thu tiền và khách (không bắt buộc).
+type BoxOfficeSale struct {
+	PaymentMethod PaymentMethod `json:"payment_method" validate:"required"`
+	CustomerName  string        `json:"customer_name"`
+	CustomerPhone string        `json:"customer_phone"`
+}
+
+// PaymentMethodTotal là số đơn và tiền thu theo một phương thức.
+type PaymentMethodTotal struct {
+	PaymentMethod PaymentMethod   `json:"payment_method"`
+	Orders        int             `json:"orders"`
+	Tickets       int             `json:"tickets"`
+	Amount        decimal.Decimal `json:"amount"`
+}
+
+// CashierShiftReport là báo cáo đối soát cuối ca của một thu ngân.
+// CashAmount là tiền mặt phải có trong két, so với tiền đếm thực tế.
+type CashierShiftReport struct {
+	CashierID   uuid.UUID            `json:"cashier_id"`
+	Orders      int                  `json:"orders"`
+	Tickets     int                  `json:"tickets"`
+	TotalAmount decimal.Decimal      `json:"total_amount"`
+	CashAmount  decimal.Decimal      `json:"cash_amount"`
+	ByMethod    []PaymentMethodTotal `json:"by_method"`
+}
+
+type ShiftReport struct {
+	From     time.Time            `json:"from"`
+	To       time.Time            `json:"to"`
+	Cashiers []CashierShiftReport `json:"cashiers"`
+}
+
+// CashierSales là một dòng doanh số gộp theo thu ngân và phương thức (repository trả về).
+type CashierSales struct {
+	CashierID     uuid.UUID
+	PaymentMethod PaymentMethod
+	Orders        int
+	Tickets       int
+	Amount        decimal.Decimal
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Bundle là gói vé combo (VD: vé 3 ngày lễ hội) gồm nhiều loại vé, bán với giá riêng.
+// Mua 1 bundle sẽ trừ kho của từng loại vé thành phần và của chính bundle.
+type Bundle struct {
+	ID                uuid.UUID       `gorm:"type:uuid;primary_key;" json:"id"`
+	Name              string          `gorm:"not null" json:"name"`
+	Description       string          `gorm:"type:text" json:"description"`
+	Price             decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"price"`
+	InitialQuantity   int             `gorm:"not null" json:"initial_quantity"`
+	RemainingQuantity int             `gorm:"not null" json:"remaining_quantity"`
+	Items             []BundleItem    `gorm:"foreignKey:BundleID;constraint:OnDelete:CASCADE;" json:"items"`
+	CreatedAt         time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type BundleItem struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;" json:"id"`
+	BundleID     uuid.UUID `gorm:"type:uuid;not null" json:"bundle_id"`
+	TicketTypeID uuid.UUID `gorm:"type:uuid;not null" json:"ticket_type_id"`
+	Quantity     int       `gorm:"not null;default:1" json:"quantity"` // Số vé loại này trong 1 bundle
+}
+
+type CreateBundleRequest struct {
+	Name            string                    `json:"name" validate:"required,min=3"`
+	Description     string                    `json:"description"`
+	Price           decimal.Decimal           `json:"price" validate:"required"`
+	InitialQuantity int                       `json:"initial_quantity" validate:"required,min=1"`
+	Items           []CreateBundleItemRequest `json:"items" validate:"required,min=2"`
+}
+
+type CreateBundleItemRequest struct {
+	TicketTypeID uuid.UUID `json:"ticket_type_id" validate:"required"`
+	Quantity     int       `json:"quantity"`
+}
@@ -16,39 +16,116 @@ const (
 	EventStatusEnded     EventStatus = "ENDED"
 )
 
+// TicketVisibility quyết định loại vé có hiện ra ở API công khai và có đặt tự do được không.
+type TicketVisibility string
+
+const (
+	TicketVisibilityPublic TicketVisibility = "PUBLIC" // Hiện công khai, ai cũng đặt được
+	TicketVisibilityHidden TicketVisibility = "HIDDEN" // Ẩn hoàn toàn, chỉ đặt được bằng mã presale
+	TicketVisibilityCode   TicketVisibility = "CODE"   // Ẩn, hiện ra khi user nhập đúng mã presale
+)
+
 type Event struct {
 	ID        uuid.UUID   `gorm:"type:uuid;primary_key;" json:"id"`
 	Name      string      `gorm:"not null" json:"name"`
 	Slug      string      `gorm:"uniqueIndex;not null" json:"slug"`
 	Location  string      `gorm:"type:varchar(255)" json:"location"`
+	VenueID   *uuid.UUID  `gorm:"type:uuid" json:"venue_id,omitempty"`
 	BannerURL string      `gorm:"type:varchar(500)" json:"banner_url"`
 	StartTime time.Time   `gorm:"not null" json:"start_time"`
 	EndTime   time.Time   `gorm:"not null" json:"end_time"`
 	Status    EventStatus `gorm:"type:varchar(20);default:'DRAFT'" json:"status"`
 	CreatedAt time.Time   `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// OrganizationID là tổ chức sở hữu event (nil = event của nền tảng, chỉ admin quản lý)
+	OrganizationID *uuid.UUID `gorm:"type:uuid" json:"organization_id,omitempty"`
+
+	// Organization (tên, tiền tệ, phí, thương hiệu) do repository điền khi trả chi tiết event
+	Organization *Organization `gorm:"-" json:"organization,omitempty"`
+
+	// Recurrence là luật lặp đã dùng để sinh các suất diễn (nil = event một suất)
+	Recurrence *RecurrenceRule `gorm:"type:jsonb;serializer:json" json:"recurrence,omitempty"`
+
+	// TransfersDisabled = true thì cấm chuyển nhượng mọi vé của event
+	TransfersDisabled bool `gorm:"not null;default:false" json:"transfers_disabled"`
+
+	// ResaleCapPercent là trần giá bán lại trên sàn theo % giá gốc (VD: 110 = tối đa +10%), 0 = không cho bán lại
+	ResaleCapPercent int `gorm:"not null;default:0" json:"resale_cap_percent"`
+
+	// AttendeeQuestions là bộ câu hỏi mỗi vé phải trả lời (lúc checkout hoặc trước AttendeeDeadline)
+	AttendeeQuestions []AttendeeQuestion `gorm:"type:jsonb;serializer:json" json:"attendee_questions,omitempty"`
+	AttendeeDeadline  *time.Time         `json:"attendee_deadline,omitempty"` // nil = sửa được tới giờ bắt đầu event
+
+	// SoldCount chỉ đọc, được tính khi list (tổng vé đã bán) để sắp xếp theo độ hot
+	SoldCount int64 `gorm:"->;-:migration" json:"-"`
+
+	// TicketTypes chỉ chứa các loại vé PUBLIC, do service điền khi trả chi tiết event
+	TicketTypes []TicketType `gorm:"-" json:"ticket_types,omitempty"`
 }
 
 type TicketType struct {
-	ID                uuid.UUID       `gorm:"type:uuid;primary_key;" json:"id"`
-	EventID           uuid.UUID       `gorm:"type:uuid;not null" json:"event_id"`
-	Name              string          `gorm:"not null" json:"name"`
-	Price             decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"price"`
-	InitialQuantity   int             `gorm:"not null" json:"initial_quantity"`
-	RemainingQuantity int             `gorm:"not null" json:"remaining_quantity"`
+	ID                uuid.UUID        `gorm:"type:uuid;primary_key;" json:"id"`
+	EventID           uuid.UUID        `gorm:"type:uuid;not null" json:"event_id"`
+	SectionID         *uuid.UUID       `gorm:"type:uuid" json:"section_id,omitempty"` // Khu của venue mà loại vé này bán
+	PoolID            *uuid.UUID       `gorm:"type:uuid" json:"pool_id,omitempty"`    // Pool sức chứa dùng chung (nếu có)
+	SessionID         *uuid.UUID       `gorm:"type:uuid" json:"session_id,omitempty"` // Suất diễn sở hữu loại vé này (nếu có)
+	Name              string           `gorm:"not null" json:"name"`
+	Price             decimal.Decimal  `gorm:"type:decimal(10,2);not null" json:"price"`
+	InitialQuantity   int              `gorm:"not null" json:"initial_quantity"`
+	RemainingQuantity int              `gorm:"not null" json:"remaining_quantity"`
+	Reserved          bool             `gorm:"not null;default:false" json:"reserved"` // true = vé ngồi theo số, phải chọn ghế khi đặt
+	Ballot            bool             `gorm:"not null;default:false" json:"ballot"`   // true = chỉ bán qua bốc thăm, không đặt trực tiếp
+	Visibility        TicketVisibility `gorm:"type:varchar(20);not null;default:'PUBLIC'" json:"visibility"`
+	TransfersDisabled bool             `gorm:"not null;default:false" json:"transfers_disabled"` // true = vé loại này không chuyển nhượng được
+}
+
+// IsPublic trả về true nếu loại vé bán công khai (không cần mã presale).
+func (t TicketType) IsPublic() bool {
+	return t.Visibility == "" || t.Visibility == TicketVisibilityPublic
 }
 
 type CreateEventRequest struct {
-	Name      string    `json:"name" validate:"required,min=3"`
-	Slug      string    `json:"slug" validate:"required,min=3"`
-	Location  string    `json:"location" validate:"required,min=3"`
-	BannerURL string    `json:"banner_url"`
-	StartTime time.Time `json:"start_time" validate:"required"`
-	EndTime   time.Time `json:"end_time" validate:"required"`
+	Name      string     `json:"name" validate:"required,min=3"`
+	Slug      string     `json:"slug" validate:"required,min=3"`
+	Location  string     `json:"location" validate:"required_without=VenueID"`
+	VenueID   *uuid.UUID `json:"venue_id"`
+	BannerURL string     `json:"banner_url"`
+
+	// OrganizationID là tổ chức sở hữu event, bỏ trống = event của nền tảng
+	OrganizationID *uuid.UUID `json:"organization_id"`
+	StartTime      time.Time  `json:"start_time" validate:"required"`
+	EndTime        time.Time  `json:"end_time" validate:"required"`
 }
 
 type CreateTicketTypeRequest struct {
-	Name            string          `json:"name" validate:"required,min=3"`
-	Price           decimal.Decimal `json:"price" validate:"required"`
-	InitialQuantity int             `json:"initial_quantity" validate:"required,min=1"`
+	Name            string           `json:"name" validate:"required,min=3"`
+	Price           decimal.Decimal  `json:"price" validate:"required"`
+	InitialQuantity int              `json:"initial_quantity" validate:"required,min=1"`
+	SectionID       *uuid.UUID       `json:"section_id"`
+	Visibility      TicketVisibility `json:"visibility"` // Bỏ trống = PUBLIC
+}
+
+type EventSort string
+
+const (
+	EventSortStartTime  EventSort = "start_time" // Sắp diễn ra trước
+	EventSortNewest     EventSort = "newest"     // Mới tạo gần đây nhất
+	EventSortPopularity EventSort = "popularity" // Bán được nhiều vé nhất
+)
+
+// EventFilter gom các điều kiện tìm kiếm sự kiện từ query string.
+// Các field con trỏ = nil nghĩa là không lọc theo field đó.
+type EventFilter struct {
+	Query     string // Tìm toàn văn trên tên + địa điểm (không phân biệt dấu)
+	Status    EventStatus
+	Location  string
+	StartFrom *time.Time
+	StartTo   *time.Time
+	MinPrice  *decimal.Decimal
+	MaxPrice  *decimal.Decimal
+	Sort      EventSort
+	Limit     int
+	Offset    int
+	Cursor    *PageCursor // Có cursor thì bỏ qua Offset, phân trang theo keyset
 }
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// LoginThrottleKind là loại khóa theo dõi đăng nhập sai.
+type LoginThrottleKind string
+
+const (
+	LoginThrottleAccount LoginThrottleKind = "ACCOUNT" // Theo email (kể cả email chưa đăng ký)
+	LoginThrottleIP      LoginThrottleKind = "IP"      // Theo địa chỉ IP client
+)
+
+// LoginThrottle đếm số lần đăng nhập sai liên tiếp của một email hoặc IP. Vượt ngưỡng thì bị khóa
+// tạm thời, thời gian khóa tăng gấp đôi sau mỗi lần sai tiếp theo.
+type LoginThrottle struct {
+	Kind          LoginThrottleKind `json:"kind"`
+	Key           string            `json:"key"`
+	Failures      int               `json:"failures"`
+	LastFailureAt time.Time         `json:"last_failure_at"`
+	LockedUntil   *time.Time        `json:"locked_until,omitempty"`
+}
@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserTOTP là ứng dụng xác thực (TOTP, RFC 6238) của user. EnabledAt nil nghĩa là đang đăng ký dở:
+// đã cấp secret nhưng user chưa nhập mã đúng lần nào.
+type UserTOTP struct {
+	UserID       uuid.UUID  `json:"user_id"`
+	Secret       string     `json:"-"`
+	EnabledAt    *time.Time `json:"enabled_at,omitempty"`
+	LastUsedStep int64      `json:"-"` // Bước thời gian của mã dùng gần nhất, chặn dùng lại mã
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// MFAEnrollment là thông tin để user thêm tài khoản vào ứng dụng xác thực.
+type MFAEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+	QRCodePNG  []byte `json:"qr_code_png"` // Ảnh PNG mã QR của otpauth_uri (JSON: base64)
+}
+
+// LoginResult là kết quả đăng nhập bằng mật khẩu: cặp token, hoặc challenge token nếu cần bước 2
+// (nhập mã TOTP, hay đăng ký ứng dụng xác thực trước nếu role bắt buộc 2FA).
+type LoginResult struct {
+	*TokenPair
+	MFARequired           bool   `json:"mfa_required,omitempty"`
+	MFAEnrollmentRequired bool   `json:"mfa_enrollment_required,omitempty"`
+	ChallengeToken        string `json:"challenge_token,omitempty"`
+}
+
+// MFALoginRequest là bước 2 của đăng nhập: challenge token kèm mã TOTP hoặc mã khôi phục.
+type MFALoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code"`
+	RecoveryCode   string `json:"recovery_code"`
+	IP             string `json:"-"`
+}
+
+// MFAChallengeRequest dùng khi đăng ký ứng dụng xác thực ngay trong lúc đăng nhập.
+type MFAChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+}
+
+// MFACodeRequest xác nhận thao tác 2FA bằng mã TOTP hiện tại.
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// MFALoginResponse là kết quả bước 2: cặp token, kèm mã khôi phục nếu vừa hoàn tất đăng ký 2FA.
+type MFALoginResponse struct {
+	*TokenPair
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
@@ -13,22 +13,58 @@ const (
 	OrderStatusPending   OrderStatus = "PENDING"
 	OrderStatusPaid      OrderStatus = "PAID"
 	OrderStatusCancelled OrderStatus = "CANCELLED"
+	OrderStatusRefunded  OrderStatus = "REFUNDED" // Đã hoàn tiền, vé bị thu hồi
+)
+
+// OrderItemKind phân biệt item trong đơn: vé lẻ, gói combo, hàng bán kèm hay vé mua lại trên sàn.
+// Chỉ TICKET và BUNDLE được phát hành vé mới; RESALE sang tên vé có sẵn cho người mua.
+type OrderItemKind string
+
+const (
+	OrderItemKindTicket OrderItemKind = "TICKET"
+	OrderItemKindBundle OrderItemKind = "BUNDLE"
+	OrderItemKindAddOn  OrderItemKind = "ADDON"
+	OrderItemKindResale OrderItemKind = "RESALE"
+)
+
+// OrderChannel là kênh bán của đơn: online (user tự đặt) hay tại quầy (box office).
+type OrderChannel string
+
+const (
+	OrderChannelOnline    OrderChannel = "ONLINE"
+	OrderChannelBoxOffice OrderChannel = "BOX_OFFICE"
 )
 
 type Order struct {
-	ID          uuid.UUID       `gorm:"type:uuid;primary_key;" json:"id"`
-	UserID      uuid.UUID       `gorm:"type:uuid;not null" json:"user_id"`
-	TotalAmount decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"total_amount"`
-	Status      OrderStatus     `gorm:"type:varchar(20);not null;default:'PENDING'" json:"status"`
-	CreatedAt   time.Time       `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt   time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
-	Items       []OrderItem     `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE;" json:"items"`
+	ID              uuid.UUID       `gorm:"type:uuid;primary_key;" json:"id"`
+	UserID          *uuid.UUID      `gorm:"type:uuid" json:"user_id"` // nil = khách vãng lai mua tại quầy
+	TotalAmount     decimal.Decimal `gorm:"type:decimal(10,2);not null" json:"total_amount"`
+	Status          OrderStatus     `gorm:"type:varchar(20);not null;default:'PENDING'" json:"status"`
+	PaymentDeadline *time.Time      `json:"payment_deadline,omitempty"`         // Hạn thanh toán riêng (VD: đơn trúng bốc thăm), nil = dùng ORDER_TTL
+	Comp            bool            `gorm:"not null;default:false" json:"comp"` // true = vé mời, không qua thanh toán
+	Channel         OrderChannel    `gorm:"type:varchar(20);not null;default:'ONLINE'" json:"channel"`
+	CashierID       *uuid.UUID      `gorm:"type:uuid" json:"cashier_id,omitempty"`            // Nhân viên quầy bán đơn (box office)
+	PaymentMethod   *PaymentMethod  `gorm:"type:varchar(20)" json:"payment_method,omitempty"` // Phương thức thu tiền tại quầy
+	CustomerName    string          `gorm:"type:varchar(100)" json:"customer_name,omitempty"` // Khách vãng lai (không bắt buộc)
+	CustomerPhone   string          `gorm:"type:varchar(20)" json:"customer_phone,omitempty"`
+	CreatedAt       time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+	Items           []OrderItem     `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE;" json:"items"`
+	Tickets         []Ticket        `gorm:"foreignKey:OrderID" json:"tickets,omitempty"`
 }
 
 type OrderItem struct {
 	ID           uuid.UUID       `gorm:"type:uuid;primary_key;" json:"id"`
 	OrderID      uuid.UUID       `gorm:"type:uuid;not null" json:"order_id"`
-	TicketTypeID uuid.UUID       `gorm:"type:uuid;not null" json:"ticket_type_id"`
+	Kind         OrderItemKind   `gorm:"type:varchar(20);not null;default:'TICKET'" json:"kind"`
+	TicketTypeID *uuid.UUID      `gorm:"type:uuid" json:"ticket_type_id"`          // nil khi item là bundle/add-on
+	BundleID     *uuid.UUID      `gorm:"type:uuid" json:"bundle_id,omitempty"`     // Gói combo (nếu có)
+	AddOnID      *uuid.UUID      `gorm:"type:uuid" json:"add_on_id,omitempty"`     // Hàng bán kèm (nếu có)
+	SessionID    *uuid.UUID      `gorm:"type:uuid" json:"session_id,omitempty"`    // Suất diễn của loại vé lúc mua
+	AllocationID *uuid.UUID      `gorm:"type:uuid" json:"allocation_id,omitempty"` // Phần vé giữ lại mà vé mời lấy từ đó
+	ResaleID     *uuid.UUID      `gorm:"type:uuid" json:"resale_id,omitempty"`     // Tin bán lại trên sàn (item RESALE)
 	Quantity     int             `gorm:"not null" json:"quantity"`
 	UnitPrice    decimal.Decimal `gorm:"column:price;type:decimal(10,2);not null" json:"unit_price"`
+	SeatIDs      []uuid.UUID     `gorm:"-" json:"seat_ids,omitempty"` // Ghế đã giữ (chỉ có với vé ngồi theo số)
+	Bundle       *Bundle         `gorm:"-" json:"-"`                  // Bundle đã khóa lúc đặt, dùng để phát hành vé
 }
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Organization là đơn vị tổ chức sở hữu các event. Thành viên và vai trò trong tổ chức là
+// các RoleAssignment với phạm vi ScopeOrganization.
+type Organization struct {
+	ID        uuid.UUID            `gorm:"type:uuid;primary_key;" json:"id"`
+	Name      string               `gorm:"not null" json:"name"`
+	Slug      string               `gorm:"uniqueIndex;not null" json:"slug"`
+	Settings  OrganizationSettings `gorm:"type:jsonb;serializer:json;not null" json:"settings"`
+	CreatedAt time.Time            `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time            `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// OrganizationSettings là cấu hình riêng của từng ban tổ chức.
+type OrganizationSettings struct {
+	Currency          string               `json:"currency"`            // Mã ISO 4217, VD: VND
+	ServiceFeePercent decimal.Decimal      `json:"service_fee_percent"` // Phí dịch vụ theo % giá vé
+	ServiceFeeFixed   decimal.Decimal      `json:"service_fee_fixed"`   // Phí cố định mỗi vé
+	Branding          OrganizationBranding `json:"branding"`
+}
+
+type OrganizationBranding struct {
+	LogoURL      string `json:"logo_url,omitempty"`
+	PrimaryColor string `json:"primary_color,omitempty"` // Dạng #RRGGBB
+	SupportEmail string `json:"support_email,omitempty"`
+}
+
+// DefaultOrganizationSettings dùng khi tạo tổ chức mà không gửi cấu hình.
+func DefaultOrganizationSettings() OrganizationSettings {
+	return OrganizationSettings{
+		Currency:          "VND",
+		ServiceFeePercent: decimal.Zero,
+		ServiceFeeFixed:   decimal.Zero,
+	}
+}
+
+type CreateOrganizationRequest struct {
+	Name       string                `json:"name" validate:"required,min=3"`
+	Slug       string                `json:"slug" validate:"required,min=3"`
+	Settings   *OrganizationSettings `json:"settings"`    // Bỏ trống = cấu hình mặc định
+	OwnerEmail string                `json:"owner_email"` // Nếu có: cấp vai trò organizer của tổ chức cho user này
+}
+
+type AddMemberRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required"`
+}
+
+// OrganizationMember là một user cùng các vai trò của họ trong tổ chức.
+type OrganizationMember struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Email    string    `json:"email"`
+	Roles    []string  `json:"roles"`
+}
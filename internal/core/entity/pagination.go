@@ -0,0 +1,26 @@
+package entity
+
+import "github.com/google/uuid"
+
+// PageCursor là vị trí keyset của một dòng trong danh sách (giá trị cột sắp xếp + id).
+// Handler ký và mã hóa struct này thành chuỗi opaque trước khi trả cho client.
+type PageCursor struct {
+	Sort     string    `json:"s"`
+	Value    string    `json:"v"`
+	ID       uuid.UUID `json:"id"`
+	Backward bool      `json:"b,omitempty"` // true = lấy trang phía trước vị trí này
+	Filter   string    `json:"f,omitempty"` // Dấu vân tay bộ lọc + sắp xếp lúc cấp cursor
+}
+
+type EventPage struct {
+	Events     []Event
+	Total      int64
+	NextCursor *PageCursor
+	PrevCursor *PageCursor
+}
+
+type OrderPage struct {
+	Orders     []Order
+	NextCursor *PageCursor
+	PrevCursor *PageCursor
+}
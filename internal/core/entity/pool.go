@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CapacityPool là sức chứa vật lý dùng chung cho nhiều loại vé của một event.
+// VD: VIP + Standard + Early Bird cùng bán trên 1000 chỗ của sân cỏ.
+// Đặt vé phải còn đủ ở cả TicketType.RemainingQuantity lẫn pool.
+type CapacityPool struct {
+	ID                uuid.UUID `gorm:"type:uuid;primary_key;" json:"id"`
+	EventID           uuid.UUID `gorm:"type:uuid;not null" json:"event_id"`
+	Name              string    `gorm:"not null" json:"name"`
+	Capacity          int       `gorm:"not null" json:"capacity"`
+	RemainingQuantity int       `gorm:"not null" json:"remaining_quantity"`
+	CreatedAt         time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt         time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type CreateCapacityPoolRequest struct {
+	Name          string      `json:"name" validate:"required"`
+	Capacity      int         `json:"capacity" validate:"required,min=1"`
+	TicketTypeIDs []uuid.UUID `json:"ticket_type_ids" validate:"required,min=1"`
+}
+
+// PoolReport là báo cáo mức sử dụng của một pool.
+type PoolReport struct {
+	PoolID      uuid.UUID         `json:"pool_id"`
+	Name        string            `json:"name"`
+	Capacity    int               `json:"capacity"`
+	Remaining   int               `json:"remaining"`
+	Sold        int               `json:"sold"`
+	Utilization float64           `json:"utilization"` // Phần trăm đã dùng, 0-100
+	TicketTypes []PoolTicketUsage `json:"ticket_types"`
+}
+
+type PoolTicketUsage struct {
+	TicketTypeID uuid.UUID `json:"ticket_type_id"`
+	Name         string    `json:"name"`
+	Sold         int       `json:"sold"`
+	Remaining    int       `json:"remaining"`
+}
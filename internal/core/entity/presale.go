@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PresaleCode là mã truy cập presale (fan club...) mở khóa các loại vé HIDDEN/CODE.
+// Mỗi user chỉ dùng một mã một lần; MaxUses giới hạn tổng số user được dùng (0 = không giới hạn).
+type PresaleCode struct {
+	ID          uuid.UUID               `gorm:"type:uuid;primary_key;" json:"id"`
+	EventID     uuid.UUID               `gorm:"type:uuid;not null" json:"event_id"`
+	Code        string                  `gorm:"type:varchar(50);not null" json:"code"` // Lưu dạng in hoa, unique trong event
+	Name        string                  `gorm:"type:varchar(255)" json:"name,omitempty"`
+	MaxUses     int                     `gorm:"not null;default:0" json:"max_uses"`
+	UsedCount   int                     `gorm:"not null;default:0" json:"used_count"`
+	StartsAt    *time.Time              `json:"starts_at,omitempty"`
+	EndsAt      *time.Time              `json:"ends_at,omitempty"`
+	TicketTypes []PresaleCodeTicketType `gorm:"foreignKey:PresaleCodeID;constraint:OnDelete:CASCADE;" json:"ticket_types"`
+	CreatedAt   time.Time               `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// PresaleCodeTicketType là loại vé mà mã presale mở khóa.
+type PresaleCodeTicketType struct {
+	PresaleCodeID uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	TicketTypeID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"ticket_type_id"`
+}
+
+// PresaleRedemption ghi nhận user đã dùng mã cho một đơn. Đơn bị hủy thì lượt dùng được trả lại.
+type PresaleRedemption struct {
+	ID            uuid.UUID `gorm:"type:uuid;primary_key;" json:"id"`
+	PresaleCodeID uuid.UUID `gorm:"type:uuid;not null" json:"presale_code_id"`
+	UserID        uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
+	OrderID       uuid.UUID `gorm:"type:uuid;not null" json:"order_id"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// Active kiểm tra mã còn trong khung giờ hiệu lực tại thời điểm now.
+func (p PresaleCode) Active(now time.Time) bool {
+	if p.StartsAt != nil && now.Before(*p.StartsAt) {
+		return false
+	}
+	if p.EndsAt != nil && !now.Before(*p.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// Grants kiểm tra mã có mở khóa loại vé ticketTypeID không.
+func (p PresaleCode) Grants(ticketTypeID uuid.UUID) bool {
+	for _, tt := range p.TicketTypes {
+		if tt.TicketTypeID == ticketTypeID {
+			return true
+		}
+	}
+	return false
+}
+
+type CreatePresaleCodeRequest struct {
+	Code          string      `json:"code" validate:"required"`
+	Name          string      `json:"name"`
+	MaxUses       int         `json:"max_uses"` // 0 = không giới hạn
+	StartsAt      *time.Time  `json:"starts_at"`
+	EndsAt        *time.Time  `json:"ends_at"`
+	TicketTypeIDs []uuid.UUID `json:"ticket_type_ids" validate:"required,min=1"`
+}
+
+type UnlockPresaleRequest struct {
+	Code string `json:"code" validate:"required"`
+}
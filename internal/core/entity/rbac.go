@@ -0,0 +1,127 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Permission là một quyền thao tác, dạng <tài nguyên>:<hành động>.
+type Permission string
+
+const (
+	PermEventsWrite   Permission = "events:write"        // Tạo/sửa event và cấu hình bán vé (ghế, pool, add-on, presale...)
+	PermReportsRead   Permission = "reports:read"        // Báo cáo doanh số, sử dụng pool
+	PermAttendeesRead Permission = "attendees:read"      // Xuất danh sách người tham dự
+	PermOrdersRead    Permission = "orders:read"         // Tra cứu đơn của khách
+	PermOrdersRefund  Permission = "orders:refund"       // Hoàn tiền đơn
+	PermTicketsScan   Permission = "tickets:scan"        // Soát vé
+	PermBoxOfficeSell Permission = "box_office:sell"     // Bán vé tại quầy
+	PermVenuesWrite   Permission = "venues:write"        // Quản lý địa điểm
+	PermOrgManage     Permission = "organization:manage" // Cấu hình và thành viên của tổ chức
+)
+
+// allPermissions là mọi quyền có trong hệ thống.
+var allPermissions = []Permission{
+	PermEventsWrite, PermReportsRead, PermAttendeesRead, PermOrdersRead, PermOrdersRefund,
+	PermTicketsScan, PermBoxOfficeSell, PermVenuesWrite, PermOrgManage,
+}
+
+// ValidPermission cho biết perm có tồn tại không.
+func ValidPermission(perm Permission) bool {
+	for _, p := range allPermissions {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// rolePermissions là quyền của từng vai trò. Admin có mọi quyền nên không liệt kê.
+var rolePermissions = map[string][]Permission{
+	RoleUser:      nil,
+	RoleOrganizer: {PermOrgManage, PermEventsWrite, PermReportsRead, PermAttendeesRead, PermOrdersRead, PermOrdersRefund, PermTicketsScan, PermBoxOfficeSell},
+	RoleScanner:   {PermTicketsScan},
+	RoleBoxOffice: {PermBoxOfficeSell, PermTicketsScan},
+	RoleSupport:   {PermOrdersRead, PermOrdersRefund},
+}
+
+// ValidRole cho biết role có tồn tại không.
+func ValidRole(role string) bool {
+	if role == RoleAdmin {
+		return true
+	}
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// scopedOnlyRoles là vai trò chỉ có hiệu lực trong event / tổ chức được cấp. Organizer toàn hệ thống
+// sẽ sửa được event của mọi tổ chức nên không được làm role gốc hay cấp GLOBAL.
+var scopedOnlyRoles = map[string]bool{
+	RoleOrganizer: true,
+}
+
+// ScopedOnlyRole cho biết vai trò chỉ được cấp theo event hoặc tổ chức.
+func ScopedOnlyRole(role string) bool {
+	return scopedOnlyRoles[role]
+}
+
+// GlobalRoleHasPermission cho biết vai trò áp dụng toàn hệ thống (role gốc, vai trò cấp GLOBAL) có
+// quyền perm không. Vai trò chỉ cấp theo phạm vi thì không có quyền toàn hệ thống.
+func GlobalRoleHasPermission(role string, perm Permission) bool {
+	return !ScopedOnlyRole(role) && RoleHasPermission(role, perm)
+}
+
+// RoleHasPermission cho biết vai trò có quyền perm không.
+func RoleHasPermission(role string, perm Permission) bool {
+	if role == RoleAdmin {
+		return true
+	}
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// ScopeType là phạm vi áp dụng của một vai trò được cấp.
+type ScopeType string
+
+const (
+	ScopeGlobal       ScopeType = "GLOBAL"       // Toàn hệ thống
+	ScopeEvent        ScopeType = "EVENT"        // Chỉ một event
+	ScopeOrganization ScopeType = "ORGANIZATION" // Mọi event của một tổ chức
+)
+
+// PermissionScope là phạm vi của thao tác cần kiểm tra quyền (ID rỗng với ScopeGlobal).
+type PermissionScope struct {
+	Type ScopeType
+	ID   uuid.UUID
+}
+
+// RoleAssignment là vai trò cấp thêm cho user ngoài role gốc (users.role, luôn áp dụng toàn hệ thống),
+// VD: organizer của riêng một event, scanner cho một đêm diễn, thành viên của một tổ chức.
+type RoleAssignment struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Role      string     `json:"role"`
+	ScopeType ScopeType  `json:"scope_type"`
+	ScopeID   *uuid.UUID `json:"scope_id,omitempty"`
+	GrantedBy *uuid.UUID `json:"granted_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type GrantRoleRequest struct {
+	UserID    uuid.UUID  `json:"user_id" validate:"required"`
+	Role      string     `json:"role" validate:"required"`
+	ScopeType ScopeType  `json:"scope_type" validate:"required"`
+	ScopeID   *uuid.UUID `json:"scope_id"`
+}
+
+// UserRoles là role gốc và các vai trò được cấp thêm của một user.
+type UserRoles struct {
+	UserID      uuid.UUID        `json:"user_id"`
+	Role        string           `json:"role"`
+	Assignments []RoleAssignment `json:"assignments"`
+}
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type ResaleListingStatus string
+
+const (
+	ResaleListingStatusActive    ResaleListingStatus = "ACTIVE"    // Đang rao bán
+	ResaleListingStatusReserved  ResaleListingStatus = "RESERVED"  // Đã có người đặt mua, chờ đơn của người mua thanh toán
+	ResaleListingStatusSold      ResaleListingStatus = "SOLD"      // Người mua đã thanh toán, vé đã sang tên người mua
+	ResaleListingStatusCancelled ResaleListingStatus = "CANCELLED" // Người bán rút lại
+)
+
+// ResaleListing là một vé được người giữ rao bán lại trên sàn chính thức. Giá không vượt quá
+// ResaleCapPercent của event tính trên giá gốc (OrderItem.UnitPrice). Vé chỉ sang tên khi đơn của
+// người mua thanh toán xong, lúc đó người bán được chi SellerPayout = Price - Fee. Nếu đơn của người
+// mua bị hủy trước khi thanh toán, tin rao bán tiếp.
+type ResaleListing struct {
+	ID            uuid.UUID           `gorm:"type:uuid;primary_key;" json:"id"`
+	TicketID      uuid.UUID           `gorm:"type:uuid;not null" json:"ticket_id"`
+	SellerID      uuid.UUID           `gorm:"type:uuid;not null" json:"seller_id"`
+	EventID       uuid.UUID           `gorm:"type:uuid;not null" json:"event_id"`
+	TicketTypeID  uuid.UUID           `gorm:"type:uuid;not null" json:"ticket_type_id"`
+	SeatID        *uuid.UUID          `gorm:"type:uuid" json:"seat_id,omitempty"`
+	Price         decimal.Decimal     `gorm:"type:decimal(10,2);not null" json:"price"`
+	OriginalPrice decimal.Decimal     `gorm:"type:decimal(10,2);not null" json:"original_price"`
+	Fee           decimal.Decimal     `gorm:"type:decimal(10,2);not null;default:0" json:"fee"`           // Phí sàn, tính khi bán
+	SellerPayout  decimal.Decimal     `gorm:"type:decimal(10,2);not null;default:0" json:"seller_payout"` // Tiền hoàn cho người bán
+	Status        ResaleListingStatus `gorm:"type:varchar(20);not null;default:'ACTIVE'" json:"status"`
+	BuyerID       *uuid.UUID          `gorm:"type:uuid" json:"-"`
+	OrderID       *uuid.UUID          `gorm:"type:uuid" json:"order_id,omitempty"` // Đơn của người mua
+	SoldAt        *time.Time          `json:"sold_at,omitempty"`
+	PaidOutAt     *time.Time          `json:"paid_out_at,omitempty"` // Đã chi tiền hoàn cho người bán
+	CreatedAt     time.Time           `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type CreateResaleListingRequest struct {
+	TicketID uuid.UUID       `json:"ticket_id" validate:"required"`
+	Price    decimal.Decimal `json:"price" validate:"required"`
+}
+
+// SetResaleCapRequest đặt trần giá bán lại của event theo % giá gốc (0 = tắt bán lại).
+type SetResaleCapRequest struct {
+	CapPercent int `json:"cap_percent"`
+}
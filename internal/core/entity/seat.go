@@ -0,0 +1,51 @@
+package entity
+
+import "github.com/google/uuid"
+
+type SeatStatus string
+
+const (
+	SeatStatusAvailable SeatStatus = "AVAILABLE"
+	SeatStatusSold      SeatStatus = "SOLD"
+)
+
+// Seat là một ghế cụ thể của một event (vé ngồi theo số).
+// Hạng giá của ghế chính là TicketType mà ghế thuộc về.
+type Seat struct {
+	ID           uuid.UUID  `gorm:"type:uuid;primary_key;" json:"id"`
+	EventID      uuid.UUID  `gorm:"type:uuid;not null" json:"event_id"`
+	TicketTypeID uuid.UUID  `gorm:"type:uuid;not null" json:"ticket_type_id"`
+	SectionID    *uuid.UUID `gorm:"type:uuid" json:"section_id,omitempty"`
+	RowLabel     string     `gorm:"type:varchar(10);not null" json:"row"`
+	Number       int        `gorm:"column:seat_number;not null" json:"number"`
+	X            float64    `gorm:"type:decimal(8,2);not null;default:0" json:"x"` // Tọa độ vẽ sơ đồ
+	Y            float64    `gorm:"type:decimal(8,2);not null;default:0" json:"y"` // Y nhỏ = gần sân khấu
+	Status       SeatStatus `gorm:"type:varchar(20);not null;default:'AVAILABLE'" json:"status"`
+	OrderItemID  *uuid.UUID `gorm:"type:uuid" json:"-"`
+}
+
+type CreateSeatRequest struct {
+	TicketTypeID uuid.UUID  `json:"ticket_type_id" validate:"required"`
+	SectionID    *uuid.UUID `json:"section_id"`
+	Row          string     `json:"row" validate:"required"`
+	Number       int        `json:"number" validate:"required,min=1"`
+	X            float64    `json:"x"`
+	Y            float64    `json:"y"`
+}
+
+// SeatMap là sơ đồ ghế của event, gom theo khu -> hàng.
+type SeatMap struct {
+	EventID  uuid.UUID        `json:"event_id"`
+	Sections []SeatMapSection `json:"sections"`
+}
+
+type SeatMapSection struct {
+	SectionID *uuid.UUID   `json:"section_id,omitempty"`
+	Name      string       `json:"name"`
+	Rows      []SeatMapRow `json:"rows"`
+}
+
+type SeatMapRow struct {
+	Label string `json:"label"`
+	Seats []Seat `json:"seats"`
+}
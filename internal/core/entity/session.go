@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SessionStatus string
+
+const (
+	SessionStatusScheduled SessionStatus = "SCHEDULED"
+	SessionStatusCancelled SessionStatus = "CANCELLED"
+)
+
+// EventSession là một suất diễn của event (VD: vở kịch diễn mỗi tối trong 2 tuần).
+// Mỗi suất có loại vé và tồn kho riêng (TicketType.SessionID).
+type EventSession struct {
+	ID          uuid.UUID     `gorm:"type:uuid;primary_key;" json:"id"`
+	EventID     uuid.UUID     `gorm:"type:uuid;not null" json:"event_id"`
+	StartTime   time.Time     `gorm:"not null" json:"start_time"`
+	EndTime     time.Time     `gorm:"not null" json:"end_time"`
+	Status      SessionStatus `gorm:"type:varchar(20);not null;default:'SCHEDULED'" json:"status"`
+	CreatedAt   time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+	Event       *Event        `gorm:"foreignKey:EventID" json:"event,omitempty"`
+	TicketTypes []TicketType  `gorm:"foreignKey:SessionID" json:"ticket_types,omitempty"`
+}
+
+type RecurrenceFrequency string
+
+const (
+	RecurrenceDaily  RecurrenceFrequency = "DAILY"
+	RecurrenceWeekly RecurrenceFrequency = "WEEKLY"
+)
+
+// RecurrenceRule là luật lặp đơn giản kiểu RRULE: lặp theo ngày/tuần,
+// dừng khi đủ Count suất hoặc qua mốc Until (phải có ít nhất một trong hai).
+type RecurrenceRule struct {
+	Frequency RecurrenceFrequency `json:"frequency"`
+	Interval  int                 `json:"interval,omitempty"` // Mặc định 1 (mỗi ngày / mỗi tuần)
+	Weekdays  []time.Weekday      `json:"weekdays,omitempty"` // Chỉ dùng cho WEEKLY, 0 = Chủ nhật
+	Count     int                 `json:"count,omitempty"`
+	Until     *time.Time          `json:"until,omitempty"`
+}
+
+type CreateSessionsRequest struct {
+	StartTime       time.Time                 `json:"start_time" validate:"required"` // Giờ bắt đầu suất đầu tiên
+	DurationMinutes int                       `json:"duration_minutes" validate:"required,min=1"`
+	Recurrence      RecurrenceRule            `json:"recurrence"`
+	TicketTypes     []CreateTicketTypeRequest `json:"ticket_types" validate:"required,min=1"` // Mẫu vé, nhân bản cho từng suất
+}
+
+type SessionFilter struct {
+	EventID *uuid.UUID
+	From    *time.Time
+	To      *time.Time
+	Limit   int
+	Offset  int
+}
@@ -0,0 +1,38 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TicketStatus string
+
+const (
+	TicketStatusUnused TicketStatus = "UNUSED"
+	TicketStatusUsed   TicketStatus = "USED"
+)
+
+// Ticket là vé đã phát hành cho một đơn (mỗi đơn vị số lượng = một vé, có mã QR riêng).
+// Vé combo (bundle) phát hành một vé cho mỗi loại vé thành phần.
+type Ticket struct {
+	ID           uuid.UUID    `gorm:"type:uuid;primary_key;" json:"id"`
+	OrderID      uuid.UUID    `gorm:"type:uuid;not null" json:"order_id"`
+	OrderItemID  uuid.UUID    `gorm:"type:uuid;not null" json:"order_item_id"`
+	TicketTypeID uuid.UUID    `gorm:"type:uuid;not null" json:"ticket_type_id"`
+	SeatID       *uuid.UUID   `gorm:"type:uuid" json:"seat_id,omitempty"`
+	TicketCode   string       `gorm:"type:varchar(50);uniqueIndex;not null" json:"ticket_code"`
+	Status       TicketStatus `gorm:"type:ticket_status;not null;default:'UNUSED'" json:"status"`
+	OwnerID      *uuid.UUID   `gorm:"type:uuid" json:"owner_id,omitempty"`           // Người đang giữ vé (người mua, hoặc người nhận chuyển nhượng)
+	OwnerName    string       `gorm:"type:varchar(100)" json:"owner_name,omitempty"` // Tên người tham dự in trên vé
+	CreatedAt    time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
+
+	// Answers là câu trả lời câu hỏi người tham dự của event, theo AttendeeQuestion.ID
+	Answers map[string]string `gorm:"type:jsonb;serializer:json" json:"answers,omitempty"`
+}
+
+// CheckInRequest là mã vé nhân viên soát vé quét tại cổng.
+type CheckInRequest struct {
+	TicketCode string `json:"ticket_code" validate:"required"`
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken là một refresh token đã phát (chỉ lưu hash). Mỗi lần refresh token cũ bị thu hồi và
+// thay bằng token mới cùng FamilyID. Token đã thu hồi mà bị dùng lại nghĩa là bị lộ: thu hồi cả họ.
+type RefreshToken struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	FamilyID   uuid.UUID  `json:"family_id"` // Chung cho cả chuỗi token sinh ra từ một lần đăng nhập
+	TokenHash  string     `json:"-"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	ReplacedBy *uuid.UUID `json:"replaced_by,omitempty"` // Token mới thay thế khi refresh
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TokenPair là cặp token trả về khi đăng nhập / refresh.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"` // Số giây access token còn hiệu lực
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// UserTokenPurpose là mục đích của token dùng một lần gửi qua email.
+type UserTokenPurpose string
+
+const (
+	TokenPurposePasswordReset UserTokenPurpose = "PASSWORD_RESET"
+	TokenPurposeVerifyEmail   UserTokenPurpose = "VERIFY_EMAIL"
+	TokenPurposeMFAChallenge  UserTokenPurpose = "MFA_CHALLENGE" // Đăng nhập đúng mật khẩu, chờ mã TOTP
+	TokenPurposeEmailChange   UserTokenPurpose = "EMAIL_CHANGE"  // Xác nhận email mới (users.pending_email)
+)
+
+// UserToken là token dùng một lần, có hạn, gửi qua email (đặt lại mật khẩu, xác thực email).
+// Chỉ lưu hash; phát token mới thì token cũ cùng mục đích của user hết hiệu lực.
+type UserToken struct {
+	ID        uuid.UUID        `json:"id"`
+	UserID    uuid.UUID        `json:"user_id"`
+	Purpose   UserTokenPurpose `json:"purpose"`
+	TokenHash string           `json:"-"`
+	ExpiresAt time.Time        `json:"expires_at"`
+	UsedAt    *time.Time       `json:"used_at,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
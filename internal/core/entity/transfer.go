@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TicketTransferStatus string
+
+const (
+	TicketTransferStatusPending   TicketTransferStatus = "PENDING"   // Chờ người nhận chấp nhận
+	TicketTransferStatusAccepted  TicketTransferStatus = "ACCEPTED"  // Vé đã sang tên, mã cũ bị hủy
+	TicketTransferStatusCancelled TicketTransferStatus = "CANCELLED" // Người gửi hủy hoặc người nhận từ chối
+)
+
+// TicketTransfer là một lần chuyển nhượng vé cho người nhận theo email. Người nhận chấp nhận
+// bằng tài khoản có đúng email đó (chưa có thì đăng ký rồi chấp nhận). Khi chấp nhận, vé đổi
+// chủ và được cấp mã mới; mã cũ được lưu lại trong lịch sử và không còn dùng được.
+type TicketTransfer struct {
+	ID            uuid.UUID            `gorm:"type:uuid;primary_key;" json:"id"`
+	TicketID      uuid.UUID            `gorm:"type:uuid;not null" json:"ticket_id"`
+	FromUserID    uuid.UUID            `gorm:"type:uuid;not null" json:"from_user_id"`
+	ToEmail       string               `gorm:"type:varchar(255);not null" json:"to_email"` // Lưu dạng chữ thường
+	ToUserID      *uuid.UUID           `gorm:"type:uuid" json:"to_user_id,omitempty"`      // Có khi người nhận chấp nhận
+	Status        TicketTransferStatus `gorm:"type:varchar(20);not null;default:'PENDING'" json:"status"`
+	OldTicketCode string               `gorm:"type:varchar(50)" json:"-"` // Mã vé bị hủy khi sang tên
+	AcceptedAt    *time.Time           `json:"accepted_at,omitempty"`
+	CreatedAt     time.Time            `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time            `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type CreateTicketTransferRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// SetTransfersRequest bật/tắt chuyển nhượng cho event hoặc loại vé.
+type SetTransfersRequest struct {
+	Disabled bool `json:"disabled"`
+}
@@ -7,8 +7,12 @@ import (
 )
 
 const (
-	RoleAdmin = "admin"
-	RoleUser  = "user"
+	RoleAdmin     = "admin"
+	RoleUser      = "user"
+	RoleBoxOffice = "box_office" // Nhân viên bán vé tại quầy
+	RoleOrganizer = "organizer"  // Ban tổ chức: quản lý event, xem báo cáo, người tham dự (chỉ cấp theo event / tổ chức)
+	RoleScanner   = "scanner"    // Nhân viên soát vé cổng vào
+	RoleSupport   = "support"    // Chăm sóc khách hàng: tra cứu, hoàn tiền đơn
 )
 
 type User struct {
@@ -18,13 +22,44 @@ type User struct {
 	PasswordHash string                 `json:"-"`
 	Role         string                 `json:"role"`
 	ProfileData  map[string]interface{} `json:"profile_data"`
-	CreatedAt    time.Time              `json:"created_at"`
-	UpdatedAt    time.Time              `json:"updated_at"`
+	// Thời điểm user xác nhận sở hữu email qua link đã gửi, nil = chưa xác thực
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	// Email mới đang chờ xác nhận qua link gửi tới chính địa chỉ đó, nil = không đổi email
+	PendingEmail *string   `json:"pending_email,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Các trường hồ sơ được kiểm tra trong profile_data (các key khác giữ nguyên)
+const (
+	ProfileFullName  = "full_name"
+	ProfilePhone     = "phone"
+	ProfileBirthDate = "birth_date" // YYYY-MM-DD
+	ProfileAvatarURL = "avatar_url"
+)
+
+// UpdateProfileRequest là body PATCH /user/me: trường bỏ qua (nil) giữ nguyên, chuỗi rỗng = xóa.
+type UpdateProfileRequest struct {
+	FullName  *string `json:"full_name"`
+	Phone     *string `json:"phone"`
+	BirthDate *string `json:"birth_date"`
+	AvatarURL *string `json:"avatar_url"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=6"`
+}
+
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
 }
 
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
+	IP       string `json:"-"` // IP client, handler điền để chống dò mật khẩu
 }
 
 type RegisterRequest struct {
@@ -32,3 +67,16 @@ type RegisterRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=6"`
 }
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=6"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
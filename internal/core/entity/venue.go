@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Venue struct {
+	ID        uuid.UUID      `gorm:"type:uuid;primary_key;" json:"id"`
+	Name      string         `gorm:"not null" json:"name"`
+	Address   string         `gorm:"type:varchar(500)" json:"address"`
+	Timezone  string         `gorm:"type:varchar(64);not null;default:'Asia/Ho_Chi_Minh'" json:"timezone"`
+	Latitude  float64        `gorm:"type:decimal(9,6)" json:"latitude"`
+	Longitude float64        `gorm:"type:decimal(9,6)" json:"longitude"`
+	Capacity  int            `gorm:"not null" json:"capacity"` // Sức chứa tổng
+	Sections  []VenueSection `gorm:"foreignKey:VenueID;constraint:OnDelete:CASCADE;" json:"sections"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// VenueSection là một khu trong địa điểm (VD: Khán đài A, Sân cỏ), có sức chứa riêng.
+type VenueSection struct {
+	ID       uuid.UUID `gorm:"type:uuid;primary_key;" json:"id"`
+	VenueID  uuid.UUID `gorm:"type:uuid;not null" json:"venue_id"`
+	Name     string    `gorm:"not null" json:"name"`
+	Capacity int       `gorm:"not null" json:"capacity"`
+}
+
+type CreateVenueRequest struct {
+	Name      string                      `json:"name" validate:"required,min=3"`
+	Address   string                      `json:"address"`
+	Timezone  string                      `json:"timezone"`
+	Latitude  float64                     `json:"latitude"`
+	Longitude float64                     `json:"longitude"`
+	Capacity  int                         `json:"capacity" validate:"required,min=1"`
+	Sections  []CreateVenueSectionRequest `json:"sections"`
+}
+
+type CreateVenueSectionRequest struct {
+	Name     string `json:"name" validate:"required"`
+	Capacity int    `json:"capacity" validate:"required,min=1"`
+}
+
+type UpdateVenueRequest struct {
+	Name      string  `json:"name" validate:"required,min=3"`
+	Address   string  `json:"address"`
+	Timezone  string  `json:"timezone"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Capacity  int     `json:"capacity" validate:"required,min=1"`
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WaitlistStatus string
+
+const (
+	WaitlistStatusWaiting   WaitlistStatus = "WAITING"   // Đang xếp hàng
+	WaitlistStatusOffered   WaitlistStatus = "OFFERED"   // Đang được giữ vé, chờ user xác nhận
+	WaitlistStatusClaimed   WaitlistStatus = "CLAIMED"   // Đã nhận vé (tạo đơn)
+	WaitlistStatusExpired   WaitlistStatus = "EXPIRED"   // Hết hạn giữ vé mà không nhận
+	WaitlistStatusCancelled WaitlistStatus = "CANCELLED" // User tự rời hàng chờ
+)
+
+// WaitlistEntry là một lượt xếp hàng chờ vé của user cho một loại vé đã hết.
+// Khi có vé trả lại, entry đầu hàng (FIFO) được giữ riêng Quantity vé tới OfferExpiresAt.
+type WaitlistEntry struct {
+	ID             uuid.UUID      `gorm:"type:uuid;primary_key;" json:"id"`
+	TicketTypeID   uuid.UUID      `gorm:"type:uuid;not null" json:"ticket_type_id"`
+	UserID         uuid.UUID      `gorm:"type:uuid;not null" json:"user_id"`
+	Quantity       int            `gorm:"not null" json:"quantity"`
+	Status         WaitlistStatus `gorm:"type:varchar(20);not null;default:'WAITING'" json:"status"`
+	OfferExpiresAt *time.Time     `json:"offer_expires_at,omitempty"`
+	OrderID        *uuid.UUID     `gorm:"type:uuid" json:"order_id,omitempty"` // Đơn tạo ra khi nhận vé
+	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type JoinWaitlistRequest struct {
+	TicketTypeID uuid.UUID `json:"ticket_type_id" validate:"required"`
+	Quantity     int       `json:"quantity" validate:"required,min=1"`
+}
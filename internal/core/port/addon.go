@@ -0,0 +1,23 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+type AddOnRepositoryPort interface {
+	CreateAddOn(ctx context.Context, addOn *entity.AddOn) error
+	ListAddOnsByEvent(ctx context.Context, eventID uuid.UUID) ([]entity.AddOn, error)
+	// SalesByKind gộp doanh số của event theo loại item (đơn đã hủy không tính)
+	SalesByKind(ctx context.Context, eventID uuid.UUID) ([]entity.ItemKindSales, error)
+	// AddOnSales trả về doanh số từng add-on của event
+	AddOnSales(ctx context.Context, eventID uuid.UUID) ([]entity.AddOnSales, error)
+}
+
+type AddOnServicePort interface {
+	CreateAddOn(ctx context.Context, eventID uuid.UUID, req entity.CreateAddOnRequest) (*entity.AddOn, error)
+	ListAddOns(ctx context.Context, eventID uuid.UUID) ([]entity.AddOn, error)
+	GetSalesReport(ctx context.Context, eventID uuid.UUID) (*entity.SalesReport, error)
+}
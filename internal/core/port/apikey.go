@@ -0,0 +1,34 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+// APIKeyRepositoryPort lưu API key (chỉ hash) và bộ đếm giới hạn tần suất của từng khóa.
+type APIKeyRepositoryPort interface {
+	CreateKey(ctx context.Context, key *entity.APIKey) error
+	GetKey(ctx context.Context, id uuid.UUID) (*entity.APIKey, error)
+	ListKeysByUser(ctx context.Context, userID uuid.UUID) ([]entity.APIKey, error)
+	CountActiveKeys(ctx context.Context, userID uuid.UUID, now time.Time) (int, error)
+	// RevokeKey trả về false nếu khóa không tồn tại hoặc đã thu hồi.
+	RevokeKey(ctx context.Context, id uuid.UUID, at time.Time) (bool, error)
+	// ExpireKey rút ngắn hạn dùng của khóa về at (không kéo dài nếu khóa đã hết hạn sớm hơn).
+	ExpireKey(ctx context.Context, id uuid.UUID, at time.Time) error
+	// UseKey tìm khóa còn hiệu lực theo hash, ghi lần dùng cuối và đếm request trong cửa sổ bắt đầu từ sau windowStart
+	// (nguyên tử, đúng cả khi nhiều instance). Khóa không hợp lệ trả về sql.ErrNoRows.
+	UseKey(ctx context.Context, keyHash string, ip string, windowStart time.Time) (*entity.APIKeyUsage, error)
+}
+
+type APIKeyServicePort interface {
+	CreateKey(ctx context.Context, userID uuid.UUID, req entity.CreateAPIKeyRequest) (*entity.CreatedAPIKey, error)
+	ListKeys(ctx context.Context, userID uuid.UUID) ([]entity.APIKey, error)
+	// RotateKey cấp khóa mới cùng cấu hình thay cho khóa cũ. Chủ khóa hoặc admin mới được xoay / thu hồi.
+	RotateKey(ctx context.Context, userID uuid.UUID, role string, keyID uuid.UUID, req entity.RotateAPIKeyRequest) (*entity.CreatedAPIKey, error)
+	RevokeKey(ctx context.Context, userID uuid.UUID, role string, keyID uuid.UUID) error
+	// Authenticate xác thực khóa thô trong header, trả *service.APIKeyRateLimitedError khi vượt giới hạn tần suất.
+	Authenticate(ctx context.Context, key string, ip string) (*entity.APIKeyIdentity, error)
+}
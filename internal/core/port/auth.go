@@ -2,6 +2,9 @@ package port
 
 import (
 	"context"
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/yourname/ticketing-system/internal/core/entity"
 )
 
@@ -9,10 +12,88 @@ type UserRepositoryPort interface {
 	CreateUser(ctx context.Context, user *entity.User) error
 	GetUserByEmail(ctx context.Context, email string) (*entity.User, error)
 	GetUserByID(ctx context.Context, id string) (*entity.User, error)
+	UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error
+	// MarkEmailVerified ghi nhận user đã xác thực email (giữ nguyên thời điểm nếu đã xác thực trước đó).
+	MarkEmailVerified(ctx context.Context, userID uuid.UUID) error
+	UpdateProfileData(ctx context.Context, userID uuid.UUID, data map[string]interface{}) error
+	SetPendingEmail(ctx context.Context, userID uuid.UUID, email string) error
+	// ApplyPendingEmail đổi email sang pending_email và đánh dấu đã xác thực; trả về false nếu không có email chờ.
+	ApplyPendingEmail(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// TokenRepositoryPort lưu refresh token (dạng hash) và danh sách access token đã thu hồi theo jti.
+type TokenRepositoryPort interface {
+	CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*entity.RefreshToken, error)
+	// RotateRefreshToken thu hồi token cũ và lưu token mới trong một transaction.
+	// Trả về false nếu token cũ đã bị thu hồi trước đó (bị dùng lại / refresh đồng thời).
+	RotateRefreshToken(ctx context.Context, oldID uuid.UUID, next *entity.RefreshToken) (bool, error)
+	RevokeRefreshFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+	// RevokeUserRefreshTokens thu hồi mọi phiên đăng nhập của user (VD: sau khi đặt lại mật khẩu).
+	RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error
+	// CreateUserToken lưu token dùng một lần mới và vô hiệu token chưa dùng cùng mục đích của user.
+	CreateUserToken(ctx context.Context, token *entity.UserToken) error
+	// GetUserToken trả về token còn hạn, chưa dùng theo hash và mục đích (không đánh dấu đã dùng).
+	GetUserToken(ctx context.Context, hash string, purpose entity.UserTokenPurpose) (*entity.UserToken, error)
+	// ConsumeUserToken đánh dấu đã dùng token còn hạn theo hash và mục đích, nguyên tử (chỉ một lần thành công).
+	ConsumeUserToken(ctx context.Context, hash string, purpose entity.UserTokenPurpose) (*entity.UserToken, error)
+	PurgeExpiredTokens(ctx context.Context, before time.Time) (int64, error)
+}
+
+// LoginThrottleRepositoryPort lưu bộ đếm đăng nhập sai theo email / IP.
+type LoginThrottleRepositoryPort interface {
+	// GetThrottle trả về nil (không lỗi) nếu chưa có lần sai nào.
+	GetThrottle(ctx context.Context, kind entity.LoginThrottleKind, key string) (*entity.LoginThrottle, error)
+	// RecordFailure tăng bộ đếm, bắt đầu lại từ 1 nếu lần sai trước cũ hơn windowStart.
+	RecordFailure(ctx context.Context, kind entity.LoginThrottleKind, key string, windowStart time.Time) (*entity.LoginThrottle, error)
+	SetLockedUntil(ctx context.Context, kind entity.LoginThrottleKind, key string, until time.Time) error
+	ResetThrottle(ctx context.Context, kind entity.LoginThrottleKind, key string) error
+	ListLocked(ctx context.Context, now time.Time) ([]entity.LoginThrottle, error)
+	PurgeThrottles(ctx context.Context, before time.Time) (int64, error)
+}
+
+// MFARepositoryPort lưu ứng dụng xác thực TOTP và mã khôi phục (dạng hash) của user.
+type MFARepositoryPort interface {
+	// GetTOTP trả về nil (không lỗi) nếu user chưa đăng ký.
+	GetTOTP(ctx context.Context, userID uuid.UUID) (*entity.UserTOTP, error)
+	// SaveTOTPSecret lưu secret mới cho lần đăng ký dở; trả về false nếu user đã bật 2FA.
+	SaveTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) (bool, error)
+	// EnableTOTP bật 2FA (ghi nhận bước đã dùng) và thay toàn bộ mã khôi phục trong một transaction.
+	EnableTOTP(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) error
+	// UseTOTPStep ghi nhận bước vừa dùng, trả về false nếu bước này (hoặc mới hơn) đã dùng rồi.
+	UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	// UseRecoveryCode đánh dấu đã dùng mã khôi phục, trả về false nếu mã không tồn tại hoặc đã dùng.
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+	DeleteTOTP(ctx context.Context, userID uuid.UUID) error
 }
 
 type AuthServicePort interface {
 	Register(ctx context.Context, req entity.RegisterRequest) (*entity.User, error)
-	Login(ctx context.Context, req entity.LoginRequest) (string, error)
+	Login(ctx context.Context, req entity.LoginRequest) (*entity.LoginResult, error)
+	VerifyMFALogin(ctx context.Context, req entity.MFALoginRequest) (*entity.MFALoginResponse, error)
+	Refresh(ctx context.Context, refreshToken string) (*entity.TokenPair, error)
+	Logout(ctx context.Context, userID uuid.UUID, jti string, accessExpiresAt time.Time, refreshToken string) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	PurgeExpiredTokens(ctx context.Context) (int64, error)
 	ValidateToken(ctx context.Context, token string) (*entity.User, error)
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, req entity.ResetPasswordRequest) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, userID uuid.UUID) error
+	IsEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error)
+	ListLockouts(ctx context.Context) ([]entity.LoginThrottle, error)
+	ClearLockout(ctx context.Context, kind entity.LoginThrottleKind, key string) error
+	BeginMFAEnrollment(ctx context.Context, userID uuid.UUID) (*entity.MFAEnrollment, error)
+	BeginMFAEnrollmentWithChallenge(ctx context.Context, challengeToken string) (*entity.MFAEnrollment, error)
+	ConfirmMFAEnrollment(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	DisableMFA(ctx context.Context, userID uuid.UUID, code string) error
+	GetProfile(ctx context.Context, userID uuid.UUID) (*entity.User, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, req entity.UpdateProfileRequest) (*entity.User, error)
+	ChangePassword(ctx context.Context, userID uuid.UUID, req entity.ChangePasswordRequest) error
+	RequestEmailChange(ctx context.Context, userID uuid.UUID, req entity.ChangeEmailRequest) error
+	ConfirmEmailChange(ctx context.Context, token string) error
 }
\ No newline at end of file
@@ -0,0 +1,20 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+type BundleRepositoryPort interface {
+	CreateBundle(ctx context.Context, bundle *entity.Bundle) error
+	GetBundleByID(ctx context.Context, id uuid.UUID) (*entity.Bundle, error)
+	ListBundles(ctx context.Context, limit int, offset int) ([]entity.Bundle, error)
+}
+
+type BundleServicePort interface {
+	CreateBundle(ctx context.Context, req entity.CreateBundleRequest) (*entity.Bundle, error)
+	GetBundle(ctx context.Context, id uuid.UUID) (*entity.Bundle, error)
+	ListBundles(ctx context.Context, limit int, offset int) ([]entity.Bundle, error)
+}
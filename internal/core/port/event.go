@@ -8,14 +8,22 @@ import (
 )
 
 type EventRepositoryPort interface {
+	// ForOrganization trả về repository chỉ thấy event (và vé, đơn hàng) của một tổ chức.
+	// Mọi truy vấn của bản sao này đều lọc theo tổ chức, event ngoài tổ chức coi như không tồn tại.
+	ForOrganization(orgID uuid.UUID) EventRepositoryPort
 	CreateEvent(ctx context.Context, event *entity.Event) error
 	GetEventByID(ctx context.Context, id uuid.UUID) (*entity.Event, error)
 	GetEventBySlug(ctx context.Context, slug string) (*entity.Event, error)
-	ListEvents(ctx context.Context, limit int, offset int) ([]entity.Event, error)
+	ListEvents(ctx context.Context, filter entity.EventFilter) ([]entity.Event, int64, error)
 	UpdateEvent(ctx context.Context, event *entity.Event) error
 	DeleteEvent(ctx context.Context, id uuid.UUID) error
 	CreateTicketType(ctx context.Context, ticketType *entity.TicketType) error
 	CreateTicketTypes(ctx context.Context, ticketTypes []entity.TicketType) error
+	ListTicketTypesByEvent(ctx context.Context, eventID uuid.UUID) ([]entity.TicketType, error)
+	GetTicketTypesByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.TicketType, error)
+	// ListOrdersByEvent lấy đơn có item thuộc event (chỉ kèm item và vé của event đó), mới nhất trước,
+	// phân trang theo keyset (created_at, id).
+	ListOrdersByEvent(ctx context.Context, eventID uuid.UUID, cursor *entity.PageCursor, limit int) ([]entity.Order, error)
 }
 
 type EventServicePort interface {
@@ -23,5 +31,5 @@ type EventServicePort interface {
 	CreateEventWithTickets(ctx context.Context, eventReq entity.CreateEventRequest, ticketTypes []entity.CreateTicketTypeRequest) (*entity.Event, error)
 	GetEvent(ctx context.Context, id uuid.UUID) (*entity.Event, error)
 	GetEventBySlug(ctx context.Context, slug string) (*entity.Event, error)
-	ListEvents(ctx context.Context, limit int, offset int) ([]entity.Event, error)
+	ListEvents(ctx context.Context, filter entity.EventFilter) (*entity.EventPage, error)
 }
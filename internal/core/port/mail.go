@@ -0,0 +1,8 @@
+package port
+
+import "context"
+
+// MailerPort gửi email tới một địa chỉ (xác thực email, đặt lại mật khẩu...).
+type MailerPort interface {
+	Send(ctx context.Context, to string, subject string, body string) error
+}
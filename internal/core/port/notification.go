@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// NotifierPort gửi thông báo tới user (email, push...). Lỗi gửi không làm fail nghiệp vụ.
+type NotifierPort interface {
+	Notify(ctx context.Context, userID uuid.UUID, subject string, message string) error
+}
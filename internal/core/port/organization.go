@@ -0,0 +1,35 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+type OrganizationRepositoryPort interface {
+	CreateOrganization(ctx context.Context, org *entity.Organization) error
+	GetOrganizationByID(ctx context.Context, id uuid.UUID) (*entity.Organization, error)
+	GetOrganizationBySlug(ctx context.Context, slug string) (*entity.Organization, error)
+	ListOrganizations(ctx context.Context) ([]entity.Organization, error)
+	// ListOrganizationsByMember lấy các tổ chức mà user có ít nhất một vai trò.
+	ListOrganizationsByMember(ctx context.Context, userID uuid.UUID) ([]entity.Organization, error)
+	UpdateOrganization(ctx context.Context, org *entity.Organization) error
+}
+
+type OrganizationServicePort interface {
+	CreateOrganization(ctx context.Context, createdBy uuid.UUID, req entity.CreateOrganizationRequest) (*entity.Organization, error)
+	GetOrganization(ctx context.Context, id uuid.UUID) (*entity.Organization, error)
+	// ListOrganizations trả về mọi tổ chức với admin, còn lại là các tổ chức user là thành viên.
+	ListOrganizations(ctx context.Context, userID uuid.UUID, role string) ([]entity.Organization, error)
+	UpdateSettings(ctx context.Context, id uuid.UUID, settings entity.OrganizationSettings) (*entity.Organization, error)
+
+	ListMembers(ctx context.Context, orgID uuid.UUID) ([]entity.OrganizationMember, error)
+	AddMember(ctx context.Context, grantedBy, orgID uuid.UUID, req entity.AddMemberRequest) (*entity.RoleAssignment, error)
+	// RemoveMember thu hồi vai trò của user trong tổ chức (role rỗng = mọi vai trò).
+	RemoveMember(ctx context.Context, orgID, userID uuid.UUID, role string) error
+
+	CreateEvent(ctx context.Context, orgID uuid.UUID, req entity.CreateEventRequest, ticketTypes []entity.CreateTicketTypeRequest) (*entity.Event, error)
+	ListEvents(ctx context.Context, orgID uuid.UUID, filter entity.EventFilter) (*entity.EventPage, error)
+	ListEventOrders(ctx context.Context, orgID, eventID uuid.UUID, cursor *entity.PageCursor, limit int) (*entity.OrderPage, error)
+}
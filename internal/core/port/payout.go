@@ -0,0 +1,14 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// PayoutPort chi tiền cho user (chuyển khoản, ví...). reference là id nghiệp vụ (VD: tin bán lại)
+// để cổng chi tiền bỏ qua lệnh trùng khi gọi lại.
+type PayoutPort interface {
+	Payout(ctx context.Context, userID uuid.UUID, reference uuid.UUID, amount decimal.Decimal) error
+}
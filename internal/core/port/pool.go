@@ -0,0 +1,19 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+type PoolRepositoryPort interface {
+	// CreatePool tạo pool, gắn các loại vé vào và trừ sẵn số vé các loại đó đã bán
+	CreatePool(ctx context.Context, pool *entity.CapacityPool, ticketTypeIDs []uuid.UUID) error
+	ListPoolsByEvent(ctx context.Context, eventID uuid.UUID) ([]entity.CapacityPool, error)
+}
+
+type PoolServicePort interface {
+	CreatePool(ctx context.Context, eventID uuid.UUID, req entity.CreateCapacityPoolRequest) (*entity.CapacityPool, error)
+	GetPoolReport(ctx context.Context, eventID uuid.UUID) ([]entity.PoolReport, error)
+}
@@ -0,0 +1,24 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+type PresaleRepositoryPort interface {
+	CreateCode(ctx context.Context, code *entity.PresaleCode) error
+	ListCodesByEvent(ctx context.Context, eventID uuid.UUID) ([]entity.PresaleCode, error)
+	// GetCodeByValue tìm mã theo event và giá trị đã chuẩn hóa (in hoa)
+	GetCodeByValue(ctx context.Context, eventID uuid.UUID, code string) (*entity.PresaleCode, error)
+	// HasRedeemed kiểm tra user đã dùng mã này cho một đơn còn hiệu lực chưa
+	HasRedeemed(ctx context.Context, codeID, userID uuid.UUID) (bool, error)
+}
+
+type PresaleServicePort interface {
+	CreateCode(ctx context.Context, eventID uuid.UUID, req entity.CreatePresaleCodeRequest) (*entity.PresaleCode, error)
+	ListCodes(ctx context.Context, eventID uuid.UUID) ([]entity.PresaleCode, error)
+	// Unlock trả về các loại vé mà mã mở khóa cho user (mã phải còn hiệu lực và user chưa dùng)
+	Unlock(ctx context.Context, userID, eventID uuid.UUID, code string) ([]entity.TicketType, error)
+}
@@ -0,0 +1,45 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+// RoleRepositoryPort lưu vai trò cấp thêm theo phạm vi và tra event của tài nguyên con để xác định phạm vi.
+type RoleRepositoryPort interface {
+	CreateAssignment(ctx context.Context, assignment *entity.RoleAssignment) error
+	// DeleteAssignment trả về false nếu không tìm thấy.
+	DeleteAssignment(ctx context.Context, id uuid.UUID) (bool, error)
+	ListAssignmentsByUser(ctx context.Context, userID uuid.UUID) ([]entity.RoleAssignment, error)
+	// ListScopeAssignments lấy mọi vai trò đã cấp trong một phạm vi (VD: thành viên của tổ chức).
+	ListScopeAssignments(ctx context.Context, scopeType entity.ScopeType, scopeID uuid.UUID) ([]entity.RoleAssignment, error)
+	// DeleteScopeAssignments thu hồi vai trò của user trong một phạm vi (role rỗng = mọi vai trò), trả về số dòng đã xóa.
+	DeleteScopeAssignments(ctx context.Context, userID uuid.UUID, scopeType entity.ScopeType, scopeID uuid.UUID, role string) (int64, error)
+	EventExists(ctx context.Context, eventID uuid.UUID) (bool, error)
+	OrganizationExists(ctx context.Context, orgID uuid.UUID) (bool, error)
+	// OrganizationIDForEvent trả về tổ chức sở hữu event (nil = event của nền tảng).
+	OrganizationIDForEvent(ctx context.Context, eventID uuid.UUID) (*uuid.UUID, error)
+	EventIDForTicketType(ctx context.Context, ticketTypeID uuid.UUID) (uuid.UUID, error)
+	EventIDForAllocation(ctx context.Context, allocationID uuid.UUID) (uuid.UUID, error)
+	EventIDForAddOn(ctx context.Context, addOnID uuid.UUID) (uuid.UUID, error)
+	EventIDForBallot(ctx context.Context, ballotID uuid.UUID) (uuid.UUID, error)
+	// EventIDsForBundle trả về các event của loại vé trong gói (gói có thể gồm nhiều event).
+	EventIDsForBundle(ctx context.Context, bundleID uuid.UUID) ([]uuid.UUID, error)
+	// EventIDsForOrder trả về các event có vé / gói / add-on trong đơn.
+	EventIDsForOrder(ctx context.Context, orderID uuid.UUID) ([]uuid.UUID, error)
+}
+
+type AccessServicePort interface {
+	HasPermission(ctx context.Context, userID uuid.UUID, role string, perm entity.Permission, scope entity.PermissionScope) (bool, error)
+	EventIDForTicketType(ctx context.Context, ticketTypeID uuid.UUID) (uuid.UUID, error)
+	EventIDForAllocation(ctx context.Context, allocationID uuid.UUID) (uuid.UUID, error)
+	EventIDForAddOn(ctx context.Context, addOnID uuid.UUID) (uuid.UUID, error)
+	EventIDForBallot(ctx context.Context, ballotID uuid.UUID) (uuid.UUID, error)
+	EventIDsForBundle(ctx context.Context, bundleID uuid.UUID) ([]uuid.UUID, error)
+	EventIDsForOrder(ctx context.Context, orderID uuid.UUID) ([]uuid.UUID, error)
+	GrantRole(ctx context.Context, grantedBy uuid.UUID, req entity.GrantRoleRequest) (*entity.RoleAssignment, error)
+	RevokeRole(ctx context.Context, assignmentID uuid.UUID) error
+	ListUserRoles(ctx context.Context, userID uuid.UUID) (*entity.UserRoles, error)
+}
@@ -0,0 +1,21 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+type SeatRepositoryPort interface {
+	// CreateSeats lưu ghế và đánh dấu các loại vé liên quan là vé ngồi theo số (reserved)
+	CreateSeats(ctx context.Context, seats []entity.Seat) error
+	ListSeatsByEvent(ctx context.Context, eventID uuid.UUID) ([]entity.Seat, error)
+	CountSeatsByTicketType(ctx context.Context, ticketTypeID uuid.UUID) (int, error)
+}
+
+type SeatServicePort interface {
+	CreateSeats(ctx context.Context, eventID uuid.UUID, req []entity.CreateSeatRequest) ([]entity.Seat, error)
+	GetSeatMap(ctx context.Context, eventID uuid.UUID) (*entity.SeatMap, error)
+	FindBestAvailable(ctx context.Context, eventID uuid.UUID, ticketTypeID uuid.UUID, quantity int) ([]entity.Seat, error)
+}
@@ -0,0 +1,22 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+type SessionRepositoryPort interface {
+	// CreateSessions lưu các suất diễn (kèm loại vé của từng suất) và cập nhật
+	// khoảng thời gian + luật lặp của event cha trong cùng một transaction
+	CreateSessions(ctx context.Context, event *entity.Event, sessions []entity.EventSession) error
+	// ListSessions trả về các suất diễn (kèm event và loại vé) theo thứ tự thời gian
+	ListSessions(ctx context.Context, filter entity.SessionFilter) ([]entity.EventSession, error)
+}
+
+type SessionServicePort interface {
+	CreateSessions(ctx context.Context, eventID uuid.UUID, req entity.CreateSessionsRequest) ([]entity.EventSession, error)
+	ListEventSessions(ctx context.Context, eventID uuid.UUID, filter entity.SessionFilter) ([]entity.EventSession, error)
+	ListUpcomingSessions(ctx context.Context, filter entity.SessionFilter) ([]entity.EventSession, error)
+}
@@ -0,0 +1,29 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+type VenueRepositoryPort interface {
+	CreateVenue(ctx context.Context, venue *entity.Venue) error
+	GetVenueByID(ctx context.Context, id uuid.UUID) (*entity.Venue, error)
+	ListVenues(ctx context.Context, limit int, offset int) ([]entity.Venue, error)
+	UpdateVenue(ctx context.Context, venue *entity.Venue) error
+	DeleteVenue(ctx context.Context, id uuid.UUID) error
+	CreateSection(ctx context.Context, section *entity.VenueSection) error
+	// MaxEventAllocation trả về tổng InitialQuantity lớn nhất của một event tại venue
+	// (hoặc tại section nếu sectionID != nil), dùng để chặn giảm sức chứa dưới số vé đã phát hành.
+	MaxEventAllocation(ctx context.Context, venueID uuid.UUID, sectionID *uuid.UUID) (int, error)
+}
+
+type VenueServicePort interface {
+	CreateVenue(ctx context.Context, req entity.CreateVenueRequest) (*entity.Venue, error)
+	GetVenue(ctx context.Context, id uuid.UUID) (*entity.Venue, error)
+	ListVenues(ctx context.Context, limit int, offset int) ([]entity.Venue, error)
+	UpdateVenue(ctx context.Context, id uuid.UUID, req entity.UpdateVenueRequest) (*entity.Venue, error)
+	DeleteVenue(ctx context.Context, id uuid.UUID) error
+	AddSection(ctx context.Context, venueID uuid.UUID, req entity.CreateVenueSectionRequest) (*entity.VenueSection, error)
+}
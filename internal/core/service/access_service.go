@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+// accessService kiểm tra quyền theo vai trò: role gốc của user (trong JWT, áp dụng toàn hệ thống)
+// cộng các vai trò được cấp thêm theo phạm vi (toàn hệ thống, một event hoặc một tổ chức).
+type accessService struct {
+	repo     port.RoleRepositoryPort
+	userRepo port.UserRepositoryPort
+}
+
+func NewAccessService(repo port.RoleRepositoryPort, userRepo port.UserRepositoryPort) port.AccessServicePort {
+	return &accessService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+// HasPermission cho biết user có quyền perm trong phạm vi scope không. Role gốc đủ quyền thì
+// không cần truy vấn DB. Organizer chỉ có quyền qua vai trò cấp theo event / tổ chức.
+func (s *accessService) HasPermission(ctx context.Context, userID uuid.UUID, role string, perm entity.Permission, scope entity.PermissionScope) (bool, error) {
+	if entity.GlobalRoleHasPermission(role, perm) {
+		return true, nil
+	}
+
+	assignments, err := s.repo.ListAssignmentsByUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	// Tổ chức sở hữu event, chỉ tra khi có vai trò cấp theo tổ chức cần xét
+	var eventOrg *uuid.UUID
+	eventOrgLoaded := false
+
+	for _, a := range assignments {
+		if !entity.RoleHasPermission(a.Role, perm) {
+			continue
+		}
+		if a.ScopeType == entity.ScopeGlobal {
+			if entity.GlobalRoleHasPermission(a.Role, perm) {
+				return true, nil
+			}
+			continue
+		}
+		if a.ScopeID == nil {
+			continue
+		}
+		if a.ScopeType == scope.Type && *a.ScopeID == scope.ID {
+			return true, nil
+		}
+
+		// Vai trò trong tổ chức áp dụng cho mọi event của tổ chức đó
+		if a.ScopeType == entity.ScopeOrganization && scope.Type == entity.ScopeEvent {
+			if !eventOrgLoaded {
+				eventOrg, err = s.repo.OrganizationIDForEvent(ctx, scope.ID)
+				if err != nil {
+					return false, err
+				}
+				eventOrgLoaded = true
+			}
+			if eventOrg != nil && *eventOrg == *a.ScopeID {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (s *accessService) EventIDForTicketType(ctx context.Context, ticketTypeID uuid.UUID) (uuid.UUID, error) {
+	return s.repo.EventIDForTicketType(ctx, ticketTypeID)
+}
+
+func (s *accessService) EventIDForAllocation(ctx context.Context, allocationID uuid.UUID) (uuid.UUID, error) {
+	return s.repo.EventIDForAllocation(ctx, allocationID)
+}
+
+func (s *accessService) EventIDForAddOn(ctx context.Context, addOnID uuid.UUID) (uuid.UUID, error) {
+	return s.repo.EventIDForAddOn(ctx, addOnID)
+}
+
+func (s *accessService) EventIDForBallot(ctx context.Context, ballotID uuid.UUID) (uuid.UUID, error) {
+	return s.repo.EventIDForBallot(ctx, ballotID)
+}
+
+func (s *accessService) EventIDsForBundle(ctx context.Context, bundleID uuid.UUID) ([]uuid.UUID, error) {
+	return s.repo.EventIDsForBundle(ctx, bundleID)
+}
+
+func (s *accessService) EventIDsForOrder(ctx context.Context, orderID uuid.UUID) ([]uuid.UUID, error) {
+	return s.repo.EventIDsForOrder(ctx, orderID)
+}
+
+// GrantRole cấp thêm vai trò cho user trong một phạm vi. Admin chỉ là role gốc (users.role) nên không cấp ở đây.
+func (s *accessService) GrantRole(ctx context.Context, grantedBy uuid.UUID, req entity.GrantRoleRequest) (*entity.RoleAssignment, error) {
+	if req.Role == entity.RoleAdmin {
+		return nil, errors.New("không cấp vai trò admin theo phạm vi, hãy đổi role gốc của user")
+	}
+	if !entity.ValidRole(req.Role) || req.Role == entity.RoleUser {
+		return nil, errors.New("vai trò không hợp lệ")
+	}
+	if _, err := s.userRepo.GetUserByID(ctx, req.UserID.String()); err != nil {
+		return nil, errors.New("user không tồn tại")
+	}
+
+	switch req.ScopeType {
+	case entity.ScopeGlobal:
+		if entity.ScopedOnlyRole(req.Role) {
+			return nil, errors.New("vai trò organizer chỉ cấp theo event hoặc tổ chức")
+		}
+		req.ScopeID = nil
+	case entity.ScopeEvent:
+		if req.ScopeID == nil {
+			return nil, errors.New("thiếu scope_id của event")
+		}
+		exists, err := s.repo.EventExists(ctx, *req.ScopeID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, errors.New("sự kiện không tồn tại")
+		}
+	case entity.ScopeOrganization:
+		if req.ScopeID == nil {
+			return nil, errors.New("thiếu scope_id của tổ chức")
+		}
+		exists, err := s.repo.OrganizationExists(ctx, *req.ScopeID)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, errors.New("tổ chức không tồn tại")
+		}
+	default:
+		return nil, errors.New("phạm vi không hợp lệ (GLOBAL, EVENT hoặc ORGANIZATION)")
+	}
+
+	assignment := &entity.RoleAssignment{
+		ID:        uuid.New(),
+		UserID:    req.UserID,
+		Role:      req.Role,
+		ScopeType: req.ScopeType,
+		ScopeID:   req.ScopeID,
+		GrantedBy: &grantedBy,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.CreateAssignment(ctx, assignment); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+func (s *accessService) RevokeRole(ctx context.Context, assignmentID uuid.UUID) error {
+	deleted, err := s.repo.DeleteAssignment(ctx, assignmentID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.New("không tìm thấy vai trò đã cấp")
+	}
+	return nil
+}
+
+func (s *accessService) ListUserRoles(ctx context.Context, userID uuid.UUID) (*entity.UserRoles, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID.String())
+	if err != nil {
+		return nil, errors.New("user không tồn tại")
+	}
+	assignments, err := s.repo.ListAssignmentsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.UserRoles{UserID: user.ID, Role: user.Role, Assignments: assignments}, nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+type addOnService struct {
+	addOnRepo port.AddOnRepositoryPort
+	eventRepo port.EventRepositoryPort
+}
+
+func NewAddOnService(addOnRepo port.AddOnRepositoryPort, eventRepo port.EventRepositoryPort) port.AddOnServicePort {
+	return &addOnService{
+		addOnRepo: addOnRepo,
+		eventRepo: eventRepo,
+	}
+}
+
+func (s *addOnService) CreateAddOn(ctx context.Context, eventID uuid.UUID, req entity.CreateAddOnRequest) (*entity.AddOn, error) {
+	if req.Name == "" {
+		return nil, errors.New("tên sản phẩm không được để trống")
+	}
+	if req.Price.IsNegative() {
+		return nil, errors.New("giá sản phẩm không được âm")
+	}
+	if req.InitialQuantity <= 0 {
+		return nil, errors.New("số lượng sản phẩm phải lớn hơn 0")
+	}
+
+	if _, err := s.eventRepo.GetEventByID(ctx, eventID); err != nil {
+		return nil, errors.New("sự kiện không tồn tại")
+	}
+
+	// Loại vé điều kiện phải thuộc chính event này
+	if len(req.RequiredTicketTypeIDs) > 0 {
+		ticketTypes, err := s.eventRepo.ListTicketTypesByEvent(ctx, eventID)
+		if err != nil {
+			return nil, err
+		}
+		owned := make(map[uuid.UUID]bool, len(ticketTypes))
+		for _, tt := range ticketTypes {
+			owned[tt.ID] = true
+		}
+		seen := make(map[uuid.UUID]bool, len(req.RequiredTicketTypeIDs))
+		for _, id := range req.RequiredTicketTypeIDs {
+			if !owned[id] {
+				return nil, errors.New("loại vé điều kiện không thuộc sự kiện này")
+			}
+			if seen[id] {
+				return nil, errors.New("loại vé điều kiện bị trùng")
+			}
+			seen[id] = true
+		}
+	}
+
+	addOn := &entity.AddOn{
+		ID:                uuid.New(),
+		EventID:           eventID,
+		Name:              req.Name,
+		Variant:           req.Variant,
+		Price:             req.Price,
+		InitialQuantity:   req.InitialQuantity,
+		RemainingQuantity: req.InitialQuantity,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+	}
+	for _, id := range req.RequiredTicketTypeIDs {
+		addOn.Restrictions = append(addOn.Restrictions, entity.AddOnRestriction{
+			AddOnID:      addOn.ID,
+			TicketTypeID: id,
+		})
+	}
+
+	if err := s.addOnRepo.CreateAddOn(ctx, addOn); err != nil {
+		return nil, err
+	}
+	return addOn, nil
+}
+
+func (s *addOnService) ListAddOns(ctx context.Context, eventID uuid.UUID) ([]entity.AddOn, error) {
+	return s.addOnRepo.ListAddOnsByEvent(ctx, eventID)
+}
+
+// GetSalesReport trả về doanh số event theo loại item và chi tiết từng add-on.
+func (s *addOnService) GetSalesReport(ctx context.Context, eventID uuid.UUID) (*entity.SalesReport, error) {
+	byKind, err := s.addOnRepo.SalesByKind(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	addOns, err := s.addOnRepo.AddOnSales(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.SalesReport{
+		EventID: eventID,
+		ByKind:  byKind,
+		AddOns:  addOns,
+	}, nil
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"github.com/yourname/ticketing-system/internal/adapter/repository"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+// AllocationService cho ban tổ chức giữ lại một phần vé khỏi bán công khai, phát vé mời
+// (đơn PAID, 0 đồng, cờ comp) từ phần giữ lại và trả phần chưa dùng về bán công khai.
+type AllocationService struct {
+	db        *gorm.DB
+	repo      *repository.AllocationRepository
+	orderRepo *repository.OrderRepository // dùng chung các hàm khóa/trừ kho với OrderService
+	userRepo  port.UserRepositoryPort
+	waitlist  *WaitlistService // vé trả về bán công khai được cấp cho hàng chờ trước (nil = không dùng)
+	notifier  port.NotifierPort
+}
+
+func NewAllocationService(db *gorm.DB, repo *repository.AllocationRepository, orderRepo *repository.OrderRepository, userRepo port.UserRepositoryPort, waitlist *WaitlistService, notifier port.NotifierPort) *AllocationService {
+	return &AllocationService{
+		db:        db,
+		repo:      repo,
+		orderRepo: orderRepo,
+		userRepo:  userRepo,
+		waitlist:  waitlist,
+		notifier:  notifier,
+	}
+}
+
+// CreateAllocation chuyển quantity vé từ kho bán công khai của loại vé sang một phần giữ lại có tên.
+func (s *AllocationService) CreateAllocation(ctx context.Context, ticketTypeID uuid.UUID, req entity.CreateAllocationRequest) (*entity.Allocation, error) {
+	if req.Name == "" {
+		return nil, errors.New("tên phần vé giữ lại không được để trống")
+	}
+	if req.Quantity <= 0 {
+		return nil, errors.New("số lượng giữ lại phải lớn hơn 0")
+	}
+
+	tx := s.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	ticketType, err := s.orderRepo.GetTicketTypeForUpdate(ctx, tx, ticketTypeID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("loại vé không tồn tại")
+		}
+		return nil, err
+	}
+	if ticketType.Reserved {
+		// Vé ngồi theo số phải gắn ghế cụ thể, chưa hỗ trợ giữ theo số lượng
+		tx.Rollback()
+		return nil, fmt.Errorf("loại vé %s bán theo ghế, không giữ lại theo số lượng được", ticketType.Name)
+	}
+
+	// Trừ kho công khai (kể cả pool dùng chung) như một đơn đặt vé
+	if err := reserveStock(ctx, tx, s.orderRepo, ticketType, req.Quantity); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	allocation := &entity.Allocation{
+		ID:           uuid.New(),
+		TicketTypeID: ticketType.ID,
+		Name:         req.Name,
+		Quantity:     req.Quantity,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	if err := s.repo.CreateAllocation(ctx, tx, allocation); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return allocation, nil
+}
+
+func (s *AllocationService) ListAllocations(ctx context.Context, ticketTypeID uuid.UUID) ([]entity.Allocation, error) {
+	return s.repo.ListByTicketType(ctx, ticketTypeID)
+}
+
+// IssueComp phát vé mời từ allocation cho user (theo user_id hoặc email tài khoản).
+// Đơn tạo ra ở trạng thái PAID, tổng tiền 0 và được đánh dấu comp.
+func (s *AllocationService) IssueComp(ctx context.Context, allocationID uuid.UUID, req entity.IssueCompRequest) (*entity.Order, error) {
+	if req.Quantity <= 0 {
+		return nil, errors.New("số lượng vé mời phải lớn hơn 0")
+	}
+	recipient, err := s.resolveRecipient(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := s.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	allocation, err := s.repo.GetAllocationForUpdate(ctx, tx, allocationID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("phần vé giữ lại không tồn tại")
+		}
+		return nil, err
+	}
+	if allocation.Available() < req.Quantity {
+		tx.Rollback()
+		return nil, fmt.Errorf("phần vé %s chỉ còn %d vé, bạn phát %d", allocation.Name, allocation.Available(), req.Quantity)
+	}
+
+	ticketType, err := s.orderRepo.GetTicketTypeForUpdate(ctx, tx, allocation.TicketTypeID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	orderID := uuid.New()
+	order := &entity.Order{
+		ID:          orderID,
+		UserID:      &recipient.ID,
+		TotalAmount: decimal.Zero,
+		Status:      entity.OrderStatusPaid, // vé mời không qua thanh toán
+		Comp:        true,
+		Channel:     entity.OrderChannelOnline,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+		Items: []entity.OrderItem{{
+			ID:           uuid.New(),
+			OrderID:      orderID,
+			Kind:         entity.OrderItemKindTicket,
+			TicketTypeID: &ticketType.ID,
+			SessionID:    ticketType.SessionID,
+			AllocationID: &allocation.ID,
+			Quantity:     req.Quantity,
+			UnitPrice:    decimal.Zero,
+		}},
+	}
+	if err := s.orderRepo.CreateOrder(ctx, tx, order); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	tickets, err := issueTickets(order)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := s.orderRepo.CreateTickets(ctx, tx, tickets); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	order.Tickets = tickets
+
+	allocation.IssuedQuantity += req.Quantity
+	if err := s.repo.SaveAllocation(ctx, tx, allocation); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	if err := s.notifier.Notify(ctx, recipient.ID, "Bạn nhận được vé mời",
+		fmt.Sprintf("Bạn được tặng %d vé %s. Xem vé trong lịch sử đơn hàng.", req.Quantity, ticketType.Name)); err != nil {
+		log.Printf("Gửi thông báo vé mời cho user %s thất bại: %v", recipient.ID, err)
+	}
+	return order, nil
+}
+
+// ReleaseAllocation trả quantity vé chưa phát của allocation về bán công khai (0 = trả hết),
+// người đầu hàng chờ của loại vé được cấp vé trước.
+func (s *AllocationService) ReleaseAllocation(ctx context.Context, allocationID uuid.UUID, quantity int) (*entity.Allocation, error) {
+	if quantity < 0 {
+		return nil, errors.New("số lượng trả lại không được âm")
+	}
+
+	tx := s.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	allocation, err := s.repo.GetAllocationForUpdate(ctx, tx, allocationID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("phần vé giữ lại không tồn tại")
+		}
+		return nil, err
+	}
+	if quantity == 0 {
+		quantity = allocation.Available()
+	}
+	if quantity == 0 || quantity > allocation.Available() {
+		tx.Rollback()
+		return nil, fmt.Errorf("phần vé %s chỉ còn %d vé chưa phát", allocation.Name, allocation.Available())
+	}
+
+	ticketType, err := s.orderRepo.GetTicketTypeForUpdate(ctx, tx, allocation.TicketTypeID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := releaseStock(ctx, tx, s.orderRepo, ticketType, quantity); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	allocation.Quantity -= quantity
+	if err := s.repo.SaveAllocation(ctx, tx, allocation); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	var offers []entity.WaitlistEntry
+	if s.waitlist != nil {
+		offers, err = s.waitlist.offerReleasedStock(ctx, tx, []uuid.UUID{ticketType.ID})
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	if s.waitlist != nil {
+		s.waitlist.notifyOffers(ctx, offers)
+	}
+	return allocation, nil
+}
+
+// resolveRecipient tìm tài khoản nhận vé mời theo user_id, không có thì theo email.
+func (s *AllocationService) resolveRecipient(ctx context.Context, req entity.IssueCompRequest) (*entity.User, error) {
+	var (
+		user *entity.User
+		err  error
+	)
+	switch {
+	case req.UserID != nil:
+		user, err = s.userRepo.GetUserByID(ctx, req.UserID.String())
+	case req.Email != "":
+		user, err = s.userRepo.GetUserByEmail(ctx, req.Email)
+	default:
+		return nil, errors.New("cần user_id hoặc email của người nhận vé mời")
+	}
+	if err != nil || user == nil {
+		return nil, errors.New("không tìm thấy tài khoản người nhận vé mời")
+	}
+	return user, nil
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+	"github.com/yourname/ticketing-system/pkg/auth"
+)
+
+const (
+	apiKeyPrefix            = "tk_" // Tiền tố giúp nhận ra khóa khi lộ trong log / repo code
+	apiKeyDisplayLength     = 11    // Số ký tự đầu lưu lại để user nhận diện khóa
+	defaultAPIKeyRateLimit  = 60    // Request / phút khi không chỉ định
+	maxAPIKeyRateLimit      = 6000
+	maxActiveAPIKeysPerUser = 20
+	maxAPIKeyNameLength     = 100
+	apiKeyRateWindow        = time.Minute
+	maxAPIKeyRotationGrace  = 7 * 24 * time.Hour
+)
+
+// errInvalidAPIKey dùng chung cho khóa sai, đã thu hồi và hết hạn.
+var errInvalidAPIKey = errors.New("API key không hợp lệ hoặc đã bị thu hồi")
+
+// APIKeyRateLimitedError là lỗi khóa vượt giới hạn request / phút. Middleware trả 429 kèm Retry-After.
+type APIKeyRateLimitedError struct {
+	Limit      int
+	RetryAfter time.Duration
+}
+
+func (e *APIKeyRateLimitedError) Error() string {
+	return fmt.Sprintf("API key vượt giới hạn %d request/phút, thử lại sau %s", e.Limit, e.RetryAfter.Round(time.Second))
+}
+
+// apiKeyService quản lý API key cho tích hợp máy-máy (website đối tác, kiosk) của organizer / admin.
+type apiKeyService struct {
+	repo   port.APIKeyRepositoryPort
+	access port.AccessServicePort
+}
+
+func NewAPIKeyService(repo port.APIKeyRepositoryPort, access port.AccessServicePort) port.APIKeyServicePort {
+	return &apiKeyService{
+		repo:   repo,
+		access: access,
+	}
+}
+
+// CreateKey tạo khóa cho user. Chỉ admin và organizer (toàn hệ thống hoặc theo phạm vi) được tạo,
+// và chỉ với các quyền mà họ đang có.
+func (s *apiKeyService) CreateKey(ctx context.Context, userID uuid.UUID, req entity.CreateAPIKeyRequest) (*entity.CreatedAPIKey, error) {
+	roles, err := s.access.ListUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !canOwnAPIKeys(roles) {
+		return nil, errors.New("chỉ organizer hoặc admin mới được tạo API key")
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxAPIKeyNameLength {
+		return nil, fmt.Errorf("tên API key phải có từ 1 đến %d ký tự", maxAPIKeyNameLength)
+	}
+	scopes, err := normalizeAPIKeyScopes(roles, req.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	rateLimit := req.RateLimitPerMinute
+	if rateLimit == 0 {
+		rateLimit = defaultAPIKeyRateLimit
+	}
+	if rateLimit < 1 || rateLimit > maxAPIKeyRateLimit {
+		return nil, fmt.Errorf("rate_limit_per_minute phải từ 1 đến %d", maxAPIKeyRateLimit)
+	}
+	now := time.Now()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return nil, errors.New("expires_at phải ở tương lai")
+	}
+
+	active, err := s.repo.CountActiveKeys(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	if active >= maxActiveAPIKeysPerUser {
+		return nil, fmt.Errorf("mỗi user tối đa %d API key đang hoạt động", maxActiveAPIKeysPerUser)
+	}
+
+	return s.issueKey(ctx, &entity.APIKey{
+		UserID:             userID,
+		Name:               name,
+		Scopes:             scopes,
+		RateLimitPerMinute: rateLimit,
+		ExpiresAt:          req.ExpiresAt,
+	})
+}
+
+// canOwnAPIKeys cho biết user có được sở hữu API key không (admin hoặc được cấp vai trò organizer).
+func canOwnAPIKeys(roles *entity.UserRoles) bool {
+	if roles.Role == entity.RoleAdmin {
+		return true
+	}
+	for _, a := range roles.Assignments {
+		if a.Role == entity.RoleOrganizer {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeAPIKeyScopes kiểm tra và bỏ trùng các quyền của khóa. Quyền phải là quyền user đang có
+// (qua role gốc hoặc một vai trò được cấp), phạm vi cụ thể vẫn kiểm tra lại mỗi request.
+func normalizeAPIKeyScopes(roles *entity.UserRoles, requested []entity.Permission) ([]entity.Permission, error) {
+	if len(requested) == 0 {
+		return nil, errors.New("API key phải có ít nhất một quyền")
+	}
+
+	seen := make(map[entity.Permission]bool, len(requested))
+	scopes := make([]entity.Permission, 0, len(requested))
+	for _, perm := range requested {
+		if !entity.ValidPermission(perm) {
+			return nil, fmt.Errorf("quyền không hợp lệ: %s", perm)
+		}
+		if seen[perm] {
+			continue
+		}
+		held := entity.GlobalRoleHasPermission(roles.Role, perm)
+		for _, a := range roles.Assignments {
+			held = held || entity.RoleHasPermission(a.Role, perm)
+		}
+		if !held {
+			return nil, fmt.Errorf("không thể cấp cho API key quyền bạn không có: %s", perm)
+		}
+		seen[perm] = true
+		scopes = append(scopes, perm)
+	}
+	return scopes, nil
+}
+
+// issueKey sinh khóa ngẫu nhiên, lưu hash và trả khóa thô (chỉ một lần).
+func (s *apiKeyService) issueKey(ctx context.Context, key *entity.APIKey) (*entity.CreatedAPIKey, error) {
+	secret, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	plain := apiKeyPrefix + secret
+
+	key.ID = uuid.New()
+	key.Prefix = plain[:apiKeyDisplayLength]
+	key.KeyHash = auth.HashToken(plain)
+	key.CreatedAt = time.Now()
+	if err := s.repo.CreateKey(ctx, key); err != nil {
+		return nil, err
+	}
+	return &entity.CreatedAPIKey{APIKey: key, Key: plain}, nil
+}
+
+func (s *apiKeyService) ListKeys(ctx context.Context, userID uuid.UUID) ([]entity.APIKey, error) {
+	return s.repo.ListKeysByUser(ctx, userID)
+}
+
+// RotateKey cấp khóa mới cùng tên, quyền, giới hạn và hạn dùng. Khóa cũ bị thu hồi ngay,
+// hoặc còn dùng được thêm thời gian chuyển tiếp để hệ thống đối tác kịp cập nhật.
+func (s *apiKeyService) RotateKey(ctx context.Context, userID uuid.UUID, role string, keyID uuid.UUID, req entity.RotateAPIKeyRequest) (*entity.CreatedAPIKey, error) {
+	grace := time.Duration(req.GracePeriodMinutes) * time.Minute
+	if grace < 0 || grace > maxAPIKeyRotationGrace {
+		return nil, fmt.Errorf("grace_period_minutes phải từ 0 đến %d", int(maxAPIKeyRotationGrace.Minutes()))
+	}
+
+	old, err := s.ownedKey(ctx, userID, role, keyID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !old.Active(now) {
+		return nil, errors.New("API key đã bị thu hồi hoặc hết hạn")
+	}
+
+	rotated, err := s.issueKey(ctx, &entity.APIKey{
+		UserID:             old.UserID,
+		Name:               old.Name,
+		Scopes:             old.Scopes,
+		RateLimitPerMinute: old.RateLimitPerMinute,
+		ExpiresAt:          old.ExpiresAt,
+		RotatedFrom:        &old.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if grace == 0 {
+		_, err = s.repo.RevokeKey(ctx, old.ID, now)
+	} else {
+		err = s.repo.ExpireKey(ctx, old.ID, now.Add(grace))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rotated, nil
+}
+
+func (s *apiKeyService) RevokeKey(ctx context.Context, userID uuid.UUID, role string, keyID uuid.UUID) error {
+	if _, err := s.ownedKey(ctx, userID, role, keyID); err != nil {
+		return err
+	}
+	revoked, err := s.repo.RevokeKey(ctx, keyID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return errors.New("API key đã bị thu hồi")
+	}
+	return nil
+}
+
+// ownedKey lấy khóa nếu user là chủ khóa hoặc admin. Khóa của người khác báo như không tồn tại.
+func (s *apiKeyService) ownedKey(ctx context.Context, userID uuid.UUID, role string, keyID uuid.UUID) (*entity.APIKey, error) {
+	key, err := s.repo.GetKey(ctx, keyID)
+	if err != nil || (key.UserID != userID && role != entity.RoleAdmin) {
+		return nil, errors.New("API key không tồn tại")
+	}
+	return key, nil
+}
+
+// Authenticate xác thực khóa thô, ghi lần dùng cuối và áp giới hạn request / phút của khóa.
+func (s *apiKeyService) Authenticate(ctx context.Context, key string, ip string) (*entity.APIKeyIdentity, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, errInvalidAPIKey
+	}
+
+	now := time.Now()
+	usage, err := s.repo.UseKey(ctx, auth.HashToken(key), ip, now.Add(-apiKeyRateWindow))
+	if err != nil {
+		return nil, errInvalidAPIKey
+	}
+
+	limit := usage.Key.RateLimitPerMinute
+	if usage.WindowCount > limit {
+		retryAfter := usage.WindowStart.Add(apiKeyRateWindow).Sub(now)
+		if retryAfter < time.Second {
+			retryAfter = time.Second
+		}
+		return nil, &APIKeyRateLimitedError{Limit: limit, RetryAfter: retryAfter}
+	}
+
+	return &entity.APIKeyIdentity{
+		KeyID:     usage.Key.ID,
+		UserID:    usage.Key.UserID,
+		Role:      usage.OwnerRole,
+		Scopes:    usage.Key.Scopes,
+		RateLimit: limit,
+		Remaining: limit - usage.WindowCount,
+	}, nil
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/yourname/ticketing-system/internal/adapter/repository"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+// idNumberPattern chấp nhận CCCD (12 số), CMND cũ (9 số) và số hộ chiếu (chữ + số).
+var idNumberPattern = regexp.MustCompile(`^[A-Z0-9]{6,20}$`)
+
+// AttendeeService quản lý thông tin người tham dự từng vé: ban tổ chức đặt bộ câu hỏi cho event,
+// người giữ vé trả lời lúc checkout hoặc sau đó tới hạn chót, ban tổ chức xuất danh sách.
+type AttendeeService struct {
+	db   *gorm.DB
+	repo *repository.AttendeeRepository
+}
+
+func NewAttendeeService(db *gorm.DB, repo *repository.AttendeeRepository) *AttendeeService {
+	return &AttendeeService{
+		db:   db,
+		repo: repo,
+	}
+}
+
+// SetQuestions đặt bộ câu hỏi người tham dự của event. Câu hỏi chưa có ID được sinh ID mới;
+// câu trả lời cũ theo ID đã bỏ vẫn nằm trong vé nhưng không còn xuất ra.
+func (s *AttendeeService) SetQuestions(ctx context.Context, eventID uuid.UUID, req entity.SetAttendeeQuestionsRequest) (*entity.Event, error) {
+	questions, err := validateAttendeeQuestions(req.Questions)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := s.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("sự kiện không tồn tại")
+		}
+		return nil, err
+	}
+	if req.Deadline != nil && req.Deadline.After(event.StartTime) {
+		return nil, errors.New("hạn trả lời phải trước giờ bắt đầu sự kiện")
+	}
+
+	if err := s.repo.SetEventQuestions(ctx, eventID, questions, req.Deadline); err != nil {
+		return nil, err
+	}
+	event.AttendeeQuestions = questions
+	event.AttendeeDeadline = req.Deadline
+	return event, nil
+}
+
+// UpdateAttendee cho người đang giữ vé điền/sửa thông tin người tham dự trước hạn chót.
+func (s *AttendeeService) UpdateAttendee(ctx context.Context, userID, ticketID uuid.UUID, details entity.AttendeeDetails) (*entity.Ticket, error) {
+	tx := s.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	ticket, err := s.repo.GetTicketForUpdate(ctx, tx, ticketID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("vé không tồn tại")
+		}
+		return nil, err
+	}
+	if ticket.OwnerID == nil || *ticket.OwnerID != userID {
+		tx.Rollback()
+		return nil, errors.New("vé không tồn tại")
+	}
+	if ticket.Status != entity.TicketStatusUnused {
+		tx.Rollback()
+		return nil, errors.New("vé đã sử dụng, không sửa được thông tin người tham dự")
+	}
+
+	event, err := s.repo.GetEventByTicketType(ctx, tx, ticket.TicketTypeID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if time.Now().After(attendeeDeadline(event)) {
+		tx.Rollback()
+		return nil, errors.New("đã quá hạn cập nhật thông tin người tham dự")
+	}
+
+	normalized, err := validateAttendeeDetails(event.AttendeeQuestions, details)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	ticket.OwnerName = normalized.Name
+	ticket.Answers = normalized.Answers
+	if err := s.repo.SaveAttendee(ctx, tx, ticket); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
+
+// Export trả về danh sách người tham dự của event (vé thuộc đơn đã thanh toán) cho ban tổ chức.
+func (s *AttendeeService) Export(ctx context.Context, eventID uuid.UUID) (*entity.AttendeeExport, error) {
+	event, err := s.repo.GetEvent(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("sự kiện không tồn tại")
+		}
+		return nil, err
+	}
+
+	records, err := s.repo.ListAttendees(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range records {
+		records[i].Complete = attendeeComplete(event.AttendeeQuestions, records[i].Name, records[i].Answers)
+	}
+	return &entity.AttendeeExport{
+		Questions: event.AttendeeQuestions,
+		Attendees: records,
+	}, nil
+}
+
+// attendeeDeadline là hạn chót sửa thông tin người tham dự: AttendeeDeadline, mặc định giờ bắt đầu event.
+func attendeeDeadline(event *entity.Event) time.Time {
+	if event.AttendeeDeadline != nil {
+		return *event.AttendeeDeadline
+	}
+	return event.StartTime
+}
+
+// validateAttendeeQuestions kiểm tra bộ câu hỏi và sinh ID cho câu hỏi mới.
+func validateAttendeeQuestions(questions []entity.AttendeeQuestion) ([]entity.AttendeeQuestion, error) {
+	seen := make(map[string]bool, len(questions))
+	result := make([]entity.AttendeeQuestion, 0, len(questions))
+	for _, q := range questions {
+		q.ID = strings.TrimSpace(q.ID)
+		if q.ID == "" {
+			q.ID = uuid.New().String()
+		}
+		if seen[q.ID] {
+			return nil, fmt.Errorf("trùng ID câu hỏi: %s", q.ID)
+		}
+		seen[q.ID] = true
+
+		q.Label = strings.TrimSpace(q.Label)
+		if q.Label == "" {
+			return nil, errors.New("câu hỏi phải có nội dung")
+		}
+
+		switch q.Type {
+		case entity.AttendeeQuestionChoice:
+			options := make([]string, 0, len(q.Options))
+			for _, option := range q.Options {
+				if option = strings.TrimSpace(option); option != "" {
+					options = append(options, option)
+				}
+			}
+			if len(options) < 2 {
+				return nil, fmt.Errorf("câu hỏi lựa chọn %q phải có ít nhất 2 đáp án", q.Label)
+			}
+			q.Options = options
+		case entity.AttendeeQuestionText, entity.AttendeeQuestionDateOfBirth, entity.AttendeeQuestionIDNumber:
+			q.Options = nil
+		default:
+			return nil, fmt.Errorf("loại câu hỏi không hợp lệ: %s", q.Type)
+		}
+		result = append(result, q)
+	}
+	return result, nil
+}
+
+// validateAttendeeDetails kiểm tra thông tin người tham dự theo bộ câu hỏi của event: bắt buộc có
+// tên, trả lời đủ câu bắt buộc, đúng định dạng từng loại câu và không có câu trả lời lạ.
+// Trả về bản đã chuẩn hóa (bỏ khoảng trắng, số giấy tờ viết hoa).
+func validateAttendeeDetails(questions []entity.AttendeeQuestion, details entity.AttendeeDetails) (entity.AttendeeDetails, error) {
+	name := strings.TrimSpace(details.Name)
+	if name == "" {
+		return entity.AttendeeDetails{}, errors.New("thiếu tên người tham dự")
+	}
+	if utf8.RuneCountInString(name) > 100 {
+		return entity.AttendeeDetails{}, errors.New("tên người tham dự quá dài")
+	}
+
+	byID := make(map[string]entity.AttendeeQuestion, len(questions))
+	for _, q := range questions {
+		byID[q.ID] = q
+	}
+	for id := range details.Answers {
+		if _, ok := byID[id]; !ok {
+			return entity.AttendeeDetails{}, fmt.Errorf("câu hỏi không tồn tại: %s", id)
+		}
+	}
+
+	answers := make(map[string]string, len(details.Answers))
+	for _, q := range questions {
+		value := strings.TrimSpace(details.Answers[q.ID])
+		if value == "" {
+			if q.Required {
+				return entity.AttendeeDetails{}, fmt.Errorf("chưa trả lời câu hỏi bắt buộc: %s", q.Label)
+			}
+			continue
+		}
+
+		switch q.Type {
+		case entity.AttendeeQuestionText:
+			if utf8.RuneCountInString(value) > 500 {
+				return entity.AttendeeDetails{}, fmt.Errorf("câu trả lời quá dài: %s", q.Label)
+			}
+		case entity.AttendeeQuestionChoice:
+			valid := false
+			for _, option := range q.Options {
+				if option == value {
+					valid = true
+					break
+				}
+			}
+			if !valid {
+				return entity.AttendeeDetails{}, fmt.Errorf("đáp án không hợp lệ cho câu hỏi: %s", q.Label)
+			}
+		case entity.AttendeeQuestionDateOfBirth:
+			dob, err := time.Parse("2006-01-02", value)
+			if err != nil || dob.After(time.Now()) || dob.Year() < 1900 {
+				return entity.AttendeeDetails{}, fmt.Errorf("ngày sinh không hợp lệ (YYYY-MM-DD): %s", q.Label)
+			}
+		case entity.AttendeeQuestionIDNumber:
+			value = strings.ToUpper(value)
+			if !idNumberPattern.MatchString(value) {
+				return entity.AttendeeDetails{}, fmt.Errorf("số giấy tờ không hợp lệ: %s", q.Label)
+			}
+		}
+		answers[q.ID] = value
+	}
+	return entity.AttendeeDetails{Name: name, Answers: answers}, nil
+}
+
+// attendeeComplete trả về true nếu vé đã có tên người tham dự và trả lời đủ câu bắt buộc.
+func attendeeComplete(questions []entity.AttendeeQuestion, name string, answers map[string]string) bool {
+	if strings.TrimSpace(name) == "" {
+		return false
+	}
+	for _, q := range questions {
+		if q.Required && answers[q.ID] == "" {
+			return false
+		}
+	}
+	return true
+}
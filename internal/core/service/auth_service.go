@@ -3,6 +3,11 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,16 +16,76 @@ import (
 	"github.com/yourname/ticketing-system/pkg/auth" // Import bộ công cụ băm và JWT
 )
 
+// errInvalidRefreshToken dùng chung cho mọi trường hợp refresh token không dùng được,
+// để không lộ cho client biết token không tồn tại hay đã bị thu hồi.
+var errInvalidRefreshToken = errors.New("refresh token không hợp lệ hoặc đã hết hạn")
+
+// errInvalidEmailToken tương tự cho link đặt lại mật khẩu / xác thực email.
+var errInvalidEmailToken = errors.New("liên kết không hợp lệ hoặc đã hết hạn")
+
+const (
+	passwordResetTTL     = time.Hour      // Link đặt lại mật khẩu
+	emailVerificationTTL = 48 * time.Hour // Link xác thực email
+	minPasswordLength    = 6
+)
+
+// Chống dò mật khẩu: vượt ngưỡng sai thì khóa tạm, mỗi lần sai tiếp theo thời gian khóa gấp đôi.
+// Bộ đếm bắt đầu lại nếu không sai lần nào trong loginFailureWindow.
+const (
+	accountMaxFailures = 5  // Theo email
+	ipMaxFailures      = 20 // Theo IP (một IP có thể thử nhiều email)
+	lockoutBase        = 30 * time.Second
+	lockoutMax         = time.Hour
+	loginFailureWindow = time.Hour
+)
+
+// errInvalidCredentials dùng chung cho sai email và sai mật khẩu để không lộ email nào đã đăng ký.
+var errInvalidCredentials = errors.New("sai email hoặc mật khẩu")
+
+// LoginLockedError là lỗi đăng nhập bị tạm khóa do sai quá nhiều lần. Handler trả 429 kèm Retry-After.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("đăng nhập sai quá nhiều lần, thử lại sau %s", e.RetryAfter.Round(time.Second))
+}
+
+// dummyPasswordHash là hash bcrypt giả để email không tồn tại vẫn tốn thời gian so mật khẩu như email có thật.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, _ := auth.HashPassword("dummy-password-for-timing")
+	return hash
+})
+
+// AuthConfig là cấu hình của auth service.
+type AuthConfig struct {
+	AccessTTL       time.Duration // Thời hạn access token (ngắn)
+	RefreshTTL      time.Duration // Thời hạn refresh token
+	AppBaseURL      string        // Gốc URL frontend để dựng link trong email
+	MFAIssuer       string        // Tên hiển thị trong ứng dụng xác thực
+	RequireAdminMFA bool          // Bắt buộc admin bật xác thực 2 bước
+}
+
 type authService struct {
 	userRepo  port.UserRepositoryPort
-	secretKey string // Khóa bí mật để ký JWT
+	tokenRepo port.TokenRepositoryPort
+	throttles port.LoginThrottleRepositoryPort
+	mfaRepo   port.MFARepositoryPort
+	mailer    port.MailerPort
+	keys      *auth.KeySet // Khóa ký JWT (RS256/EdDSA, xoay vòng theo lịch)
+	cfg       AuthConfig
 }
 
-// NewAuthService khởi tạo Service với Repository và Secret Key
-func NewAuthService(repo port.UserRepositoryPort, secretKey string) port.AuthServicePort {
+// NewAuthService khởi tạo Service với các Repository, mailer, bộ khóa ký và cấu hình
+func NewAuthService(repo port.UserRepositoryPort, tokenRepo port.TokenRepositoryPort, throttles port.LoginThrottleRepositoryPort, mfaRepo port.MFARepositoryPort, mailer port.MailerPort, keys *auth.KeySet, cfg AuthConfig) port.AuthServicePort {
 	return &authService{
 		userRepo:  repo,
-		secretKey: secretKey,
+		tokenRepo: tokenRepo,
+		throttles: throttles,
+		mfaRepo:   mfaRepo,
+		mailer:    mailer,
+		keys:      keys,
+		cfg:       cfg,
 	}
 }
 
@@ -55,37 +120,406 @@ func (s *authService) Register(ctx context.Context, req entity.RegisterRequest)
 		return nil, err
 	}
 
+	// 5. Gửi link xác thực email (lỗi gửi không làm fail đăng ký, user có thể yêu cầu gửi lại)
+	if err := s.sendVerification(ctx, newUser); err != nil {
+		log.Printf("Gửi email xác thực cho user %s thất bại: %v", newUser.ID, err)
+	}
+
 	return newUser, nil
 }
 
 // Login xử lý logic Đăng nhập
-func (s *authService) Login(ctx context.Context, req entity.LoginRequest) (string, error) {
-	// 1. Tìm User theo email
+func (s *authService) Login(ctx context.Context, req entity.LoginRequest) (*entity.LoginResult, error) {
+	// 1. Email hoặc IP đang bị khóa tạm do sai nhiều lần thì từ chối ngay
+	keys := loginThrottleKeys(req)
+	if err := s.checkLoginLock(ctx, keys); err != nil {
+		return nil, err
+	}
+
+	// 2. Tìm User theo email. Không có thì vẫn so với hash giả để thời gian phản hồi như email có thật
 	user, err := s.userRepo.GetUserByEmail(ctx, req.Email)
 	if err != nil {
-		return "", errors.New("sai email hoặc mật khẩu")
+		auth.CheckPasswordHash(req.Password, dummyPasswordHash())
+		s.recordLoginFailure(ctx, keys)
+		return nil, errInvalidCredentials
 	}
 
-	// 2. Đối chiếu mật khẩu bằng Bcrypt
+	// 3. Đối chiếu mật khẩu bằng Bcrypt
 	if !auth.CheckPasswordHash(req.Password, user.PasswordHash) {
-		return "", errors.New("sai email hoặc mật khẩu")
+		s.recordLoginFailure(ctx, keys)
+		return nil, errInvalidCredentials
+	}
+
+	// 4. Đã bật 2FA (hoặc role bắt buộc 2FA): chưa phát token, trả challenge chờ mã TOTP
+	enabled, err := s.mfaEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled || s.mfaRequired(user) {
+		challenge, err := s.issueUserToken(ctx, user.ID, entity.TokenPurposeMFAChallenge, mfaChallengeTTL)
+		if err != nil {
+			return nil, err
+		}
+		return &entity.LoginResult{MFARequired: true, MFAEnrollmentRequired: !enabled, ChallengeToken: challenge}, nil
+	}
+
+	// 5. Đủ điều kiện: phát access token + refresh token mở đầu một họ token mới
+	pair, err := s.startSession(ctx, user, keys)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.LoginResult{TokenPair: pair}, nil
+}
+
+// startSession kết thúc đăng nhập thành công: xóa bộ đếm sai của email (bộ đếm IP giữ nguyên để không
+// "rửa" được bằng tài khoản của mình) và phát cặp token mở đầu một họ refresh token mới.
+func (s *authService) startSession(ctx context.Context, user *entity.User, keys []loginThrottleKey) (*entity.TokenPair, error) {
+	if err := s.throttles.ResetThrottle(ctx, entity.LoginThrottleAccount, keys[0].Key); err != nil {
+		log.Printf("Xóa bộ đếm đăng nhập sai của %s thất bại: %v", keys[0].Key, err)
+	}
+
+	refresh, plain, err := s.newRefreshToken(user.ID, uuid.New())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tokenRepo.CreateRefreshToken(ctx, refresh); err != nil {
+		return nil, err
+	}
+	return s.tokenPair(user, plain)
+}
+
+// Refresh đổi refresh token lấy cặp token mới (xoay vòng: token cũ hết dùng được).
+// Token đã thu hồi mà bị dùng lại thì coi như bị lộ và thu hồi cả họ token của lần đăng nhập đó.
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*entity.TokenPair, error) {
+	if refreshToken == "" {
+		return nil, errInvalidRefreshToken
+	}
+	stored, err := s.tokenRepo.GetRefreshTokenByHash(ctx, auth.HashToken(refreshToken))
+	if err != nil {
+		return nil, errInvalidRefreshToken
+	}
+	if stored.RevokedAt != nil {
+		s.revokeFamily(ctx, stored)
+		return nil, errInvalidRefreshToken
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, errInvalidRefreshToken
+	}
+
+	// Lấy lại user để token mới mang role hiện tại
+	user, err := s.userRepo.GetUserByID(ctx, stored.UserID.String())
+	if err != nil {
+		return nil, errInvalidRefreshToken
+	}
+	// Role bắt buộc 2FA mà chưa bật (VD: phiên có từ trước khi bật cấu hình): buộc đăng nhập lại để đăng ký
+	if s.mfaRequired(user) {
+		enabled, err := s.mfaEnabled(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			return nil, errInvalidRefreshToken
+		}
+	}
+
+	next, plain, err := s.newRefreshToken(user.ID, stored.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+	rotated, err := s.tokenRepo.RotateRefreshToken(ctx, stored.ID, next)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		// Một request khác vừa dùng token này: một trong hai là kẻ gian
+		s.revokeFamily(ctx, stored)
+		return nil, errInvalidRefreshToken
+	}
+	return s.tokenPair(user, plain)
+}
+
+// Logout thu hồi access token đang dùng (theo jti, tới khi nó hết hạn) và họ refresh token của phiên.
+func (s *authService) Logout(ctx context.Context, userID uuid.UUID, jti string, accessExpiresAt time.Time, refreshToken string) error {
+	if jti != "" {
+		if err := s.tokenRepo.RevokeAccessToken(ctx, jti, accessExpiresAt); err != nil {
+			return err
+		}
+	}
+	if refreshToken == "" {
+		return nil
+	}
+
+	stored, err := s.tokenRepo.GetRefreshTokenByHash(ctx, auth.HashToken(refreshToken))
+	if err != nil || stored.UserID != userID {
+		return nil // refresh token không thuộc user: bỏ qua, access token đã bị thu hồi
+	}
+	return s.tokenRepo.RevokeRefreshFamily(ctx, stored.FamilyID)
+}
+
+// IsTokenRevoked kiểm tra access token (theo jti) đã bị thu hồi chưa.
+func (s *authService) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	return s.tokenRepo.IsAccessTokenRevoked(ctx, jti)
+}
+
+// PurgeExpiredTokens dọn jti thu hồi, token đã hết hạn và bộ đếm đăng nhập sai cũ. Gọi định kỳ từ background job.
+func (s *authService) PurgeExpiredTokens(ctx context.Context) (int64, error) {
+	now := time.Now()
+	n, err := s.tokenRepo.PurgeExpiredTokens(ctx, now)
+	if err != nil {
+		return n, err
+	}
+	purged, err := s.throttles.PurgeThrottles(ctx, now.Add(-loginFailureWindow))
+	return n + purged, err
+}
+
+// ListLockouts trả về các email / IP đang bị khóa đăng nhập (cho admin).
+func (s *authService) ListLockouts(ctx context.Context) ([]entity.LoginThrottle, error) {
+	return s.throttles.ListLocked(ctx, time.Now())
+}
+
+// ClearLockout mở khóa và xóa bộ đếm đăng nhập sai của một email / IP (admin).
+func (s *authService) ClearLockout(ctx context.Context, kind entity.LoginThrottleKind, key string) error {
+	if kind != entity.LoginThrottleAccount && kind != entity.LoginThrottleIP {
+		return fmt.Errorf("loại khóa không hợp lệ: %s", kind)
 	}
+	if kind == entity.LoginThrottleAccount {
+		key = normalizeEmail(key)
+	}
+	return s.throttles.ResetThrottle(ctx, kind, key)
+}
+
+// loginThrottleKey là một bộ đếm đăng nhập sai áp dụng cho request.
+type loginThrottleKey struct {
+	Kind        entity.LoginThrottleKind
+	Key         string
+	MaxFailures int
+}
+
+// loginThrottleKeys trả về bộ đếm theo email (luôn ở đầu) và theo IP (nếu biết IP).
+func loginThrottleKeys(req entity.LoginRequest) []loginThrottleKey {
+	keys := []loginThrottleKey{{Kind: entity.LoginThrottleAccount, Key: normalizeEmail(req.Email), MaxFailures: accountMaxFailures}}
+	if ip := strings.TrimSpace(req.IP); ip != "" {
+		keys = append(keys, loginThrottleKey{Kind: entity.LoginThrottleIP, Key: ip, MaxFailures: ipMaxFailures})
+	}
+	return keys
+}
 
-	// 3. Nếu đúng mật khẩu, tiến hành tạo thẻ JWT (Token)
-	token, err := auth.GenerateToken(user.ID.String(), user.Role, s.secretKey)
+// checkLoginLock trả về LoginLockedError nếu email hoặc IP đang bị khóa (lấy thời hạn khóa dài nhất).
+func (s *authService) checkLoginLock(ctx context.Context, keys []loginThrottleKey) error {
+	now := time.Now()
+	var retryAfter time.Duration
+	for _, k := range keys {
+		throttle, err := s.throttles.GetThrottle(ctx, k.Kind, k.Key)
+		if err != nil {
+			return err
+		}
+		if throttle != nil && throttle.LockedUntil != nil && throttle.LockedUntil.After(now) {
+			if wait := throttle.LockedUntil.Sub(now); wait > retryAfter {
+				retryAfter = wait
+			}
+		}
+	}
+	if retryAfter > 0 {
+		return &LoginLockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// recordLoginFailure tăng bộ đếm sai và khóa tạm khi vượt ngưỡng. Lỗi ghi chỉ log, không đổi kết quả đăng nhập.
+func (s *authService) recordLoginFailure(ctx context.Context, keys []loginThrottleKey) {
+	now := time.Now()
+	for _, k := range keys {
+		throttle, err := s.throttles.RecordFailure(ctx, k.Kind, k.Key, now.Add(-loginFailureWindow))
+		if err != nil {
+			log.Printf("Ghi nhận đăng nhập sai (%s %s) thất bại: %v", k.Kind, k.Key, err)
+			continue
+		}
+		if throttle.Failures < k.MaxFailures {
+			continue
+		}
+		lock := lockoutDuration(throttle.Failures - k.MaxFailures)
+		if err := s.throttles.SetLockedUntil(ctx, k.Kind, k.Key, now.Add(lock)); err != nil {
+			log.Printf("Khóa đăng nhập (%s %s) thất bại: %v", k.Kind, k.Key, err)
+			continue
+		}
+		log.Printf("Khóa đăng nhập %s %s trong %s sau %d lần sai", k.Kind, k.Key, lock, throttle.Failures)
+	}
+}
+
+// lockoutDuration tính thời gian khóa sau lần sai thứ excess vượt ngưỡng (0 = vừa chạm ngưỡng):
+// lockoutBase, gấp đôi mỗi lần, tối đa lockoutMax.
+func lockoutDuration(excess int) time.Duration {
+	lock := lockoutBase
+	for i := 0; i < excess && lock < lockoutMax; i++ {
+		lock *= 2
+	}
+	if lock > lockoutMax {
+		lock = lockoutMax
+	}
+	return lock
+}
+
+// ForgotPassword gửi link đặt lại mật khẩu nếu email tồn tại. Luôn trả về nil khi email không tồn tại
+// hoặc gửi thất bại để không lộ email nào đã đăng ký.
+func (s *authService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil
+	}
+
+	plain, err := s.issueUserToken(ctx, user.ID, entity.TokenPurposePasswordReset, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+	body := fmt.Sprintf("Xin chào %s,\n\nMở liên kết sau để đặt lại mật khẩu (hết hạn sau %s):\n%s\n\nNếu bạn không yêu cầu, hãy bỏ qua email này.",
+		user.Username, passwordResetTTL, s.emailLink("/reset-password", plain))
+	if err := s.mailer.Send(ctx, user.Email, "Đặt lại mật khẩu", body); err != nil {
+		log.Printf("Gửi email đặt lại mật khẩu cho user %s thất bại: %v", user.ID, err)
+	}
+	return nil
+}
+
+// ResetPassword đặt mật khẩu mới bằng token trong email và đăng xuất mọi phiên cũ.
+// Nhận được email nghĩa là sở hữu địa chỉ đó nên email cũng được coi là đã xác thực.
+func (s *authService) ResetPassword(ctx context.Context, req entity.ResetPasswordRequest) error {
+	if len(req.NewPassword) < minPasswordLength {
+		return fmt.Errorf("mật khẩu phải có ít nhất %d ký tự", minPasswordLength)
+	}
+	token, err := s.tokenRepo.ConsumeUserToken(ctx, auth.HashToken(req.Token), entity.TokenPurposePasswordReset)
+	if err != nil {
+		return errInvalidEmailToken
+	}
+
+	hashedPassword, err := auth.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.UpdatePassword(ctx, token.UserID, hashedPassword); err != nil {
+		return err
+	}
+	if err := s.tokenRepo.RevokeUserRefreshTokens(ctx, token.UserID); err != nil {
+		return err
+	}
+	return s.userRepo.MarkEmailVerified(ctx, token.UserID)
+}
+
+// VerifyEmail xác thực email bằng token đã gửi lúc đăng ký (hoặc gửi lại).
+func (s *authService) VerifyEmail(ctx context.Context, token string) error {
+	stored, err := s.tokenRepo.ConsumeUserToken(ctx, auth.HashToken(token), entity.TokenPurposeVerifyEmail)
+	if err != nil {
+		return errInvalidEmailToken
+	}
+	return s.userRepo.MarkEmailVerified(ctx, stored.UserID)
+}
+
+// ResendVerification gửi lại link xác thực email (link cũ hết hiệu lực).
+func (s *authService) ResendVerification(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID.String())
+	if err != nil {
+		return errors.New("user không tồn tại")
+	}
+	if user.EmailVerifiedAt != nil {
+		return errors.New("email đã được xác thực")
+	}
+	return s.sendVerification(ctx, user)
+}
+
+// IsEmailVerified cho biết user đã xác thực email chưa (dùng để chặn đặt vé khi bật yêu cầu xác thực).
+func (s *authService) IsEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID.String())
+	if err != nil {
+		return false, err
+	}
+	return user.EmailVerifiedAt != nil, nil
+}
+
+func (s *authService) sendVerification(ctx context.Context, user *entity.User) error {
+	plain, err := s.issueUserToken(ctx, user.ID, entity.TokenPurposeVerifyEmail, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+	body := fmt.Sprintf("Xin chào %s,\n\nMở liên kết sau để xác thực email (hết hạn sau %s):\n%s",
+		user.Username, emailVerificationTTL, s.emailLink("/verify-email", plain))
+	return s.mailer.Send(ctx, user.Email, "Xác thực email", body)
+}
+
+// issueUserToken phát token dùng một lần cho user (chỉ lưu hash), trả về token gốc để gửi qua email.
+func (s *authService) issueUserToken(ctx context.Context, userID uuid.UUID, purpose entity.UserTokenPurpose, ttl time.Duration) (string, error) {
+	plain, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	err = s.tokenRepo.CreateUserToken(ctx, &entity.UserToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: auth.HashToken(plain),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	})
 	if err != nil {
 		return "", err
 	}
+	return plain, nil
+}
+
+// emailLink dựng link frontend mang token, VD: https://app/reset-password?token=...
+func (s *authService) emailLink(path, token string) string {
+	return s.cfg.AppBaseURL + path + "?token=" + url.QueryEscape(token)
+}
+
+// newRefreshToken sinh refresh token mới thuộc họ familyID. Trả về bản ghi (chỉ có hash) và token gốc gửi cho client.
+func (s *authService) newRefreshToken(userID, familyID uuid.UUID) (*entity.RefreshToken, string, error) {
+	plain, err := auth.GenerateOpaqueToken()
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+	return &entity.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: auth.HashToken(plain),
+		ExpiresAt: now.Add(s.cfg.RefreshTTL),
+		CreatedAt: now,
+	}, plain, nil
+}
+
+// tokenPair ký access token cho user và ghép với refresh token vừa phát.
+func (s *authService) tokenPair(user *entity.User, refreshToken string) (*entity.TokenPair, error) {
+	accessToken, _, err := auth.GenerateToken(user.ID.String(), user.Role, s.keys, s.cfg.AccessTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(s.cfg.AccessTTL.Seconds()),
+	}, nil
+}
 
-	return token, nil
+func (s *authService) revokeFamily(ctx context.Context, token *entity.RefreshToken) {
+	log.Printf("Phát hiện refresh token bị dùng lại (user %s), thu hồi cả họ %s", token.UserID, token.FamilyID)
+	if err := s.tokenRepo.RevokeRefreshFamily(ctx, token.FamilyID); err != nil {
+		log.Printf("Thu hồi họ refresh token %s thất bại: %v", token.FamilyID, err)
+	}
 }
 
 // ValidateToken dùng để kiểm tra thẻ JWT có hợp lệ không
 func (s *authService) ValidateToken(ctx context.Context, token string) (*entity.User, error) {
-	claims, err := auth.ValidateToken(token, s.secretKey)
+	claims, err := auth.ValidateToken(token, s.keys)
 	if err != nil {
 		return nil, err
 	}
+	revoked, err := s.tokenRepo.IsAccessTokenRevoked(ctx, claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, errors.New("token đã bị thu hồi")
+	}
 
 	// Lấy lại thông tin User từ DB dựa trên ID trong token (để đảm bảo user vẫn tồn tại)
 	return s.userRepo.GetUserByID(ctx, claims.UserID)
@@ -0,0 +1,391 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/yourname/ticketing-system/internal/adapter/repository"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+const (
+	defaultBallotMaxPerEntry   = 4
+	defaultBallotPaymentWindow = 24 * 60 // phút
+)
+
+// BallotService bán vé theo hình thức bốc thăm cho loại vé cầu vượt cung:
+// đăng ký trong khung giờ -> admin bốc -> người trúng có đơn PENDING tới hạn thanh toán
+// -> admin bốc bổ sung từ người chưa trúng cho các suất không thanh toán.
+type BallotService struct {
+	db        *gorm.DB
+	repo      *repository.BallotRepository
+	orderRepo *repository.OrderRepository
+	orders    *OrderService // hủy đơn trúng quá hạn (trả kho) dùng chung logic với hủy đơn thường
+	notifier  port.NotifierPort
+}
+
+func NewBallotService(db *gorm.DB, repo *repository.BallotRepository, orderRepo *repository.OrderRepository, orders *OrderService, notifier port.NotifierPort) *BallotService {
+	return &BallotService{
+		db:        db,
+		repo:      repo,
+		orderRepo: orderRepo,
+		orders:    orders,
+		notifier:  notifier,
+	}
+}
+
+// BallotRank tính thứ hạng bốc thăm của entry từ seed đã công bố. Ai có seed và danh sách
+// entry id đều tính lại được đúng thứ tự: rank nhỏ hơn xếp trước.
+func BallotRank(seed string, entryID uuid.UUID) string {
+	sum := sha256.Sum256([]byte(seed + ":" + entryID.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func (b *BallotService) CreateBallot(ctx context.Context, req entity.CreateBallotRequest) (*entity.Ballot, error) {
+	if !req.EntryClosesAt.After(req.EntryOpensAt) {
+		return nil, errors.New("thời gian đóng đăng ký phải sau thời gian mở")
+	}
+	if req.MaxPerEntry == 0 {
+		req.MaxPerEntry = defaultBallotMaxPerEntry
+	}
+	if req.PaymentWindowMinutes == 0 {
+		req.PaymentWindowMinutes = defaultBallotPaymentWindow
+	}
+	if req.MaxPerEntry < 0 || req.PaymentWindowMinutes < 0 {
+		return nil, errors.New("số vé tối đa và thời gian thanh toán phải lớn hơn 0")
+	}
+
+	seedBytes := make([]byte, 32)
+	if _, err := rand.Read(seedBytes); err != nil {
+		return nil, err
+	}
+	seed := hex.EncodeToString(seedBytes)
+	seedHash := sha256.Sum256([]byte(seed))
+
+	tx := b.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	ticketType, err := b.orderRepo.GetTicketTypeForUpdate(ctx, tx, req.TicketTypeID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("loại vé không tồn tại")
+		}
+		return nil, err
+	}
+	if ticketType.Reserved {
+		tx.Rollback()
+		return nil, errors.New("vé ngồi theo số không hỗ trợ bốc thăm")
+	}
+	if ticketType.Ballot {
+		tx.Rollback()
+		return nil, errors.New("loại vé này đã có đợt bốc thăm")
+	}
+
+	ballot := &entity.Ballot{
+		ID:                   uuid.New(),
+		TicketTypeID:         req.TicketTypeID,
+		EntryOpensAt:         req.EntryOpensAt,
+		EntryClosesAt:        req.EntryClosesAt,
+		MaxPerEntry:          req.MaxPerEntry,
+		PaymentWindowMinutes: req.PaymentWindowMinutes,
+		Status:               entity.BallotStatusOpen,
+		Seed:                 seed,
+		SeedHash:             hex.EncodeToString(seedHash[:]),
+		CreatedAt:            time.Now(),
+		UpdatedAt:            time.Now(),
+	}
+	if err := b.repo.CreateBallot(ctx, tx, ballot); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return hideSeed(ballot), nil
+}
+
+// GetBallot trả về ballot; seed chỉ lộ sau khi đã bốc.
+func (b *BallotService) GetBallot(ctx context.Context, id uuid.UUID) (*entity.Ballot, error) {
+	ballot, err := b.repo.GetBallot(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return hideSeed(ballot), nil
+}
+
+// Enter đăng ký tham gia bốc thăm. Mỗi user một lượt cho mỗi ballot.
+func (b *BallotService) Enter(ctx context.Context, userID, ballotID uuid.UUID, req entity.EnterBallotRequest) (*entity.BallotEntry, error) {
+	ballot, err := b.repo.GetBallot(ctx, ballotID)
+	if err != nil {
+		return nil, errors.New("không tìm thấy đợt bốc thăm")
+	}
+
+	now := time.Now()
+	if ballot.Status != entity.BallotStatusOpen || now.Before(ballot.EntryOpensAt) || !now.Before(ballot.EntryClosesAt) {
+		return nil, errors.New("ngoài thời gian đăng ký bốc thăm")
+	}
+	if req.Quantity <= 0 || req.Quantity > ballot.MaxPerEntry {
+		return nil, fmt.Errorf("số lượng phải từ 1 đến %d", ballot.MaxPerEntry)
+	}
+
+	if _, err := b.repo.GetEntryByUser(ctx, ballotID, userID); err == nil {
+		return nil, errors.New("bạn đã đăng ký đợt bốc thăm này")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	entry := &entity.BallotEntry{
+		ID:        uuid.New(),
+		BallotID:  ballotID,
+		UserID:    userID,
+		Quantity:  req.Quantity,
+		Status:    entity.BallotEntryStatusEntered,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := b.repo.CreateEntry(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// GetMyEntry trả về lượt đăng ký của user (kèm kết quả nếu đã bốc).
+func (b *BallotService) GetMyEntry(ctx context.Context, userID, ballotID uuid.UUID) (*entity.BallotEntry, error) {
+	return b.repo.GetEntryByUser(ctx, ballotID, userID)
+}
+
+// Results trả về toàn bộ entry theo thứ tự bốc để công bố và kiểm chứng (không kèm user).
+func (b *BallotService) Results(ctx context.Context, ballotID uuid.UUID) ([]entity.BallotEntry, error) {
+	ballot, err := b.repo.GetBallot(ctx, ballotID)
+	if err != nil {
+		return nil, err
+	}
+	if ballot.Status != entity.BallotStatusDrawn {
+		return nil, errors.New("đợt bốc thăm chưa có kết quả")
+	}
+	return b.repo.ListResults(ctx, ballotID)
+}
+
+// Draw bốc thăm lần đầu sau khi đóng đăng ký: xếp hạng mọi entry bằng seed rồi phân bổ vé.
+func (b *BallotService) Draw(ctx context.Context, ballotID uuid.UUID) (*entity.BallotDrawResult, error) {
+	tx := b.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	ballot, err := b.repo.GetBallotForUpdate(ctx, tx, ballotID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if ballot.Status != entity.BallotStatusOpen {
+		tx.Rollback()
+		return nil, errors.New("đợt bốc thăm đã được bốc")
+	}
+	if time.Now().Before(ballot.EntryClosesAt) {
+		tx.Rollback()
+		return nil, errors.New("chưa hết thời gian đăng ký")
+	}
+
+	entries, err := b.repo.ListEntriesForUpdate(ctx, tx, ballotID, []entity.BallotEntryStatus{entity.BallotEntryStatusEntered})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	for i := range entries {
+		entries[i].DrawRank = BallotRank(ballot.Seed, entries[i].ID)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].DrawRank < entries[j].DrawRank
+	})
+	for i := range entries {
+		position := i + 1
+		entries[i].Position = &position
+	}
+
+	now := time.Now()
+	ballot.Status = entity.BallotStatusDrawn
+	ballot.DrawnAt = &now
+	if err := b.repo.SaveBallot(ctx, tx, ballot); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	result := &entity.BallotDrawResult{Ballot: ballot}
+	winners, err := b.allocate(ctx, tx, ballot, entries, result)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	b.notifyWinners(ctx, winners)
+	return result, nil
+}
+
+// Redraw thu hồi các suất trúng không thanh toán (đơn đã hủy/hết hạn, hoặc còn PENDING nhưng
+// quá hạn) rồi phân bổ vé đó cho người chưa trúng, tiếp tục theo đúng thứ tự của lần bốc đầu.
+func (b *BallotService) Redraw(ctx context.Context, ballotID uuid.UUID) (*entity.BallotDrawResult, error) {
+	tx := b.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	ballot, err := b.repo.GetBallotForUpdate(ctx, tx, ballotID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if ballot.Status != entity.BallotStatusDrawn {
+		tx.Rollback()
+		return nil, errors.New("đợt bốc thăm chưa được bốc lần đầu")
+	}
+
+	result := &entity.BallotDrawResult{Ballot: ballot}
+
+	won, err := b.repo.ListEntriesForUpdate(ctx, tx, ballotID, []entity.BallotEntryStatus{entity.BallotEntryStatusWon})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	for i := range won {
+		unpaid, err := b.reclaimIfUnpaid(ctx, tx, &won[i])
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		if unpaid {
+			result.Unpaid++
+		}
+	}
+
+	candidates, err := b.repo.ListEntriesForUpdate(ctx, tx, ballotID, []entity.BallotEntryStatus{entity.BallotEntryStatusNotSelected})
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	winners, err := b.allocate(ctx, tx, ballot, candidates, result)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	b.notifyWinners(ctx, winners)
+	return result, nil
+}
+
+// reclaimIfUnpaid đánh dấu UNPAID cho entry trúng mà đơn đã bị hủy, hoặc hủy luôn đơn
+// còn PENDING đã quá hạn thanh toán (trả vé về kho để bốc bổ sung).
+func (b *BallotService) reclaimIfUnpaid(ctx context.Context, tx *gorm.DB, entry *entity.BallotEntry) (bool, error) {
+	if entry.OrderID == nil {
+		return false, nil
+	}
+	order, err := b.orderRepo.GetOrderForUpdate(ctx, tx, *entry.OrderID)
+	if err != nil {
+		return false, err
+	}
+
+	switch order.Status {
+	case entity.OrderStatusPaid:
+		return false, nil
+	case entity.OrderStatusPending:
+		if entry.PaymentDeadline == nil || time.Now().Before(*entry.PaymentDeadline) {
+			return false, nil
+		}
+		// Loại vé bốc thăm không có hàng chờ nên không có offer nào cần báo
+		if _, err := b.orders.cancelLockedOrder(ctx, tx, order); err != nil {
+			return false, err
+		}
+	}
+
+	entry.Status = entity.BallotEntryStatusUnpaid
+	if err := b.repo.SaveEntry(ctx, tx, entry); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// allocate phân bổ vé còn lại cho entries theo thứ tự đã xếp. Entry cần nhiều hơn số vé còn
+// thì bỏ qua (NOT_SELECTED, chờ lượt bổ sung) và xét tiếp entry sau.
+func (b *BallotService) allocate(ctx context.Context, tx *gorm.DB, ballot *entity.Ballot, entries []entity.BallotEntry, result *entity.BallotDrawResult) ([]entity.BallotEntry, error) {
+	ticketType, err := b.orderRepo.GetTicketTypeForUpdate(ctx, tx, ballot.TicketTypeID)
+	if err != nil {
+		return nil, err
+	}
+	available, err := availableStock(ctx, tx, b.orderRepo, ticketType)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(time.Duration(ballot.PaymentWindowMinutes) * time.Minute)
+	var winners []entity.BallotEntry
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Quantity > available {
+			entry.Status = entity.BallotEntryStatusNotSelected
+			result.NotSelected++
+		} else {
+			if err := reserveStock(ctx, tx, b.orderRepo, ticketType, entry.Quantity); err != nil {
+				return nil, err
+			}
+			ticketType.RemainingQuantity -= entry.Quantity
+			available -= entry.Quantity
+
+			order, err := createHeldTicketOrder(ctx, tx, b.orderRepo, entry.UserID, ticketType, entry.Quantity, &deadline)
+			if err != nil {
+				return nil, err
+			}
+			entry.Status = entity.BallotEntryStatusWon
+			entry.OrderID = &order.ID
+			entry.PaymentDeadline = &deadline
+			result.Winners++
+			result.Allocated += entry.Quantity
+			winners = append(winners, *entry)
+		}
+
+		if err := b.repo.SaveEntry(ctx, tx, entry); err != nil {
+			return nil, err
+		}
+	}
+	return winners, nil
+}
+
+// notifyWinners báo cho người trúng. Gọi sau khi commit; lỗi gửi chỉ log lại.
+func (b *BallotService) notifyWinners(ctx context.Context, winners []entity.BallotEntry) {
+	for _, winner := range winners {
+		message := fmt.Sprintf("Bạn đã trúng %d vé. Vui lòng thanh toán đơn %s trước %s",
+			winner.Quantity, winner.OrderID, winner.PaymentDeadline.Format(time.RFC3339))
+		if err := b.notifier.Notify(ctx, winner.UserID, "Bạn đã trúng bốc thăm", message); err != nil {
+			log.Printf("Gửi thông báo trúng bốc thăm %s thất bại: %v", winner.ID, err)
+		}
+	}
+}
+
+// hideSeed xóa seed khỏi ballot chưa bốc, chỉ để lại seed_hash đã cam kết.
+func hideSeed(ballot *entity.Ballot) *entity.Ballot {
+	if ballot.Status != entity.BallotStatusDrawn {
+		ballot.Seed = ""
+	}
+	return ballot
+}
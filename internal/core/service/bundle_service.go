@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+type bundleService struct {
+	bundleRepo port.BundleRepositoryPort
+	eventRepo  port.EventRepositoryPort
+}
+
+func NewBundleService(bundleRepo port.BundleRepositoryPort, eventRepo port.EventRepositoryPort) port.BundleServicePort {
+	return &bundleService{
+		bundleRepo: bundleRepo,
+		eventRepo:  eventRepo,
+	}
+}
+
+func (s *bundleService) CreateBundle(ctx context.Context, req entity.CreateBundleRequest) (*entity.Bundle, error) {
+	if req.Name == "" {
+		return nil, errors.New("tên gói vé không được để trống")
+	}
+	if req.Price.IsNegative() || req.Price.IsZero() {
+		return nil, errors.New("giá gói vé phải lớn hơn 0")
+	}
+	if req.InitialQuantity <= 0 {
+		return nil, errors.New("số lượng gói vé phải lớn hơn 0")
+	}
+	if len(req.Items) < 2 {
+		return nil, errors.New("gói vé phải gồm ít nhất 2 loại vé")
+	}
+
+	ids := make([]uuid.UUID, 0, len(req.Items))
+	seen := make(map[uuid.UUID]bool, len(req.Items))
+	for _, item := range req.Items {
+		if seen[item.TicketTypeID] {
+			return nil, errors.New("loại vé bị trùng trong gói")
+		}
+		seen[item.TicketTypeID] = true
+		ids = append(ids, item.TicketTypeID)
+	}
+
+	ticketTypes, err := s.eventRepo.GetTicketTypesByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(ticketTypes) != len(ids) {
+		return nil, errors.New("có loại vé không tồn tại")
+	}
+	for _, tt := range ticketTypes {
+		// Vé ngồi theo số cần chọn ghế cụ thể, không bán gộp trong combo được
+		if tt.Reserved {
+			return nil, errors.New("loại vé " + tt.Name + " là vé ngồi theo số, không đưa vào gói được")
+		}
+		if tt.Ballot {
+			return nil, errors.New("loại vé " + tt.Name + " chỉ bán qua bốc thăm, không đưa vào gói được")
+		}
+		if !tt.IsPublic() {
+			return nil, errors.New("loại vé " + tt.Name + " chỉ bán bằng mã presale, không đưa vào gói được")
+		}
+	}
+
+	bundle := &entity.Bundle{
+		ID:                uuid.New(),
+		Name:              req.Name,
+		Description:       req.Description,
+		Price:             req.Price,
+		InitialQuantity:   req.InitialQuantity,
+		RemainingQuantity: req.InitialQuantity,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+	}
+	for _, item := range req.Items {
+		quantity := item.Quantity
+		if quantity <= 0 {
+			quantity = 1
+		}
+		bundle.Items = append(bundle.Items, entity.BundleItem{
+			ID:           uuid.New(),
+			BundleID:     bundle.ID,
+			TicketTypeID: item.TicketTypeID,
+			Quantity:     quantity,
+		})
+	}
+
+	if err := s.bundleRepo.CreateBundle(ctx, bundle); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+func (s *bundleService) GetBundle(ctx context.Context, id uuid.UUID) (*entity.Bundle, error) {
+	return s.bundleRepo.GetBundleByID(ctx, id)
+}
+
+func (s *bundleService) ListBundles(ctx context.Context, limit int, offset int) ([]entity.Bundle, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.bundleRepo.ListBundles(ctx, limit, offset)
+}
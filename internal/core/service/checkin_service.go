@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/yourname/ticketing-system/internal/adapter/repository"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+// CheckInService soát vé tại cổng (quyền tickets:scan theo event): vé hợp lệ chuyển sang USED
+// nên mỗi mã chỉ qua cổng một lần.
+type CheckInService struct {
+	db      *gorm.DB
+	tickets *repository.TransferRepository // khóa vé dùng chung với chuyển nhượng
+}
+
+func NewCheckInService(db *gorm.DB, tickets *repository.TransferRepository) *CheckInService {
+	return &CheckInService{
+		db:      db,
+		tickets: tickets,
+	}
+}
+
+// CheckIn soát vé có mã req.TicketCode cho event eventID và trả về vé đã đánh dấu USED.
+func (s *CheckInService) CheckIn(ctx context.Context, eventID uuid.UUID, req entity.CheckInRequest) (*entity.Ticket, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.TicketCode))
+	if code == "" {
+		return nil, errors.New("thiếu mã vé")
+	}
+
+	tx := s.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	ticket, err := s.tickets.GetTicketByCodeForUpdate(ctx, tx, code)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("mã vé không hợp lệ")
+		}
+		return nil, err
+	}
+
+	ticketEventID, err := s.tickets.TicketTypeEventID(ctx, tx, ticket.TicketTypeID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if ticketEventID != eventID {
+		tx.Rollback()
+		return nil, errors.New("vé không thuộc sự kiện này")
+	}
+	if ticket.Status != entity.TicketStatusUnused {
+		tx.Rollback()
+		return nil, errors.New("vé đã được sử dụng")
+	}
+
+	status, err := s.tickets.OrderStatus(ctx, tx, ticket.OrderID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if status != entity.OrderStatusPaid {
+		tx.Rollback()
+		return nil, errors.New("đơn của vé chưa thanh toán")
+	}
+
+	// Vé đang rao bán lại có thể đã có người đặt mua, người bán phải gỡ tin trước khi vào cổng
+	listed, err := s.tickets.HasActiveListing(ctx, tx, ticket.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if listed {
+		tx.Rollback()
+		return nil, errors.New("vé đang được rao bán lại, hãy gỡ tin bán trước khi vào cổng")
+	}
+
+	ticket.Status = entity.TicketStatusUsed
+	if err := s.tickets.SaveTicket(ctx, tx, ticket); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return ticket, nil
+}
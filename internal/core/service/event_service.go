@@ -6,17 +6,30 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
 	"github.com/yourname/ticketing-system/internal/core/entity"
 	"github.com/yourname/ticketing-system/internal/core/port"
+	"github.com/yourname/ticketing-system/pkg/cursor"
 )
 
+// FilterError là lỗi tham số lọc / sắp xếp / cursor không hợp lệ. Handler trả 400, lỗi khác (DB...) trả 500.
+type FilterError struct {
+	Reason string
+}
+
+func (e *FilterError) Error() string {
+	return e.Reason
+}
+
 type eventService struct {
 	eventRepo port.EventRepositoryPort
+	venueRepo port.VenueRepositoryPort
 }
 
-func NewEventService(eventRepo port.EventRepositoryPort) port.EventServicePort {
+func NewEventService(eventRepo port.EventRepositoryPort, venueRepo port.VenueRepositoryPort) port.EventServicePort {
 	return &eventService{
 		eventRepo: eventRepo,
+		venueRepo: venueRepo,
 	}
 }
 
@@ -32,18 +45,32 @@ func (s *eventService) CreateEvent(ctx context.Context, req entity.CreateEventRe
 		return nil, errors.New("slug đã được sử dụng")
 	}
 
+	// Event gắn với venue thì lấy tên venue làm location nếu không nhập
+	if req.VenueID != nil {
+		venue, err := s.venueRepo.GetVenueByID(ctx, *req.VenueID)
+		if err != nil {
+			return nil, errors.New("địa điểm không tồn tại")
+		}
+		if req.Location == "" {
+			req.Location = venue.Name
+		}
+	}
+
 	// Create event
 	event := &entity.Event{
 		ID:        uuid.New(),
 		Name:      req.Name,
 		Slug:      req.Slug,
 		Location:  req.Location,
+		VenueID:   req.VenueID,
 		BannerURL: req.BannerURL,
 		StartTime: req.StartTime,
 		EndTime:   req.EndTime,
 		Status:    entity.EventStatusDraft,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
+
+		OrganizationID: req.OrganizationID,
 	}
 
 	// Save event to database
@@ -56,6 +83,30 @@ func (s *eventService) CreateEvent(ctx context.Context, req entity.CreateEventRe
 
 // CreateEventWithTickets tạo event + ticket types (dùng cho test)
 func (s *eventService) CreateEventWithTickets(ctx context.Context, eventReq entity.CreateEventRequest, ticketTypes []entity.CreateTicketTypeRequest) (*entity.Event, error) {
+	// Kiểm tra vé trước khi tạo event để không sinh event "mồ côi" khi vé sai
+	for _, tt := range ticketTypes {
+		if err := validateCreateTicketTypeRequest(tt); err != nil {
+			return nil, err
+		}
+	}
+
+	// Tổng số vé không được vượt sức chứa venue (và từng khu)
+	if eventReq.VenueID != nil {
+		venue, err := s.venueRepo.GetVenueByID(ctx, *eventReq.VenueID)
+		if err != nil {
+			return nil, errors.New("địa điểm không tồn tại")
+		}
+		if err := validateTicketCapacity(venue, ticketTypes); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, tt := range ticketTypes {
+			if tt.SectionID != nil {
+				return nil, errors.New("sự kiện chưa gắn địa điểm nên không chọn được khu")
+			}
+		}
+	}
+
 	// Tạo event trước
 	event, err := s.CreateEvent(ctx, eventReq)
 	if err != nil {
@@ -66,17 +117,15 @@ func (s *eventService) CreateEventWithTickets(ctx context.Context, eventReq enti
 	if len(ticketTypes) > 0 {
 		tickets := make([]entity.TicketType, 0, len(ticketTypes))
 		for _, tt := range ticketTypes {
-			if err := validateCreateTicketTypeRequest(tt); err != nil {
-				return nil, err
-			}
-
 			ticketType := entity.TicketType{
 				ID:                uuid.New(),
 				EventID:           event.ID,
+				SectionID:         tt.SectionID,
 				Name:              tt.Name,
 				Price:             tt.Price,
 				InitialQuantity:   tt.InitialQuantity,
 				RemainingQuantity: tt.InitialQuantity,
+				Visibility:        ticketVisibility(tt.Visibility),
 			}
 			tickets = append(tickets, ticketType)
 		}
@@ -91,24 +140,156 @@ func (s *eventService) CreateEventWithTickets(ctx context.Context, eventReq enti
 }
 
 func (s *eventService) GetEvent(ctx context.Context, id uuid.UUID) (*entity.Event, error) {
-	return s.eventRepo.GetEventByID(ctx, id)
+	event, err := s.eventRepo.GetEventByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.withPublicTicketTypes(ctx, event)
 }
 
 func (s *eventService) GetEventBySlug(ctx context.Context, slug string) (*entity.Event, error) {
-	return s.eventRepo.GetEventBySlug(ctx, slug)
+	event, err := s.eventRepo.GetEventBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	return s.withPublicTicketTypes(ctx, event)
 }
 
-func (s *eventService) ListEvents(ctx context.Context, limit int, offset int) ([]entity.Event, error) {
-	if limit <= 0 {
-		limit = 10
+// withPublicTicketTypes gắn các loại vé công khai vào event; vé ẩn/presale không bao giờ lộ ra đây.
+func (s *eventService) withPublicTicketTypes(ctx context.Context, event *entity.Event) (*entity.Event, error) {
+	ticketTypes, err := s.eventRepo.ListTicketTypesByEvent(ctx, event.ID)
+	if err != nil {
+		return nil, err
 	}
-	if limit > 100 {
-		limit = 100
+	event.TicketTypes = publicTicketTypes(ticketTypes)
+	return event, nil
+}
+
+// publicTicketTypes lọc bỏ các loại vé HIDDEN/CODE.
+func publicTicketTypes(ticketTypes []entity.TicketType) []entity.TicketType {
+	public := make([]entity.TicketType, 0, len(ticketTypes))
+	for _, tt := range ticketTypes {
+		if tt.IsPublic() {
+			public = append(public, tt)
+		}
 	}
-	if offset < 0 {
-		offset = 0
+	return public
+}
+
+// ticketVisibility chuẩn hóa visibility từ request, bỏ trống = PUBLIC.
+func ticketVisibility(v entity.TicketVisibility) entity.TicketVisibility {
+	if v == "" {
+		return entity.TicketVisibilityPublic
 	}
-	return s.eventRepo.ListEvents(ctx, limit, offset)
+	return v
+}
+
+func (s *eventService) ListEvents(ctx context.Context, filter entity.EventFilter) (*entity.EventPage, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 10
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+	if filter.Sort == "" {
+		filter.Sort = entity.EventSortStartTime
+	}
+	if err := validateEventFilter(filter); err != nil {
+		return nil, err
+	}
+	key := eventFilterKey(filter)
+	if err := checkPageCursor(filter.Cursor, key); err != nil {
+		return nil, err
+	}
+	if filter.Cursor != nil {
+		filter.Offset = 0
+	}
+
+	// Lấy dư 1 dòng để biết còn trang tiếp theo hay không
+	limit := filter.Limit
+	filter.Limit = limit + 1
+	events, total, err := s.eventRepo.ListEvents(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(events) > limit
+	if hasMore {
+		events = events[:limit]
+	}
+
+	backward := filter.Cursor != nil && filter.Cursor.Backward
+	if backward {
+		// Repo trả về theo chiều ngược, đảo lại cho đúng thứ tự hiển thị
+		for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+			events[i], events[j] = events[j], events[i]
+		}
+	}
+
+	page := &entity.EventPage{Events: events, Total: total}
+	if len(events) == 0 || filter.Sort == entity.EventSortPopularity {
+		// Popularity chỉ phân trang bằng offset (xem validateEventFilter) nên không cấp cursor
+		return page, nil
+	}
+
+	first, last := events[0], events[len(events)-1]
+	if backward {
+		// Đi lùi: phía sau chắc chắn còn (trang vừa rời khỏi), phía trước còn nếu hasMore
+		page.NextCursor = eventCursor(filter.Sort, key, last, false)
+		if hasMore {
+			page.PrevCursor = eventCursor(filter.Sort, key, first, true)
+		}
+	} else {
+		if hasMore {
+			page.NextCursor = eventCursor(filter.Sort, key, last, false)
+		}
+		if filter.Cursor != nil || filter.Offset > 0 {
+			page.PrevCursor = eventCursor(filter.Sort, key, first, true)
+		}
+	}
+	return page, nil
+}
+
+// eventFilterKey là dấu vân tay của bộ lọc + sắp xếp (không gồm limit / offset / cursor).
+func eventFilterKey(filter entity.EventFilter) string {
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+	formatPrice := func(p *decimal.Decimal) string {
+		if p == nil {
+			return ""
+		}
+		return p.String()
+	}
+	return cursor.Fingerprint("events", filter.Query, string(filter.Status), filter.Location,
+		formatTime(filter.StartFrom), formatTime(filter.StartTo), formatPrice(filter.MinPrice), formatPrice(filter.MaxPrice),
+		string(filter.Sort))
+}
+
+// checkPageCursor từ chối cursor cấp cho danh sách khác (bộ lọc / sắp xếp khác) để không trả về trang sai.
+func checkPageCursor(cur *entity.PageCursor, key string) error {
+	if cur != nil && cur.Filter != key {
+		return &FilterError{Reason: "cursor không khớp với bộ lọc hoặc kiểu sắp xếp"}
+	}
+	return nil
+}
+
+// eventCursor lấy vị trí keyset của event theo cột đang sắp xếp.
+func eventCursor(sort entity.EventSort, key string, event entity.Event, backward bool) *entity.PageCursor {
+	var value string
+	switch sort {
+	case entity.EventSortNewest:
+		value = event.CreatedAt.Format(time.RFC3339Nano)
+	default:
+		value = event.StartTime.Format(time.RFC3339Nano)
+	}
+	return &entity.PageCursor{Sort: string(sort), Value: value, ID: event.ID, Backward: backward, Filter: key}
 }
 
 func validateCreateEventRequest(req entity.CreateEventRequest) error {
@@ -118,7 +299,7 @@ func validateCreateEventRequest(req entity.CreateEventRequest) error {
 	if req.Slug == "" {
 		return errors.New("slug không được để trống")
 	}
-	if req.Location == "" {
+	if req.Location == "" && req.VenueID == nil {
 		return errors.New("địa điểm không được để trống")
 	}
 	if req.StartTime.IsZero() {
@@ -143,5 +324,37 @@ func validateCreateTicketTypeRequest(req entity.CreateTicketTypeRequest) error {
 	if req.InitialQuantity <= 0 {
 		return errors.New("số lượng vé phải lớn hơn 0")
 	}
+	switch req.Visibility {
+	case "", entity.TicketVisibilityPublic, entity.TicketVisibilityHidden, entity.TicketVisibilityCode:
+	default:
+		return errors.New("visibility của loại vé không hợp lệ")
+	}
+	return nil
+}
+
+func validateEventFilter(filter entity.EventFilter) error {
+	switch filter.Status {
+	case "", entity.EventStatusDraft, entity.EventStatusPublished, entity.EventStatusCancelled, entity.EventStatusEnded:
+	default:
+		return &FilterError{Reason: "trạng thái sự kiện không hợp lệ"}
+	}
+	switch filter.Sort {
+	case entity.EventSortStartTime, entity.EventSortNewest, entity.EventSortPopularity:
+	default:
+		return &FilterError{Reason: "kiểu sắp xếp không hợp lệ"}
+	}
+	// Số vé đã bán đổi theo từng đơn, keyset trên nó làm lặp / sót event giữa các trang
+	if filter.Sort == entity.EventSortPopularity && filter.Cursor != nil {
+		return &FilterError{Reason: "sắp xếp theo độ phổ biến chỉ phân trang bằng offset, không dùng cursor"}
+	}
+	if filter.StartFrom != nil && filter.StartTo != nil && filter.StartTo.Before(*filter.StartFrom) {
+		return &FilterError{Reason: "khoảng thời gian không hợp lệ"}
+	}
+	if filter.MinPrice != nil && filter.MinPrice.IsNegative() {
+		return &FilterError{Reason: "giá tối thiểu không được âm"}
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && filter.MaxPrice.LessThan(*filter.MinPrice) {
+		return &FilterError{Reason: "giá tối đa phải lớn hơn giá tối thiểu"}
+	}
 	return nil
 }
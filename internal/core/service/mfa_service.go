@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/pkg/auth"
+)
+
+// Xác thực 2 bước (TOTP) của authService: đăng ký ứng dụng xác thực, bước 2 khi đăng nhập,
+// mã khôi phục và tắt 2FA.
+
+const (
+	mfaChallengeTTL   = 5 * time.Minute // Thời gian nhập mã TOTP sau khi đúng mật khẩu
+	recoveryCodeCount = 10
+)
+
+var (
+	errInvalidMFAChallenge = errors.New("phiên đăng nhập 2 bước không hợp lệ hoặc đã hết hạn, vui lòng đăng nhập lại")
+	errInvalidMFACode      = errors.New("mã xác thực không đúng")
+)
+
+// VerifyMFALogin là bước 2 của đăng nhập: kiểm tra mã TOTP (hoặc mã khôi phục) theo challenge token
+// rồi phát cặp token. Nếu user đang đăng ký dở (role bắt buộc 2FA), mã đúng đầu tiên sẽ bật 2FA
+// và trả kèm mã khôi phục. Mã sai được tính vào bộ đếm đăng nhập sai như sai mật khẩu.
+func (s *authService) VerifyMFALogin(ctx context.Context, req entity.MFALoginRequest) (*entity.MFALoginResponse, error) {
+	challenge, err := s.tokenRepo.GetUserToken(ctx, auth.HashToken(req.ChallengeToken), entity.TokenPurposeMFAChallenge)
+	if err != nil {
+		return nil, errInvalidMFAChallenge
+	}
+	user, err := s.userRepo.GetUserByID(ctx, challenge.UserID.String())
+	if err != nil {
+		return nil, errInvalidMFAChallenge
+	}
+
+	keys := loginThrottleKeys(entity.LoginRequest{Email: user.Email, IP: req.IP})
+	if err := s.checkLoginLock(ctx, keys); err != nil {
+		return nil, err
+	}
+
+	totp, err := s.mfaRepo.GetTOTP(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if totp == nil {
+		return nil, errors.New("chưa đăng ký ứng dụng xác thực")
+	}
+
+	var recoveryCodes []string
+	if totp.EnabledAt == nil {
+		recoveryCodes, err = s.enableTOTP(ctx, totp, req.Code)
+	} else {
+		err = s.checkSecondFactor(ctx, totp, req.Code, req.RecoveryCode)
+	}
+	if err != nil {
+		if errors.Is(err, errInvalidMFACode) {
+			s.recordLoginFailure(ctx, keys)
+		}
+		return nil, err
+	}
+
+	// Challenge chỉ dùng được một lần
+	if _, err := s.tokenRepo.ConsumeUserToken(ctx, challenge.TokenHash, entity.TokenPurposeMFAChallenge); err != nil {
+		return nil, errInvalidMFAChallenge
+	}
+
+	pair, err := s.startSession(ctx, user, keys)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.MFALoginResponse{TokenPair: pair, RecoveryCodes: recoveryCodes}, nil
+}
+
+// BeginMFAEnrollment cấp secret TOTP mới cho user đang đăng nhập (chưa bật 2FA).
+func (s *authService) BeginMFAEnrollment(ctx context.Context, userID uuid.UUID) (*entity.MFAEnrollment, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID.String())
+	if err != nil {
+		return nil, errors.New("user không tồn tại")
+	}
+	return s.beginEnrollment(ctx, user)
+}
+
+// BeginMFAEnrollmentWithChallenge cấp secret TOTP trong lúc đăng nhập, cho user thuộc role bắt buộc
+// 2FA mà chưa đăng ký (chưa có access token). Hoàn tất bằng VerifyMFALogin.
+func (s *authService) BeginMFAEnrollmentWithChallenge(ctx context.Context, challengeToken string) (*entity.MFAEnrollment, error) {
+	challenge, err := s.tokenRepo.GetUserToken(ctx, auth.HashToken(challengeToken), entity.TokenPurposeMFAChallenge)
+	if err != nil {
+		return nil, errInvalidMFAChallenge
+	}
+	user, err := s.userRepo.GetUserByID(ctx, challenge.UserID.String())
+	if err != nil {
+		return nil, errInvalidMFAChallenge
+	}
+	return s.beginEnrollment(ctx, user)
+}
+
+// ConfirmMFAEnrollment bật 2FA khi user nhập đúng mã từ ứng dụng vừa thêm, trả về mã khôi phục (chỉ hiện một lần).
+func (s *authService) ConfirmMFAEnrollment(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	totp, err := s.mfaRepo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if totp == nil {
+		return nil, errors.New("chưa bắt đầu đăng ký ứng dụng xác thực")
+	}
+	if totp.EnabledAt != nil {
+		return nil, errors.New("đã bật xác thực 2 bước")
+	}
+	return s.enableTOTP(ctx, totp, code)
+}
+
+// RegenerateRecoveryCodes cấp bộ mã khôi phục mới (bộ cũ hết hiệu lực), cần mã TOTP hiện tại.
+func (s *authService) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	if _, err := s.verifyCurrentCode(ctx, userID, code); err != nil {
+		return nil, err
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// DisableMFA tắt 2FA (cần mã TOTP hiện tại). Role bắt buộc 2FA thì không được tắt.
+func (s *authService) DisableMFA(ctx context.Context, userID uuid.UUID, code string) error {
+	user, err := s.verifyCurrentCode(ctx, userID, code)
+	if err != nil {
+		return err
+	}
+	if s.mfaRequired(user) {
+		return errors.New("tài khoản admin bắt buộc bật xác thực 2 bước")
+	}
+	return s.mfaRepo.DeleteTOTP(ctx, userID)
+}
+
+// mfaRequired cho biết user có bắt buộc bật 2FA không (admin, khi bật cấu hình).
+func (s *authService) mfaRequired(user *entity.User) bool {
+	return s.cfg.RequireAdminMFA && user.Role == entity.RoleAdmin
+}
+
+func (s *authService) mfaEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	totp, err := s.mfaRepo.GetTOTP(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return totp != nil && totp.EnabledAt != nil, nil
+}
+
+func (s *authService) beginEnrollment(ctx context.Context, user *entity.User) (*entity.MFAEnrollment, error) {
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	saved, err := s.mfaRepo.SaveTOTPSecret(ctx, user.ID, secret)
+	if err != nil {
+		return nil, err
+	}
+	if !saved {
+		return nil, errors.New("đã bật xác thực 2 bước")
+	}
+
+	uri := auth.TOTPURI(s.cfg.MFAIssuer, user.Email, secret)
+	qr, err := auth.TOTPQRCode(uri)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.MFAEnrollment{Secret: secret, OTPAuthURI: uri, QRCodePNG: qr}, nil
+}
+
+// enableTOTP bật 2FA đang đăng ký dở nếu code đúng, trả về bộ mã khôi phục mới.
+func (s *authService) enableTOTP(ctx context.Context, totp *entity.UserTOTP, code string) ([]string, error) {
+	step, ok := auth.ValidateTOTP(totp.Secret, code, time.Now())
+	if !ok {
+		return nil, errInvalidMFACode
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.EnableTOTP(ctx, totp.UserID, step, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// checkSecondFactor kiểm tra mã khôi phục (nếu có) hoặc mã TOTP. Mỗi mã chỉ dùng được một lần.
+func (s *authService) checkSecondFactor(ctx context.Context, totp *entity.UserTOTP, code, recoveryCode string) error {
+	if recoveryCode != "" {
+		used, err := s.mfaRepo.UseRecoveryCode(ctx, totp.UserID, auth.HashToken(auth.NormalizeRecoveryCode(recoveryCode)))
+		if err != nil {
+			return err
+		}
+		if !used {
+			return errInvalidMFACode
+		}
+		return nil
+	}
+
+	step, ok := auth.ValidateTOTP(totp.Secret, code, time.Now())
+	if !ok {
+		return errInvalidMFACode
+	}
+	fresh, err := s.mfaRepo.UseTOTPStep(ctx, totp.UserID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return errInvalidMFACode // Mã đã dùng rồi (bị nghe lén / gửi lại)
+	}
+	return nil
+}
+
+// verifyCurrentCode xác nhận thao tác nhạy cảm trên 2FA bằng mã TOTP hiện tại. Mã sai cũng bị tính
+// vào bộ đếm đăng nhập sai để access token bị lộ không dò mã được.
+func (s *authService) verifyCurrentCode(ctx context.Context, userID uuid.UUID, code string) (*entity.User, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID.String())
+	if err != nil {
+		return nil, errors.New("user không tồn tại")
+	}
+	totp, err := s.mfaRepo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if totp == nil || totp.EnabledAt == nil {
+		return nil, errors.New("chưa bật xác thực 2 bước")
+	}
+
+	keys := loginThrottleKeys(entity.LoginRequest{Email: user.Email})
+	if err := s.checkLoginLock(ctx, keys); err != nil {
+		return nil, err
+	}
+	if err := s.checkSecondFactor(ctx, totp, code, ""); err != nil {
+		if errors.Is(err, errInvalidMFACode) {
+			s.recordLoginFailure(ctx, keys)
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// newRecoveryCodes sinh bộ mã khôi phục, trả về mã gốc (cho user) và hash (để lưu).
+func newRecoveryCodes() ([]string, []string, error) {
+	codes, err := auth.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, nil, err
+	}
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = auth.HashToken(auth.NormalizeRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
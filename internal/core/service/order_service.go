@@ -2,7 +2,12 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
 	"fmt"
+	"log"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,25 +16,36 @@ import (
 
 	"github.com/yourname/ticketing-system/internal/adapter/repository"
 	"github.com/yourname/ticketing-system/internal/core/entity"
+	pagecursor "github.com/yourname/ticketing-system/pkg/cursor" // Tên cursor dùng cho tham số phân trang
 )
 
 // RequestItem là struct đơn giản để nhận input từ client (mobile/web).
 // Ví dụ: {"ticket_type_id": "...", "quantity": 2}
 type RequestItem struct {
 	TicketTypeID uuid.UUID
+	BundleID     *uuid.UUID // Mua gói combo thay vì một loại vé (khi đó bỏ qua TicketTypeID)
+	AddOnID      *uuid.UUID // Mua hàng bán kèm (gửi xe, áo...), không phát hành vé
 	Quantity     int
+	SeatIDs      []uuid.UUID              // Bắt buộc với loại vé ngồi theo số (reserved), len = Quantity
+	AccessCode   string                   // Mã presale, bắt buộc với loại vé HIDDEN/CODE
+	ResaleID     *uuid.UUID               // Mua vé bán lại trên sàn (Quantity = 1, bỏ qua TicketTypeID)
+	Attendees    []entity.AttendeeDetails // Thông tin người tham dự từng vé (tùy chọn, len = Quantity), điền sau được
 }
 
 type OrderService struct {
-	db   *gorm.DB                    // connection DB để bắt đầu transaction
-	repo *repository.OrderRepository // repo để gọi các hàm lock/trừ kho/tạo order
+	db       *gorm.DB                    // connection DB để bắt đầu transaction
+	repo     *repository.OrderRepository // repo để gọi các hàm lock/trừ kho/tạo order
+	waitlist *WaitlistService            // cấp vé trả lại cho hàng chờ (nil = không dùng hàng chờ)
+	resale   *ResaleService              // sàn bán lại (nil = không bán vé bán lại)
 }
 
 // NewOrderService tạo service mới, inject db và repo vào.
-func NewOrderService(db *gorm.DB, repo *repository.OrderRepository) *OrderService {
+func NewOrderService(db *gorm.DB, repo *repository.OrderRepository, waitlist *WaitlistService, resale *ResaleService) *OrderService {
 	return &OrderService{
-		db:   db,
-		repo: repo,
+		db:       db,
+		repo:     repo,
+		waitlist: waitlist,
+		resale:   resale,
 	}
 }
 
@@ -37,6 +53,30 @@ func NewOrderService(db *gorm.DB, repo *repository.OrderRepository) *OrderServic
 // Nhận userID và list các loại vé muốn mua (có thể mua nhiều loại cùng lúc).
 // Trả về order vừa tạo nếu thành công, hoặc lỗi nếu fail (hết vé, lỗi DB...).
 func (s *OrderService) PlaceOrder(ctx context.Context, userID uuid.UUID, requestItems []RequestItem) (*entity.Order, error) {
+	return s.placeOrder(ctx, &userID, nil, requestItems)
+}
+
+// PlaceBoxOfficeOrder bán vé tại quầy cho khách vãng lai (không cần tài khoản). Tiền đã thu tại chỗ
+// nên đơn tạo ở trạng thái PAID và phát vé ngay, kèm thu ngân và phương thức thanh toán để đối soát cuối ca.
+// Nhân viên quầy bán được cả loại vé ẩn mà không cần mã presale.
+func (s *OrderService) PlaceBoxOfficeOrder(ctx context.Context, cashierID uuid.UUID, sale entity.BoxOfficeSale, requestItems []RequestItem) (*entity.Order, error) {
+	switch sale.PaymentMethod {
+	case entity.PaymentMethodCash, entity.PaymentMethodCard:
+	default:
+		return nil, fmt.Errorf("phương thức thanh toán không hợp lệ")
+	}
+	return s.placeOrder(ctx, nil, &boxOfficeCheckout{cashierID: cashierID, sale: sale}, requestItems)
+}
+
+// boxOfficeCheckout là thông tin bán tại quầy truyền vào placeOrder (nil = đơn online).
+type boxOfficeCheckout struct {
+	cashierID uuid.UUID
+	sale      entity.BoxOfficeSale
+}
+
+// placeOrder giữ kho, tạo đơn và phát vé trong một transaction.
+// userID = nil khi bán tại quầy cho khách không có tài khoản.
+func (s *OrderService) placeOrder(ctx context.Context, userID *uuid.UUID, boxOffice *boxOfficeCheckout, requestItems []RequestItem) (*entity.Order, error) {
 	// Bắt đầu transaction – mọi thứ từ đây phải thành công hết, không thì rollback sạch
 	tx := s.db.WithContext(ctx).Begin()
 	if tx.Error != nil {
@@ -58,8 +98,56 @@ func (s *OrderService) PlaceOrder(ctx context.Context, userID uuid.UUID, request
 	var orderItems []entity.OrderItem
 	orderID := uuid.New() // sinh ID đơn hàng mới
 
+	// Loại vé có trong đơn này, để kiểm tra điều kiện mua add-on
+	purchased := make(map[uuid.UUID]bool)
+	var addOnRequests []RequestItem
+
+	// Mã presale dùng trong đơn này (theo id), mỗi mã chỉ tính một lượt dù mở nhiều loại vé
+	presaleCodes := make(map[uuid.UUID]*entity.PresaleCode)
+
+	// Thông tin người tham dự đã kiểm tra, theo order item (thứ tự trùng thứ tự vé phát hành)
+	attendees := make(map[uuid.UUID][]entity.AttendeeDetails)
+
 	// Duyệt từng loại vé user muốn mua
 	for _, item := range requestItems {
+		// Add-on xử lý sau cùng vì cần biết đơn đã có những loại vé nào
+		if item.AddOnID != nil {
+			addOnRequests = append(addOnRequests, item)
+			continue
+		}
+
+		// Vé bán lại: khóa tin trong transaction này, vé sang tên khi đơn thanh toán (ConfirmPayment)
+		if item.ResaleID != nil {
+			if s.resale == nil || boxOffice != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("không hỗ trợ mua vé bán lại")
+			}
+			orderItem, err := s.resale.reserveListing(ctx, tx, *userID, orderID, item)
+			if err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			purchased[*orderItem.TicketTypeID] = true
+			totalAmount = totalAmount.Add(orderItem.UnitPrice)
+			orderItems = append(orderItems, *orderItem)
+			continue
+		}
+
+		// Gói combo đi đường riêng: khóa bundle rồi khóa từng loại vé thành phần
+		if item.BundleID != nil {
+			orderItem, err := s.reserveBundle(ctx, tx, orderID, item)
+			if err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			for _, component := range orderItem.Bundle.Items {
+				purchased[component.TicketTypeID] = true
+			}
+			totalAmount = totalAmount.Add(orderItem.UnitPrice.Mul(decimal.NewFromInt(int64(item.Quantity))))
+			orderItems = append(orderItems, *orderItem)
+			continue
+		}
+
 		// 1. Khóa vé lại (FOR UPDATE) để check và trừ kho an toàn
 		ticketType, err := s.repo.GetTicketTypeForUpdate(ctx, tx, item.TicketTypeID)
 		if err != nil {
@@ -67,54 +155,986 @@ func (s *OrderService) PlaceOrder(ctx context.Context, userID uuid.UUID, request
 			return nil, err
 		}
 
-		// 2. Check xem còn đủ vé không
-		if ticketType.RemainingQuantity < item.Quantity {
+		if ticketType.Ballot {
 			tx.Rollback()
-			return nil, fmt.Errorf("hết vé rồi bro! Loại vé: %s chỉ còn %d, bạn mua %d",
-				ticketType.Name, ticketType.RemainingQuantity, item.Quantity)
+			return nil, fmt.Errorf("loại vé %s chỉ bán qua bốc thăm", ticketType.Name)
 		}
 
-		// 3. Tính tiền cho loại vé này và cộng dồn tổng
-		itemTotal := ticketType.Price.Mul(decimal.NewFromInt(int64(item.Quantity)))
-		totalAmount = totalAmount.Add(itemTotal)
+		// 1b. Vé ẩn / presale: phải có mã hợp lệ, còn lượt và user chưa dùng
+		if !ticketType.IsPublic() && boxOffice == nil {
+			if err := s.usePresaleCode(ctx, tx, *userID, ticketType, item.AccessCode, presaleCodes); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+
+		// 2. Check xem còn đủ vé không (kể cả pool dùng chung), trừ kho luôn
+		if err := reserveStock(ctx, tx, s.repo, ticketType, item.Quantity); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
 
-		// 4. Trừ kho ngay (remaining_quantity -= quantity)
-		if err := s.repo.DecreaseStock(ctx, tx, item.TicketTypeID, item.Quantity); err != nil {
+		// 2b. Vé ngồi theo số: khóa từng ghế, ghế nào đã bán thì fail cả đơn
+		if err := s.lockSeats(ctx, tx, ticketType, item); err != nil {
 			tx.Rollback()
 			return nil, err
 		}
 
-		// 5. Tạo OrderItem (snapshot giá lúc mua, để sau này tính tiền không bị thay đổi)
+		// 2c. Người tham dự điền lúc checkout: kiểm tra theo bộ câu hỏi của event
+		var itemAttendees []entity.AttendeeDetails
+		if len(item.Attendees) > 0 {
+			itemAttendees, err = s.checkAttendees(ctx, tx, ticketType, item)
+			if err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+		}
+
+		// 3. Tính tiền cho loại vé này và cộng dồn tổng
+		itemTotal := ticketType.Price.Mul(decimal.NewFromInt(int64(item.Quantity)))
+		totalAmount = totalAmount.Add(itemTotal)
+
+		// 4. Tạo OrderItem (snapshot giá lúc mua, để sau này tính tiền không bị thay đổi)
+		ticketTypeID := ticketType.ID
+		purchased[ticketTypeID] = true
+		orderItemID := uuid.New()
+		if itemAttendees != nil {
+			attendees[orderItemID] = itemAttendees
+		}
 		orderItems = append(orderItems, entity.OrderItem{
-			ID:           uuid.New(),
+			ID:           orderItemID,
 			OrderID:      orderID,
-			TicketTypeID: item.TicketTypeID,
+			Kind:         entity.OrderItemKindTicket,
+			TicketTypeID: &ticketTypeID,
+			SessionID:    ticketType.SessionID, // suất diễn mà vé này thuộc về
 			Quantity:     item.Quantity,
 			UnitPrice:    ticketType.Price, // lưu giá lúc mua
+			SeatIDs:      item.SeatIDs,
 		})
 	}
 
+	// 5. Hàng bán kèm: khóa kho add-on giống loại vé, kiểm tra điều kiện loại vé đã mua
+	for _, item := range addOnRequests {
+		orderItem, err := s.reserveAddOn(ctx, tx, userID, orderID, item, purchased)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		totalAmount = totalAmount.Add(orderItem.UnitPrice.Mul(decimal.NewFromInt(int64(item.Quantity))))
+		orderItems = append(orderItems, *orderItem)
+	}
+
 	// 6. Tạo entity Order chính
 	order := &entity.Order{
 		ID:          orderID,
 		UserID:      userID,
 		TotalAmount: totalAmount,
 		Status:      entity.OrderStatusPending, // chờ thanh toán
+		Channel:     entity.OrderChannelOnline,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 		Items:       orderItems, // gắn luôn list items vào order
 	}
 
+	// 6b. Bán tại quầy: đã thu tiền tại chỗ nên đơn PAID ngay, ghi lại thu ngân để đối soát
+	if boxOffice != nil {
+		order.Status = entity.OrderStatusPaid
+		order.Channel = entity.OrderChannelBoxOffice
+		order.CashierID = &boxOffice.cashierID
+		order.PaymentMethod = &boxOffice.sale.PaymentMethod
+		order.CustomerName = boxOffice.sale.CustomerName
+		order.CustomerPhone = boxOffice.sale.CustomerPhone
+	}
+
 	// 7. Lưu order + order_items vào DB (GORM tự handle association)
 	if err := s.repo.CreateOrder(ctx, tx, order); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
+	// 7a. Tiêu lượt dùng mã presale (trả lại khi đơn bị hủy)
+	for codeID := range presaleCodes {
+		if err := s.repo.RedeemPresaleCode(ctx, tx, codeID, *userID, orderID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// 7b. Gắn ghế đã khóa vào order item (ghế vẫn đang bị khóa từ bước 2b)
+	for _, orderItem := range orderItems {
+		if len(orderItem.SeatIDs) == 0 {
+			continue
+		}
+		if err := s.repo.MarkSeatsSold(ctx, tx, orderItem.SeatIDs, orderItem.ID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// 7c. Phát hành vé: mỗi đơn vị số lượng một vé, bundle thì mỗi loại vé thành phần một vé
+	tickets, err := issueTickets(order)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	issued := make(map[uuid.UUID]int)
+	for i := range tickets {
+		tickets[i].OwnerName = order.CustomerName // in tên khách lên vé bán tại quầy (nếu có)
+		if details := attendees[tickets[i].OrderItemID]; len(details) > 0 {
+			n := issued[tickets[i].OrderItemID]
+			tickets[i].OwnerName = details[n].Name
+			tickets[i].Answers = details[n].Answers
+			issued[tickets[i].OrderItemID] = n + 1
+		}
+	}
+	if err := s.repo.CreateTickets(ctx, tx, tickets); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	order.Tickets = tickets
+
 	// 8. Commit transaction – nếu tới đây thì coi như thành công
 	if err := tx.Commit().Error; err != nil {
 		return nil, err
 	}
+	return order, nil
+}
+
+// checkAttendees kiểm tra thông tin người tham dự gửi kèm item: đủ một bộ cho mỗi vé và đúng
+// bộ câu hỏi của event. Trả về bản đã chuẩn hóa.
+func (s *OrderService) checkAttendees(ctx context.Context, tx *gorm.DB, ticketType *entity.TicketType, item RequestItem) ([]entity.AttendeeDetails, error) {
+	if len(item.Attendees) != item.Quantity {
+		return nil, fmt.Errorf("cần thông tin người tham dự cho đủ %d vé %s", item.Quantity, ticketType.Name)
+	}
+	questions, err := s.repo.GetAttendeeQuestions(ctx, tx, ticketType.EventID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]entity.AttendeeDetails, 0, len(item.Attendees))
+	for _, details := range item.Attendees {
+		normalized, err := validateAttendeeDetails(questions, details)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, normalized)
+	}
+	return result, nil
+}
 
+// SoldOutError là lỗi "hết vé" của một loại vé. Handler dựa vào đây để gợi ý user vào hàng chờ.
+type SoldOutError struct {
+	TicketTypeID uuid.UUID
+	Name         string
+	Remaining    int
+	Requested    int
+}
+
+func (e *SoldOutError) Error() string {
+	return fmt.Sprintf("hết vé rồi bro! Loại vé: %s chỉ còn %d, bạn mua %d", e.Name, e.Remaining, e.Requested)
+}
+
+// usePresaleCode kiểm tra mã presale (đã khóa FOR UPDATE) cho phép user đặt loại vé ẩn ticketType.
+// Mã hợp lệ được ghi vào codes để tiêu lượt sau khi tạo đơn.
+func (s *OrderService) usePresaleCode(ctx context.Context, tx *gorm.DB, userID uuid.UUID, ticketType *entity.TicketType, accessCode string, codes map[uuid.UUID]*entity.PresaleCode) error {
+	if accessCode == "" {
+		return fmt.Errorf("loại vé %s chỉ bán cho người có mã presale", ticketType.Name)
+	}
+
+	code := normalizePresaleCode(accessCode)
+	for _, presale := range codes {
+		if presale.Code == code && presale.EventID == ticketType.EventID {
+			if !presale.Grants(ticketType.ID) {
+				return fmt.Errorf("mã presale không áp dụng cho loại vé %s", ticketType.Name)
+			}
+			return nil
+		}
+	}
+
+	presale, err := s.repo.GetPresaleCodeForUpdate(ctx, tx, ticketType.EventID, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("mã presale không hợp lệ")
+		}
+		return err
+	}
+	redeemed, err := s.repo.HasRedeemedPresaleCode(ctx, tx, presale.ID, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkPresaleCode(presale, redeemed, time.Now()); err != nil {
+		return err
+	}
+	if !presale.Grants(ticketType.ID) {
+		return fmt.Errorf("mã presale không áp dụng cho loại vé %s", ticketType.Name)
+	}
+
+	codes[presale.ID] = presale
+	return nil
+}
+
+// reserveStock kiểm tra loại vé (đã khóa) còn đủ quantity, kể cả pool dùng chung nếu có,
+// rồi trừ kho. Phải gọi trong transaction, sau GetTicketTypeForUpdate.
+func reserveStock(ctx context.Context, tx *gorm.DB, repo *repository.OrderRepository, ticketType *entity.TicketType, quantity int) error {
+	if ticketType.RemainingQuantity < quantity {
+		return &SoldOutError{TicketTypeID: ticketType.ID, Name: ticketType.Name, Remaining: ticketType.RemainingQuantity, Requested: quantity}
+	}
+
+	// Loại vé dùng chung sức chứa: pool cũng phải còn đủ, khóa và trừ luôn
+	if ticketType.PoolID != nil {
+		pool, err := repo.GetPoolForUpdate(ctx, tx, *ticketType.PoolID)
+		if err != nil {
+			return err
+		}
+		if pool.RemainingQuantity < quantity {
+			return &SoldOutError{TicketTypeID: ticketType.ID, Name: ticketType.Name, Remaining: pool.RemainingQuantity, Requested: quantity}
+		}
+		if err := repo.DecreasePoolStock(ctx, tx, pool.ID, quantity); err != nil {
+			return err
+		}
+	}
+
+	// remaining_quantity -= quantity
+	return repo.DecreaseStock(ctx, tx, ticketType.ID, quantity)
+}
+
+// releaseStock là chiều ngược của reserveStock: cộng trả kho loại vé (và pool nếu có).
+func releaseStock(ctx context.Context, tx *gorm.DB, repo *repository.OrderRepository, ticketType *entity.TicketType, quantity int) error {
+	if ticketType.PoolID != nil {
+		if err := repo.IncreasePoolStock(ctx, tx, *ticketType.PoolID, quantity); err != nil {
+			return err
+		}
+	}
+	return repo.IncreaseStock(ctx, tx, ticketType.ID, quantity)
+}
+
+// availableStock là số vé còn bán được của loại vé (đã khóa): min(kho loại vé, kho pool).
+func availableStock(ctx context.Context, tx *gorm.DB, repo *repository.OrderRepository, ticketType *entity.TicketType) (int, error) {
+	available := ticketType.RemainingQuantity
+	if ticketType.PoolID != nil {
+		pool, err := repo.GetPoolForUpdate(ctx, tx, *ticketType.PoolID)
+		if err != nil {
+			return 0, err
+		}
+		if pool.RemainingQuantity < available {
+			available = pool.RemainingQuantity
+		}
+	}
+	return available, nil
+}
+
+// reserveBundle khóa gói combo và toàn bộ loại vé thành phần (theo thứ tự id để tránh deadlock),
+// trừ kho của bundle và của từng loại vé. Trả về OrderItem với giá snapshot của bundle.
+func (s *OrderService) reserveBundle(ctx context.Context, tx *gorm.DB, orderID uuid.UUID, item RequestItem) (*entity.OrderItem, error) {
+	if len(item.SeatIDs) > 0 {
+		return nil, fmt.Errorf("gói combo không bán theo ghế")
+	}
+
+	bundle, err := s.repo.GetBundleForUpdate(ctx, tx, *item.BundleID)
+	if err != nil {
+		return nil, err
+	}
+	if bundle.RemainingQuantity < item.Quantity {
+		return nil, fmt.Errorf("hết vé rồi bro! Gói: %s chỉ còn %d, bạn mua %d",
+			bundle.Name, bundle.RemainingQuantity, item.Quantity)
+	}
+
+	components := make([]entity.BundleItem, len(bundle.Items))
+	copy(components, bundle.Items)
+	sort.Slice(components, func(i, j int) bool {
+		return components[i].TicketTypeID.String() < components[j].TicketTypeID.String()
+	})
+
+	for _, component := range components {
+		ticketType, err := s.repo.GetTicketTypeForUpdate(ctx, tx, component.TicketTypeID)
+		if err != nil {
+			return nil, err
+		}
+		if ticketType.Reserved {
+			return nil, fmt.Errorf("loại vé %s là vé ngồi theo số, không bán trong gói được", ticketType.Name)
+		}
+		if ticketType.Ballot {
+			return nil, fmt.Errorf("loại vé %s chỉ bán qua bốc thăm", ticketType.Name)
+		}
+		if !ticketType.IsPublic() {
+			return nil, fmt.Errorf("loại vé %s chỉ bán bằng mã presale, không bán trong gói được", ticketType.Name)
+		}
+		if err := reserveStock(ctx, tx, s.repo, ticketType, item.Quantity*component.Quantity); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.DecreaseBundleStock(ctx, tx, bundle.ID, item.Quantity); err != nil {
+		return nil, err
+	}
+
+	return &entity.OrderItem{
+		ID:        uuid.New(),
+		OrderID:   orderID,
+		Kind:      entity.OrderItemKindBundle,
+		BundleID:  &bundle.ID,
+		Quantity:  item.Quantity,
+		UnitPrice: bundle.Price, // giá combo lúc mua
+		Bundle:    bundle,
+	}, nil
+}
+
+// reserveAddOn khóa add-on (FOR UPDATE), kiểm tra điều kiện loại vé và trừ kho.
+// purchased là các loại vé đã có trong đơn này; nếu chưa có thì xét tới vé user đã mua trước đó
+// (khách vãng lai tại quầy, userID = nil, chỉ xét trong đơn này).
+func (s *OrderService) reserveAddOn(ctx context.Context, tx *gorm.DB, userID *uuid.UUID, orderID uuid.UUID, item RequestItem, purchased map[uuid.UUID]bool) (*entity.OrderItem, error) {
+	if len(item.SeatIDs) > 0 {
+		return nil, fmt.Errorf("hàng bán kèm không bán theo ghế")
+	}
+
+	addOn, err := s.repo.GetAddOnForUpdate(ctx, tx, *item.AddOnID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(addOn.Restrictions) > 0 {
+		required := make([]uuid.UUID, 0, len(addOn.Restrictions))
+		eligible := false
+		for _, restriction := range addOn.Restrictions {
+			if purchased[restriction.TicketTypeID] {
+				eligible = true
+				break
+			}
+			required = append(required, restriction.TicketTypeID)
+		}
+		if !eligible && userID != nil {
+			eligible, err = s.repo.UserHasTicketTypes(ctx, tx, *userID, required)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !eligible {
+			return nil, fmt.Errorf("%s chỉ bán cho người mua loại vé được chỉ định", addOn.Name)
+		}
+	}
+
+	if addOn.RemainingQuantity < item.Quantity {
+		return nil, fmt.Errorf("hết hàng rồi bro! %s chỉ còn %d, bạn mua %d",
+			addOn.Name, addOn.RemainingQuantity, item.Quantity)
+	}
+	if err := s.repo.DecreaseAddOnStock(ctx, tx, addOn.ID, item.Quantity); err != nil {
+		return nil, err
+	}
+
+	return &entity.OrderItem{
+		ID:        uuid.New(),
+		OrderID:   orderID,
+		Kind:      entity.OrderItemKindAddOn,
+		AddOnID:   &addOn.ID,
+		Quantity:  item.Quantity,
+		UnitPrice: addOn.Price, // snapshot giá lúc mua
+	}, nil
+}
+
+// createHeldTicketOrder tạo đơn PENDING cho quantity vé của loại vé mà kho đã được trừ trước
+// (vé giữ cho hàng chờ, vé trúng bốc thăm), kèm phát hành vé. Gọi trong transaction.
+func createHeldTicketOrder(ctx context.Context, tx *gorm.DB, repo *repository.OrderRepository, userID uuid.UUID, ticketType *entity.TicketType, quantity int, deadline *time.Time) (*entity.Order, error) {
+	orderID := uuid.New()
+	ticketTypeID := ticketType.ID
+	order := &entity.Order{
+		ID:              orderID,
+		UserID:          &userID,
+		TotalAmount:     ticketType.Price.Mul(decimal.NewFromInt(int64(quantity))),
+		Status:          entity.OrderStatusPending,
+		PaymentDeadline: deadline,
+		Channel:         entity.OrderChannelOnline,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+		Items: []entity.OrderItem{{
+			ID:           uuid.New(),
+			OrderID:      orderID,
+			Kind:         entity.OrderItemKindTicket,
+			TicketTypeID: &ticketTypeID,
+			SessionID:    ticketType.SessionID,
+			Quantity:     quantity,
+			UnitPrice:    ticketType.Price, // snapshot giá lúc nhận vé
+		}},
+	}
+	if err := repo.CreateOrder(ctx, tx, order); err != nil {
+		return nil, err
+	}
+
+	tickets, err := issueTickets(order)
+	if err != nil {
+		return nil, err
+	}
+	if err := repo.CreateTickets(ctx, tx, tickets); err != nil {
+		return nil, err
+	}
+	order.Tickets = tickets
 	return order, nil
 }
+
+// issueTickets sinh danh sách vé cho đơn vừa tạo. Loại vé thường: một vé mỗi đơn vị
+// (gắn ghế nếu có). Bundle: mỗi đơn vị phát một vé cho từng loại vé thành phần.
+// Add-on và vé bán lại (đã có sẵn vé) không phát hành vé.
+func issueTickets(order *entity.Order) ([]entity.Ticket, error) {
+	var tickets []entity.Ticket
+	add := func(orderItem entity.OrderItem, ticketTypeID uuid.UUID, seatID *uuid.UUID) error {
+		code, err := newTicketCode()
+		if err != nil {
+			return err
+		}
+		tickets = append(tickets, entity.Ticket{
+			ID:           uuid.New(),
+			OrderID:      order.ID,
+			OrderItemID:  orderItem.ID,
+			TicketTypeID: ticketTypeID,
+			SeatID:       seatID,
+			TicketCode:   code,
+			Status:       entity.TicketStatusUnused,
+			OwnerID:      order.UserID, // người mua giữ vé cho tới khi chuyển nhượng
+		})
+		return nil
+	}
+
+	for _, orderItem := range order.Items {
+		switch orderItem.Kind {
+		case entity.OrderItemKindBundle:
+			for i := 0; i < orderItem.Quantity; i++ {
+				for _, component := range orderItem.Bundle.Items {
+					for j := 0; j < component.Quantity; j++ {
+						if err := add(orderItem, component.TicketTypeID, nil); err != nil {
+							return nil, err
+						}
+					}
+				}
+			}
+		case entity.OrderItemKindTicket:
+			for i := 0; i < orderItem.Quantity; i++ {
+				var seatID *uuid.UUID
+				if i < len(orderItem.SeatIDs) {
+					seatID = &orderItem.SeatIDs[i]
+				}
+				if err := add(orderItem, *orderItem.TicketTypeID, seatID); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return tickets, nil
+}
+
+// newTicketCode sinh mã vé ngẫu nhiên (dùng làm nội dung QR), không đoán được.
+func newTicketCode() (string, error) {
+	buf := make([]byte, 15)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf), nil
+}
+
+// lockSeats kiểm tra và khóa ghế cho một item. Loại vé reserved bắt buộc chọn ghế,
+// loại vé đứng (GA) thì không được gửi seat_ids.
+func (s *OrderService) lockSeats(ctx context.Context, tx *gorm.DB, ticketType *entity.TicketType, item RequestItem) error {
+	if !ticketType.Reserved {
+		if len(item.SeatIDs) > 0 {
+			return fmt.Errorf("loại vé %s không bán theo ghế", ticketType.Name)
+		}
+		return nil
+	}
+
+	if len(item.SeatIDs) != item.Quantity {
+		return fmt.Errorf("loại vé %s cần chọn đúng %d ghế", ticketType.Name, item.Quantity)
+	}
+
+	seats, err := s.repo.GetSeatsForUpdate(ctx, tx, item.SeatIDs)
+	if err != nil {
+		return err
+	}
+	if len(seats) != len(item.SeatIDs) {
+		return fmt.Errorf("có ghế không tồn tại hoặc bị chọn trùng")
+	}
+
+	for _, seat := range seats {
+		if seat.TicketTypeID != ticketType.ID {
+			return fmt.Errorf("ghế %s-%d không thuộc loại vé %s", seat.RowLabel, seat.Number, ticketType.Name)
+		}
+		if seat.Status != entity.SeatStatusAvailable {
+			return fmt.Errorf("ghế %s-%d đã có người đặt", seat.RowLabel, seat.Number)
+		}
+	}
+	return nil
+}
+
+// CancelOrder cho user hủy đơn PENDING của mình, trả kho và cấp vé cho hàng chờ.
+func (s *OrderService) CancelOrder(ctx context.Context, userID, orderID uuid.UUID) error {
+	return s.cancelOrder(ctx, orderID, &userID)
+}
+
+// ConfirmPayment chuyển đơn PENDING sang PAID khi cổng thanh toán báo đã nhận tiền. Vé bán lại trong
+// đơn được sang tên người mua ngay trong transaction này, sau đó người bán được chi tiền hoàn.
+func (s *OrderService) ConfirmPayment(ctx context.Context, orderID uuid.UUID) error {
+	tx := s.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	order, err := s.repo.GetOrderForUpdate(ctx, tx, orderID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("không tìm thấy đơn hàng")
+		}
+		return err
+	}
+	if order.Status != entity.OrderStatusPending {
+		tx.Rollback()
+		return fmt.Errorf("đơn không ở trạng thái chờ thanh toán")
+	}
+
+	var resaleIDs []uuid.UUID
+	for _, item := range order.Items {
+		if item.Kind != entity.OrderItemKindResale {
+			continue
+		}
+		if s.resale == nil {
+			tx.Rollback()
+			return fmt.Errorf("không hỗ trợ mua vé bán lại")
+		}
+		if err := s.resale.completeListing(ctx, tx, *item.ResaleID); err != nil {
+			tx.Rollback()
+			return err
+		}
+		resaleIDs = append(resaleIDs, *item.ResaleID)
+	}
+
+	if err := s.repo.UpdateOrderStatus(ctx, tx, order.ID, entity.OrderStatusPaid); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	if len(resaleIDs) > 0 {
+		s.resale.settleSold(ctx, resaleIDs)
+	}
+	return nil
+}
+
+// ExpirePendingOrders hủy các đơn PENDING quá ttl chưa thanh toán. Trả về số đơn đã hủy.
+// Gọi định kỳ từ background job; mỗi đơn một transaction để đơn lỗi không chặn đơn khác
+// (đơn lỗi chỉ ghi log, lượt quét sau thử lại).
+func (s *OrderService) ExpirePendingOrders(ctx context.Context, ttl time.Duration) (int, error) {
+	ids, err := s.repo.ListExpiredPendingOrders(ctx, time.Now().Add(-ttl), 100)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, id := range ids {
+		if err := s.cancelOrder(ctx, id, nil); err != nil {
+			log.Printf("Hủy đơn quá hạn %s thất bại: %v", id, err)
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// cancelOrder hủy đơn PENDING: trả kho vé/bundle/add-on, nhả ghế, thu hồi vé đã phát hành.
+// userID != nil thì chỉ chủ đơn được hủy.
+func (s *OrderService) cancelOrder(ctx context.Context, orderID uuid.UUID, userID *uuid.UUID) error {
+	tx := s.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	order, err := s.repo.GetOrderForUpdate(ctx, tx, orderID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if userID != nil && (order.UserID == nil || *order.UserID != *userID) {
+		tx.Rollback()
+		return fmt.Errorf("không tìm thấy đơn hàng")
+	}
+	if order.Status != entity.OrderStatusPending {
+		// Job hết hạn có thể chạy trùng lúc user thanh toán/hủy: bỏ qua êm
+		tx.Rollback()
+		if userID == nil {
+			return nil
+		}
+		return fmt.Errorf("chỉ hủy được đơn đang chờ thanh toán")
+	}
+
+	offers, err := s.cancelLockedOrder(ctx, tx, order)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	s.notifyOffers(ctx, offers)
+	return nil
+}
+
+// RefundOrder hoàn đơn đã thanh toán (quyền orders:refund): vé bị thu hồi, kho trả lại cho bán công
+// khai / hàng chờ và đơn chuyển REFUNDED. Tiền trả khách qua cổng thanh toán hoặc tại quầy như lúc thu.
+// Vé đã soát, đã sang tay người khác hoặc đang rao bán / chờ chuyển nhượng thì không hoàn được.
+func (s *OrderService) RefundOrder(ctx context.Context, orderID uuid.UUID) error {
+	tx := s.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	order, err := s.repo.GetOrderForUpdate(ctx, tx, orderID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("không tìm thấy đơn hàng")
+		}
+		return err
+	}
+	if order.Status != entity.OrderStatusPaid {
+		tx.Rollback()
+		return fmt.Errorf("chỉ hoàn được đơn đã thanh toán")
+	}
+	if order.Comp {
+		tx.Rollback()
+		return fmt.Errorf("vé mời không hoàn tiền")
+	}
+	for _, item := range order.Items {
+		// Vé bán lại đã sang tên và người bán đã nhận tiền, hoàn lại phải đi qua người bán
+		if item.Kind == entity.OrderItemKindResale {
+			tx.Rollback()
+			return fmt.Errorf("không hoàn được đơn mua vé bán lại")
+		}
+	}
+
+	tickets, err := s.repo.GetTicketsByOrderForUpdate(ctx, tx, order.ID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	for _, ticket := range tickets {
+		if ticket.Status != entity.TicketStatusUnused {
+			tx.Rollback()
+			return fmt.Errorf("vé %s đã được soát, không hoàn được", ticket.TicketCode)
+		}
+		if (ticket.OwnerID == nil) != (order.UserID == nil) || (ticket.OwnerID != nil && *ticket.OwnerID != *order.UserID) {
+			tx.Rollback()
+			return fmt.Errorf("vé của đơn đã chuyển nhượng hoặc bán lại, không hoàn được")
+		}
+	}
+	busy, err := s.repo.OrderTicketsInResale(ctx, tx, order.ID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if busy {
+		tx.Rollback()
+		return fmt.Errorf("vé của đơn đang được rao bán lại hoặc chờ chuyển nhượng, hãy hủy trước khi hoàn tiền")
+	}
+
+	offers, err := s.voidLockedOrder(ctx, tx, order, entity.OrderStatusRefunded)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	s.notifyOffers(ctx, offers)
+	return nil
+}
+
+// cancelLockedOrder hủy đơn đã khóa (GetOrderForUpdate) trong transaction của caller và cấp vé
+// trả lại cho hàng chờ. Trả về các offer vừa cấp; caller gọi notifyOffers sau khi commit.
+func (s *OrderService) cancelLockedOrder(ctx context.Context, tx *gorm.DB, order *entity.Order) ([]entity.WaitlistEntry, error) {
+	return s.voidLockedOrder(ctx, tx, order, entity.OrderStatusCancelled)
+}
+
+// voidLockedOrder trả kho, thu hồi vé và mã presale của đơn đã khóa rồi chuyển đơn sang status
+// (CANCELLED hoặc REFUNDED). Trả về các offer hàng chờ vừa cấp.
+func (s *OrderService) voidLockedOrder(ctx context.Context, tx *gorm.DB, order *entity.Order, status entity.OrderStatus) ([]entity.WaitlistEntry, error) {
+	released, err := s.releaseOrderItems(ctx, tx, order)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.DeleteTicketsByOrder(ctx, tx, order.ID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReleasePresaleRedemptions(ctx, tx, order.ID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateOrderStatus(ctx, tx, order.ID, status); err != nil {
+		return nil, err
+	}
+	order.Status = status
+
+	if s.waitlist == nil {
+		return nil, nil
+	}
+	return s.waitlist.offerReleasedStock(ctx, tx, released)
+}
+
+func (s *OrderService) notifyOffers(ctx context.Context, offers []entity.WaitlistEntry) {
+	if s.waitlist != nil {
+		s.waitlist.notifyOffers(ctx, offers)
+	}
+}
+
+// releaseOrderItems cộng trả kho cho từng item của đơn. Trả về các loại vé vừa có vé trả lại
+// (sắp theo id, khóa theo thứ tự này để tránh deadlock).
+func (s *OrderService) releaseOrderItems(ctx context.Context, tx *gorm.DB, order *entity.Order) ([]uuid.UUID, error) {
+	released := make(map[uuid.UUID]int)
+	var ticketItemIDs []uuid.UUID
+
+	for _, item := range order.Items {
+		switch item.Kind {
+		case entity.OrderItemKindTicket:
+			released[*item.TicketTypeID] += item.Quantity
+			ticketItemIDs = append(ticketItemIDs, item.ID)
+		case entity.OrderItemKindBundle:
+			bundle, err := s.repo.GetBundleForUpdate(ctx, tx, *item.BundleID)
+			if err != nil {
+				return nil, err
+			}
+			for _, component := range bundle.Items {
+				released[component.TicketTypeID] += item.Quantity * component.Quantity
+			}
+			if err := s.repo.IncreaseBundleStock(ctx, tx, bundle.ID, item.Quantity); err != nil {
+				return nil, err
+			}
+		case entity.OrderItemKindAddOn:
+			if err := s.repo.IncreaseAddOnStock(ctx, tx, *item.AddOnID, item.Quantity); err != nil {
+				return nil, err
+			}
+		case entity.OrderItemKindResale:
+			// Vé bán lại không trừ kho: vé chưa sang tên, chỉ cần rao bán tiếp
+			if s.resale == nil {
+				return nil, fmt.Errorf("không hỗ trợ hủy vé bán lại")
+			}
+			if err := s.resale.releaseListing(ctx, tx, *item.ResaleID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(ticketItemIDs) > 0 {
+		if err := s.repo.ReleaseSeats(ctx, tx, ticketItemIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	ids := make([]uuid.UUID, 0, len(released))
+	for id := range released {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+
+	for _, id := range ids {
+		ticketType, err := s.repo.GetTicketTypeForUpdate(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := releaseStock(ctx, tx, s.repo, ticketType, released[id]); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// TopUpStock cho admin mở bán thêm quantity vé của loại vé. Vé mới ưu tiên cấp cho hàng chờ trước.
+func (s *OrderService) TopUpStock(ctx context.Context, ticketTypeID uuid.UUID, quantity int) error {
+	if quantity <= 0 {
+		return fmt.Errorf("số lượng mở bán thêm phải lớn hơn 0")
+	}
+
+	tx := s.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	ticketType, err := s.repo.GetTicketTypeForUpdate(ctx, tx, ticketTypeID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if ticketType.Reserved {
+		// Vé ngồi theo số: số vé = số ghế, muốn thêm thì thêm ghế
+		tx.Rollback()
+		return fmt.Errorf("loại vé %s bán theo ghế, hãy thêm ghế thay vì mở bán thêm", ticketType.Name)
+	}
+	if err := s.checkTopUpCapacity(ctx, tx, ticketType, quantity); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := s.repo.TopUpStock(ctx, tx, ticketTypeID, quantity); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var offers []entity.WaitlistEntry
+	if s.waitlist != nil {
+		offers, err = s.waitlist.offerReleasedStock(ctx, tx, []uuid.UUID{ticketTypeID})
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	s.notifyOffers(ctx, offers)
+	return nil
+}
+
+// checkTopUpCapacity kiểm tra mở bán thêm quantity vé vẫn không vượt sức chứa venue và khu
+// (cùng quy tắc lúc tạo event: tổng vé của event / suất diễn ≤ sức chứa).
+func (s *OrderService) checkTopUpCapacity(ctx context.Context, tx *gorm.DB, ticketType *entity.TicketType, quantity int) error {
+	event, err := s.repo.GetEventForUpdate(ctx, tx, ticketType.EventID)
+	if err != nil {
+		return err
+	}
+	if event.VenueID == nil {
+		return nil
+	}
+	venue, err := s.repo.GetVenueWithSections(ctx, tx, *event.VenueID)
+	if err != nil {
+		return err
+	}
+
+	peers, err := s.repo.ListSessionTicketTypes(ctx, tx, event.ID, ticketType.SessionID)
+	if err != nil {
+		return err
+	}
+	quantities := make([]entity.CreateTicketTypeRequest, 0, len(peers))
+	for _, tt := range peers {
+		qty := tt.InitialQuantity
+		if tt.ID == ticketType.ID {
+			qty += quantity
+		}
+		quantities = append(quantities, entity.CreateTicketTypeRequest{SectionID: tt.SectionID, InitialQuantity: qty})
+	}
+	return validateTicketCapacity(venue, quantities)
+}
+
+// ShiftReport đối soát cuối ca bán tại quầy trong [from, to): số đơn, số vé, tiền thu theo từng
+// phương thức của mỗi thu ngân. cashierID = nil lấy tất cả thu ngân.
+func (s *OrderService) ShiftReport(ctx context.Context, from, to time.Time, cashierID *uuid.UUID) (*entity.ShiftReport, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("thời gian kết thúc ca phải sau thời gian bắt đầu")
+	}
+
+	rows, err := s.repo.CashierSales(ctx, from, to, cashierID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entity.ShiftReport{From: from, To: to, Cashiers: []entity.CashierShiftReport{}}
+	index := make(map[uuid.UUID]int)
+	for _, row := range rows {
+		i, ok := index[row.CashierID]
+		if !ok {
+			report.Cashiers = append(report.Cashiers, entity.CashierShiftReport{
+				CashierID:   row.CashierID,
+				TotalAmount: decimal.Zero,
+				CashAmount:  decimal.Zero,
+			})
+			i = len(report.Cashiers) - 1
+			index[row.CashierID] = i
+		}
+		cashier := &report.Cashiers[i]
+		cashier.Orders += row.Orders
+		cashier.Tickets += row.Tickets
+		cashier.TotalAmount = cashier.TotalAmount.Add(row.Amount)
+		if row.PaymentMethod == entity.PaymentMethodCash {
+			cashier.CashAmount = cashier.CashAmount.Add(row.Amount)
+		}
+		cashier.ByMethod = append(cashier.ByMethod, entity.PaymentMethodTotal{
+			PaymentMethod: row.PaymentMethod,
+			Orders:        row.Orders,
+			Tickets:       row.Tickets,
+			Amount:        row.Amount,
+		})
+	}
+	return report, nil
+}
+
+// ListOrders trả về lịch sử đơn hàng của user theo trang (cursor = nil là trang đầu).
+func (s *OrderService) ListOrders(ctx context.Context, userID uuid.UUID, cursor *entity.PageCursor, limit int) (*entity.OrderPage, error) {
+	limit = orderPageLimit(limit)
+	key := orderListKey("user", userID)
+	if err := checkPageCursor(cursor, key); err != nil {
+		return nil, err
+	}
+
+	// Lấy dư 1 dòng để biết còn trang tiếp theo hay không
+	orders, err := s.repo.ListOrdersByUser(ctx, userID, cursor, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	return newOrderPage(orders, cursor, limit, key), nil
+}
+
+// orderPageLimit đưa số đơn mỗi trang về khoảng [1, 100], mặc định 10.
+func orderPageLimit(limit int) int {
+	if limit <= 0 {
+		return 10
+	}
+	if limit > 100 {
+		return 100
+	}
+	return limit
+}
+
+// orderListKey là dấu vân tay của danh sách đơn (đơn của một user, của một event...) để gắn vào cursor.
+func orderListKey(list string, id uuid.UUID) string {
+	return pagecursor.Fingerprint("orders", list, id.String())
+}
+
+// newOrderPage dựng trang từ kết quả lấy dư 1 dòng (limit+1) theo keyset (created_at, id).
+func newOrderPage(orders []entity.Order, cursor *entity.PageCursor, limit int, key string) *entity.OrderPage {
+	hasMore := len(orders) > limit
+	if hasMore {
+		orders = orders[:limit]
+	}
+
+	backward := cursor != nil && cursor.Backward
+	if backward {
+		for i, j := 0, len(orders)-1; i < j; i, j = i+1, j-1 {
+			orders[i], orders[j] = orders[j], orders[i]
+		}
+	}
+
+	page := &entity.OrderPage{Orders: orders}
+	if len(orders) == 0 {
+		return page
+	}
+
+	first, last := orders[0], orders[len(orders)-1]
+	if backward {
+		page.NextCursor = orderCursor(last, key, false)
+		if hasMore {
+			page.PrevCursor = orderCursor(first, key, true)
+		}
+	} else {
+		if hasMore {
+			page.NextCursor = orderCursor(last, key, false)
+		}
+		if cursor != nil {
+			page.PrevCursor = orderCursor(first, key, true)
+		}
+	}
+	return page
+}
+
+func orderCursor(order entity.Order, key string, backward bool) *entity.PageCursor {
+	return &entity.PageCursor{
+		Sort:     "created_at",
+		Value:    order.CreatedAt.Format(time.RFC3339Nano),
+		ID:       order.ID,
+		Backward: backward,
+		Filter:   key,
+	}
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+	"gorm.io/gorm"
+)
+
+var (
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+	colorPattern    = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+)
+
+// organizationService quản lý tổ chức (ban tổ chức), thành viên và event của tổ chức.
+// Event và đơn hàng của tổ chức luôn đọc qua eventRepo.ForOrganization để không lộ dữ liệu tổ chức khác.
+type organizationService struct {
+	orgRepo   port.OrganizationRepositoryPort
+	eventRepo port.EventRepositoryPort
+	venueRepo port.VenueRepositoryPort
+	roleRepo  port.RoleRepositoryPort
+	userRepo  port.UserRepositoryPort
+	access    port.AccessServicePort
+}
+
+func NewOrganizationService(orgRepo port.OrganizationRepositoryPort, eventRepo port.EventRepositoryPort, venueRepo port.VenueRepositoryPort, roleRepo port.RoleRepositoryPort, userRepo port.UserRepositoryPort, access port.AccessServicePort) port.OrganizationServicePort {
+	return &organizationService{
+		orgRepo:   orgRepo,
+		eventRepo: eventRepo,
+		venueRepo: venueRepo,
+		roleRepo:  roleRepo,
+		userRepo:  userRepo,
+		access:    access,
+	}
+}
+
+// CreateOrganization tạo tổ chức, nếu có owner_email thì cấp luôn vai trò organizer của tổ chức cho user đó.
+func (s *organizationService) CreateOrganization(ctx context.Context, createdBy uuid.UUID, req entity.CreateOrganizationRequest) (*entity.Organization, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Slug = strings.TrimSpace(req.Slug)
+	if len(req.Name) < 3 {
+		return nil, errors.New("tên tổ chức phải có ít nhất 3 ký tự")
+	}
+	if len(req.Slug) < 3 {
+		return nil, errors.New("slug phải có ít nhất 3 ký tự")
+	}
+	if existing, _ := s.orgRepo.GetOrganizationBySlug(ctx, req.Slug); existing != nil {
+		return nil, errors.New("slug đã được sử dụng")
+	}
+
+	settings := entity.DefaultOrganizationSettings()
+	if req.Settings != nil {
+		settings = *req.Settings
+	}
+	settings, err := normalizeOrganizationSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	var owner *entity.User
+	if req.OwnerEmail != "" {
+		owner, err = s.userRepo.GetUserByEmail(ctx, normalizeEmail(req.OwnerEmail))
+		if err != nil {
+			return nil, errors.New("không tìm thấy user với email owner_email")
+		}
+	}
+
+	org := &entity.Organization{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Slug:      req.Slug,
+		Settings:  settings,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := s.orgRepo.CreateOrganization(ctx, org); err != nil {
+		return nil, err
+	}
+
+	if owner != nil {
+		if _, err := s.access.GrantRole(ctx, createdBy, entity.GrantRoleRequest{
+			UserID:    owner.ID,
+			Role:      entity.RoleOrganizer,
+			ScopeType: entity.ScopeOrganization,
+			ScopeID:   &org.ID,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return org, nil
+}
+
+func (s *organizationService) GetOrganization(ctx context.Context, id uuid.UUID) (*entity.Organization, error) {
+	return s.orgRepo.GetOrganizationByID(ctx, id)
+}
+
+func (s *organizationService) ListOrganizations(ctx context.Context, userID uuid.UUID, role string) ([]entity.Organization, error) {
+	if role == entity.RoleAdmin {
+		return s.orgRepo.ListOrganizations(ctx)
+	}
+	return s.orgRepo.ListOrganizationsByMember(ctx, userID)
+}
+
+func (s *organizationService) UpdateSettings(ctx context.Context, id uuid.UUID, settings entity.OrganizationSettings) (*entity.Organization, error) {
+	org, err := s.orgRepo.GetOrganizationByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("tổ chức không tồn tại")
+	}
+
+	settings, err = normalizeOrganizationSettings(settings)
+	if err != nil {
+		return nil, err
+	}
+	org.Settings = settings
+	org.UpdatedAt = time.Now()
+	if err := s.orgRepo.UpdateOrganization(ctx, org); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+// normalizeOrganizationSettings kiểm tra tiền tệ, phí và thương hiệu; tiền tệ bỏ trống = VND.
+func normalizeOrganizationSettings(settings entity.OrganizationSettings) (entity.OrganizationSettings, error) {
+	settings.Currency = strings.ToUpper(strings.TrimSpace(settings.Currency))
+	if settings.Currency == "" {
+		settings.Currency = "VND"
+	}
+	if !currencyPattern.MatchString(settings.Currency) {
+		return settings, errors.New("mã tiền tệ không hợp lệ (ISO 4217, VD: VND)")
+	}
+
+	if settings.ServiceFeePercent.IsNegative() || settings.ServiceFeePercent.GreaterThan(decimal.NewFromInt(100)) {
+		return settings, errors.New("phí dịch vụ phải trong khoảng 0-100%")
+	}
+	if settings.ServiceFeeFixed.IsNegative() {
+		return settings, errors.New("phí cố định không được âm")
+	}
+
+	b := &settings.Branding
+	b.LogoURL = strings.TrimSpace(b.LogoURL)
+	if b.LogoURL != "" && !isHTTPURL(b.LogoURL) {
+		return settings, errors.New("logo_url phải là URL http(s)")
+	}
+	if b.PrimaryColor != "" && !colorPattern.MatchString(b.PrimaryColor) {
+		return settings, errors.New("primary_color phải có dạng #RRGGBB")
+	}
+	b.SupportEmail = normalizeEmail(b.SupportEmail)
+	if b.SupportEmail != "" && !strings.Contains(b.SupportEmail, "@") {
+		return settings, errors.New("support_email không hợp lệ")
+	}
+	return settings, nil
+}
+
+// ListMembers gộp các vai trò trong tổ chức theo user.
+func (s *organizationService) ListMembers(ctx context.Context, orgID uuid.UUID) ([]entity.OrganizationMember, error) {
+	assignments, err := s.roleRepo.ListScopeAssignments(ctx, entity.ScopeOrganization, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	members := []entity.OrganizationMember{}
+	index := make(map[uuid.UUID]int)
+	for _, a := range assignments {
+		if i, ok := index[a.UserID]; ok {
+			members[i].Roles = append(members[i].Roles, a.Role)
+			continue
+		}
+		user, err := s.userRepo.GetUserByID(ctx, a.UserID.String())
+		if err != nil {
+			return nil, err
+		}
+		index[a.UserID] = len(members)
+		members = append(members, entity.OrganizationMember{
+			UserID:   user.ID,
+			Username: user.Username,
+			Email:    user.Email,
+			Roles:    []string{a.Role},
+		})
+	}
+	return members, nil
+}
+
+// AddMember cấp vai trò trong tổ chức cho user theo email.
+func (s *organizationService) AddMember(ctx context.Context, grantedBy, orgID uuid.UUID, req entity.AddMemberRequest) (*entity.RoleAssignment, error) {
+	user, err := s.userRepo.GetUserByEmail(ctx, normalizeEmail(req.Email))
+	if err != nil {
+		return nil, errors.New("không tìm thấy user với email này")
+	}
+	return s.access.GrantRole(ctx, grantedBy, entity.GrantRoleRequest{
+		UserID:    user.ID,
+		Role:      req.Role,
+		ScopeType: entity.ScopeOrganization,
+		ScopeID:   &orgID,
+	})
+}
+
+func (s *organizationService) RemoveMember(ctx context.Context, orgID, userID uuid.UUID, role string) error {
+	deleted, err := s.roleRepo.DeleteScopeAssignments(ctx, userID, entity.ScopeOrganization, orgID, role)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return errors.New("user không có vai trò này trong tổ chức")
+	}
+	return nil
+}
+
+// events trả về EventService chỉ làm việc với event của tổ chức.
+func (s *organizationService) events(orgID uuid.UUID) port.EventServicePort {
+	return NewEventService(s.eventRepo.ForOrganization(orgID), s.venueRepo)
+}
+
+func (s *organizationService) CreateEvent(ctx context.Context, orgID uuid.UUID, req entity.CreateEventRequest, ticketTypes []entity.CreateTicketTypeRequest) (*entity.Event, error) {
+	if _, err := s.orgRepo.GetOrganizationByID(ctx, orgID); err != nil {
+		return nil, errors.New("tổ chức không tồn tại")
+	}
+	if req.OrganizationID != nil && *req.OrganizationID != orgID {
+		return nil, errors.New("organization_id không khớp với tổ chức")
+	}
+	req.OrganizationID = &orgID
+
+	// Slug là duy nhất trên toàn hệ thống nên phải kiểm tra cả event của tổ chức khác
+	if existing, _ := s.eventRepo.GetEventBySlug(ctx, req.Slug); existing != nil {
+		return nil, errors.New("slug đã được sử dụng")
+	}
+
+	if len(ticketTypes) > 0 {
+		return s.events(orgID).CreateEventWithTickets(ctx, req, ticketTypes)
+	}
+	return s.events(orgID).CreateEvent(ctx, req)
+}
+
+// ListEvents liệt kê event của tổ chức (kể cả bản nháp).
+func (s *organizationService) ListEvents(ctx context.Context, orgID uuid.UUID, filter entity.EventFilter) (*entity.EventPage, error) {
+	return s.events(orgID).ListEvents(ctx, filter)
+}
+
+// ListEventOrders liệt kê đơn hàng của một event thuộc tổ chức.
+func (s *organizationService) ListEventOrders(ctx context.Context, orgID, eventID uuid.UUID, cursor *entity.PageCursor, limit int) (*entity.OrderPage, error) {
+	limit = orderPageLimit(limit)
+	key := orderListKey("event", eventID)
+	if err := checkPageCursor(cursor, key); err != nil {
+		return nil, err
+	}
+
+	// Lấy dư 1 dòng để biết còn trang tiếp theo hay không
+	orders, err := s.eventRepo.ForOrganization(orgID).ListOrdersByEvent(ctx, eventID, cursor, limit+1)
+	if err != nil {
+		// Event của tổ chức khác cũng báo không tồn tại để không lộ event nào có thật
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("sự kiện không tồn tại trong tổ chức")
+		}
+		return nil, err
+	}
+	return newOrderPage(orders, cursor, limit, key), nil
+}
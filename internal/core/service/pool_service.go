@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+type poolService struct {
+	poolRepo  port.PoolRepositoryPort
+	eventRepo port.EventRepositoryPort
+}
+
+func NewPoolService(poolRepo port.PoolRepositoryPort, eventRepo port.EventRepositoryPort) port.PoolServicePort {
+	return &poolService{
+		poolRepo:  poolRepo,
+		eventRepo: eventRepo,
+	}
+}
+
+func (s *poolService) CreatePool(ctx context.Context, eventID uuid.UUID, req entity.CreateCapacityPoolRequest) (*entity.CapacityPool, error) {
+	if req.Name == "" {
+		return nil, errors.New("tên pool không được để trống")
+	}
+	if req.Capacity <= 0 {
+		return nil, errors.New("sức chứa pool phải lớn hơn 0")
+	}
+	if len(req.TicketTypeIDs) == 0 {
+		return nil, errors.New("pool phải có ít nhất một loại vé")
+	}
+
+	seen := make(map[uuid.UUID]bool, len(req.TicketTypeIDs))
+	for _, id := range req.TicketTypeIDs {
+		if seen[id] {
+			return nil, errors.New("loại vé bị trùng trong pool")
+		}
+		seen[id] = true
+	}
+
+	if _, err := s.eventRepo.GetEventByID(ctx, eventID); err != nil {
+		return nil, errors.New("sự kiện không tồn tại")
+	}
+
+	pool := &entity.CapacityPool{
+		ID:        uuid.New(),
+		EventID:   eventID,
+		Name:      req.Name,
+		Capacity:  req.Capacity,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if err := s.poolRepo.CreatePool(ctx, pool, req.TicketTypeIDs); err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+// GetPoolReport trả về mức sử dụng của từng pool, kèm số vé đã bán theo từng loại vé.
+func (s *poolService) GetPoolReport(ctx context.Context, eventID uuid.UUID) ([]entity.PoolReport, error) {
+	pools, err := s.poolRepo.ListPoolsByEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	ticketTypes, err := s.eventRepo.ListTicketTypesByEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]entity.PoolReport, 0, len(pools))
+	for _, pool := range pools {
+		sold := pool.Capacity - pool.RemainingQuantity
+		report := entity.PoolReport{
+			PoolID:      pool.ID,
+			Name:        pool.Name,
+			Capacity:    pool.Capacity,
+			Remaining:   pool.RemainingQuantity,
+			Sold:        sold,
+			Utilization: math.Round(float64(sold)/float64(pool.Capacity)*10000) / 100,
+			TicketTypes: []entity.PoolTicketUsage{},
+		}
+
+		for _, tt := range ticketTypes {
+			if tt.PoolID == nil || *tt.PoolID != pool.ID {
+				continue
+			}
+			report.TicketTypes = append(report.TicketTypes, entity.PoolTicketUsage{
+				TicketTypeID: tt.ID,
+				Name:         tt.Name,
+				Sold:         tt.InitialQuantity - tt.RemainingQuantity,
+				Remaining:    tt.RemainingQuantity,
+			})
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
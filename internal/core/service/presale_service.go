@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+type presaleService struct {
+	presaleRepo port.PresaleRepositoryPort
+	eventRepo   port.EventRepositoryPort
+}
+
+func NewPresaleService(presaleRepo port.PresaleRepositoryPort, eventRepo port.EventRepositoryPort) port.PresaleServicePort {
+	return &presaleService{
+		presaleRepo: presaleRepo,
+		eventRepo:   eventRepo,
+	}
+}
+
+func (s *presaleService) CreateCode(ctx context.Context, eventID uuid.UUID, req entity.CreatePresaleCodeRequest) (*entity.PresaleCode, error) {
+	code := normalizePresaleCode(req.Code)
+	if len(code) < 3 || len(code) > 50 {
+		return nil, errors.New("mã presale phải dài từ 3 đến 50 ký tự")
+	}
+	if req.MaxUses < 0 {
+		return nil, errors.New("số lượt dùng tối đa không được âm")
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return nil, errors.New("thời gian kết thúc phải sau thời gian bắt đầu")
+	}
+	if len(req.TicketTypeIDs) == 0 {
+		return nil, errors.New("mã presale phải mở khóa ít nhất một loại vé")
+	}
+
+	if _, err := s.eventRepo.GetEventByID(ctx, eventID); err != nil {
+		return nil, errors.New("sự kiện không tồn tại")
+	}
+
+	// Loại vé được mở khóa phải thuộc chính event này
+	ticketTypes, err := s.eventRepo.ListTicketTypesByEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	owned := make(map[uuid.UUID]bool, len(ticketTypes))
+	for _, tt := range ticketTypes {
+		owned[tt.ID] = true
+	}
+	seen := make(map[uuid.UUID]bool, len(req.TicketTypeIDs))
+	for _, id := range req.TicketTypeIDs {
+		if !owned[id] {
+			return nil, errors.New("loại vé không thuộc sự kiện này")
+		}
+		if seen[id] {
+			return nil, errors.New("loại vé bị trùng")
+		}
+		seen[id] = true
+	}
+
+	if existing, err := s.presaleRepo.GetCodeByValue(ctx, eventID, code); err == nil && existing != nil {
+		return nil, errors.New("mã presale đã tồn tại trong sự kiện này")
+	}
+
+	presale := &entity.PresaleCode{
+		ID:        uuid.New(),
+		EventID:   eventID,
+		Code:      code,
+		Name:      req.Name,
+		MaxUses:   req.MaxUses,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		CreatedAt: time.Now(),
+	}
+	for _, id := range req.TicketTypeIDs {
+		presale.TicketTypes = append(presale.TicketTypes, entity.PresaleCodeTicketType{
+			PresaleCodeID: presale.ID,
+			TicketTypeID:  id,
+		})
+	}
+
+	if err := s.presaleRepo.CreateCode(ctx, presale); err != nil {
+		return nil, err
+	}
+	return presale, nil
+}
+
+func (s *presaleService) ListCodes(ctx context.Context, eventID uuid.UUID) ([]entity.PresaleCode, error) {
+	return s.presaleRepo.ListCodesByEvent(ctx, eventID)
+}
+
+// Unlock kiểm tra mã và trả về các loại vé user được đặt bằng mã này.
+// Không tiêu lượt dùng: lượt chỉ bị trừ khi PlaceOrder thành công.
+func (s *presaleService) Unlock(ctx context.Context, userID, eventID uuid.UUID, code string) ([]entity.TicketType, error) {
+	presale, err := s.presaleRepo.GetCodeByValue(ctx, eventID, normalizePresaleCode(code))
+	if err != nil {
+		return nil, errors.New("mã presale không hợp lệ")
+	}
+	redeemed, err := s.presaleRepo.HasRedeemed(ctx, presale.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPresaleCode(presale, redeemed, time.Now()); err != nil {
+		return nil, err
+	}
+
+	ticketTypes, err := s.eventRepo.ListTicketTypesByEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	unlocked := make([]entity.TicketType, 0, len(presale.TicketTypes))
+	for _, tt := range ticketTypes {
+		if presale.Grants(tt.ID) {
+			unlocked = append(unlocked, tt)
+		}
+	}
+	return unlocked, nil
+}
+
+// normalizePresaleCode bỏ khoảng trắng và đưa về in hoa để so khớp không phân biệt hoa thường.
+func normalizePresaleCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// checkPresaleCode kiểm tra mã còn dùng được cho user: trong khung giờ, còn lượt và user chưa dùng.
+func checkPresaleCode(presale *entity.PresaleCode, redeemed bool, now time.Time) error {
+	if !presale.Active(now) {
+		return errors.New("mã presale chưa mở hoặc đã hết hạn")
+	}
+	if redeemed {
+		return errors.New("bạn đã dùng mã presale này rồi")
+	}
+	if presale.MaxUses > 0 && presale.UsedCount >= presale.MaxUses {
+		return errors.New("mã presale đã hết lượt dùng")
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/pkg/auth"
+)
+
+const (
+	maxFullNameLength  = 100
+	maxAvatarURLLength = 500
+	maxAgeYears        = 120
+)
+
+var phonePattern = regexp.MustCompile(`^\+?[0-9]{9,15}$`)
+
+// GetProfile trả về thông tin đầy đủ của user từ DB (không chỉ claims trong JWT).
+func (s *authService) GetProfile(ctx context.Context, userID uuid.UUID) (*entity.User, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID.String())
+	if err != nil {
+		return nil, errors.New("user không tồn tại")
+	}
+	return user, nil
+}
+
+// UpdateProfile kiểm tra và ghi các trường hồ sơ vào profile_data, giữ nguyên các key khác.
+func (s *authService) UpdateProfile(ctx context.Context, userID uuid.UUID, req entity.UpdateProfileRequest) (*entity.User, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID.String())
+	if err != nil {
+		return nil, errors.New("user không tồn tại")
+	}
+
+	fields := map[string]*string{
+		entity.ProfileFullName:  req.FullName,
+		entity.ProfilePhone:     req.Phone,
+		entity.ProfileBirthDate: req.BirthDate,
+		entity.ProfileAvatarURL: req.AvatarURL,
+	}
+	profile := make(map[string]interface{}, len(user.ProfileData))
+	for key, value := range user.ProfileData {
+		profile[key] = value
+	}
+	for key, value := range fields {
+		if value == nil {
+			continue
+		}
+		normalized, err := normalizeProfileField(key, *value)
+		if err != nil {
+			return nil, err
+		}
+		if normalized == "" {
+			delete(profile, key)
+		} else {
+			profile[key] = normalized
+		}
+	}
+
+	if err := s.userRepo.UpdateProfileData(ctx, userID, profile); err != nil {
+		return nil, err
+	}
+	user.ProfileData = profile
+	user.UpdatedAt = time.Now()
+	return user, nil
+}
+
+// normalizeProfileField kiểm tra một trường hồ sơ, trả về giá trị đã chuẩn hóa (rỗng = xóa trường).
+func normalizeProfileField(key, value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	switch key {
+	case entity.ProfileFullName:
+		if utf8.RuneCountInString(value) > maxFullNameLength {
+			return "", fmt.Errorf("họ tên tối đa %d ký tự", maxFullNameLength)
+		}
+	case entity.ProfilePhone:
+		value = strings.NewReplacer(" ", "", "-", "", ".", "").Replace(value)
+		if !phonePattern.MatchString(value) {
+			return "", errors.New("số điện thoại không hợp lệ")
+		}
+	case entity.ProfileBirthDate:
+		birthDate, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return "", errors.New("ngày sinh không hợp lệ (định dạng YYYY-MM-DD)")
+		}
+		now := time.Now()
+		if birthDate.After(now) || birthDate.Before(now.AddDate(-maxAgeYears, 0, 0)) {
+			return "", errors.New("ngày sinh không hợp lệ")
+		}
+	case entity.ProfileAvatarURL:
+		if len(value) > maxAvatarURLLength || !isHTTPURL(value) {
+			return "", errors.New("avatar_url phải là URL http(s)")
+		}
+	}
+	return value, nil
+}
+
+// isHTTPURL cho biết chuỗi có phải URL tuyệt đối http(s) không.
+func isHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// ChangePassword đổi mật khẩu khi biết mật khẩu hiện tại, đăng xuất mọi phiên (kể cả phiên này khi hết access token).
+func (s *authService) ChangePassword(ctx context.Context, userID uuid.UUID, req entity.ChangePasswordRequest) error {
+	if len(req.NewPassword) < minPasswordLength {
+		return fmt.Errorf("mật khẩu phải có ít nhất %d ký tự", minPasswordLength)
+	}
+	user, err := s.userRepo.GetUserByID(ctx, userID.String())
+	if err != nil {
+		return errors.New("user không tồn tại")
+	}
+	if !auth.CheckPasswordHash(req.CurrentPassword, user.PasswordHash) {
+		return errors.New("mật khẩu hiện tại không đúng")
+	}
+
+	hashedPassword, err := auth.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.UpdatePassword(ctx, userID, hashedPassword); err != nil {
+		return err
+	}
+	if err := s.tokenRepo.RevokeUserRefreshTokens(ctx, userID); err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Xin chào %s,\n\nMật khẩu tài khoản của bạn vừa được thay đổi. Nếu không phải bạn, hãy đặt lại mật khẩu ngay.",
+		user.Username)
+	if err := s.mailer.Send(ctx, user.Email, "Mật khẩu đã thay đổi", body); err != nil {
+		log.Printf("Gửi thông báo đổi mật khẩu cho user %s thất bại: %v", user.ID, err)
+	}
+	return nil
+}
+
+// RequestEmailChange lưu email mới ở trạng thái chờ và gửi link xác nhận tới email mới.
+// Email chỉ đổi khi user mở link, email cũ được báo để phát hiện đổi trái phép.
+func (s *authService) RequestEmailChange(ctx context.Context, userID uuid.UUID, req entity.ChangeEmailRequest) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID.String())
+	if err != nil {
+		return errors.New("user không tồn tại")
+	}
+	if !auth.CheckPasswordHash(req.Password, user.PasswordHash) {
+		return errors.New("mật khẩu không đúng")
+	}
+
+	newEmail := normalizeEmail(req.NewEmail)
+	if newEmail == "" || !strings.Contains(newEmail, "@") {
+		return errors.New("email không hợp lệ")
+	}
+	if newEmail == normalizeEmail(user.Email) {
+		return errors.New("email mới trùng email hiện tại")
+	}
+	if existing, _ := s.userRepo.GetUserByEmail(ctx, newEmail); existing != nil {
+		return errors.New("email đã được sử dụng")
+	}
+
+	if err := s.userRepo.SetPendingEmail(ctx, userID, newEmail); err != nil {
+		return err
+	}
+	plain, err := s.issueUserToken(ctx, userID, entity.TokenPurposeEmailChange, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Xin chào %s,\n\nMở liên kết sau để xác nhận đổi email đăng nhập sang địa chỉ này (hết hạn sau %s):\n%s",
+		user.Username, emailVerificationTTL, s.emailLink("/confirm-email", plain))
+	if err := s.mailer.Send(ctx, newEmail, "Xác nhận email mới", body); err != nil {
+		return err
+	}
+
+	notice := fmt.Sprintf("Xin chào %s,\n\nCó yêu cầu đổi email đăng nhập của bạn sang %s. Nếu không phải bạn, hãy đổi mật khẩu ngay.",
+		user.Username, newEmail)
+	if err := s.mailer.Send(ctx, user.Email, "Yêu cầu đổi email", notice); err != nil {
+		log.Printf("Gửi thông báo đổi email cho user %s thất bại: %v", user.ID, err)
+	}
+	return nil
+}
+
+// ConfirmEmailChange đổi email sang email đang chờ bằng token đã gửi tới email đó.
+func (s *authService) ConfirmEmailChange(ctx context.Context, token string) error {
+	stored, err := s.tokenRepo.ConsumeUserToken(ctx, auth.HashToken(token), entity.TokenPurposeEmailChange)
+	if err != nil {
+		return errInvalidEmailToken
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, stored.UserID.String())
+	if err != nil || user.PendingEmail == nil {
+		return errInvalidEmailToken
+	}
+	// Email có thể đã được tài khoản khác đăng ký trong lúc chờ xác nhận
+	if existing, _ := s.userRepo.GetUserByEmail(ctx, *user.PendingEmail); existing != nil {
+		return errors.New("email đã được sử dụng")
+	}
+
+	applied, err := s.userRepo.ApplyPendingEmail(ctx, stored.UserID)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return errInvalidEmailToken
+	}
+	return nil
+}
@@ -0,0 +1,394 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+
+	"github.com/yourname/ticketing-system/internal/adapter/repository"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+// ResaleService vận hành sàn bán lại chính thức: người giữ vé rao bán với giá không vượt trần của
+// event, người mua thanh toán qua checkout thường. Tin bị khóa (RESERVED) ngay trong transaction đặt
+// đơn nên một vé không bao giờ bán được hai lần; vé chỉ sang tên người mua (cấp mã mới) và người bán
+// chỉ được chi tiền khi đơn của người mua đã thanh toán.
+type ResaleService struct {
+	db         *gorm.DB
+	repo       *repository.ResaleRepository
+	tickets    *repository.TransferRepository // khóa/sang tên vé dùng chung với chuyển nhượng
+	notifier   port.NotifierPort
+	payouts    port.PayoutPort
+	feePercent decimal.Decimal // phí sàn (% giá bán) trừ vào tiền hoàn cho người bán
+}
+
+func NewResaleService(db *gorm.DB, repo *repository.ResaleRepository, tickets *repository.TransferRepository, notifier port.NotifierPort, payouts port.PayoutPort, feePercent decimal.Decimal) *ResaleService {
+	return &ResaleService{
+		db:         db,
+		repo:       repo,
+		tickets:    tickets,
+		notifier:   notifier,
+		payouts:    payouts,
+		feePercent: feePercent,
+	}
+}
+
+// CreateListing rao bán lại vé ticketID của sellerID.
+func (s *ResaleService) CreateListing(ctx context.Context, sellerID uuid.UUID, req entity.CreateResaleListingRequest) (*entity.ResaleListing, error) {
+	if !req.Price.IsPositive() {
+		return nil, errors.New("giá bán lại phải lớn hơn 0")
+	}
+
+	tx := s.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	ticket, err := s.lockSellableTicket(ctx, tx, sellerID, req.TicketID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	eventID, capPercent, err := s.repo.ResaleCap(ctx, tx, ticket.TicketTypeID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if capPercent <= 0 {
+		tx.Rollback()
+		return nil, errors.New("sự kiện không mở bán lại vé")
+	}
+
+	// Giá gốc là giá người mua đầu tiên trả cho loại vé (snapshot trên order item)
+	original, err := s.repo.GetOrderItem(ctx, tx, ticket.OrderItemID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if original.Kind != entity.OrderItemKindTicket || !original.UnitPrice.IsPositive() {
+		tx.Rollback()
+		return nil, errors.New("chỉ bán lại được vé mua lẻ có trả tiền")
+	}
+	maxPrice := original.UnitPrice.Mul(decimal.NewFromInt(int64(capPercent))).Div(decimal.NewFromInt(100)).Round(2)
+	if req.Price.GreaterThan(maxPrice) {
+		tx.Rollback()
+		return nil, fmt.Errorf("giá bán lại tối đa là %s", maxPrice.StringFixed(2))
+	}
+
+	listing := &entity.ResaleListing{
+		ID:            uuid.New(),
+		TicketID:      ticket.ID,
+		SellerID:      sellerID,
+		EventID:       eventID,
+		TicketTypeID:  ticket.TicketTypeID,
+		SeatID:        ticket.SeatID,
+		Price:         req.Price.Round(2),
+		OriginalPrice: original.UnitPrice,
+		Status:        entity.ResaleListingStatusActive,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+	if err := s.repo.CreateListing(ctx, tx, listing); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return listing, nil
+}
+
+// CancelListing cho người bán gỡ tin đang rao.
+func (s *ResaleService) CancelListing(ctx context.Context, sellerID, listingID uuid.UUID) error {
+	tx := s.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	listing, err := s.repo.GetListingForUpdate(ctx, tx, listingID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("tin bán lại không tồn tại")
+		}
+		return err
+	}
+	if listing.SellerID != sellerID {
+		tx.Rollback()
+		return errors.New("tin bán lại không tồn tại")
+	}
+	if listing.Status != entity.ResaleListingStatusActive {
+		tx.Rollback()
+		return errors.New("tin bán lại đã bán hoặc đã gỡ")
+	}
+
+	listing.Status = entity.ResaleListingStatusCancelled
+	if err := s.repo.SaveListing(ctx, tx, listing); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// ListEventListings trả về các vé đang rao bán lại của event, rẻ nhất trước.
+func (s *ResaleService) ListEventListings(ctx context.Context, eventID uuid.UUID) ([]entity.ResaleListing, error) {
+	return s.repo.ListActiveByEvent(ctx, eventID)
+}
+
+// ListMyListings trả về các tin bán lại của user, kèm phí và tiền hoàn của tin đã bán.
+func (s *ResaleService) ListMyListings(ctx context.Context, sellerID uuid.UUID) ([]entity.ResaleListing, error) {
+	return s.repo.ListBySeller(ctx, sellerID)
+}
+
+// SetEventCap đặt trần giá bán lại của event (% giá gốc, 0 = tắt bán lại). Tin đang rao
+// giữ nguyên giá; trần mới áp dụng cho tin tạo sau.
+func (s *ResaleService) SetEventCap(ctx context.Context, eventID uuid.UUID, capPercent int) error {
+	if capPercent < 0 {
+		return errors.New("trần giá bán lại không được âm")
+	}
+	updated, err := s.repo.SetEventResaleCap(ctx, eventID, capPercent)
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return errors.New("sự kiện không tồn tại")
+	}
+	return nil
+}
+
+// reserveListing giữ tin bán lại cho đơn orderID của buyerID trong transaction đặt đơn: khóa tin,
+// kiểm tra vé còn bán được và chốt phí + tiền hoàn cho người bán. Vé vẫn thuộc người bán (mã cũ) tới
+// khi đơn thanh toán, xem completeListing.
+func (s *ResaleService) reserveListing(ctx context.Context, tx *gorm.DB, buyerID, orderID uuid.UUID, item RequestItem) (*entity.OrderItem, error) {
+	if item.Quantity != 1 {
+		return nil, errors.New("mỗi tin bán lại chỉ có 1 vé")
+	}
+
+	listing, err := s.repo.GetListingForUpdate(ctx, tx, *item.ResaleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tin bán lại không tồn tại")
+		}
+		return nil, err
+	}
+	if listing.Status != entity.ResaleListingStatusActive {
+		return nil, errors.New("vé bán lại đã có người mua")
+	}
+	if listing.SellerID == buyerID {
+		return nil, errors.New("không thể mua vé của chính mình")
+	}
+
+	// Khóa tin rồi mới khóa vé; các luồng khác chỉ khóa một trong hai nên không deadlock
+	ticket, err := s.tickets.GetTicketForUpdate(ctx, tx, listing.TicketID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResaleTicket(listing, ticket); err != nil {
+		return nil, err
+	}
+
+	listing.Status = entity.ResaleListingStatusReserved
+	listing.BuyerID = &buyerID
+	listing.OrderID = &orderID
+	listing.Fee = listing.Price.Mul(s.feePercent).Div(decimal.NewFromInt(100)).Round(2)
+	listing.SellerPayout = listing.Price.Sub(listing.Fee)
+	if err := s.repo.SaveListing(ctx, tx, listing); err != nil {
+		return nil, err
+	}
+
+	ticketTypeID := listing.TicketTypeID
+	resaleID := listing.ID
+	return &entity.OrderItem{
+		ID:           uuid.New(),
+		OrderID:      orderID,
+		Kind:         entity.OrderItemKindResale,
+		TicketTypeID: &ticketTypeID,
+		ResaleID:     &resaleID,
+		Quantity:     1,
+		UnitPrice:    listing.Price,
+	}, nil
+}
+
+// completeListing chạy trong transaction xác nhận thanh toán đơn của người mua: sang tên vé cho người
+// mua với mã mới (mã của người bán hết hiệu lực) và chốt tin là đã bán. Tiền hoàn cho người bán
+// được chi sau khi commit, xem settleSold.
+func (s *ResaleService) completeListing(ctx context.Context, tx *gorm.DB, listingID uuid.UUID) error {
+	listing, err := s.repo.GetListingForUpdate(ctx, tx, listingID)
+	if err != nil {
+		return err
+	}
+	if listing.Status != entity.ResaleListingStatusReserved || listing.BuyerID == nil {
+		return errors.New("tin bán lại không còn chờ thanh toán")
+	}
+
+	ticket, err := s.tickets.GetTicketForUpdate(ctx, tx, listing.TicketID)
+	if err != nil {
+		return err
+	}
+	if err := checkResaleTicket(listing, ticket); err != nil {
+		return err
+	}
+
+	code, err := newTicketCode()
+	if err != nil {
+		return err
+	}
+	ticket.TicketCode = code
+	ticket.OwnerID = listing.BuyerID
+	ticket.OwnerName = ""
+	ticket.Answers = nil // người nhận tự điền thông tin người tham dự
+	if err := s.tickets.SaveTicket(ctx, tx, ticket); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	listing.Status = entity.ResaleListingStatusSold
+	listing.SoldAt = &now
+	return s.repo.SaveListing(ctx, tx, listing)
+}
+
+// releaseListing chạy khi đơn của người mua bị hủy trước khi thanh toán: tin được rao bán tiếp.
+// Vé chưa sang tên nên người bán vẫn giữ vé với mã cũ.
+func (s *ResaleService) releaseListing(ctx context.Context, tx *gorm.DB, listingID uuid.UUID) error {
+	listing, err := s.repo.GetListingForUpdate(ctx, tx, listingID)
+	if err != nil {
+		return err
+	}
+	if listing.Status != entity.ResaleListingStatusReserved {
+		return nil
+	}
+
+	listing.Status = entity.ResaleListingStatusActive
+	listing.BuyerID = nil
+	listing.OrderID = nil
+	listing.Fee = decimal.Zero
+	listing.SellerPayout = decimal.Zero
+	return s.repo.SaveListing(ctx, tx, listing)
+}
+
+// RetryPayouts chi lại tiền hoàn cho các tin đã bán mà lần chi trước lỗi. Gọi định kỳ từ background
+// job, trả về số tin đã chi xong.
+func (s *ResaleService) RetryPayouts(ctx context.Context) (int, error) {
+	ids, err := s.repo.ListUnpaidPayouts(ctx, 100)
+	if err != nil {
+		return 0, err
+	}
+	return s.settleSold(ctx, ids), nil
+}
+
+// settleSold chi tiền hoàn cho người bán của các tin vừa bán và báo cho họ. Gọi sau khi commit
+// thanh toán đơn của người mua; chi lỗi thì chỉ ghi log, RetryPayouts sẽ chi lại.
+func (s *ResaleService) settleSold(ctx context.Context, listingIDs []uuid.UUID) int {
+	paid := 0
+	for _, id := range listingIDs {
+		listing, err := s.payOut(ctx, id)
+		if err != nil {
+			log.Printf("Chi tiền bán lại cho tin %s thất bại: %v", id, err)
+			continue
+		}
+		if listing == nil {
+			continue
+		}
+		paid++
+		message := fmt.Sprintf("Vé bán lại của bạn đã bán với giá %s. Phí sàn %s, bạn đã được chuyển %s.",
+			listing.Price.StringFixed(2), listing.Fee.StringFixed(2), listing.SellerPayout.StringFixed(2))
+		if err := s.notifier.Notify(ctx, listing.SellerID, "Vé bán lại đã bán", message); err != nil {
+			log.Printf("Gửi thông báo bán lại cho user %s thất bại: %v", listing.SellerID, err)
+		}
+	}
+	return paid
+}
+
+// payOut chi SellerPayout của tin đã bán, khóa tin để job chi lại chạy trùng không chi hai lần.
+// Trả về nil nếu tin chưa bán hoặc đã chi rồi.
+func (s *ResaleService) payOut(ctx context.Context, listingID uuid.UUID) (*entity.ResaleListing, error) {
+	tx := s.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	listing, err := s.repo.GetListingForUpdate(ctx, tx, listingID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if listing.Status != entity.ResaleListingStatusSold || listing.PaidOutAt != nil {
+		tx.Rollback()
+		return nil, nil
+	}
+
+	if err := s.payouts.Payout(ctx, listing.SellerID, listing.ID, listing.SellerPayout); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	now := time.Now()
+	listing.PaidOutAt = &now
+	if err := s.repo.SaveListing(ctx, tx, listing); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return listing, nil
+}
+
+// checkResaleTicket kiểm tra vé của tin vẫn thuộc người bán và chưa dùng (người bán có thể đã vào
+// cổng bằng mã cũ trong lúc chờ người mua thanh toán).
+func checkResaleTicket(listing *entity.ResaleListing, ticket *entity.Ticket) error {
+	if ticket.OwnerID == nil || *ticket.OwnerID != listing.SellerID || ticket.Status != entity.TicketStatusUnused {
+		return errors.New("vé bán lại không còn hiệu lực")
+	}
+	return nil
+}
+
+// lockSellableTicket khóa vé và kiểm tra vé rao bán được: đúng chủ, chưa dùng, đơn đã thanh toán,
+// không đang chờ chuyển nhượng và chưa có tin bán lại khác.
+func (s *ResaleService) lockSellableTicket(ctx context.Context, tx *gorm.DB, sellerID, ticketID uuid.UUID) (*entity.Ticket, error) {
+	ticket, err := s.tickets.GetTicketForUpdate(ctx, tx, ticketID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("vé không tồn tại")
+		}
+		return nil, err
+	}
+	if ticket.OwnerID == nil || *ticket.OwnerID != sellerID {
+		return nil, errors.New("vé không tồn tại")
+	}
+	if ticket.Status != entity.TicketStatusUnused {
+		return nil, errors.New("vé đã sử dụng, không bán lại được")
+	}
+
+	status, err := s.tickets.OrderStatus(ctx, tx, ticket.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if status != entity.OrderStatusPaid {
+		return nil, errors.New("chỉ bán lại được vé của đơn đã thanh toán")
+	}
+
+	pending, err := s.tickets.HasPendingTransfer(ctx, tx, ticket.ID)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, errors.New("vé đang chờ chuyển nhượng, hãy hủy lượt chuyển trước khi bán lại")
+	}
+	listed, err := s.tickets.HasActiveListing(ctx, tx, ticket.ID)
+	if err != nil {
+		return nil, err
+	}
+	if listed {
+		return nil, errors.New("vé đã được rao bán lại")
+	}
+	return ticket, nil
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+type seatService struct {
+	seatRepo  port.SeatRepositoryPort
+	eventRepo port.EventRepositoryPort
+	venueRepo port.VenueRepositoryPort
+}
+
+func NewSeatService(seatRepo port.SeatRepositoryPort, eventRepo port.EventRepositoryPort, venueRepo port.VenueRepositoryPort) port.SeatServicePort {
+	return &seatService{
+		seatRepo:  seatRepo,
+		eventRepo: eventRepo,
+		venueRepo: venueRepo,
+	}
+}
+
+// CreateSeats thêm ghế vào sơ đồ của event. Số ghế của mỗi loại vé không được vượt
+// InitialQuantity của loại vé đó, và khu phải thuộc venue của event.
+func (s *seatService) CreateSeats(ctx context.Context, eventID uuid.UUID, req []entity.CreateSeatRequest) ([]entity.Seat, error) {
+	if len(req) == 0 {
+		return nil, errors.New("danh sách ghế không được để trống")
+	}
+
+	event, err := s.eventRepo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, errors.New("sự kiện không tồn tại")
+	}
+
+	ticketTypes, err := s.eventRepo.ListTicketTypesByEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	ticketByID := make(map[uuid.UUID]entity.TicketType, len(ticketTypes))
+	for _, tt := range ticketTypes {
+		ticketByID[tt.ID] = tt
+	}
+
+	sections, err := s.eventSections(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	seats := make([]entity.Seat, 0, len(req))
+	perTicket := make(map[uuid.UUID]int)
+	positions := make(map[string]bool)
+	for _, r := range req {
+		if _, ok := ticketByID[r.TicketTypeID]; !ok {
+			return nil, errors.New("loại vé không thuộc sự kiện")
+		}
+		if r.SectionID != nil {
+			if _, ok := sections[*r.SectionID]; !ok {
+				return nil, errors.New("khu (section) không thuộc địa điểm của sự kiện")
+			}
+		}
+		if r.Row == "" || r.Number <= 0 {
+			return nil, errors.New("hàng và số ghế không hợp lệ")
+		}
+
+		// Chặn trùng ghế ngay trong request (DB còn unique index chặn trùng với ghế cũ)
+		key := fmt.Sprintf("%v/%s/%d", r.SectionID, r.Row, r.Number)
+		if positions[key] {
+			return nil, fmt.Errorf("ghế %s-%d bị trùng", r.Row, r.Number)
+		}
+		positions[key] = true
+		perTicket[r.TicketTypeID]++
+
+		seats = append(seats, entity.Seat{
+			ID:           uuid.New(),
+			EventID:      eventID,
+			TicketTypeID: r.TicketTypeID,
+			SectionID:    r.SectionID,
+			RowLabel:     r.Row,
+			Number:       r.Number,
+			X:            r.X,
+			Y:            r.Y,
+			Status:       entity.SeatStatusAvailable,
+		})
+	}
+
+	for ticketTypeID, added := range perTicket {
+		existing, err := s.seatRepo.CountSeatsByTicketType(ctx, ticketTypeID)
+		if err != nil {
+			return nil, err
+		}
+		if tt := ticketByID[ticketTypeID]; existing+added > tt.InitialQuantity {
+			return nil, fmt.Errorf("số ghế của loại vé %s vượt quá số lượng vé (%d)", tt.Name, tt.InitialQuantity)
+		}
+	}
+
+	if err := s.seatRepo.CreateSeats(ctx, seats); err != nil {
+		return nil, err
+	}
+	return seats, nil
+}
+
+// GetSeatMap trả sơ đồ ghế kèm trạng thái từng ghế, gom theo khu -> hàng.
+func (s *seatService) GetSeatMap(ctx context.Context, eventID uuid.UUID) (*entity.SeatMap, error) {
+	event, err := s.eventRepo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, errors.New("sự kiện không tồn tại")
+	}
+
+	sections, err := s.eventSections(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+
+	seats, err := s.seatRepo.ListSeatsByEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	seatMap := &entity.SeatMap{EventID: eventID, Sections: []entity.SeatMapSection{}}
+	sectionIdx := make(map[uuid.UUID]int)
+	rowIdx := make(map[string]int)
+	for _, seat := range seats {
+		var key uuid.UUID // uuid.Nil = ghế không thuộc khu nào
+		if seat.SectionID != nil {
+			key = *seat.SectionID
+		}
+
+		si, ok := sectionIdx[key]
+		if !ok {
+			sec := entity.SeatMapSection{SectionID: seat.SectionID, Name: sections[key].Name}
+			seatMap.Sections = append(seatMap.Sections, sec)
+			si = len(seatMap.Sections) - 1
+			sectionIdx[key] = si
+		}
+
+		rowKey := key.String() + "/" + seat.RowLabel
+		ri, ok := rowIdx[rowKey]
+		if !ok {
+			seatMap.Sections[si].Rows = append(seatMap.Sections[si].Rows, entity.SeatMapRow{Label: seat.RowLabel})
+			ri = len(seatMap.Sections[si].Rows) - 1
+			rowIdx[rowKey] = ri
+		}
+		seatMap.Sections[si].Rows[ri].Seats = append(seatMap.Sections[si].Rows[ri].Seats, seat)
+	}
+	return seatMap, nil
+}
+
+func (s *seatService) FindBestAvailable(ctx context.Context, eventID uuid.UUID, ticketTypeID uuid.UUID, quantity int) ([]entity.Seat, error) {
+	if quantity <= 0 || quantity > 20 {
+		return nil, errors.New("số ghế phải từ 1 đến 20")
+	}
+
+	seats, err := s.seatRepo.ListSeatsByEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	best := FindBestSeats(seats, ticketTypeID, quantity)
+	if best == nil {
+		return nil, fmt.Errorf("không còn %d ghế liền nhau", quantity)
+	}
+	return best, nil
+}
+
+// FindBestSeats chọn n ghế trống liền nhau "đẹp" nhất của loại vé: ưu tiên hàng gần
+// sân khấu (Y nhỏ), trong cùng hàng ưu tiên cụm gần giữa hàng nhất. Trả về nil nếu không có.
+// Chỉ gợi ý, không giữ ghế; việc khóa ghế thật sự nằm ở OrderService.PlaceOrder.
+func FindBestSeats(seats []entity.Seat, ticketTypeID uuid.UUID, n int) []entity.Seat {
+	type rowKey struct {
+		section uuid.UUID
+		label   string
+	}
+
+	rows := make(map[rowKey][]entity.Seat)
+	var order []rowKey
+	for _, seat := range seats {
+		var section uuid.UUID
+		if seat.SectionID != nil {
+			section = *seat.SectionID
+		}
+		key := rowKey{section, seat.RowLabel}
+		if _, ok := rows[key]; !ok {
+			order = append(order, key)
+		}
+		rows[key] = append(rows[key], seat)
+	}
+
+	// Hàng gần sân khấu trước (Y trung bình nhỏ nhất)
+	avgY := func(list []entity.Seat) float64 {
+		sum := 0.0
+		for _, seat := range list {
+			sum += seat.Y
+		}
+		return sum / float64(len(list))
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return avgY(rows[order[i]]) < avgY(rows[order[j]])
+	})
+
+	usable := func(seat entity.Seat) bool {
+		return seat.Status == entity.SeatStatusAvailable && seat.TicketTypeID == ticketTypeID
+	}
+
+	for _, key := range order {
+		row := rows[key]
+		if len(row) < n {
+			continue
+		}
+		sort.Slice(row, func(i, j int) bool { return row[i].Number < row[j].Number })
+
+		// Tâm hàng tính trên toàn bộ ghế của hàng (kể cả ghế đã bán)
+		center := (row[0].X + row[len(row)-1].X) / 2
+
+		var best []entity.Seat
+		bestDist := math.MaxFloat64
+		for i := 0; i+n <= len(row); i++ {
+			window := row[i : i+n]
+			if window[n-1].Number-window[0].Number != n-1 {
+				continue // Hàng bị đứt quãng (lối đi...)
+			}
+
+			ok := true
+			for _, seat := range window {
+				if !usable(seat) {
+					ok = false
+					break
+				}
+			}
+			if !ok {
+				continue
+			}
+
+			mid := (window[0].X + window[n-1].X) / 2
+			if dist := math.Abs(mid - center); dist < bestDist {
+				bestDist = dist
+				best = window
+			}
+		}
+		if best != nil {
+			return append([]entity.Seat(nil), best...)
+		}
+	}
+	return nil
+}
+
+// eventSections trả về các khu của venue mà event đang dùng (rỗng nếu chưa gắn venue).
+func (s *seatService) eventSections(ctx context.Context, event *entity.Event) (map[uuid.UUID]entity.VenueSection, error) {
+	sections := make(map[uuid.UUID]entity.VenueSection)
+	if event.VenueID == nil {
+		return sections, nil
+	}
+
+	venue, err := s.venueRepo.GetVenueByID(ctx, *event.VenueID)
+	if err != nil {
+		return nil, err
+	}
+	for _, sec := range venue.Sections {
+		sections[sec.ID] = sec
+	}
+	return sections, nil
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+// maxSessionsPerRequest chặn luật lặp vô tận (VD: DAILY không có count/until hợp lý)
+const maxSessionsPerRequest = 366
+
+type sessionService struct {
+	sessionRepo port.SessionRepositoryPort
+	eventRepo   port.EventRepositoryPort
+	venueRepo   port.VenueRepositoryPort
+}
+
+func NewSessionService(sessionRepo port.SessionRepositoryPort, eventRepo port.EventRepositoryPort, venueRepo port.VenueRepositoryPort) port.SessionServicePort {
+	return &sessionService{
+		sessionRepo: sessionRepo,
+		eventRepo:   eventRepo,
+		venueRepo:   venueRepo,
+	}
+}
+
+// CreateSessions sinh các suất diễn theo luật lặp, mỗi suất có bộ loại vé riêng
+// nhân bản từ req.TicketTypes.
+func (s *sessionService) CreateSessions(ctx context.Context, eventID uuid.UUID, req entity.CreateSessionsRequest) ([]entity.EventSession, error) {
+	if req.StartTime.IsZero() {
+		return nil, errors.New("thời gian bắt đầu không được để trống")
+	}
+	if req.DurationMinutes <= 0 {
+		return nil, errors.New("thời lượng suất diễn phải lớn hơn 0")
+	}
+	if len(req.TicketTypes) == 0 {
+		return nil, errors.New("suất diễn phải có ít nhất một loại vé")
+	}
+	for _, tt := range req.TicketTypes {
+		if err := validateCreateTicketTypeRequest(tt); err != nil {
+			return nil, err
+		}
+	}
+
+	event, err := s.eventRepo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, errors.New("sự kiện không tồn tại")
+	}
+
+	// Mỗi suất dùng toàn bộ venue nên kiểm tra sức chứa trên bộ vé mẫu là đủ
+	if event.VenueID != nil {
+		venue, err := s.venueRepo.GetVenueByID(ctx, *event.VenueID)
+		if err != nil {
+			return nil, err
+		}
+		if err := validateTicketCapacity(venue, req.TicketTypes); err != nil {
+			return nil, err
+		}
+	}
+
+	starts, err := ExpandRecurrence(req.StartTime, req.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := time.Duration(req.DurationMinutes) * time.Minute
+	sessions := make([]entity.EventSession, 0, len(starts))
+	for _, start := range starts {
+		session := entity.EventSession{
+			ID:        uuid.New(),
+			EventID:   event.ID,
+			StartTime: start,
+			EndTime:   start.Add(duration),
+			Status:    entity.SessionStatusScheduled,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		for _, tt := range req.TicketTypes {
+			session.TicketTypes = append(session.TicketTypes, entity.TicketType{
+				ID:                uuid.New(),
+				EventID:           event.ID,
+				SessionID:         &session.ID,
+				SectionID:         tt.SectionID,
+				Name:              tt.Name,
+				Price:             tt.Price,
+				InitialQuantity:   tt.InitialQuantity,
+				RemainingQuantity: tt.InitialQuantity,
+				Visibility:        ticketVisibility(tt.Visibility),
+			})
+		}
+		sessions = append(sessions, session)
+	}
+
+	// Event cha bao trùm toàn bộ các suất để tìm kiếm theo ngày vẫn đúng
+	first, last := sessions[0], sessions[len(sessions)-1]
+	if first.StartTime.Before(event.StartTime) {
+		event.StartTime = first.StartTime
+	}
+	if last.EndTime.After(event.EndTime) {
+		event.EndTime = last.EndTime
+	}
+	rule := req.Recurrence
+	event.Recurrence = &rule
+
+	if err := s.sessionRepo.CreateSessions(ctx, event, sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (s *sessionService) ListEventSessions(ctx context.Context, eventID uuid.UUID, filter entity.SessionFilter) ([]entity.EventSession, error) {
+	filter.EventID = &eventID
+	normalizeSessionFilter(&filter)
+	return s.listPublicSessions(ctx, filter)
+}
+
+// ListUpcomingSessions trải phẳng các suất sắp diễn của mọi event đã publish.
+func (s *sessionService) ListUpcomingSessions(ctx context.Context, filter entity.SessionFilter) ([]entity.EventSession, error) {
+	filter.EventID = nil
+	if filter.From == nil {
+		now := time.Now()
+		filter.From = &now
+	}
+	normalizeSessionFilter(&filter)
+	return s.listPublicSessions(ctx, filter)
+}
+
+// listPublicSessions list suất diễn và giấu các loại vé ẩn/presale khỏi response.
+func (s *sessionService) listPublicSessions(ctx context.Context, filter entity.SessionFilter) ([]entity.EventSession, error) {
+	sessions, err := s.sessionRepo.ListSessions(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].TicketTypes = publicTicketTypes(sessions[i].TicketTypes)
+	}
+	return sessions, nil
+}
+
+func normalizeSessionFilter(filter *entity.SessionFilter) {
+	if filter.Limit <= 0 {
+		filter.Limit = 20
+	}
+	if filter.Limit > 100 {
+		filter.Limit = 100
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+}
+
+// ExpandRecurrence trả về giờ bắt đầu của từng suất theo luật lặp, tính theo
+// giờ địa phương của start để giữ nguyên "19:30 mỗi tối" qua các ngày.
+// Rule rỗng (không có Frequency) nghĩa là chỉ một suất.
+func ExpandRecurrence(start time.Time, rule entity.RecurrenceRule) ([]time.Time, error) {
+	if rule.Frequency == "" {
+		return []time.Time{start}, nil
+	}
+	if rule.Count <= 0 && rule.Until == nil {
+		return nil, errors.New("luật lặp cần count hoặc until")
+	}
+	if rule.Count > maxSessionsPerRequest {
+		return nil, errors.New("số suất diễn vượt quá giới hạn")
+	}
+	if rule.Until != nil && rule.Until.Before(start) {
+		return nil, errors.New("until phải sau thời gian bắt đầu")
+	}
+
+	interval := rule.Interval
+	if interval <= 0 {
+		interval = 1
+	}
+
+	done := func(times []time.Time, next time.Time) bool {
+		if rule.Count > 0 && len(times) >= rule.Count {
+			return true
+		}
+		return rule.Until != nil && next.After(*rule.Until)
+	}
+
+	var times []time.Time
+	switch rule.Frequency {
+	case entity.RecurrenceDaily:
+		for i := 0; ; i++ {
+			next := start.AddDate(0, 0, i*interval)
+			if done(times, next) || len(times) >= maxSessionsPerRequest {
+				break
+			}
+			times = append(times, next)
+		}
+
+	case entity.RecurrenceWeekly:
+		weekdays := rule.Weekdays
+		if len(weekdays) == 0 {
+			weekdays = []time.Weekday{start.Weekday()}
+		}
+		for _, wd := range weekdays {
+			if wd < time.Sunday || wd > time.Saturday {
+				return nil, errors.New("thứ trong tuần không hợp lệ")
+			}
+		}
+		sorted := append([]time.Weekday(nil), weekdays...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		// Đầu tuần (Chủ nhật) chứa suất đầu tiên
+		weekStart := start.AddDate(0, 0, -int(start.Weekday()))
+	weeks:
+		for w := 0; ; w++ {
+			for _, wd := range sorted {
+				next := weekStart.AddDate(0, 0, w*7*interval+int(wd))
+				if next.Before(start) {
+					continue
+				}
+				if done(times, next) || len(times) >= maxSessionsPerRequest {
+					break weeks
+				}
+				times = append(times, next)
+			}
+		}
+
+	default:
+		return nil, errors.New("tần suất lặp không hợp lệ (DAILY hoặc WEEKLY)")
+	}
+
+	if len(times) == 0 {
+		return nil, errors.New("luật lặp không sinh ra suất diễn nào")
+	}
+	return times, nil
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/yourname/ticketing-system/internal/adapter/repository"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+// TransferService cho người giữ vé chuyển nhượng vé cho bạn bè qua email. Người nhận chấp nhận
+// bằng tài khoản có đúng email đó; vé đổi chủ và được cấp mã mới để mã cũ không vào cổng được nữa.
+type TransferService struct {
+	db       *gorm.DB
+	repo     *repository.TransferRepository
+	userRepo port.UserRepositoryPort
+	notifier port.NotifierPort
+}
+
+func NewTransferService(db *gorm.DB, repo *repository.TransferRepository, userRepo port.UserRepositoryPort, notifier port.NotifierPort) *TransferService {
+	return &TransferService{
+		db:       db,
+		repo:     repo,
+		userRepo: userRepo,
+		notifier: notifier,
+	}
+}
+
+// ListMyTickets trả về các vé user đang giữ.
+func (s *TransferService) ListMyTickets(ctx context.Context, userID uuid.UUID) ([]entity.Ticket, error) {
+	return s.repo.ListTicketsByOwner(ctx, userID)
+}
+
+// Initiate tạo lượt chuyển vé ticketID của userID tới email người nhận.
+func (s *TransferService) Initiate(ctx context.Context, userID, ticketID uuid.UUID, req entity.CreateTicketTransferRequest) (*entity.TicketTransfer, error) {
+	email := normalizeEmail(req.Email)
+	if email == "" || !strings.Contains(email, "@") {
+		return nil, errors.New("email người nhận không hợp lệ")
+	}
+	sender, err := s.userRepo.GetUserByID(ctx, userID.String())
+	if err != nil {
+		return nil, errors.New("tài khoản không tồn tại")
+	}
+	if normalizeEmail(sender.Email) == email {
+		return nil, errors.New("không thể chuyển vé cho chính mình")
+	}
+
+	tx := s.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	ticket, err := s.lockOwnedTicket(ctx, tx, userID, ticketID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	pending, err := s.repo.HasPendingTransfer(ctx, tx, ticket.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if pending {
+		tx.Rollback()
+		return nil, errors.New("vé đang chờ người nhận chấp nhận, hãy hủy lượt chuyển cũ trước")
+	}
+	listed, err := s.repo.HasActiveListing(ctx, tx, ticket.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if listed {
+		tx.Rollback()
+		return nil, errors.New("vé đang được rao bán lại, hãy gỡ tin bán trước khi chuyển nhượng")
+	}
+
+	transfer := &entity.TicketTransfer{
+		ID:         uuid.New(),
+		TicketID:   ticket.ID,
+		FromUserID: userID,
+		ToEmail:    email,
+		Status:     entity.TicketTransferStatusPending,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	if err := s.repo.CreateTransfer(ctx, tx, transfer); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	// Người nhận đã có tài khoản thì báo luôn, chưa có thì thấy lượt chuyển sau khi đăng ký bằng email này
+	if recipient, err := s.userRepo.GetUserByEmail(ctx, email); err == nil && recipient != nil {
+		s.notify(ctx, recipient.ID, "Bạn được chuyển nhượng một vé",
+			fmt.Sprintf("%s muốn chuyển cho bạn một vé. Vào mục chuyển nhượng để chấp nhận.", sender.Username))
+	}
+	return transfer, nil
+}
+
+// Accept cho người nhận (đăng nhập bằng tài khoản có email người nhận, đã xác thực) nhận vé: vé đổi chủ,
+// được cấp mã mới và mã cũ bị hủy.
+func (s *TransferService) Accept(ctx context.Context, userID, transferID uuid.UUID) (*entity.Ticket, error) {
+	recipient, err := s.userRepo.GetUserByID(ctx, userID.String())
+	if err != nil {
+		return nil, errors.New("tài khoản không tồn tại")
+	}
+	// Luôn bắt buộc (không phụ thuộc REQUIRE_EMAIL_VERIFIED): chưa xác thực thì chưa chứng minh được
+	// sở hữu hộp thư, ai đăng ký trước bằng email người nhận sẽ lấy mất vé
+	if recipient.EmailVerifiedAt == nil {
+		return nil, errors.New("vui lòng xác thực email trước khi nhận vé")
+	}
+
+	tx := s.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	transfer, err := s.repo.GetTransferForUpdate(ctx, tx, transferID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("lượt chuyển nhượng không tồn tại")
+		}
+		return nil, err
+	}
+	if transfer.ToEmail != normalizeEmail(recipient.Email) {
+		tx.Rollback()
+		return nil, errors.New("lượt chuyển nhượng không dành cho tài khoản này")
+	}
+	if transfer.Status != entity.TicketTransferStatusPending {
+		tx.Rollback()
+		return nil, errors.New("lượt chuyển nhượng đã được xử lý")
+	}
+
+	// Kiểm tra lại vé: người gửi có thể đã dùng vé hoặc ban tổ chức vừa tắt chuyển nhượng
+	ticket, err := s.lockOwnedTicket(ctx, tx, transfer.FromUserID, transfer.TicketID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	code, err := newTicketCode()
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	transfer.OldTicketCode = ticket.TicketCode
+	ticket.TicketCode = code // mã cũ không còn tồn tại nên không soát vé được nữa
+	ticket.OwnerID = &recipient.ID
+	ticket.OwnerName = ""
+	ticket.Answers = nil // người nhận tự điền thông tin người tham dự
+	if err := s.repo.SaveTicket(ctx, tx, ticket); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	now := time.Now()
+	transfer.Status = entity.TicketTransferStatusAccepted
+	transfer.ToUserID = &recipient.ID
+	transfer.AcceptedAt = &now
+	if err := s.repo.SaveTransfer(ctx, tx, transfer); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	s.notify(ctx, transfer.FromUserID, "Vé đã được chuyển nhượng",
+		fmt.Sprintf("%s đã nhận vé bạn chuyển. Mã vé cũ không còn hiệu lực.", recipient.Email))
+	return ticket, nil
+}
+
+// Cancel hủy lượt chuyển đang chờ: người gửi rút lại hoặc người nhận từ chối.
+func (s *TransferService) Cancel(ctx context.Context, userID, transferID uuid.UUID) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID.String())
+	if err != nil {
+		return errors.New("tài khoản không tồn tại")
+	}
+
+	tx := s.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	transfer, err := s.repo.GetTransferForUpdate(ctx, tx, transferID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("lượt chuyển nhượng không tồn tại")
+		}
+		return err
+	}
+	if transfer.FromUserID != userID && transfer.ToEmail != normalizeEmail(user.Email) {
+		tx.Rollback()
+		return errors.New("lượt chuyển nhượng không tồn tại")
+	}
+	if transfer.Status != entity.TicketTransferStatusPending {
+		tx.Rollback()
+		return errors.New("lượt chuyển nhượng đã được xử lý")
+	}
+
+	transfer.Status = entity.TicketTransferStatusCancelled
+	if err := s.repo.SaveTransfer(ctx, tx, transfer); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit().Error
+}
+
+// ListTransfers trả về các lượt chuyển user đã gửi hoặc nhận (theo email tài khoản).
+func (s *TransferService) ListTransfers(ctx context.Context, userID uuid.UUID) ([]entity.TicketTransfer, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID.String())
+	if err != nil {
+		return nil, errors.New("tài khoản không tồn tại")
+	}
+	return s.repo.ListByUser(ctx, userID, normalizeEmail(user.Email))
+}
+
+// TicketHistory trả về lịch sử chuyển nhượng của vé; chỉ người đang giữ vé được xem.
+func (s *TransferService) TicketHistory(ctx context.Context, userID, ticketID uuid.UUID) ([]entity.TicketTransfer, error) {
+	tickets, err := s.repo.ListTicketsByOwner(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ticket := range tickets {
+		if ticket.ID == ticketID {
+			return s.repo.ListByTicket(ctx, ticketID)
+		}
+	}
+	return nil, errors.New("vé không tồn tại")
+}
+
+// SetEventTransfers bật/tắt chuyển nhượng cho toàn bộ vé của event.
+func (s *TransferService) SetEventTransfers(ctx context.Context, eventID uuid.UUID, disabled bool) error {
+	updated, err := s.repo.SetEventTransfersDisabled(ctx, eventID, disabled)
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return errors.New("sự kiện không tồn tại")
+	}
+	return nil
+}
+
+// SetTicketTypeTransfers bật/tắt chuyển nhượng cho một loại vé.
+func (s *TransferService) SetTicketTypeTransfers(ctx context.Context, ticketTypeID uuid.UUID, disabled bool) error {
+	updated, err := s.repo.SetTicketTypeTransfersDisabled(ctx, ticketTypeID, disabled)
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return errors.New("loại vé không tồn tại")
+	}
+	return nil
+}
+
+// lockOwnedTicket khóa vé và kiểm tra vé chuyển được: đúng chủ, chưa dùng, đơn đã thanh toán
+// và ban tổ chức không tắt chuyển nhượng.
+func (s *TransferService) lockOwnedTicket(ctx context.Context, tx *gorm.DB, ownerID, ticketID uuid.UUID) (*entity.Ticket, error) {
+	ticket, err := s.repo.GetTicketForUpdate(ctx, tx, ticketID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("vé không tồn tại")
+		}
+		return nil, err
+	}
+	if ticket.OwnerID == nil || *ticket.OwnerID != ownerID {
+		return nil, errors.New("vé không tồn tại")
+	}
+	if ticket.Status != entity.TicketStatusUnused {
+		return nil, errors.New("vé đã sử dụng, không chuyển nhượng được")
+	}
+
+	status, err := s.repo.OrderStatus(ctx, tx, ticket.OrderID)
+	if err != nil {
+		return nil, err
+	}
+	if status != entity.OrderStatusPaid {
+		return nil, errors.New("chỉ chuyển nhượng được vé của đơn đã thanh toán")
+	}
+
+	disabled, err := s.repo.TransfersDisabled(ctx, tx, ticket.TicketTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if disabled {
+		return nil, errors.New("ban tổ chức không cho phép chuyển nhượng vé này")
+	}
+	return ticket, nil
+}
+
+func (s *TransferService) notify(ctx context.Context, userID uuid.UUID, subject, message string) {
+	if err := s.notifier.Notify(ctx, userID, subject, message); err != nil {
+		log.Printf("Gửi thông báo chuyển nhượng cho user %s thất bại: %v", userID, err)
+	}
+}
+
+// normalizeEmail đưa email về chữ thường để so khớp người nhận.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+const defaultVenueTimezone = "Asia/Ho_Chi_Minh"
+
+type venueService struct {
+	venueRepo port.VenueRepositoryPort
+}
+
+func NewVenueService(venueRepo port.VenueRepositoryPort) port.VenueServicePort {
+	return &venueService{
+		venueRepo: venueRepo,
+	}
+}
+
+func (s *venueService) CreateVenue(ctx context.Context, req entity.CreateVenueRequest) (*entity.Venue, error) {
+	if req.Timezone == "" {
+		req.Timezone = defaultVenueTimezone
+	}
+	if err := validateVenueInfo(req.Name, req.Timezone, req.Latitude, req.Longitude, req.Capacity); err != nil {
+		return nil, err
+	}
+
+	venue := &entity.Venue{
+		ID:        uuid.New(),
+		Name:      req.Name,
+		Address:   req.Address,
+		Timezone:  req.Timezone,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		Capacity:  req.Capacity,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	// Tổng sức chứa các khu không được vượt sức chứa venue
+	sectionTotal := 0
+	for _, sec := range req.Sections {
+		if err := validateVenueSection(sec); err != nil {
+			return nil, err
+		}
+		sectionTotal += sec.Capacity
+
+		venue.Sections = append(venue.Sections, entity.VenueSection{
+			ID:       uuid.New(),
+			VenueID:  venue.ID,
+			Name:     sec.Name,
+			Capacity: sec.Capacity,
+		})
+	}
+	if sectionTotal > venue.Capacity {
+		return nil, errors.New("tổng sức chứa các khu vượt quá sức chứa địa điểm")
+	}
+
+	if err := s.venueRepo.CreateVenue(ctx, venue); err != nil {
+		return nil, err
+	}
+	return venue, nil
+}
+
+func (s *venueService) GetVenue(ctx context.Context, id uuid.UUID) (*entity.Venue, error) {
+	return s.venueRepo.GetVenueByID(ctx, id)
+}
+
+func (s *venueService) ListVenues(ctx context.Context, limit int, offset int) ([]entity.Venue, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.venueRepo.ListVenues(ctx, limit, offset)
+}
+
+func (s *venueService) UpdateVenue(ctx context.Context, id uuid.UUID, req entity.UpdateVenueRequest) (*entity.Venue, error) {
+	venue, err := s.venueRepo.GetVenueByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Timezone == "" {
+		req.Timezone = venue.Timezone
+	}
+	if err := validateVenueInfo(req.Name, req.Timezone, req.Latitude, req.Longitude, req.Capacity); err != nil {
+		return nil, err
+	}
+
+	// Không cho giảm sức chứa xuống dưới số vé đã phát hành của bất kỳ event nào
+	if req.Capacity < venue.Capacity {
+		allocated, err := s.venueRepo.MaxEventAllocation(ctx, venue.ID, nil)
+		if err != nil {
+			return nil, err
+		}
+		if allocated > req.Capacity {
+			return nil, errors.New("sức chứa mới nhỏ hơn số vé đã phát hành cho sự kiện tại địa điểm này")
+		}
+	}
+
+	sectionTotal := 0
+	for _, sec := range venue.Sections {
+		sectionTotal += sec.Capacity
+	}
+	if sectionTotal > req.Capacity {
+		return nil, errors.New("tổng sức chứa các khu vượt quá sức chứa địa điểm")
+	}
+
+	venue.Name = req.Name
+	venue.Address = req.Address
+	venue.Timezone = req.Timezone
+	venue.Latitude = req.Latitude
+	venue.Longitude = req.Longitude
+	venue.Capacity = req.Capacity
+	venue.UpdatedAt = time.Now()
+
+	if err := s.venueRepo.UpdateVenue(ctx, venue); err != nil {
+		return nil, err
+	}
+	return venue, nil
+}
+
+func (s *venueService) DeleteVenue(ctx context.Context, id uuid.UUID) error {
+	// Event đang tham chiếu venue sẽ bị chặn bởi khóa ngoại (ON DELETE RESTRICT)
+	return s.venueRepo.DeleteVenue(ctx, id)
+}
+
+func (s *venueService) AddSection(ctx context.Context, venueID uuid.UUID, req entity.CreateVenueSectionRequest) (*entity.VenueSection, error) {
+	if err := validateVenueSection(req); err != nil {
+		return nil, err
+	}
+
+	venue, err := s.venueRepo.GetVenueByID(ctx, venueID)
+	if err != nil {
+		return nil, err
+	}
+
+	sectionTotal := req.Capacity
+	for _, sec := range venue.Sections {
+		sectionTotal += sec.Capacity
+	}
+	if sectionTotal > venue.Capacity {
+		return nil, errors.New("tổng sức chứa các khu vượt quá sức chứa địa điểm")
+	}
+
+	section := &entity.VenueSection{
+		ID:       uuid.New(),
+		VenueID:  venue.ID,
+		Name:     req.Name,
+		Capacity: req.Capacity,
+	}
+	if err := s.venueRepo.CreateSection(ctx, section); err != nil {
+		return nil, err
+	}
+	return section, nil
+}
+
+// validateTicketCapacity kiểm tra tổng InitialQuantity của các loại vé không vượt
+// sức chứa venue, và từng khu không vượt sức chứa của khu đó.
+func validateTicketCapacity(venue *entity.Venue, ticketTypes []entity.CreateTicketTypeRequest) error {
+	sections := make(map[uuid.UUID]entity.VenueSection, len(venue.Sections))
+	for _, sec := range venue.Sections {
+		sections[sec.ID] = sec
+	}
+
+	total := 0
+	perSection := make(map[uuid.UUID]int)
+	for _, tt := range ticketTypes {
+		total += tt.InitialQuantity
+		if tt.SectionID == nil {
+			continue
+		}
+
+		if _, ok := sections[*tt.SectionID]; !ok {
+			return errors.New("khu (section) không thuộc địa điểm của sự kiện")
+		}
+		perSection[*tt.SectionID] += tt.InitialQuantity
+	}
+
+	if total > venue.Capacity {
+		return errors.New("tổng số vé vượt quá sức chứa địa điểm")
+	}
+	for id, qty := range perSection {
+		if qty > sections[id].Capacity {
+			return errors.New("số vé vượt quá sức chứa của khu " + sections[id].Name)
+		}
+	}
+	return nil
+}
+
+func validateVenueInfo(name string, timezone string, lat float64, lng float64, capacity int) error {
+	if name == "" {
+		return errors.New("tên địa điểm không được để trống")
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return errors.New("múi giờ không hợp lệ")
+	}
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return errors.New("tọa độ không hợp lệ")
+	}
+	if capacity <= 0 {
+		return errors.New("sức chứa phải lớn hơn 0")
+	}
+	return nil
+}
+
+func validateVenueSection(req entity.CreateVenueSectionRequest) error {
+	if req.Name == "" {
+		return errors.New("tên khu không được để trống")
+	}
+	if req.Capacity <= 0 {
+		return errors.New("sức chứa của khu phải lớn hơn 0")
+	}
+	return nil
+}
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/yourname/ticketing-system/internal/adapter/repository"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+)
+
+// maxWaitlistQuantity giới hạn số vé một lượt chờ được xin, tránh một người ôm hết vé trả lại.
+const maxWaitlistQuantity = 10
+
+// WaitlistService quản lý hàng chờ cho loại vé đã hết. Khi có vé trả lại (hủy đơn, đơn hết hạn,
+// admin mở bán thêm), người đầu hàng được giữ riêng số vé họ cần trong offerTTL; không nhận thì
+// vé chuyển cho người kế tiếp.
+type WaitlistService struct {
+	db        *gorm.DB
+	repo      *repository.WaitlistRepository
+	orderRepo *repository.OrderRepository // dùng chung các hàm khóa/trừ kho với OrderService
+	notifier  port.NotifierPort
+	offerTTL  time.Duration // thời gian giữ vé cho một offer
+}
+
+func NewWaitlistService(db *gorm.DB, repo *repository.WaitlistRepository, orderRepo *repository.OrderRepository, notifier port.NotifierPort, offerTTL time.Duration) *WaitlistService {
+	return &WaitlistService{
+		db:        db,
+		repo:      repo,
+		orderRepo: orderRepo,
+		notifier:  notifier,
+		offerTTL:  offerTTL,
+	}
+}
+
+// Join cho user vào hàng chờ của loại vé. Chỉ vào được khi loại vé thật sự không đủ vé.
+func (w *WaitlistService) Join(ctx context.Context, userID uuid.UUID, req entity.JoinWaitlistRequest) (*entity.WaitlistEntry, error) {
+	if req.Quantity <= 0 || req.Quantity > maxWaitlistQuantity {
+		return nil, fmt.Errorf("số lượng chờ phải từ 1 đến %d", maxWaitlistQuantity)
+	}
+
+	tx := w.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	// Khóa loại vé để không lọt khe giữa lúc kiểm tra kho và lúc có vé trả lại
+	ticketType, err := w.orderRepo.GetTicketTypeForUpdate(ctx, tx, req.TicketTypeID)
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("loại vé không tồn tại")
+		}
+		return nil, err
+	}
+	if ticketType.Reserved {
+		tx.Rollback()
+		return nil, errors.New("vé ngồi theo số không hỗ trợ hàng chờ")
+	}
+	if ticketType.Ballot {
+		tx.Rollback()
+		return nil, errors.New("loại vé bán qua bốc thăm không hỗ trợ hàng chờ")
+	}
+	if !ticketType.IsPublic() {
+		tx.Rollback()
+		return nil, errors.New("loại vé presale không hỗ trợ hàng chờ")
+	}
+
+	available, err := availableStock(ctx, tx, w.orderRepo, ticketType)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if available >= req.Quantity {
+		tx.Rollback()
+		return nil, errors.New("loại vé vẫn còn đủ vé, hãy đặt trực tiếp")
+	}
+
+	active, err := w.repo.HasActiveEntry(ctx, tx, userID, req.TicketTypeID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if active {
+		tx.Rollback()
+		return nil, errors.New("bạn đã ở trong hàng chờ của loại vé này")
+	}
+
+	entry := &entity.WaitlistEntry{
+		ID:           uuid.New(),
+		TicketTypeID: req.TicketTypeID,
+		UserID:       userID,
+		Quantity:     req.Quantity,
+		Status:       entity.WaitlistStatusWaiting,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+	if err := w.repo.CreateEntry(ctx, tx, entry); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// ListEntries trả về các lượt chờ của user.
+func (w *WaitlistService) ListEntries(ctx context.Context, userID uuid.UUID) ([]entity.WaitlistEntry, error) {
+	return w.repo.ListByUser(ctx, userID)
+}
+
+// Leave cho user rời hàng chờ. Nếu đang được giữ vé thì vé chuyển ngay cho người kế tiếp.
+func (w *WaitlistService) Leave(ctx context.Context, userID, entryID uuid.UUID) error {
+	tx := w.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	entry, err := w.getOwnedEntry(ctx, tx, userID, entryID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	var offers []entity.WaitlistEntry
+	switch entry.Status {
+	case entity.WaitlistStatusWaiting:
+		entry.Status = entity.WaitlistStatusCancelled
+		if err := w.repo.SaveEntry(ctx, tx, entry); err != nil {
+			tx.Rollback()
+			return err
+		}
+	case entity.WaitlistStatusOffered:
+		offers, err = w.releaseOffer(ctx, tx, entry, entity.WaitlistStatusCancelled)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	default:
+		tx.Rollback()
+		return errors.New("lượt chờ này đã kết thúc")
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	w.notifyOffers(ctx, offers)
+	return nil
+}
+
+// Claim nhận số vé đang được giữ: tạo đơn PENDING với giá hiện tại của loại vé và phát hành vé.
+// Kho đã được trừ lúc cấp offer nên không trừ lại.
+func (w *WaitlistService) Claim(ctx context.Context, userID, entryID uuid.UUID) (*entity.Order, error) {
+	tx := w.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	entry, err := w.getOwnedEntry(ctx, tx, userID, entryID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if entry.Status != entity.WaitlistStatusOffered {
+		tx.Rollback()
+		return nil, errors.New("lượt chờ này không có vé đang được giữ")
+	}
+	if entry.OfferExpiresAt != nil && time.Now().After(*entry.OfferExpiresAt) {
+		tx.Rollback()
+		return nil, errors.New("đã hết thời gian giữ vé")
+	}
+
+	ticketType, err := w.orderRepo.GetTicketTypeForUpdate(ctx, tx, entry.TicketTypeID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	order, err := createHeldTicketOrder(ctx, tx, w.orderRepo, userID, ticketType, entry.Quantity, nil)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	entry.Status = entity.WaitlistStatusClaimed
+	entry.OrderID = &order.ID
+	if err := w.repo.SaveEntry(ctx, tx, entry); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// ExpireOffers thu hồi các offer quá hạn và chuyển vé cho người kế tiếp. Trả về số offer đã thu hồi.
+// Gọi định kỳ từ background job; offer lỗi chỉ ghi log để không chặn hàng chờ phía sau.
+func (w *WaitlistService) ExpireOffers(ctx context.Context) (int, error) {
+	ids, err := w.repo.ListExpiredOffers(ctx, time.Now(), 100)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, id := range ids {
+		ok, err := w.expireOffer(ctx, id)
+		if err != nil {
+			log.Printf("Thu hồi vé giữ %s cho hàng chờ thất bại: %v", id, err)
+			continue
+		}
+		if ok {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+func (w *WaitlistService) expireOffer(ctx context.Context, entryID uuid.UUID) (bool, error) {
+	tx := w.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	entry, err := w.repo.GetEntryForUpdate(ctx, tx, entryID)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	// User có thể vừa nhận vé/rời hàng trước khi job khóa được entry
+	if entry.Status != entity.WaitlistStatusOffered || entry.OfferExpiresAt == nil || time.Now().Before(*entry.OfferExpiresAt) {
+		tx.Rollback()
+		return false, nil
+	}
+
+	offers, err := w.releaseOffer(ctx, tx, entry, entity.WaitlistStatusExpired)
+	if err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+	w.notifyOffers(ctx, offers)
+	return true, nil
+}
+
+// releaseOffer trả số vé đang giữ của entry về kho, đóng entry với status
+// rồi cấp lại cho người kế tiếp trong hàng chờ.
+func (w *WaitlistService) releaseOffer(ctx context.Context, tx *gorm.DB, entry *entity.WaitlistEntry, status entity.WaitlistStatus) ([]entity.WaitlistEntry, error) {
+	ticketType, err := w.orderRepo.GetTicketTypeForUpdate(ctx, tx, entry.TicketTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if err := releaseStock(ctx, tx, w.orderRepo, ticketType, entry.Quantity); err != nil {
+		return nil, err
+	}
+
+	entry.Status = status
+	if err := w.repo.SaveEntry(ctx, tx, entry); err != nil {
+		return nil, err
+	}
+	return w.offerReleasedStock(ctx, tx, []uuid.UUID{entry.TicketTypeID})
+}
+
+// offerReleasedStock cấp vé vừa trả lại cho hàng chờ theo FIFO: người đầu hàng được giữ đủ số vé
+// họ cần (trừ kho luôn, người khác không mua được). Đầu hàng cần nhiều hơn số vé đang có thì dừng,
+// không nhảy cóc người sau. Gọi trong cùng transaction với việc trả kho; ticketTypeIDs phải sắp theo id.
+func (w *WaitlistService) offerReleasedStock(ctx context.Context, tx *gorm.DB, ticketTypeIDs []uuid.UUID) ([]entity.WaitlistEntry, error) {
+	var offers []entity.WaitlistEntry
+	for _, id := range ticketTypeIDs {
+		ticketType, err := w.orderRepo.GetTicketTypeForUpdate(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		available, err := availableStock(ctx, tx, w.orderRepo, ticketType)
+		if err != nil {
+			return nil, err
+		}
+
+		for available > 0 {
+			entry, err := w.repo.NextWaiting(ctx, tx, id)
+			if err != nil {
+				return nil, err
+			}
+			if entry == nil || entry.Quantity > available {
+				break
+			}
+
+			if err := reserveStock(ctx, tx, w.orderRepo, ticketType, entry.Quantity); err != nil {
+				return nil, err
+			}
+			ticketType.RemainingQuantity -= entry.Quantity
+			available -= entry.Quantity
+
+			expiresAt := time.Now().Add(w.offerTTL)
+			entry.Status = entity.WaitlistStatusOffered
+			entry.OfferExpiresAt = &expiresAt
+			if err := w.repo.SaveEntry(ctx, tx, entry); err != nil {
+				return nil, err
+			}
+			offers = append(offers, *entry)
+		}
+	}
+	return offers, nil
+}
+
+// notifyOffers báo cho user được giữ vé. Gọi sau khi commit; gửi lỗi chỉ log lại,
+// offer vẫn còn hiệu lực và user xem được qua GET /waitlist.
+func (w *WaitlistService) notifyOffers(ctx context.Context, offers []entity.WaitlistEntry) {
+	for _, offer := range offers {
+		message := fmt.Sprintf("Có %d vé đang được giữ cho bạn tới %s. Xác nhận tại /api/v1/waitlist/%s/claim",
+			offer.Quantity, offer.OfferExpiresAt.Format(time.RFC3339), offer.ID)
+		if err := w.notifier.Notify(ctx, offer.UserID, "Bạn đã có vé từ hàng chờ", message); err != nil {
+			log.Printf("Gửi thông báo hàng chờ %s thất bại: %v", offer.ID, err)
+		}
+	}
+}
+
+func (w *WaitlistService) getOwnedEntry(ctx context.Context, tx *gorm.DB, userID, entryID uuid.UUID) (*entity.WaitlistEntry, error) {
+	entry, err := w.repo.GetEntryForUpdate(ctx, tx, entryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("không tìm thấy lượt chờ")
+		}
+		return nil, err
+	}
+	if entry.UserID != userID {
+		return nil, errors.New("không tìm thấy lượt chờ")
+	}
+	return entry, nil
+}
@@ -1,9 +1,11 @@
 package auth
 
 import (
+	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 type Claims struct {
@@ -12,28 +14,55 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID string, role string, secret string) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
+// GenerateToken ký access token sống ttl bằng khóa đang hiệu lực của keys (kid ghi trong header).
+// Mỗi token có jti (Claims.ID) riêng để thu hồi được trước khi hết hạn (logout).
+// Trả về cả claims để caller biết jti và thời điểm hết hạn.
+func GenerateToken(userID string, role string, keys *KeySet, ttl time.Duration) (string, *Claims, error) {
+	now := time.Now()
+	key, err := keys.SigningKey(now)
+	if err != nil {
+		return "", nil, err
+	}
 
 	claims := &Claims{
 		UserID: userID,
 		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			ID:        uuid.New().String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+	token := jwt.NewWithClaims(key.signingMethod(), claims)
+	token.Header["kid"] = key.ID
+	signed, err := token.SignedString(key.signer)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
 }
 
-func ValidateToken(tokenString string, secret string) (*Claims, error) {
+// ValidateToken kiểm tra chữ ký bằng khóa theo kid trong header (kể cả khóa đã thôi ký nhưng
+// token của nó chưa hết hạn). Thuật toán phải khớp đúng khóa để chặn token giả alg.
+func ValidateToken(tokenString string, keys *KeySet) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		key, err := keys.VerificationKey(kid, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != key.Algorithm {
+			return nil, errors.New("thuật toán không khớp khóa")
+		}
+		return key.signer.Public(), nil
+	}, jwt.WithValidMethods([]string{AlgRS256, AlgEdDSA}))
+	if err != nil {
+		return nil, err
+	}
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
 		return claims, nil
 	}
-	return nil, err
+	return nil, jwt.ErrTokenInvalidClaims
 }
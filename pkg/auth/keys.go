@@ -0,0 +1,249 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// Key là một khóa ký JWT. Khóa bắt đầu được dùng để ký từ ActiveFrom, thôi ký từ RetireAt
+// nhưng vẫn dùng để kiểm tra token cho tới khi token cuối cùng ký bằng nó hết hạn.
+type Key struct {
+	ID         string // kid trong header JWT
+	Algorithm  string // RS256 hoặc EdDSA
+	ActiveFrom time.Time
+	RetireAt   *time.Time // nil = chưa lên lịch thay
+	signer     crypto.Signer
+}
+
+func (k *Key) signingMethod() jwt.SigningMethod {
+	if k.Algorithm == AlgEdDSA {
+		return jwt.SigningMethodEdDSA
+	}
+	return jwt.SigningMethodRS256
+}
+
+// KeySet giữ các khóa ký theo lịch xoay vòng. Khóa mới được khai báo trước với ActiveFrom
+// trong tương lai: JWKS công bố sớm để bên kiểm tra kịp cache, tới giờ thì tự chuyển sang ký bằng nó.
+type KeySet struct {
+	keys        []*Key        // sắp theo ActiveFrom tăng dần
+	maxTokenTTL time.Duration // token dài nhất ký bằng các khóa này, để biết khi nào khóa hết dùng kiểm tra
+}
+
+// NewKeySet tạo KeySet từ danh sách khóa. maxTokenTTL là thời hạn token dài nhất được ký.
+func NewKeySet(keys []*Key, maxTokenTTL time.Duration) (*KeySet, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("cần ít nhất một khóa ký JWT")
+	}
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if k.ID == "" || seen[k.ID] {
+			return nil, fmt.Errorf("kid rỗng hoặc bị trùng: %q", k.ID)
+		}
+		seen[k.ID] = true
+		if err := checkKeyAlgorithm(k); err != nil {
+			return nil, err
+		}
+	}
+
+	sorted := append([]*Key(nil), keys...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ActiveFrom.Before(sorted[j].ActiveFrom) })
+	return &KeySet{keys: sorted, maxTokenTTL: maxTokenTTL}, nil
+}
+
+// keyManifest là file JSON khai báo các khóa và lịch xoay vòng, VD:
+//
+//	{"keys": [{"kid": "2026-10", "alg": "EdDSA", "private_key_file": "keys/2026-10.pem",
+//	           "active_from": "2026-10-01T00:00:00Z", "retire_at": "2026-11-01T00:00:00Z"}]}
+//
+// Đường dẫn private_key_file tương đối tính từ thư mục chứa manifest. Khóa dạng PEM PKCS#8
+// (hoặc PKCS#1 với RSA), sinh bằng: openssl genpkey -algorithm ed25519 -out 2026-10.pem
+type keyManifest struct {
+	Keys []struct {
+		ID             string     `json:"kid"`
+		Algorithm      string     `json:"alg"`
+		PrivateKeyFile string     `json:"private_key_file"`
+		ActiveFrom     time.Time  `json:"active_from"`
+		RetireAt       *time.Time `json:"retire_at"`
+	} `json:"keys"`
+}
+
+// LoadKeySet đọc manifest khóa ký và các file private key.
+func LoadKeySet(manifestPath string, maxTokenTTL time.Duration) (*KeySet, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, err
+	}
+	var manifest keyManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("manifest khóa JWT không hợp lệ: %w", err)
+	}
+
+	dir := filepath.Dir(manifestPath)
+	keys := make([]*Key, 0, len(manifest.Keys))
+	for _, entry := range manifest.Keys {
+		path := entry.PrivateKeyFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		signer, err := readPrivateKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("khóa %s: %w", entry.ID, err)
+		}
+		keys = append(keys, &Key{
+			ID:         entry.ID,
+			Algorithm:  entry.Algorithm,
+			ActiveFrom: entry.ActiveFrom,
+			RetireAt:   entry.RetireAt,
+			signer:     signer,
+		})
+	}
+	return NewKeySet(keys, maxTokenTTL)
+}
+
+// GenerateEphemeralKeySet sinh một khóa Ed25519 tạm trong bộ nhớ (chỉ dùng khi dev):
+// khởi động lại là token cũ hết hiệu lực và mỗi instance có khóa riêng.
+func GenerateEphemeralKeySet(maxTokenTTL time.Duration) (*KeySet, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeySet([]*Key{{
+		ID:         "ephemeral-" + time.Now().UTC().Format("20060102T150405"),
+		Algorithm:  AlgEdDSA,
+		ActiveFrom: time.Now(),
+		signer:     private,
+	}}, maxTokenTTL)
+}
+
+// SigningKey trả về khóa dùng để ký lúc now: khóa đã tới ActiveFrom, chưa tới RetireAt, mới nhất.
+func (ks *KeySet) SigningKey(now time.Time) (*Key, error) {
+	for i := len(ks.keys) - 1; i >= 0; i-- {
+		k := ks.keys[i]
+		if k.ActiveFrom.After(now) {
+			continue
+		}
+		if k.RetireAt != nil && !now.Before(*k.RetireAt) {
+			continue
+		}
+		return k, nil
+	}
+	return nil, errors.New("không có khóa ký JWT nào đang hiệu lực")
+}
+
+// VerificationKey trả về khóa theo kid nếu còn dùng để kiểm tra được lúc now.
+func (ks *KeySet) VerificationKey(kid string, now time.Time) (*Key, error) {
+	for _, k := range ks.keys {
+		if k.ID == kid && ks.verifiable(k, now) {
+			return k, nil
+		}
+	}
+	return nil, fmt.Errorf("không tìm thấy khóa kiểm tra cho kid %q", kid)
+}
+
+// verifiable: khóa đã thôi ký vẫn kiểm tra được tới khi token dài nhất ký trước RetireAt hết hạn.
+func (ks *KeySet) verifiable(k *Key, now time.Time) bool {
+	return k.RetireAt == nil || now.Before(k.RetireAt.Add(ks.maxTokenTTL))
+}
+
+// JWK là public key dạng JSON Web Key (RFC 7517).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`   // RSA modulus
+	E         string `json:"e,omitempty"`   // RSA exponent
+	Curve     string `json:"crv,omitempty"` // OKP
+	X         string `json:"x,omitempty"`   // Ed25519 public key
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS trả về public key của mọi khóa còn kiểm tra được, kể cả khóa sắp tới lượt ký.
+func (ks *KeySet) JWKS(now time.Time) JWKS {
+	set := JWKS{Keys: make([]JWK, 0, len(ks.keys))}
+	for _, k := range ks.keys {
+		if !ks.verifiable(k, now) {
+			continue
+		}
+		jwk := JWK{KeyID: k.ID, Use: "sig", Algorithm: k.Algorithm}
+		switch pub := k.signer.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+func checkKeyAlgorithm(k *Key) error {
+	switch k.Algorithm {
+	case AlgRS256:
+		if _, ok := k.signer.(*rsa.PrivateKey); !ok {
+			return fmt.Errorf("khóa %s khai báo RS256 nhưng không phải khóa RSA", k.ID)
+		}
+	case AlgEdDSA:
+		if _, ok := k.signer.(ed25519.PrivateKey); !ok {
+			return fmt.Errorf("khóa %s khai báo EdDSA nhưng không phải khóa Ed25519", k.ID)
+		}
+	default:
+		return fmt.Errorf("thuật toán không hỗ trợ cho khóa %s: %q", k.ID, k.Algorithm)
+	}
+	return nil
+}
+
+func readPrivateKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("file không phải PEM")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := parsed.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("loại khóa không hỗ trợ")
+		}
+		return signer, nil
+	default:
+		return nil, fmt.Errorf("loại PEM không hỗ trợ: %s", block.Type)
+	}
+}
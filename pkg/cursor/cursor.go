@@ -0,0 +1,58 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var ErrInvalidCursor = errors.New("cursor không hợp lệ")
+
+// Encode đóng gói payload thành chuỗi opaque dạng <base64(json)>.<base64(hmac)>.
+// Client chỉ việc gửi lại nguyên chuỗi, không đọc/sửa được nội dung bên trong.
+func Encode(payload interface{}, secret string) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	body := base64.RawURLEncoding.EncodeToString(data)
+	return body + "." + sign(body, secret), nil
+}
+
+// Decode kiểm tra chữ ký rồi giải nén payload vào out.
+func Decode(token string, secret string, out interface{}) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return ErrInvalidCursor
+	}
+
+	if !hmac.Equal([]byte(sign(parts[0], secret)), []byte(parts[1])) {
+		return ErrInvalidCursor
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+// Fingerprint băm các tham số của danh sách (bộ lọc, kiểu sắp xếp...) để gắn vào cursor:
+// cursor cấp cho danh sách này không dùng được cho danh sách khác.
+func Fingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+func sign(body string, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
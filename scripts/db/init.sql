@@ -1,7 +1,15 @@
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() mặc định là STABLE nên không dùng được trong generated column/index.
+-- Bọc lại thành IMMUTABLE (chỉ định rõ dictionary) để tìm kiếm tiếng Việt không dấu.
+CREATE OR REPLACE FUNCTION f_unaccent(text)
+RETURNS text AS $$
+    SELECT public.unaccent('public.unaccent', $1)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
 
 CREATE TYPE event_status AS ENUM ('DRAFT', 'PUBLISHED', 'CANCELLED', 'ENDED');
-CREATE TYPE order_status AS ENUM ('PENDING', 'PAID', 'CANCELLED', 'TIMEOUT');
+CREATE TYPE order_status AS ENUM ('PENDING', 'PAID', 'CANCELLED', 'TIMEOUT', 'REFUNDED');
 CREATE TYPE ticket_status AS ENUM ('UNUSED', 'USED');
 
 
@@ -12,12 +20,138 @@ CREATE TABLE IF NOT EXISTS users (
     password_hash text NOT NULL,
     role character varying(20) DEFAULT 'user'::character varying,
     profile_data jsonb DEFAULT '{}'::jsonb,
+    email_verified_at timestamp with time zone, -- NULL = chưa xác thực email
+    pending_email character varying(255), -- Email mới chờ xác nhận qua link, NULL = không đổi email
     created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
     updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP,
     CONSTRAINT users_pkey PRIMARY KEY (id),
     CONSTRAINT users_email_key UNIQUE (email),
     CONSTRAINT users_username_key UNIQUE (username),
-    CONSTRAINT users_role_check CHECK (((role)::text = ANY ((ARRAY['user'::character varying, 'admin'::character varying])::text[])))
+    CONSTRAINT users_role_check CHECK (((role)::text = ANY ((ARRAY['user'::character varying, 'admin'::character varying, 'box_office'::character varying, 'scanner'::character varying, 'support'::character varying])::text[])))
+);
+
+
+-- Refresh token (chỉ lưu SHA-256). Mỗi lần refresh token cũ bị thu hồi và thay bằng token mới cùng family_id;
+-- token đã thu hồi bị dùng lại thì thu hồi cả family.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    family_id UUID NOT NULL, -- Chuỗi token sinh ra từ một lần đăng nhập
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    replaced_by UUID, -- Token mới thay thế khi refresh
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Access token đã thu hồi (logout) theo jti, giữ tới khi token tự hết hạn
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti VARCHAR(64) PRIMARY KEY,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Ứng dụng xác thực TOTP (RFC 6238). enabled_at NULL = đang đăng ký dở
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL, -- Base32
+    enabled_at TIMESTAMP WITH TIME ZONE,
+    last_used_step BIGINT NOT NULL DEFAULT 0, -- Bước 30s của mã dùng gần nhất, chặn dùng lại mã
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Mã khôi phục 2FA (chỉ lưu SHA-256), mỗi mã dùng một lần
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (user_id, code_hash)
+);
+
+-- Bộ đếm đăng nhập sai theo email (kể cả email chưa đăng ký) và theo IP; vượt ngưỡng thì khóa tạm
+CREATE TABLE IF NOT EXISTS login_throttles (
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('ACCOUNT', 'IP')),
+    key VARCHAR(255) NOT NULL, -- Email đã chuẩn hóa hoặc IP
+    failures INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (kind, key)
+);
+
+-- Token dùng một lần (đặt lại mật khẩu, xác thực email, challenge đăng nhập 2 bước), chỉ lưu SHA-256
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(20) NOT NULL CHECK (purpose IN ('PASSWORD_RESET', 'VERIFY_EMAIL', 'MFA_CHALLENGE', 'EMAIL_CHANGE')),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE, -- Đã dùng hoặc bị thay bằng link mới hơn
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Vai trò cấp thêm ngoài users.role, áp dụng toàn hệ thống (scope_id NULL), cho một event hoặc một tổ chức.
+-- Organizer không phải role gốc và không cấp GLOBAL để ban tổ chức không chạm được event của tổ chức khác.
+CREATE TABLE IF NOT EXISTS role_assignments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('organizer', 'scanner', 'box_office', 'support')),
+    scope_type VARCHAR(20) NOT NULL CHECK (scope_type IN ('GLOBAL', 'EVENT', 'ORGANIZATION')),
+    scope_id UUID, -- ID của event hoặc tổ chức (không FK vì tùy scope_type)
+    granted_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((scope_type = 'GLOBAL') = (scope_id IS NULL)),
+    CHECK (role <> 'organizer' OR scope_type <> 'GLOBAL') -- Organizer chỉ cấp theo event / tổ chức
+);
+
+-- API key cho tích hợp máy-máy (chỉ lưu SHA-256), quyền là tập con quyền của chủ khóa
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE, -- Chủ khóa (organizer / admin)
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL, -- Vài ký tự đầu để nhận diện khóa
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    scopes JSONB NOT NULL DEFAULT '[]'::jsonb, -- Danh sách quyền, VD: ["box_office:sell"]
+    rate_limit_per_minute INT NOT NULL CHECK (rate_limit_per_minute > 0),
+    rate_window_start TIMESTAMP WITH TIME ZONE, -- Đầu cửa sổ 1 phút đang đếm
+    rate_window_count INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    last_used_ip VARCHAR(45),
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    rotated_from UUID REFERENCES api_keys(id) ON DELETE SET NULL, -- Khóa cũ được thay khi xoay khóa
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+
+CREATE TABLE IF NOT EXISTS venues (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    address VARCHAR(500),
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Ho_Chi_Minh',
+    latitude DECIMAL(9, 6),
+    longitude DECIMAL(9, 6),
+    capacity INT NOT NULL CHECK (capacity > 0), -- Sức chứa tổng
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+
+CREATE TABLE IF NOT EXISTS venue_sections (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    venue_id UUID NOT NULL REFERENCES venues(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL, -- Vd: Khán đài A, Sân cỏ
+    capacity INT NOT NULL CHECK (capacity > 0)
+);
+
+
+-- Tổ chức (ban tổ chức) sở hữu event. Thành viên là role_assignments với scope_type = 'ORGANIZATION'
+CREATE TABLE IF NOT EXISTS organizations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) UNIQUE NOT NULL,
+    settings jsonb NOT NULL DEFAULT '{"currency": "VND"}'::jsonb, -- Tiền tệ, phí dịch vụ, thương hiệu
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
 
@@ -26,57 +160,305 @@ CREATE TABLE IF NOT EXISTS events (
     name VARCHAR(255) NOT NULL,
     slug VARCHAR(255) UNIQUE NOT NULL,
     location VARCHAR(255),
+    venue_id UUID REFERENCES venues(id) ON DELETE RESTRICT, -- Không xóa venue khi còn event
+    organization_id UUID REFERENCES organizations(id) ON DELETE RESTRICT, -- NULL = event của nền tảng
     banner_url VARCHAR(500),
     start_time TIMESTAMP WITH TIME ZONE NOT NULL,
     end_time TIMESTAMP WITH TIME ZONE NOT NULL,
     status event_status DEFAULT 'DRAFT',
+    recurrence jsonb, -- Luật lặp đã dùng để sinh suất diễn (NULL = một suất)
+    transfers_disabled BOOLEAN NOT NULL DEFAULT FALSE, -- TRUE = cấm chuyển nhượng vé của cả event
+    resale_cap_percent INT NOT NULL DEFAULT 0 CHECK (resale_cap_percent >= 0), -- Trần giá bán lại (% giá gốc), 0 = không mở bán lại
+    attendee_questions jsonb, -- Câu hỏi người tham dự [{id, label, type, options, required}]
+    attendee_deadline TIMESTAMP WITH TIME ZONE, -- Hạn điền thông tin người tham dự (NULL = tới giờ bắt đầu)
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    -- Tìm kiếm toàn văn trên tên + địa điểm, đã bỏ dấu (VD: "my dinh" khớp "Mỹ Đình")
+    search_vector tsvector GENERATED ALWAYS AS (
+        to_tsvector('simple', f_unaccent(coalesce(name, '') || ' ' || coalesce(location, '')))
+    ) STORED,
     CONSTRAINT check_dates CHECK (end_time > start_time)
 );
 
 
+-- Suất diễn của event (show diễn nhiều tối dưới một event/slug)
+CREATE TABLE IF NOT EXISTS event_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    start_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'SCHEDULED' CHECK (status IN ('SCHEDULED', 'CANCELLED')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT check_session_dates CHECK (end_time > start_time)
+);
+
+
+-- Sức chứa dùng chung cho nhiều loại vé của cùng một event
+CREATE TABLE IF NOT EXISTS capacity_pools (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    capacity INT NOT NULL CHECK (capacity > 0),
+    remaining_quantity INT NOT NULL CHECK (remaining_quantity >= 0), -- Giống ticket_types: không bao giờ âm
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+
 CREATE TABLE IF NOT EXISTS ticket_types (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     event_id UUID REFERENCES events(id) ON DELETE CASCADE,
+    section_id UUID REFERENCES venue_sections(id), -- Khu của venue (NULL = không giới hạn theo khu)
+    pool_id UUID REFERENCES capacity_pools(id) ON DELETE SET NULL, -- Pool sức chứa dùng chung (nếu có)
+    session_id UUID REFERENCES event_sessions(id) ON DELETE CASCADE, -- Suất diễn sở hữu loại vé (nếu có)
     name VARCHAR(100) NOT NULL, -- Vd: VIP, GA, Early Bird
     price DECIMAL(10, 2) NOT NULL CHECK (price >= 0),
     initial_quantity INT NOT NULL CHECK (initial_quantity >= 0),
     remaining_quantity INT NOT NULL CHECK (remaining_quantity >= 0), -- Quan trọng: Không bao giờ được âm
+    reserved BOOLEAN NOT NULL DEFAULT FALSE, -- TRUE = vé ngồi theo số, phải chọn ghế
+    ballot BOOLEAN NOT NULL DEFAULT FALSE, -- TRUE = chỉ bán qua bốc thăm
+    visibility VARCHAR(20) NOT NULL DEFAULT 'PUBLIC' CHECK (visibility IN ('PUBLIC', 'HIDDEN', 'CODE')), -- HIDDEN/CODE = chỉ bán bằng mã presale
+    transfers_disabled BOOLEAN NOT NULL DEFAULT FALSE, -- TRUE = vé loại này không chuyển nhượng được
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
 
+-- Gói combo (VD: vé 3 ngày lễ hội): bán giá riêng, mua 1 gói trừ kho từng loại vé thành phần
+CREATE TABLE IF NOT EXISTS bundles (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    price DECIMAL(10, 2) NOT NULL CHECK (price > 0),
+    initial_quantity INT NOT NULL CHECK (initial_quantity > 0),
+    remaining_quantity INT NOT NULL CHECK (remaining_quantity >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+
+CREATE TABLE IF NOT EXISTS bundle_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    bundle_id UUID NOT NULL REFERENCES bundles(id) ON DELETE CASCADE,
+    ticket_type_id UUID NOT NULL REFERENCES ticket_types(id),
+    quantity INT NOT NULL DEFAULT 1 CHECK (quantity > 0),
+    UNIQUE (bundle_id, ticket_type_id)
+);
+
+
+-- Hàng bán kèm (vé gửi xe, áo theo size...): kho riêng, không phát hành vé
+CREATE TABLE IF NOT EXISTS add_ons (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    variant VARCHAR(50), -- VD: size S/M/L
+    price DECIMAL(10, 2) NOT NULL CHECK (price >= 0),
+    initial_quantity INT NOT NULL CHECK (initial_quantity > 0),
+    remaining_quantity INT NOT NULL CHECK (remaining_quantity >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+
+-- Add-on chỉ bán cho người mua một trong các loại vé này (không có dòng nào = ai cũng mua được)
+CREATE TABLE IF NOT EXISTS add_on_restrictions (
+    add_on_id UUID NOT NULL REFERENCES add_ons(id) ON DELETE CASCADE,
+    ticket_type_id UUID NOT NULL REFERENCES ticket_types(id) ON DELETE CASCADE,
+    PRIMARY KEY (add_on_id, ticket_type_id)
+);
+
+
+-- Vé ban tổ chức giữ lại khỏi bán công khai (nhà tài trợ, khách mời). quantity gồm cả vé mời đã phát
+CREATE TABLE IF NOT EXISTS allocations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ticket_type_id UUID NOT NULL REFERENCES ticket_types(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL CHECK (quantity >= 0),
+    issued_quantity INT NOT NULL DEFAULT 0 CHECK (issued_quantity >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (issued_quantity <= quantity)
+);
+
+
 CREATE TABLE IF NOT EXISTS orders (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     user_id UUID REFERENCES users(id),
     total_amount DECIMAL(12, 2) NOT NULL DEFAULT 0,
     status order_status DEFAULT 'PENDING',
+    payment_deadline TIMESTAMP WITH TIME ZONE, -- Hạn thanh toán riêng (đơn trúng bốc thăm), NULL = dùng ORDER_TTL
+    comp BOOLEAN NOT NULL DEFAULT FALSE, -- TRUE = vé mời, PAID không qua thanh toán
+    channel VARCHAR(20) NOT NULL DEFAULT 'ONLINE' CHECK (channel IN ('ONLINE', 'BOX_OFFICE')),
+    cashier_id UUID REFERENCES users(id), -- Nhân viên quầy bán đơn (BOX_OFFICE)
+    payment_method VARCHAR(20) CHECK (payment_method IN ('CASH', 'CARD')),
+    customer_name VARCHAR(100), -- Khách vãng lai tại quầy (không bắt buộc)
+    customer_phone VARCHAR(20),
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP, -- Dùng field này để quét đơn quá hạn (TTL)
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (user_id IS NOT NULL OR channel = 'BOX_OFFICE') -- Chỉ đơn bán tại quầy được không có tài khoản
 );
 
 
 CREATE TABLE IF NOT EXISTS order_items (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     order_id UUID REFERENCES orders(id) ON DELETE CASCADE,
-    ticket_type_id UUID REFERENCES ticket_types(id),
+    kind VARCHAR(20) NOT NULL DEFAULT 'TICKET' CHECK (kind IN ('TICKET', 'BUNDLE', 'ADDON', 'RESALE')),
+    ticket_type_id UUID REFERENCES ticket_types(id), -- NULL khi item là bundle/add-on
+    bundle_id UUID REFERENCES bundles(id),
+    add_on_id UUID REFERENCES add_ons(id),
+    session_id UUID REFERENCES event_sessions(id), -- Snapshot suất diễn của loại vé lúc mua
+    allocation_id UUID REFERENCES allocations(id), -- Vé mời lấy từ phần vé giữ lại nào
+    resale_id UUID, -- Tin bán lại đã mua (item RESALE), FK thêm sau khi tạo resale_listings
     quantity INT NOT NULL CHECK (quantity > 0),
     price DECIMAL(10, 2) NOT NULL -- Lưu giá tại thời điểm mua (Snapshot price)
 );
 
 
+-- Ghế của từng event (vé ngồi theo số). Hạng giá = ticket_type_id.
+CREATE TABLE IF NOT EXISTS seats (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    ticket_type_id UUID NOT NULL REFERENCES ticket_types(id) ON DELETE CASCADE,
+    section_id UUID REFERENCES venue_sections(id),
+    row_label VARCHAR(10) NOT NULL,
+    seat_number INT NOT NULL CHECK (seat_number > 0),
+    x DECIMAL(8, 2) NOT NULL DEFAULT 0, -- Tọa độ để vẽ sơ đồ
+    y DECIMAL(8, 2) NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'AVAILABLE' CHECK (status IN ('AVAILABLE', 'SOLD')),
+    order_item_id UUID REFERENCES order_items(id) ON DELETE SET NULL
+);
+
+
 CREATE TABLE IF NOT EXISTS tickets (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     order_id UUID REFERENCES orders(id),
+    order_item_id UUID REFERENCES order_items(id) ON DELETE CASCADE, -- Bundle: nhiều vé chung một item
     ticket_type_id UUID REFERENCES ticket_types(id),
+    seat_id UUID REFERENCES seats(id),
     ticket_code VARCHAR(50) UNIQUE NOT NULL, -- Mã QR
     status ticket_status DEFAULT 'UNUSED',
+    owner_id UUID REFERENCES users(id), -- Người đang giữ vé (đổi khi chuyển nhượng)
     owner_name VARCHAR(100), -- Tên người đi xem
+    answers jsonb, -- Câu trả lời câu hỏi người tham dự {question_id: value}
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Lịch sử chuyển nhượng vé. Khi ACCEPTED vé được cấp mã mới, old_ticket_code không còn hiệu lực.
+CREATE TABLE IF NOT EXISTS ticket_transfers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ticket_id UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    from_user_id UUID NOT NULL REFERENCES users(id),
+    to_email VARCHAR(255) NOT NULL, -- Chữ thường
+    to_user_id UUID REFERENCES users(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'ACCEPTED', 'CANCELLED')),
+    old_ticket_code VARCHAR(50),
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Sàn bán lại vé. RESERVED = người mua đã đặt, chờ thanh toán (đơn bị hủy thì tin về lại ACTIVE);
+-- SOLD = đơn người mua đã thanh toán, vé đã sang tên người mua (mã mới) và người bán được chi tiền.
+CREATE TABLE IF NOT EXISTS resale_listings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ticket_id UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    seller_id UUID NOT NULL REFERENCES users(id),
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    ticket_type_id UUID NOT NULL REFERENCES ticket_types(id) ON DELETE CASCADE,
+    seat_id UUID REFERENCES seats(id),
+    price DECIMAL(10, 2) NOT NULL CHECK (price > 0),
+    original_price DECIMAL(10, 2) NOT NULL, -- Giá gốc của vé (order_items.price) để đối chiếu trần
+    fee DECIMAL(10, 2) NOT NULL DEFAULT 0, -- Phí sàn
+    seller_payout DECIMAL(10, 2) NOT NULL DEFAULT 0, -- Tiền hoàn cho người bán = price - fee
+    status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('ACTIVE', 'RESERVED', 'SOLD', 'CANCELLED')),
+    buyer_id UUID REFERENCES users(id),
+    order_id UUID REFERENCES orders(id), -- Đơn của người mua
+    sold_at TIMESTAMP WITH TIME ZONE,
+    paid_out_at TIMESTAMP WITH TIME ZONE, -- Đã chi tiền hoàn cho người bán
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE order_items ADD CONSTRAINT fk_order_items_resale FOREIGN KEY (resale_id) REFERENCES resale_listings(id);
+
+-- Hàng chờ loại vé đã hết. Vé trả lại được giữ riêng cho người đầu hàng (FIFO) tới offer_expires_at.
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ticket_type_id UUID NOT NULL REFERENCES ticket_types(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'WAITING' CHECK (status IN ('WAITING', 'OFFERED', 'CLAIMED', 'EXPIRED', 'CANCELLED')),
+    offer_expires_at TIMESTAMP WITH TIME ZONE,
+    order_id UUID REFERENCES orders(id),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Bán vé bốc thăm: seed sinh lúc tạo, công bố seed_hash trước, lộ seed sau khi bốc để kiểm chứng
+CREATE TABLE IF NOT EXISTS ballots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ticket_type_id UUID NOT NULL UNIQUE REFERENCES ticket_types(id) ON DELETE CASCADE,
+    entry_opens_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    entry_closes_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    max_per_entry INT NOT NULL CHECK (max_per_entry > 0),
+    payment_window_minutes INT NOT NULL CHECK (payment_window_minutes > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'DRAWN')),
+    seed VARCHAR(64) NOT NULL,
+    seed_hash VARCHAR(64) NOT NULL,
+    drawn_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (entry_closes_at > entry_opens_at)
+);
+
+
+CREATE TABLE IF NOT EXISTS ballot_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ballot_id UUID NOT NULL REFERENCES ballots(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'ENTERED' CHECK (status IN ('ENTERED', 'WON', 'NOT_SELECTED', 'UNPAID')),
+    draw_rank VARCHAR(64), -- sha256(seed:id)
+    position INT,
+    order_id UUID REFERENCES orders(id),
+    payment_deadline TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (ballot_id, user_id) -- Mỗi user một lượt
+);
+
+-- Mã presale: mở khóa loại vé HIDDEN/CODE, mỗi user dùng một lần, max_uses = 0 là không giới hạn
+CREATE TABLE IF NOT EXISTS presale_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    event_id UUID NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    code VARCHAR(50) NOT NULL, -- Lưu in hoa
+    name VARCHAR(255),
+    max_uses INT NOT NULL DEFAULT 0 CHECK (max_uses >= 0),
+    used_count INT NOT NULL DEFAULT 0 CHECK (used_count >= 0),
+    starts_at TIMESTAMP WITH TIME ZONE,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (event_id, code),
+    CHECK (max_uses = 0 OR used_count <= max_uses)
+);
+
+CREATE TABLE IF NOT EXISTS presale_code_ticket_types (
+    presale_code_id UUID NOT NULL REFERENCES presale_codes(id) ON DELETE CASCADE,
+    ticket_type_id UUID NOT NULL REFERENCES ticket_types(id) ON DELETE CASCADE,
+    PRIMARY KEY (presale_code_id, ticket_type_id)
+);
+
+CREATE TABLE IF NOT EXISTS presale_redemptions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    presale_code_id UUID NOT NULL REFERENCES presale_codes(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (presale_code_id, user_id) -- Mỗi user dùng mã một lần
+);
+
 CREATE OR REPLACE FUNCTION update_updated_at_column()
 RETURNS TRIGGER AS $$
 BEGIN
@@ -86,16 +468,75 @@ END;
 $$ language 'plpgsql';
 
 
+CREATE TRIGGER update_venues_modtime BEFORE UPDATE ON venues FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
 CREATE TRIGGER update_users_modtime BEFORE UPDATE ON users FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
 CREATE TRIGGER update_events_modtime BEFORE UPDATE ON events FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
 CREATE TRIGGER update_ticket_types_modtime BEFORE UPDATE ON ticket_types FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
+CREATE TRIGGER update_event_sessions_modtime BEFORE UPDATE ON event_sessions FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
+CREATE TRIGGER update_capacity_pools_modtime BEFORE UPDATE ON capacity_pools FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
+CREATE TRIGGER update_bundles_modtime BEFORE UPDATE ON bundles FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
+CREATE TRIGGER update_tickets_modtime BEFORE UPDATE ON tickets FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
+CREATE TRIGGER update_add_ons_modtime BEFORE UPDATE ON add_ons FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
+CREATE TRIGGER update_waitlist_entries_modtime BEFORE UPDATE ON waitlist_entries FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
+CREATE TRIGGER update_ballots_modtime BEFORE UPDATE ON ballots FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
+CREATE TRIGGER update_ballot_entries_modtime BEFORE UPDATE ON ballot_entries FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
+CREATE TRIGGER update_allocations_modtime BEFORE UPDATE ON allocations FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
+CREATE TRIGGER update_ticket_transfers_modtime BEFORE UPDATE ON ticket_transfers FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
+CREATE TRIGGER update_resale_listings_modtime BEFORE UPDATE ON resale_listings FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
 CREATE TRIGGER update_orders_modtime BEFORE UPDATE ON orders FOR EACH ROW EXECUTE PROCEDURE update_updated_at_column();
 
 
 CREATE INDEX idx_events_slug ON events(slug);
+CREATE INDEX idx_events_start_time ON events(start_time, id);
+CREATE INDEX idx_events_search_vector ON events USING GIN (search_vector);
 CREATE INDEX idx_orders_user_id ON orders(user_id);
+CREATE INDEX idx_orders_user_created ON orders(user_id, created_at DESC, id DESC); -- Keyset pagination lịch sử đơn
 CREATE INDEX idx_orders_status ON orders(status);
 CREATE INDEX idx_ticket_types_event_id ON ticket_types(event_id);
+CREATE INDEX idx_events_venue_id ON events(venue_id);
+CREATE INDEX idx_events_organization_id ON events(organization_id);
+CREATE INDEX idx_venue_sections_venue_id ON venue_sections(venue_id);
+CREATE INDEX idx_event_sessions_event_start ON event_sessions(event_id, start_time);
+CREATE INDEX idx_event_sessions_start_time ON event_sessions(start_time);
+CREATE INDEX idx_ticket_types_session_id ON ticket_types(session_id);
+CREATE INDEX idx_capacity_pools_event_id ON capacity_pools(event_id);
+CREATE INDEX idx_bundle_items_ticket_type_id ON bundle_items(ticket_type_id);
+CREATE INDEX idx_add_ons_event_id ON add_ons(event_id);
+CREATE INDEX idx_order_items_add_on_id ON order_items(add_on_id);
+CREATE INDEX idx_orders_pending_created ON orders(created_at) WHERE status = 'PENDING'; -- Quét đơn quá hạn
+CREATE INDEX idx_orders_pending_deadline ON orders(payment_deadline) WHERE status = 'PENDING' AND payment_deadline IS NOT NULL;
+CREATE INDEX idx_ballot_entries_position ON ballot_entries(ballot_id, status, position);
+-- Mỗi user chỉ có một lượt chờ còn hiệu lực cho một loại vé
+CREATE UNIQUE INDEX idx_waitlist_active ON waitlist_entries(user_id, ticket_type_id) WHERE status IN ('WAITING', 'OFFERED');
+CREATE INDEX idx_waitlist_fifo ON waitlist_entries(ticket_type_id, created_at, id) WHERE status = 'WAITING';
+CREATE INDEX idx_waitlist_offer_expiry ON waitlist_entries(offer_expires_at) WHERE status = 'OFFERED';
+CREATE INDEX idx_tickets_order_id ON tickets(order_id);
+CREATE INDEX idx_orders_cashier_created ON orders(cashier_id, created_at) WHERE channel = 'BOX_OFFICE'; -- Đối soát cuối ca
+CREATE INDEX idx_allocations_ticket_type_id ON allocations(ticket_type_id);
+CREATE INDEX idx_tickets_owner_id ON tickets(owner_id);
+-- Mỗi vé chỉ có một lượt chuyển đang chờ
+CREATE UNIQUE INDEX idx_ticket_transfers_pending ON ticket_transfers(ticket_id) WHERE status = 'PENDING';
+-- Không cấp trùng một vai trò trong cùng phạm vi
+CREATE INDEX idx_role_assignments_scope ON role_assignments(scope_type, scope_id);
+CREATE UNIQUE INDEX idx_role_assignments_unique ON role_assignments(user_id, role, scope_type, COALESCE(scope_id, '00000000-0000-0000-0000-000000000000'));
+CREATE INDEX idx_ticket_transfers_from_user ON ticket_transfers(from_user_id, created_at);
+CREATE INDEX idx_ticket_transfers_to_email ON ticket_transfers(to_email, created_at);
+CREATE UNIQUE INDEX idx_resale_listings_active ON resale_listings(ticket_id) WHERE status IN ('ACTIVE', 'RESERVED'); -- Mỗi vé một tin đang rao / chờ thanh toán
+CREATE INDEX idx_resale_listings_payout ON resale_listings(sold_at) WHERE status = 'SOLD' AND paid_out_at IS NULL; -- Tiền hoàn chưa chi
+CREATE INDEX idx_resale_listings_event ON resale_listings(event_id, price) WHERE status = 'ACTIVE';
+CREATE INDEX idx_resale_listings_seller ON resale_listings(seller_id, created_at);
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+CREATE INDEX idx_refresh_tokens_expires_at ON refresh_tokens(expires_at);
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+CREATE INDEX idx_user_tokens_user_purpose ON user_tokens(user_id, purpose) WHERE used_at IS NULL;
+CREATE INDEX idx_user_tokens_expires_at ON user_tokens(expires_at);
+CREATE INDEX idx_api_keys_user_id ON api_keys(user_id, created_at);
+CREATE INDEX idx_login_throttles_locked_until ON login_throttles(locked_until) WHERE locked_until IS NOT NULL;
+CREATE INDEX idx_presale_redemptions_order_id ON presale_redemptions(order_id);
+CREATE INDEX idx_seats_event_id ON seats(event_id);
+CREATE INDEX idx_seats_ticket_type_id ON seats(ticket_type_id);
+-- Một vị trí ghế chỉ xuất hiện một lần trong sơ đồ của event (section NULL coi như một khu)
+CREATE UNIQUE INDEX idx_seats_position ON seats(event_id, COALESCE(section_id, '00000000-0000-0000-0000-000000000000'::uuid), row_label, seat_number);
 
 INSERT INTO users (username, email, password_hash, role) 
 VALUES ('admin', 'admin@example.com', '$2a$10$WGkl8JLxQSRPXfnM8qxQi.XAJ4kX4p7N5nN5nN5nN5nN5nN5nN5nK', 'admin');
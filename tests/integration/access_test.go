@@ -0,0 +1,452 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/adapter/handler"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+type mockRoleRepository struct {
+	assignments map[uuid.UUID]entity.RoleAssignment
+	events      map[uuid.UUID]bool
+	eventOrgs   map[uuid.UUID]uuid.UUID // event -> tổ chức sở hữu
+	orgs        map[uuid.UUID]bool
+	ticketTypes map[uuid.UUID]uuid.UUID   // ticket_type -> event
+	addOns      map[uuid.UUID]uuid.UUID   // add-on -> event
+	ballots     map[uuid.UUID]uuid.UUID   // ballot -> event
+	bundles     map[uuid.UUID][]uuid.UUID // bundle -> các event
+	orders      map[uuid.UUID][]uuid.UUID // order -> các event
+}
+
+func newMockRoleRepository() *mockRoleRepository {
+	return &mockRoleRepository{
+		assignments: map[uuid.UUID]entity.RoleAssignment{},
+		events:      map[uuid.UUID]bool{},
+		eventOrgs:   map[uuid.UUID]uuid.UUID{},
+		orgs:        map[uuid.UUID]bool{},
+		ticketTypes: map[uuid.UUID]uuid.UUID{},
+		addOns:      map[uuid.UUID]uuid.UUID{},
+		ballots:     map[uuid.UUID]uuid.UUID{},
+		bundles:     map[uuid.UUID][]uuid.UUID{},
+		orders:      map[uuid.UUID][]uuid.UUID{},
+	}
+}
+
+func (m *mockRoleRepository) CreateAssignment(ctx context.Context, assignment *entity.RoleAssignment) error {
+	for _, a := range m.assignments {
+		if a.UserID == assignment.UserID && a.Role == assignment.Role && a.ScopeType == assignment.ScopeType &&
+			(a.ScopeID == nil) == (assignment.ScopeID == nil) && (a.ScopeID == nil || *a.ScopeID == *assignment.ScopeID) {
+			return errors.New("duplicate assignment")
+		}
+	}
+	m.assignments[assignment.ID] = *assignment
+	return nil
+}
+
+func (m *mockRoleRepository) DeleteAssignment(ctx context.Context, id uuid.UUID) (bool, error) {
+	if _, ok := m.assignments[id]; !ok {
+		return false, nil
+	}
+	delete(m.assignments, id)
+	return true, nil
+}
+
+func (m *mockRoleRepository) ListAssignmentsByUser(ctx context.Context, userID uuid.UUID) ([]entity.RoleAssignment, error) {
+	result := []entity.RoleAssignment{}
+	for _, a := range m.assignments {
+		if a.UserID == userID {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockRoleRepository) ListScopeAssignments(ctx context.Context, scopeType entity.ScopeType, scopeID uuid.UUID) ([]entity.RoleAssignment, error) {
+	result := []entity.RoleAssignment{}
+	for _, a := range m.assignments {
+		if a.ScopeType == scopeType && a.ScopeID != nil && *a.ScopeID == scopeID {
+			result = append(result, a)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Role < result[j].Role })
+	return result, nil
+}
+
+func (m *mockRoleRepository) DeleteScopeAssignments(ctx context.Context, userID uuid.UUID, scopeType entity.ScopeType, scopeID uuid.UUID, role string) (int64, error) {
+	var deleted int64
+	for id, a := range m.assignments {
+		if a.UserID == userID && a.ScopeType == scopeType && a.ScopeID != nil && *a.ScopeID == scopeID && (role == "" || a.Role == role) {
+			delete(m.assignments, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func (m *mockRoleRepository) OrganizationExists(ctx context.Context, orgID uuid.UUID) (bool, error) {
+	return m.orgs[orgID], nil
+}
+
+func (m *mockRoleRepository) OrganizationIDForEvent(ctx context.Context, eventID uuid.UUID) (*uuid.UUID, error) {
+	orgID, ok := m.eventOrgs[eventID]
+	if !ok {
+		return nil, nil
+	}
+	return &orgID, nil
+}
+
+func (m *mockRoleRepository) EventExists(ctx context.Context, eventID uuid.UUID) (bool, error) {
+	return m.events[eventID], nil
+}
+
+func (m *mockRoleRepository) EventIDForTicketType(ctx context.Context, ticketTypeID uuid.UUID) (uuid.UUID, error) {
+	eventID, ok := m.ticketTypes[ticketTypeID]
+	if !ok {
+		return uuid.Nil, sql.ErrNoRows
+	}
+	return eventID, nil
+}
+
+func (m *mockRoleRepository) EventIDForAllocation(ctx context.Context, allocationID uuid.UUID) (uuid.UUID, error) {
+	return uuid.Nil, sql.ErrNoRows
+}
+
+func (m *mockRoleRepository) EventIDForAddOn(ctx context.Context, addOnID uuid.UUID) (uuid.UUID, error) {
+	eventID, ok := m.addOns[addOnID]
+	if !ok {
+		return uuid.Nil, sql.ErrNoRows
+	}
+	return eventID, nil
+}
+
+func (m *mockRoleRepository) EventIDForBallot(ctx context.Context, ballotID uuid.UUID) (uuid.UUID, error) {
+	eventID, ok := m.ballots[ballotID]
+	if !ok {
+		return uuid.Nil, sql.ErrNoRows
+	}
+	return eventID, nil
+}
+
+func (m *mockRoleRepository) EventIDsForBundle(ctx context.Context, bundleID uuid.UUID) ([]uuid.UUID, error) {
+	return m.bundles[bundleID], nil
+}
+
+func (m *mockRoleRepository) EventIDsForOrder(ctx context.Context, orderID uuid.UUID) ([]uuid.UUID, error) {
+	return m.orders[orderID], nil
+}
+
+func newTestAccessService(t *testing.T) (port.AccessServicePort, *mockRoleRepository, *mockUserRepository) {
+	t.Helper()
+	roles := newMockRoleRepository()
+	users := &mockUserRepository{users: map[uuid.UUID]*entity.User{}}
+	return service.NewAccessService(roles, users), roles, users
+}
+
+func addTestUser(users *mockUserRepository, role string) *entity.User {
+	user := &entity.User{ID: uuid.New(), Username: "u-" + uuid.NewString()[:8], Email: uuid.NewString() + "@example.com", Role: role}
+	users.users[user.ID] = user
+	return user
+}
+
+func TestAccess_BaseRolePermissions(t *testing.T) {
+	svc, _, users := newTestAccessService(t)
+	ctx := context.Background()
+	eventScope := entity.PermissionScope{Type: entity.ScopeEvent, ID: uuid.New()}
+
+	cases := []struct {
+		role    string
+		perm    entity.Permission
+		allowed bool
+	}{
+		{entity.RoleAdmin, entity.PermVenuesWrite, true},
+		{entity.RoleOrganizer, entity.PermEventsWrite, false}, // organizer chỉ có quyền qua vai trò cấp theo event / tổ chức
+		{entity.RoleOrganizer, entity.PermVenuesWrite, false},
+		{entity.RoleScanner, entity.PermTicketsScan, true},
+		{entity.RoleScanner, entity.PermEventsWrite, false},
+		{entity.RoleBoxOffice, entity.PermBoxOfficeSell, true},
+		{entity.RoleSupport, entity.PermOrdersRefund, true},
+		{entity.RoleSupport, entity.PermEventsWrite, false},
+		{entity.RoleUser, entity.PermOrdersRead, false},
+	}
+	for _, tc := range cases {
+		user := addTestUser(users, tc.role)
+		allowed, err := svc.HasPermission(ctx, user.ID, tc.role, tc.perm, eventScope)
+		if err != nil {
+			t.Fatalf("HasPermission failed: %v", err)
+		}
+		if allowed != tc.allowed {
+			t.Errorf("%s / %s: expected %v, got %v", tc.role, tc.perm, tc.allowed, allowed)
+		}
+	}
+}
+
+func TestAccess_EventScopedRole(t *testing.T) {
+	svc, roles, users := newTestAccessService(t)
+	ctx := context.Background()
+	admin := addTestUser(users, entity.RoleAdmin)
+	user := addTestUser(users, entity.RoleUser)
+	eventA, eventB := uuid.New(), uuid.New()
+	roles.events[eventA] = true
+	roles.events[eventB] = true
+
+	assignment, err := svc.GrantRole(ctx, admin.ID, entity.GrantRoleRequest{
+		UserID: user.ID, Role: entity.RoleOrganizer, ScopeType: entity.ScopeEvent, ScopeID: &eventA,
+	})
+	if err != nil {
+		t.Fatalf("GrantRole failed: %v", err)
+	}
+	if assignment.GrantedBy == nil || *assignment.GrantedBy != admin.ID {
+		t.Errorf("Expected granted_by to be recorded")
+	}
+
+	// Organizer của event A chỉ sửa được event A
+	scopeA := entity.PermissionScope{Type: entity.ScopeEvent, ID: eventA}
+	scopeB := entity.PermissionScope{Type: entity.ScopeEvent, ID: eventB}
+	if ok, _ := svc.HasPermission(ctx, user.ID, user.Role, entity.PermEventsWrite, scopeA); !ok {
+		t.Error("Expected events:write on own event")
+	}
+	if ok, _ := svc.HasPermission(ctx, user.ID, user.Role, entity.PermEventsWrite, scopeB); ok {
+		t.Error("Expected no events:write on another event")
+	}
+	if ok, _ := svc.HasPermission(ctx, user.ID, user.Role, entity.PermEventsWrite, entity.PermissionScope{Type: entity.ScopeGlobal}); ok {
+		t.Error("Expected event-scoped role not to grant global permission")
+	}
+
+	// Vai trò GLOBAL áp dụng cho mọi event
+	if _, err := svc.GrantRole(ctx, admin.ID, entity.GrantRoleRequest{
+		UserID: user.ID, Role: entity.RoleScanner, ScopeType: entity.ScopeGlobal,
+	}); err != nil {
+		t.Fatalf("GrantRole global failed: %v", err)
+	}
+	if ok, _ := svc.HasPermission(ctx, user.ID, user.Role, entity.PermTicketsScan, scopeB); !ok {
+		t.Error("Expected global scanner to scan any event")
+	}
+
+	// Thu hồi thì mất quyền
+	if err := svc.RevokeRole(ctx, assignment.ID); err != nil {
+		t.Fatalf("RevokeRole failed: %v", err)
+	}
+	if ok, _ := svc.HasPermission(ctx, user.ID, user.Role, entity.PermEventsWrite, scopeA); ok {
+		t.Error("Expected permission gone after revoke")
+	}
+	if err := svc.RevokeRole(ctx, assignment.ID); err == nil {
+		t.Error("Expected error revoking twice")
+	}
+
+	userRoles, err := svc.ListUserRoles(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("ListUserRoles failed: %v", err)
+	}
+	if userRoles.Role != entity.RoleUser || len(userRoles.Assignments) != 1 || userRoles.Assignments[0].Role != entity.RoleScanner {
+		t.Errorf("Unexpected roles: %+v", userRoles)
+	}
+}
+
+func TestAccess_GrantRoleValidation(t *testing.T) {
+	svc, roles, users := newTestAccessService(t)
+	ctx := context.Background()
+	admin := addTestUser(users, entity.RoleAdmin)
+	user := addTestUser(users, entity.RoleUser)
+	eventID := uuid.New()
+	roles.events[eventID] = true
+	missingEvent := uuid.New()
+
+	cases := map[string]entity.GrantRoleRequest{
+		"admin role":       {UserID: user.ID, Role: entity.RoleAdmin, ScopeType: entity.ScopeGlobal},
+		"user role":        {UserID: user.ID, Role: entity.RoleUser, ScopeType: entity.ScopeGlobal},
+		"unknown role":     {UserID: user.ID, Role: "owner", ScopeType: entity.ScopeGlobal},
+		"unknown scope":    {UserID: user.ID, Role: entity.RoleScanner, ScopeType: "VENUE", ScopeID: &eventID},
+		"missing scope_id": {UserID: user.ID, Role: entity.RoleScanner, ScopeType: entity.ScopeEvent},
+		"missing event":    {UserID: user.ID, Role: entity.RoleScanner, ScopeType: entity.ScopeEvent, ScopeID: &missingEvent},
+		"missing user":     {UserID: uuid.New(), Role: entity.RoleScanner, ScopeType: entity.ScopeGlobal},
+		"global organizer": {UserID: user.ID, Role: entity.RoleOrganizer, ScopeType: entity.ScopeGlobal},
+	}
+	for name, req := range cases {
+		if _, err := svc.GrantRole(ctx, admin.ID, req); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	// GLOBAL bỏ qua scope_id gửi kèm
+	assignment, err := svc.GrantRole(ctx, admin.ID, entity.GrantRoleRequest{
+		UserID: user.ID, Role: entity.RoleSupport, ScopeType: entity.ScopeGlobal, ScopeID: &eventID,
+	})
+	if err != nil {
+		t.Fatalf("GrantRole failed: %v", err)
+	}
+	if assignment.ScopeID != nil {
+		t.Error("Expected scope_id cleared for GLOBAL scope")
+	}
+	if len(roles.assignments) != 1 {
+		t.Errorf("Expected only the valid grant stored, got %d", len(roles.assignments))
+	}
+}
+
+// permissionApp dựng app Fiber với user đăng nhập sẵn (role gốc role) và route đi qua guard
+func permissionApp(user *entity.User, method, path string, guard fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("user_id", user.ID.String())
+		c.Locals("role", user.Role)
+		return c.Next()
+	})
+	app.Add(method, path, guard, func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	return app
+}
+
+func doJSON(t *testing.T, app *fiber.App, method, path, body string) int {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	return resp.StatusCode
+}
+
+func TestAccess_BoxOfficeSaleScopedToEvents(t *testing.T) {
+	svc, roles, users := newTestAccessService(t)
+	ctx := context.Background()
+	admin := addTestUser(users, entity.RoleAdmin)
+	cashier := addTestUser(users, entity.RoleUser)
+
+	eventA, eventB := uuid.New(), uuid.New()
+	roles.events[eventA] = true
+	roles.events[eventB] = true
+	typeA, typeB, addOnA, bundleAB := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	roles.ticketTypes[typeA] = eventA
+	roles.ticketTypes[typeB] = eventB
+	roles.addOns[addOnA] = eventA
+	roles.bundles[bundleAB] = []uuid.UUID{eventA, eventB}
+
+	if _, err := svc.GrantRole(ctx, admin.ID, entity.GrantRoleRequest{
+		UserID: cashier.ID, Role: entity.RoleBoxOffice, ScopeType: entity.ScopeEvent, ScopeID: &eventA,
+	}); err != nil {
+		t.Fatalf("GrantRole failed: %v", err)
+	}
+
+	guard := handler.RequirePermissionAll(svc, entity.PermBoxOfficeSell, handler.SaleEventScopes(svc))
+	app := permissionApp(cashier, fiber.MethodPost, "/box-office/orders", guard)
+
+	cases := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"own event", fmt.Sprintf(`{"items":[{"ticket_type_id":%q,"quantity":1}]}`, typeA), fiber.StatusOK},
+		{"own event add-on", fmt.Sprintf(`{"items":[{"ticket_type_id":%q,"quantity":1},{"add_on_id":%q,"quantity":1}]}`, typeA, addOnA), fiber.StatusOK},
+		{"other event", fmt.Sprintf(`{"items":[{"ticket_type_id":%q,"quantity":1}]}`, typeB), fiber.StatusForbidden},
+		{"mixed events", fmt.Sprintf(`{"items":[{"ticket_type_id":%q,"quantity":1},{"ticket_type_id":%q,"quantity":1}]}`, typeA, typeB), fiber.StatusForbidden},
+		{"bundle across events", fmt.Sprintf(`{"items":[{"bundle_id":%q,"quantity":1}]}`, bundleAB), fiber.StatusForbidden},
+		{"unknown ticket type", fmt.Sprintf(`{"items":[{"ticket_type_id":%q,"quantity":1}]}`, uuid.New()), fiber.StatusForbidden},
+		{"no items", `{"items":[]}`, fiber.StatusForbidden},
+	}
+	for _, tc := range cases {
+		if status := doJSON(t, app, fiber.MethodPost, "/box-office/orders", tc.body); status != tc.status {
+			t.Errorf("%s: expected %d, got %d", tc.name, tc.status, status)
+		}
+	}
+
+	// Role gốc box_office bán được mọi event
+	global := addTestUser(users, entity.RoleBoxOffice)
+	app = permissionApp(global, fiber.MethodPost, "/box-office/orders", guard)
+	if status := doJSON(t, app, fiber.MethodPost, "/box-office/orders", fmt.Sprintf(`{"items":[{"bundle_id":%q,"quantity":1}]}`, bundleAB)); status != fiber.StatusOK {
+		t.Errorf("Expected global box office to sell bundle, got %d", status)
+	}
+}
+
+func TestAccess_RefundScopedToOrderEvents(t *testing.T) {
+	svc, roles, users := newTestAccessService(t)
+	ctx := context.Background()
+	admin := addTestUser(users, entity.RoleAdmin)
+	agent := addTestUser(users, entity.RoleUser)
+
+	eventA, eventB := uuid.New(), uuid.New()
+	roles.events[eventA] = true
+	orderA, orderAB := uuid.New(), uuid.New()
+	roles.orders[orderA] = []uuid.UUID{eventA}
+	roles.orders[orderAB] = []uuid.UUID{eventA, eventB}
+
+	if _, err := svc.GrantRole(ctx, admin.ID, entity.GrantRoleRequest{
+		UserID: agent.ID, Role: entity.RoleSupport, ScopeType: entity.ScopeEvent, ScopeID: &eventA,
+	}); err != nil {
+		t.Fatalf("GrantRole failed: %v", err)
+	}
+
+	guard := handler.RequirePermissionAll(svc, entity.PermOrdersRefund, handler.OrderEventScopes(svc, "id"))
+	app := permissionApp(agent, fiber.MethodPost, "/orders/:id/refund", guard)
+
+	if status := doJSON(t, app, fiber.MethodPost, "/orders/"+orderA.String()+"/refund", ""); status != fiber.StatusOK {
+		t.Errorf("Expected refund allowed on own event order, got %d", status)
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/orders/"+orderAB.String()+"/refund", ""); status != fiber.StatusForbidden {
+		t.Errorf("Expected 403 when order spans another event, got %d", status)
+	}
+	if status := doJSON(t, app, fiber.MethodPost, "/orders/"+uuid.NewString()+"/refund", ""); status != fiber.StatusForbidden {
+		t.Errorf("Expected 403 for unknown order, got %d", status)
+	}
+}
+
+func TestAccess_OrgOrganizerBundlesAndBallotsScopedToOwnEvents(t *testing.T) {
+	svc, roles, users := newTestAccessService(t)
+	organizer := addTestUser(users, entity.RoleUser)
+
+	orgA, orgB := uuid.New(), uuid.New()
+	eventA, eventB := uuid.New(), uuid.New()
+	roles.events[eventA] = true
+	roles.events[eventB] = true
+	roles.eventOrgs[eventA] = orgA
+	roles.eventOrgs[eventB] = orgB
+	typeA1, typeA2, typeB := uuid.New(), uuid.New(), uuid.New()
+	roles.ticketTypes[typeA1] = eventA
+	roles.ticketTypes[typeA2] = eventA
+	roles.ticketTypes[typeB] = eventB
+	ballotA, ballotB := uuid.New(), uuid.New()
+	roles.ballots[ballotA] = eventA
+	roles.ballots[ballotB] = eventB
+	roles.assignments[uuid.New()] = entity.RoleAssignment{UserID: organizer.ID, Role: entity.RoleOrganizer, ScopeType: entity.ScopeOrganization, ScopeID: &orgA}
+
+	bundleApp := permissionApp(organizer, fiber.MethodPost, "/bundles", handler.RequirePermissionAll(svc, entity.PermEventsWrite, handler.NewBundleEventScopes(svc)))
+	bundleBody := func(types ...uuid.UUID) string {
+		items := make([]string, len(types))
+		for i, id := range types {
+			items[i] = fmt.Sprintf(`{"ticket_type_id":%q,"quantity":1}`, id)
+		}
+		return `{"name":"Combo","items":[` + strings.Join(items, ",") + `]}`
+	}
+	if status := doJSON(t, bundleApp, fiber.MethodPost, "/bundles", bundleBody(typeA1, typeA2)); status != fiber.StatusOK {
+		t.Errorf("Expected organizer to create bundle for own org event, got %d", status)
+	}
+	if status := doJSON(t, bundleApp, fiber.MethodPost, "/bundles", bundleBody(typeA1, typeB)); status != fiber.StatusForbidden {
+		t.Errorf("Expected 403 for bundle including another org's event, got %d", status)
+	}
+
+	ballotApp := permissionApp(organizer, fiber.MethodPost, "/ballots", handler.RequirePermission(svc, entity.PermEventsWrite, handler.NewBallotEventScope(svc)))
+	if status := doJSON(t, ballotApp, fiber.MethodPost, "/ballots", fmt.Sprintf(`{"ticket_type_id":%q}`, typeA1)); status != fiber.StatusOK {
+		t.Errorf("Expected organizer to open ballot for own org event, got %d", status)
+	}
+	if status := doJSON(t, ballotApp, fiber.MethodPost, "/ballots", fmt.Sprintf(`{"ticket_type_id":%q}`, typeB)); status != fiber.StatusForbidden {
+		t.Errorf("Expected 403 for ballot on another org's event, got %d", status)
+	}
+
+	drawApp := permissionApp(organizer, fiber.MethodPost, "/ballots/:id/draw", handler.RequirePermission(svc, entity.PermEventsWrite, handler.BallotEventScope(svc, "id")))
+	if status := doJSON(t, drawApp, fiber.MethodPost, "/ballots/"+ballotA.String()+"/draw", ""); status != fiber.StatusOK {
+		t.Errorf("Expected organizer to draw own org ballot, got %d", status)
+	}
+	if status := doJSON(t, drawApp, fiber.MethodPost, "/ballots/"+ballotB.String()+"/draw", ""); status != fiber.StatusForbidden {
+		t.Errorf("Expected 403 drawing another org's ballot, got %d", status)
+	}
+}
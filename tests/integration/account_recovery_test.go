@@ -0,0 +1,162 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yourname/ticketing-system/internal/adapter/mailer"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/pkg/auth"
+)
+
+func TestRegister_SendsVerificationEmail(t *testing.T) {
+	svc, _, mails, _ := newTestAuthService(t)
+	ctx := context.Background()
+
+	user, err := svc.Register(ctx, entity.RegisterRequest{Username: "bob", Email: "bob@example.com", Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if verified, _ := svc.IsEmailVerified(ctx, user.ID); verified {
+		t.Fatal("Expected new user to be unverified")
+	}
+
+	token := mails.lastToken(t, "bob@example.com")
+	if !strings.Contains(mails.sent[len(mails.sent)-1].Body, "https://app.example.com/verify-email?token=") {
+		t.Errorf("Expected verification link in email, got %q", mails.sent[len(mails.sent)-1].Body)
+	}
+	if err := svc.VerifyEmail(ctx, token); err != nil {
+		t.Fatalf("VerifyEmail failed: %v", err)
+	}
+	if verified, _ := svc.IsEmailVerified(ctx, user.ID); !verified {
+		t.Error("Expected email verified")
+	}
+
+	// Token dùng một lần
+	if err := svc.VerifyEmail(ctx, token); err == nil {
+		t.Error("Expected verification token to be single-use")
+	}
+	if err := svc.ResendVerification(ctx, user.ID); err == nil {
+		t.Error("Expected resend to fail for verified email")
+	}
+}
+
+func TestResendVerification_InvalidatesPreviousLink(t *testing.T) {
+	svc, _, mails, user := newTestAuthService(t)
+	ctx := context.Background()
+
+	if err := svc.ResendVerification(ctx, user.ID); err != nil {
+		t.Fatalf("ResendVerification failed: %v", err)
+	}
+	first := mails.lastToken(t, user.Email)
+	if err := svc.ResendVerification(ctx, user.ID); err != nil {
+		t.Fatalf("ResendVerification failed: %v", err)
+	}
+	second := mails.lastToken(t, user.Email)
+
+	if err := svc.VerifyEmail(ctx, first); err == nil {
+		t.Error("Expected older verification link to be invalidated")
+	}
+	if err := svc.VerifyEmail(ctx, second); err != nil {
+		t.Errorf("Expected latest link to verify, got %v", err)
+	}
+}
+
+func TestForgotPassword_UnknownEmailSendsNothing(t *testing.T) {
+	svc, _, mails, _ := newTestAuthService(t)
+
+	if err := svc.ForgotPassword(context.Background(), "nobody@example.com"); err != nil {
+		t.Fatalf("Expected no error for unknown email, got %v", err)
+	}
+	if len(mails.sent) != 0 {
+		t.Errorf("Expected no email sent, got %d", len(mails.sent))
+	}
+}
+
+func TestResetPassword_ChangesPasswordAndRevokesSessions(t *testing.T) {
+	svc, _, mails, user := newTestAuthService(t)
+	ctx := context.Background()
+
+	session, err := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	if err := svc.ForgotPassword(ctx, user.Email); err != nil {
+		t.Fatalf("ForgotPassword failed: %v", err)
+	}
+	token := mails.lastToken(t, user.Email)
+
+	if err := svc.ResetPassword(ctx, entity.ResetPasswordRequest{Token: token, NewPassword: "123"}); err == nil {
+		t.Fatal("Expected error for too short password")
+	}
+	if err := svc.ResetPassword(ctx, entity.ResetPasswordRequest{Token: token, NewPassword: "new-secret"}); err != nil {
+		t.Fatalf("ResetPassword failed: %v", err)
+	}
+
+	if _, err := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "secret123"}); err == nil {
+		t.Error("Expected old password rejected")
+	}
+	if _, err := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "new-secret"}); err != nil {
+		t.Errorf("Expected login with new password, got %v", err)
+	}
+	if _, err := svc.Refresh(ctx, session.RefreshToken); err == nil {
+		t.Error("Expected existing sessions revoked after reset")
+	}
+	if verified, _ := svc.IsEmailVerified(ctx, user.ID); !verified {
+		t.Error("Expected reset via email to verify the address")
+	}
+
+	// Link đã dùng không dùng lại được
+	if err := svc.ResetPassword(ctx, entity.ResetPasswordRequest{Token: token, NewPassword: "another-secret"}); err == nil {
+		t.Error("Expected reset token to be single-use")
+	}
+}
+
+func TestResetPassword_RejectsExpiredAndWrongPurposeToken(t *testing.T) {
+	svc, tokens, mails, user := newTestAuthService(t)
+	ctx := context.Background()
+
+	if err := svc.ForgotPassword(ctx, user.Email); err != nil {
+		t.Fatalf("ForgotPassword failed: %v", err)
+	}
+	token := mails.lastToken(t, user.Email)
+
+	// Token đặt lại mật khẩu không dùng để xác thực email được
+	if err := svc.VerifyEmail(ctx, token); err == nil {
+		t.Error("Expected reset token rejected for email verification")
+	}
+
+	tokens.userTokens[auth.HashToken(token)].ExpiresAt = time.Now().Add(-time.Minute)
+	if err := svc.ResetPassword(ctx, entity.ResetPasswordRequest{Token: token, NewPassword: "new-secret"}); err == nil {
+		t.Error("Expected expired reset token rejected")
+	}
+}
+
+func TestFileMailer_WritesMessageWithoutHeaderInjection(t *testing.T) {
+	dir := t.TempDir()
+	m, err := mailer.NewFileMailer(dir, "no-reply@example.com")
+	if err != nil {
+		t.Fatalf("NewFileMailer failed: %v", err)
+	}
+	if err := m.Send(context.Background(), "bob@example.com\r\nBcc: evil@example.com", "Xác thực email", "Dòng 1\nDòng 2"); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 .eml file, got %d", len(files))
+	}
+	data, _ := os.ReadFile(files[0])
+	content := string(data)
+	if strings.Contains(content, "\r\nBcc:") {
+		t.Error("Expected CRLF stripped from header values")
+	}
+	if !strings.Contains(content, "Subject: Xác thực email\r\n") || !strings.Contains(content, "Dòng 1\r\nDòng 2") {
+		t.Errorf("Unexpected message: %q", content)
+	}
+}
@@ -0,0 +1,107 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+// Mock AddOn Repository cho testing
+type mockAddOnRepository struct {
+	addOns []entity.AddOn
+}
+
+func (m *mockAddOnRepository) CreateAddOn(ctx context.Context, addOn *entity.AddOn) error {
+	m.addOns = append(m.addOns, *addOn)
+	return nil
+}
+
+func (m *mockAddOnRepository) ListAddOnsByEvent(ctx context.Context, eventID uuid.UUID) ([]entity.AddOn, error) {
+	addOns := make([]entity.AddOn, 0)
+	for _, addOn := range m.addOns {
+		if addOn.EventID == eventID {
+			addOns = append(addOns, addOn)
+		}
+	}
+	return addOns, nil
+}
+
+func (m *mockAddOnRepository) SalesByKind(ctx context.Context, eventID uuid.UUID) ([]entity.ItemKindSales, error) {
+	return nil, nil
+}
+
+func (m *mockAddOnRepository) AddOnSales(ctx context.Context, eventID uuid.UUID) ([]entity.AddOnSales, error) {
+	return nil, nil
+}
+
+func newTestEventWithTicketType(t *testing.T, repo *mockEventRepository) (uuid.UUID, uuid.UUID) {
+	eventID := uuid.New()
+	event := &entity.Event{
+		ID:        eventID,
+		Name:      "Lễ hội mùa hè",
+		Slug:      "le-hoi-mua-he",
+		StartTime: time.Now().Add(24 * time.Hour),
+		EndTime:   time.Now().Add(48 * time.Hour),
+		Status:    entity.EventStatusPublished,
+	}
+	if err := repo.CreateEvent(context.Background(), event); err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+
+	ticketTypeID := addTicketType(repo, "VIP", false)
+	repo.ticketTypes[ticketTypeID].EventID = eventID
+	return eventID, ticketTypeID
+}
+
+func TestCreateAddOn_WithRestriction(t *testing.T) {
+	eventRepo := NewMockEventRepository()
+	eventID, vipID := newTestEventWithTicketType(t, eventRepo)
+
+	addOnRepo := &mockAddOnRepository{}
+	svc := service.NewAddOnService(addOnRepo, eventRepo)
+
+	addOn, err := svc.CreateAddOn(context.Background(), eventID, entity.CreateAddOnRequest{
+		Name:                  "Vé gửi xe VIP",
+		Price:                 decimal.NewFromInt(50000),
+		InitialQuantity:       200,
+		RequiredTicketTypeIDs: []uuid.UUID{vipID},
+	})
+	if err != nil {
+		t.Fatalf("CreateAddOn failed: %v", err)
+	}
+
+	if addOn.RemainingQuantity != 200 {
+		t.Errorf("Expected remaining 200, got %d", addOn.RemainingQuantity)
+	}
+	if len(addOn.Restrictions) != 1 || addOn.Restrictions[0].TicketTypeID != vipID {
+		t.Errorf("Expected restriction on VIP ticket type, got %+v", addOn.Restrictions)
+	}
+}
+
+func TestCreateAddOn_RejectsForeignTicketType(t *testing.T) {
+	eventRepo := NewMockEventRepository()
+	eventID, _ := newTestEventWithTicketType(t, eventRepo)
+	_, otherTicketType := newTestEventWithTicketType(t, eventRepo)
+
+	addOnRepo := &mockAddOnRepository{}
+	svc := service.NewAddOnService(addOnRepo, eventRepo)
+
+	_, err := svc.CreateAddOn(context.Background(), eventID, entity.CreateAddOnRequest{
+		Name:                  "Áo thun",
+		Variant:               "M",
+		Price:                 decimal.NewFromInt(150000),
+		InitialQuantity:       50,
+		RequiredTicketTypeIDs: []uuid.UUID{otherTicketType},
+	})
+	if err == nil {
+		t.Fatal("Expected error for ticket type of another event, got nil")
+	}
+	if len(addOnRepo.addOns) != 0 {
+		t.Errorf("Expected no add-on to be saved, got %d", len(addOnRepo.addOns))
+	}
+}
@@ -0,0 +1,88 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/yourname/ticketing-system/internal/adapter/repository"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+func TestCompAllocation_DB(t *testing.T) {
+	db := setupDB()
+	ctx := context.Background()
+
+	sponsorID := uuid.New()
+	email := "sponsor-" + sponsorID.String()[:8] + "@example.com"
+	if err := db.Exec("INSERT INTO users (id, username, email, password_hash) VALUES (?, ?, ?, ?)",
+		sponsorID, "sponsor-"+sponsorID.String()[:8], email, "hash").Error; err != nil {
+		t.Fatalf("Failed to seed user: %v", err)
+	}
+
+	eventID := uuid.New()
+	if err := db.Exec("INSERT INTO events (id, name, slug, start_time, end_time) VALUES (?, ?, ?, ?, ?)",
+		eventID, "Comp Event", "comp-"+eventID.String()[:8], time.Now().Add(time.Hour), time.Now().Add(2*time.Hour)).Error; err != nil {
+		t.Fatalf("Failed to seed event: %v", err)
+	}
+
+	ticketTypeID := uuid.New()
+	if err := db.Create(&entity.TicketType{
+		ID:                ticketTypeID,
+		EventID:           eventID,
+		Name:              "VIP",
+		Price:             decimal.NewFromInt(1000),
+		InitialQuantity:   10,
+		RemainingQuantity: 10,
+	}).Error; err != nil {
+		t.Fatalf("Failed to seed ticket type: %v", err)
+	}
+
+	sqlDB, _ := db.DB()
+	orderRepo := repository.NewOrderRepository(db)
+	allocations := service.NewAllocationService(db, repository.NewAllocationRepository(db), orderRepo,
+		repository.NewUserRepository(sqlDB), nil, &recordingNotifier{})
+
+	allocation, err := allocations.CreateAllocation(ctx, ticketTypeID, entity.CreateAllocationRequest{Name: "Nhà tài trợ", Quantity: 4})
+	if err != nil {
+		t.Fatalf("CreateAllocation failed: %v", err)
+	}
+	remaining := func() int {
+		var tt entity.TicketType
+		db.First(&tt, "id = ?", ticketTypeID)
+		return tt.RemainingQuantity
+	}
+	if got := remaining(); got != 6 {
+		t.Errorf("Expected 6 tickets left for public sale, got %d", got)
+	}
+
+	order, err := allocations.IssueComp(ctx, allocation.ID, entity.IssueCompRequest{Email: email, Quantity: 1})
+	if err != nil {
+		t.Fatalf("IssueComp failed: %v", err)
+	}
+	if order.Status != entity.OrderStatusPaid || !order.Comp || !order.TotalAmount.IsZero() {
+		t.Errorf("Expected PAID zero-amount comp order, got status=%s comp=%v amount=%s", order.Status, order.Comp, order.TotalAmount)
+	}
+	if order.UserID == nil || *order.UserID != sponsorID || len(order.Tickets) != 1 {
+		t.Errorf("Expected 1 ticket for sponsor, got user=%v tickets=%d", order.UserID, len(order.Tickets))
+	}
+
+	if _, err := allocations.IssueComp(ctx, allocation.ID, entity.IssueCompRequest{Email: email, Quantity: 4}); err == nil {
+		t.Error("Expected error when issuing more comps than held")
+	}
+
+	released, err := allocations.ReleaseAllocation(ctx, allocation.ID, 0)
+	if err != nil {
+		t.Fatalf("ReleaseAllocation failed: %v", err)
+	}
+	if released.Quantity != 1 || released.Available() != 0 {
+		t.Errorf("Expected allocation to keep only issued comp, got quantity=%d available=%d", released.Quantity, released.Available())
+	}
+	if got := remaining(); got != 9 {
+		t.Errorf("Expected unused tickets back on public sale (9), got %d", got)
+	}
+}
@@ -0,0 +1,326 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/adapter/handler"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+type mockAPIKeyRepository struct {
+	mu          sync.Mutex
+	keys        map[uuid.UUID]*entity.APIKey
+	windowStart map[uuid.UUID]time.Time
+	windowCount map[uuid.UUID]int
+	users       *mockUserRepository
+}
+
+func newMockAPIKeyRepository(users *mockUserRepository) *mockAPIKeyRepository {
+	return &mockAPIKeyRepository{
+		keys:        map[uuid.UUID]*entity.APIKey{},
+		windowStart: map[uuid.UUID]time.Time{},
+		windowCount: map[uuid.UUID]int{},
+		users:       users,
+	}
+}
+
+func (m *mockAPIKeyRepository) CreateKey(ctx context.Context, key *entity.APIKey) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *key
+	m.keys[key.ID] = &stored
+	return nil
+}
+
+func (m *mockAPIKeyRepository) GetKey(ctx context.Context, id uuid.UUID) (*entity.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, ok := m.keys[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *key
+	return &copied, nil
+}
+
+func (m *mockAPIKeyRepository) ListKeysByUser(ctx context.Context, userID uuid.UUID) ([]entity.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := []entity.APIKey{}
+	for _, key := range m.keys {
+		if key.UserID == userID {
+			keys = append(keys, *key)
+		}
+	}
+	return keys, nil
+}
+
+func (m *mockAPIKeyRepository) CountActiveKeys(ctx context.Context, userID uuid.UUID, now time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	count := 0
+	for _, key := range m.keys {
+		if key.UserID == userID && key.Active(now) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockAPIKeyRepository) RevokeKey(ctx context.Context, id uuid.UUID, at time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, ok := m.keys[id]
+	if !ok || key.RevokedAt != nil {
+		return false, nil
+	}
+	key.RevokedAt = &at
+	return true, nil
+}
+
+func (m *mockAPIKeyRepository) ExpireKey(ctx context.Context, id uuid.UUID, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if key, ok := m.keys[id]; ok && key.RevokedAt == nil && (key.ExpiresAt == nil || key.ExpiresAt.After(at)) {
+		key.ExpiresAt = &at
+	}
+	return nil
+}
+
+func (m *mockAPIKeyRepository) UseKey(ctx context.Context, keyHash string, ip string, windowStart time.Time) (*entity.APIKeyUsage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for _, key := range m.keys {
+		if key.KeyHash != keyHash || !key.Active(now) {
+			continue
+		}
+		if start, ok := m.windowStart[key.ID]; !ok || !start.After(windowStart) {
+			m.windowStart[key.ID] = now
+			m.windowCount[key.ID] = 0
+		}
+		m.windowCount[key.ID]++
+		key.LastUsedAt = &now
+		key.LastUsedIP = &ip
+
+		copied := *key
+		return &entity.APIKeyUsage{
+			Key:         &copied,
+			OwnerRole:   m.users.users[key.UserID].Role,
+			WindowStart: m.windowStart[key.ID],
+			WindowCount: m.windowCount[key.ID],
+		}, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func newTestAPIKeyService(t *testing.T) (port.APIKeyServicePort, *mockAPIKeyRepository, *mockRoleRepository, *mockUserRepository) {
+	t.Helper()
+	access, roles, users := newTestAccessService(t)
+	keys := newMockAPIKeyRepository(users)
+	return service.NewAPIKeyService(keys, access), keys, roles, users
+}
+
+// addTestOrganizer tạo user được cấp vai trò organizer của một tổ chức (organizer không phải role gốc)
+func addTestOrganizer(users *mockUserRepository, roles *mockRoleRepository) *entity.User {
+	user := addTestUser(users, entity.RoleUser)
+	orgID := uuid.New()
+	roles.assignments[uuid.New()] = entity.RoleAssignment{UserID: user.ID, Role: entity.RoleOrganizer, ScopeType: entity.ScopeOrganization, ScopeID: &orgID}
+	return user
+}
+
+func TestAPIKey_CreateRequiresOrganizerAndHeldScopes(t *testing.T) {
+	svc, _, roles, users := newTestAPIKeyService(t)
+	ctx := context.Background()
+	customer := addTestUser(users, entity.RoleUser)
+	organizer := addTestOrganizer(users, roles)
+	scanner := addTestUser(users, entity.RoleScanner)
+	rootOrganizer := addTestUser(users, entity.RoleOrganizer)
+
+	boxOffice := []entity.Permission{entity.PermBoxOfficeSell}
+	if _, err := svc.CreateKey(ctx, customer.ID, entity.CreateAPIKeyRequest{Name: "kiosk", Scopes: boxOffice}); err == nil {
+		t.Error("Expected plain user rejected")
+	}
+	if _, err := svc.CreateKey(ctx, scanner.ID, entity.CreateAPIKeyRequest{Name: "gate", Scopes: []entity.Permission{entity.PermTicketsScan}}); err == nil {
+		t.Error("Expected scanner rejected")
+	}
+	if _, err := svc.CreateKey(ctx, rootOrganizer.ID, entity.CreateAPIKeyRequest{Name: "kiosk", Scopes: boxOffice}); err == nil {
+		t.Error("Expected root-role organizer without organization rejected")
+	}
+
+	invalid := map[string]entity.CreateAPIKeyRequest{
+		"empty name":     {Name: " ", Scopes: boxOffice},
+		"no scopes":      {Name: "kiosk"},
+		"unknown scope":  {Name: "kiosk", Scopes: []entity.Permission{"tickets:print"}},
+		"not held scope": {Name: "kiosk", Scopes: []entity.Permission{entity.PermVenuesWrite}},
+		"rate limit":     {Name: "kiosk", Scopes: boxOffice, RateLimitPerMinute: -1},
+	}
+	for name, req := range invalid {
+		if _, err := svc.CreateKey(ctx, organizer.ID, req); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+
+	created, err := svc.CreateKey(ctx, organizer.ID, entity.CreateAPIKeyRequest{
+		Name: "kiosk", Scopes: []entity.Permission{entity.PermBoxOfficeSell, entity.PermBoxOfficeSell, entity.PermTicketsScan},
+	})
+	if err != nil {
+		t.Fatalf("CreateKey failed: %v", err)
+	}
+	if created.Key == "" || created.KeyHash == created.Key || created.Prefix != created.Key[:len(created.Prefix)] {
+		t.Errorf("Expected plain key returned once and only hash stored, got %+v", created)
+	}
+	if len(created.Scopes) != 2 || created.RateLimitPerMinute != 60 {
+		t.Errorf("Expected deduplicated scopes and default rate limit, got %v / %d", created.Scopes, created.RateLimitPerMinute)
+	}
+
+	// Organizer được cấp theo event cũng được tạo khóa
+	eventOrganizer := addTestUser(users, entity.RoleUser)
+	eventID := uuid.New()
+	roles.assignments[uuid.New()] = entity.RoleAssignment{UserID: eventOrganizer.ID, Role: entity.RoleOrganizer, ScopeType: entity.ScopeEvent, ScopeID: &eventID}
+	if _, err := svc.CreateKey(ctx, eventOrganizer.ID, entity.CreateAPIKeyRequest{Name: "partner", Scopes: []entity.Permission{entity.PermOrdersRead}}); err != nil {
+		t.Errorf("Expected event-scoped organizer allowed, got %v", err)
+	}
+}
+
+func TestAPIKey_AuthenticateTracksUsageAndRateLimits(t *testing.T) {
+	svc, keys, roles, users := newTestAPIKeyService(t)
+	ctx := context.Background()
+	organizer := addTestOrganizer(users, roles)
+
+	created, err := svc.CreateKey(ctx, organizer.ID, entity.CreateAPIKeyRequest{
+		Name: "partner", Scopes: []entity.Permission{entity.PermOrdersRead}, RateLimitPerMinute: 2,
+	})
+	if err != nil {
+		t.Fatalf("CreateKey failed: %v", err)
+	}
+
+	identity, err := svc.Authenticate(ctx, created.Key, "203.0.113.7")
+	if err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	if identity.UserID != organizer.ID || identity.Role != entity.RoleUser || identity.KeyID != created.ID || identity.Remaining != 1 {
+		t.Errorf("Unexpected identity: %+v", identity)
+	}
+	if stored := keys.keys[created.ID]; stored.LastUsedAt == nil || *stored.LastUsedIP != "203.0.113.7" {
+		t.Error("Expected last used time and IP recorded")
+	}
+
+	if _, err := svc.Authenticate(ctx, created.Key, "203.0.113.7"); err != nil {
+		t.Fatalf("Authenticate failed: %v", err)
+	}
+	_, err = svc.Authenticate(ctx, created.Key, "203.0.113.7")
+	var limited *service.APIKeyRateLimitedError
+	if !errors.As(err, &limited) || limited.Limit != 2 || limited.RetryAfter <= 0 {
+		t.Fatalf("Expected rate limit error, got %v", err)
+	}
+
+	// Sang cửa sổ mới thì dùng lại được
+	keys.windowStart[created.ID] = time.Now().Add(-2 * time.Minute)
+	if _, err := svc.Authenticate(ctx, created.Key, "203.0.113.7"); err != nil {
+		t.Errorf("Expected new window to reset limit, got %v", err)
+	}
+
+	if _, err := svc.Authenticate(ctx, created.Key+"x", "203.0.113.7"); err == nil {
+		t.Error("Expected wrong key rejected")
+	}
+}
+
+func TestAPIKey_RotateAndRevoke(t *testing.T) {
+	svc, _, roles, users := newTestAPIKeyService(t)
+	ctx := context.Background()
+	owner := addTestOrganizer(users, roles)
+	other := addTestOrganizer(users, roles)
+	admin := addTestUser(users, entity.RoleAdmin)
+
+	created, err := svc.CreateKey(ctx, owner.ID, entity.CreateAPIKeyRequest{Name: "kiosk", Scopes: []entity.Permission{entity.PermBoxOfficeSell}})
+	if err != nil {
+		t.Fatalf("CreateKey failed: %v", err)
+	}
+
+	if _, err := svc.RotateKey(ctx, other.ID, other.Role, created.ID, entity.RotateAPIKeyRequest{}); err == nil {
+		t.Error("Expected other organizer unable to rotate key")
+	}
+
+	// Xoay có thời gian chuyển tiếp: khóa cũ vẫn dùng được
+	rotated, err := svc.RotateKey(ctx, owner.ID, owner.Role, created.ID, entity.RotateAPIKeyRequest{GracePeriodMinutes: 30})
+	if err != nil {
+		t.Fatalf("RotateKey failed: %v", err)
+	}
+	if rotated.Key == created.Key || rotated.RotatedFrom == nil || *rotated.RotatedFrom != created.ID || rotated.Name != "kiosk" {
+		t.Errorf("Unexpected rotated key: %+v", rotated)
+	}
+	if _, err := svc.Authenticate(ctx, created.Key, "10.0.0.1"); err != nil {
+		t.Errorf("Expected old key valid during grace period, got %v", err)
+	}
+
+	// Xoay không có thời gian chuyển tiếp: khóa cũ hết hiệu lực ngay
+	again, err := svc.RotateKey(ctx, owner.ID, owner.Role, rotated.ID, entity.RotateAPIKeyRequest{})
+	if err != nil {
+		t.Fatalf("RotateKey failed: %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, rotated.Key, "10.0.0.1"); err == nil {
+		t.Error("Expected rotated key revoked immediately")
+	}
+
+	// Admin thu hồi được khóa của người khác
+	if err := svc.RevokeKey(ctx, other.ID, other.Role, again.ID); err == nil {
+		t.Error("Expected other organizer unable to revoke key")
+	}
+	if err := svc.RevokeKey(ctx, admin.ID, admin.Role, again.ID); err != nil {
+		t.Fatalf("RevokeKey failed: %v", err)
+	}
+	if _, err := svc.Authenticate(ctx, again.Key, "10.0.0.1"); err == nil {
+		t.Error("Expected revoked key rejected")
+	}
+	if err := svc.RevokeKey(ctx, owner.ID, owner.Role, again.ID); err == nil {
+		t.Error("Expected error revoking twice")
+	}
+}
+
+func TestAPIKey_RejectedOnOwnerOnlyRoutes(t *testing.T) {
+	svc, _, roles, users := newTestAPIKeyService(t)
+	ctx := context.Background()
+	organizer := addTestOrganizer(users, roles)
+
+	created, err := svc.CreateKey(ctx, organizer.ID, entity.CreateAPIKeyRequest{
+		Name: "reports", Scopes: []entity.Permission{entity.PermOrdersRead},
+	})
+	if err != nil {
+		t.Fatalf("CreateKey failed: %v", err)
+	}
+
+	// Đặt vé không khai báo quyền: khóa hợp lệ vẫn không được đặt thay chủ khóa
+	app := fiber.New()
+	app.Post("/api/v1/orders", handler.AuthOrAPIKeyMiddleware(nil, nil, svc), handler.RejectAPIKeyMiddleware,
+		func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+
+	placeOrder := func(key string) int {
+		req := httptest.NewRequest(fiber.MethodPost, "/api/v1/orders", strings.NewReader(`{"items":[]}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(entity.APIKeyHeader, key)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	if status := placeOrder(created.Key); status != fiber.StatusForbidden {
+		t.Errorf("Expected 403 for scoped API key on POST /orders, got %d", status)
+	}
+	if status := placeOrder(created.Key + "x"); status != fiber.StatusUnauthorized {
+		t.Errorf("Expected 401 for invalid API key, got %d", status)
+	}
+}
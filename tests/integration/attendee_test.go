@@ -0,0 +1,134 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/yourname/ticketing-system/internal/adapter/repository"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+func TestSetAttendeeQuestions_Validation(t *testing.T) {
+	svc := service.NewAttendeeService(nil, nil)
+
+	tests := []struct {
+		name      string
+		questions []entity.AttendeeQuestion
+	}{
+		{"missing label", []entity.AttendeeQuestion{{ID: "q1", Type: entity.AttendeeQuestionText}}},
+		{"unknown type", []entity.AttendeeQuestion{{ID: "q1", Label: "Size", Type: "COLOR"}}},
+		{"choice without options", []entity.AttendeeQuestion{{ID: "q1", Label: "Size", Type: entity.AttendeeQuestionChoice, Options: []string{"M"}}}},
+		{"duplicate id", []entity.AttendeeQuestion{
+			{ID: "q1", Label: "A", Type: entity.AttendeeQuestionText},
+			{ID: "q1", Label: "B", Type: entity.AttendeeQuestionText},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.SetQuestions(context.Background(), uuid.New(), entity.SetAttendeeQuestionsRequest{Questions: tt.questions}); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
+
+func TestAttendeeDetails_DB(t *testing.T) {
+	db := setupDB()
+	ctx := context.Background()
+
+	userID := uuid.New()
+	if err := db.Exec("INSERT INTO users (id, username, email, password_hash) VALUES (?, ?, ?, ?)",
+		userID, "at-"+userID.String()[:8], "at-"+userID.String()[:8]+"@example.com", "hash").Error; err != nil {
+		t.Fatalf("Failed to seed user: %v", err)
+	}
+
+	eventID := uuid.New()
+	if err := db.Exec("INSERT INTO events (id, name, slug, start_time, end_time) VALUES (?, ?, ?, ?, ?)",
+		eventID, "Attendee Event", "attendee-"+eventID.String()[:8], time.Now().Add(48*time.Hour), time.Now().Add(50*time.Hour)).Error; err != nil {
+		t.Fatalf("Failed to seed event: %v", err)
+	}
+
+	ticketTypeID := uuid.New()
+	if err := db.Create(&entity.TicketType{
+		ID:                ticketTypeID,
+		EventID:           eventID,
+		Name:              "GA",
+		Price:             decimal.NewFromInt(100),
+		InitialQuantity:   5,
+		RemainingQuantity: 5,
+	}).Error; err != nil {
+		t.Fatalf("Failed to seed ticket type: %v", err)
+	}
+
+	attendees := service.NewAttendeeService(db, repository.NewAttendeeRepository(db))
+	if _, err := attendees.SetQuestions(ctx, eventID, entity.SetAttendeeQuestionsRequest{Questions: []entity.AttendeeQuestion{
+		{ID: "size", Label: "Áo size", Type: entity.AttendeeQuestionChoice, Options: []string{"S", "M", "L"}, Required: true},
+		{ID: "dob", Label: "Ngày sinh", Type: entity.AttendeeQuestionDateOfBirth},
+		{ID: "cccd", Label: "Số CCCD", Type: entity.AttendeeQuestionIDNumber, Required: true},
+	}}); err != nil {
+		t.Fatalf("SetQuestions failed: %v", err)
+	}
+
+	orders := service.NewOrderService(db, repository.NewOrderRepository(db), nil, nil)
+
+	// Câu trả lời sai đáp án thì không đặt được
+	bad := []entity.AttendeeDetails{{Name: "An", Answers: map[string]string{"size": "XXL", "cccd": "001099012345"}}}
+	if _, err := orders.PlaceOrder(ctx, userID, []service.RequestItem{{TicketTypeID: ticketTypeID, Quantity: 1, Attendees: bad}}); err == nil {
+		t.Error("Expected error for invalid choice answer")
+	}
+
+	good := []entity.AttendeeDetails{{Name: " An ", Answers: map[string]string{"size": "M", "cccd": "001099012345", "dob": "1999-05-01"}}}
+	order, err := orders.PlaceOrder(ctx, userID, []service.RequestItem{
+		{TicketTypeID: ticketTypeID, Quantity: 1, Attendees: good},
+		{TicketTypeID: ticketTypeID, Quantity: 1}, // điền sau
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	db.Model(&entity.Order{}).Where("id = ?", order.ID).Update("status", entity.OrderStatusPaid)
+
+	var later entity.Ticket
+	for _, ticket := range order.Tickets {
+		if ticket.OwnerName == "" {
+			later = ticket
+		} else if ticket.OwnerName != "An" || ticket.Answers["size"] != "M" {
+			t.Errorf("Expected normalized attendee on ticket, got %q %v", ticket.OwnerName, ticket.Answers)
+		}
+	}
+
+	export, err := attendees.Export(ctx, eventID)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	incomplete := 0
+	for _, a := range export.Attendees {
+		if !a.Complete {
+			incomplete++
+		}
+	}
+	if len(export.Attendees) != 2 || incomplete != 1 {
+		t.Errorf("Expected 2 attendees with 1 incomplete, got %d / %d", len(export.Attendees), incomplete)
+	}
+
+	// Thiếu câu bắt buộc
+	if _, err := attendees.UpdateAttendee(ctx, userID, later.ID, entity.AttendeeDetails{Name: "Bình", Answers: map[string]string{"size": "L"}}); err == nil {
+		t.Error("Expected error for missing required answer")
+	}
+	updated, err := attendees.UpdateAttendee(ctx, userID, later.ID, entity.AttendeeDetails{Name: "Bình", Answers: map[string]string{"size": "L", "cccd": "b1234567"}})
+	if err != nil {
+		t.Fatalf("UpdateAttendee failed: %v", err)
+	}
+	if updated.Answers["cccd"] != "B1234567" {
+		t.Errorf("Expected ID number upper-cased, got %q", updated.Answers["cccd"])
+	}
+
+	// Người khác không sửa được
+	if _, err := attendees.UpdateAttendee(ctx, uuid.New(), later.ID, entity.AttendeeDetails{Name: "X", Answers: map[string]string{"size": "L", "cccd": "B1234567"}}); err == nil {
+		t.Error("Expected error when updating someone else's ticket")
+	}
+}
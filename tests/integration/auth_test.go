@@ -0,0 +1,553 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+	"github.com/yourname/ticketing-system/internal/core/service"
+	"github.com/yourname/ticketing-system/pkg/auth"
+)
+
+// Mock User Repository cho testing
+type mockUserRepository struct {
+	users map[uuid.UUID]*entity.User
+}
+
+func (m *mockUserRepository) CreateUser(ctx context.Context, user *entity.User) error {
+	m.users[user.ID] = user
+	return nil
+}
+
+func (m *mockUserRepository) GetUserByEmail(ctx context.Context, email string) (*entity.User, error) {
+	for _, user := range m.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *mockUserRepository) GetUserByID(ctx context.Context, id string) (*entity.User, error) {
+	for _, user := range m.users {
+		if user.ID.String() == id {
+			return user, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *mockUserRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	user, ok := m.users[userID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	user.PasswordHash = passwordHash
+	return nil
+}
+
+func (m *mockUserRepository) MarkEmailVerified(ctx context.Context, userID uuid.UUID) error {
+	user, ok := m.users[userID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if user.EmailVerifiedAt == nil {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	return nil
+}
+
+func (m *mockUserRepository) UpdateProfileData(ctx context.Context, userID uuid.UUID, profile map[string]interface{}) error {
+	user, ok := m.users[userID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	user.ProfileData = profile
+	return nil
+}
+
+func (m *mockUserRepository) SetPendingEmail(ctx context.Context, userID uuid.UUID, email string) error {
+	user, ok := m.users[userID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	user.PendingEmail = &email
+	return nil
+}
+
+func (m *mockUserRepository) ApplyPendingEmail(ctx context.Context, userID uuid.UUID) (bool, error) {
+	user, ok := m.users[userID]
+	if !ok || user.PendingEmail == nil {
+		return false, nil
+	}
+	now := time.Now()
+	user.Email = *user.PendingEmail
+	user.PendingEmail = nil
+	user.EmailVerifiedAt = &now
+	return true, nil
+}
+
+// Mock Token Repository cho testing
+type mockTokenRepository struct {
+	mu         sync.Mutex
+	refresh    map[string]*entity.RefreshToken // key = token hash
+	revoked    map[string]time.Time            // key = jti
+	userTokens map[string]*entity.UserToken    // key = token hash
+}
+
+func newMockTokenRepository() *mockTokenRepository {
+	return &mockTokenRepository{
+		refresh:    make(map[string]*entity.RefreshToken),
+		revoked:    make(map[string]time.Time),
+		userTokens: make(map[string]*entity.UserToken),
+	}
+}
+
+func (m *mockTokenRepository) CreateRefreshToken(ctx context.Context, token *entity.RefreshToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	copied := *token
+	m.refresh[token.TokenHash] = &copied
+	return nil
+}
+
+func (m *mockTokenRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*entity.RefreshToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token, ok := m.refresh[hash]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	copied := *token
+	return &copied, nil
+}
+
+func (m *mockTokenRepository) RotateRefreshToken(ctx context.Context, oldID uuid.UUID, next *entity.RefreshToken) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, token := range m.refresh {
+		if token.ID == oldID {
+			if token.RevokedAt != nil {
+				return false, nil
+			}
+			now := time.Now()
+			token.RevokedAt = &now
+			token.ReplacedBy = &next.ID
+			copied := *next
+			m.refresh[next.TokenHash] = &copied
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockTokenRepository) RevokeRefreshFamily(ctx context.Context, familyID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for _, token := range m.refresh {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *mockTokenRepository) RevokeAccessToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.revoked[jti] = expiresAt
+	return nil
+}
+
+func (m *mockTokenRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.revoked[jti]
+	return ok, nil
+}
+
+func (m *mockTokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for _, token := range m.refresh {
+		if token.UserID == userID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *mockTokenRepository) CreateUserToken(ctx context.Context, token *entity.UserToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for _, existing := range m.userTokens {
+		if existing.UserID == token.UserID && existing.Purpose == token.Purpose && existing.UsedAt == nil {
+			existing.UsedAt = &now
+		}
+	}
+	copied := *token
+	m.userTokens[token.TokenHash] = &copied
+	return nil
+}
+
+func (m *mockTokenRepository) GetUserToken(ctx context.Context, hash string, purpose entity.UserTokenPurpose) (*entity.UserToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token, ok := m.userTokens[hash]
+	if !ok || token.Purpose != purpose || token.UsedAt != nil || !time.Now().Before(token.ExpiresAt) {
+		return nil, sql.ErrNoRows
+	}
+	copied := *token
+	return &copied, nil
+}
+
+func (m *mockTokenRepository) ConsumeUserToken(ctx context.Context, hash string, purpose entity.UserTokenPurpose) (*entity.UserToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	token, ok := m.userTokens[hash]
+	if !ok || token.Purpose != purpose || token.UsedAt != nil || !time.Now().Before(token.ExpiresAt) {
+		return nil, sql.ErrNoRows
+	}
+	now := time.Now()
+	token.UsedAt = &now
+	copied := *token
+	return &copied, nil
+}
+
+// Mock Login Throttle Repository cho testing
+type mockLoginThrottleRepository struct {
+	mu        sync.Mutex
+	throttles map[string]*entity.LoginThrottle // key = kind + "|" + key
+}
+
+func newMockLoginThrottleRepository() *mockLoginThrottleRepository {
+	return &mockLoginThrottleRepository{throttles: make(map[string]*entity.LoginThrottle)}
+}
+
+func (m *mockLoginThrottleRepository) GetThrottle(ctx context.Context, kind entity.LoginThrottleKind, key string) (*entity.LoginThrottle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	throttle, ok := m.throttles[string(kind)+"|"+key]
+	if !ok {
+		return nil, nil
+	}
+	copied := *throttle
+	return &copied, nil
+}
+
+func (m *mockLoginThrottleRepository) RecordFailure(ctx context.Context, kind entity.LoginThrottleKind, key string, windowStart time.Time) (*entity.LoginThrottle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	throttle, ok := m.throttles[string(kind)+"|"+key]
+	if !ok {
+		throttle = &entity.LoginThrottle{Kind: kind, Key: key}
+		m.throttles[string(kind)+"|"+key] = throttle
+	}
+	if throttle.LastFailureAt.Before(windowStart) {
+		throttle.Failures = 0
+	}
+	throttle.Failures++
+	throttle.LastFailureAt = time.Now()
+	copied := *throttle
+	return &copied, nil
+}
+
+func (m *mockLoginThrottleRepository) SetLockedUntil(ctx context.Context, kind entity.LoginThrottleKind, key string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if throttle, ok := m.throttles[string(kind)+"|"+key]; ok {
+		throttle.LockedUntil = &until
+	}
+	return nil
+}
+
+func (m *mockLoginThrottleRepository) ResetThrottle(ctx context.Context, kind entity.LoginThrottleKind, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.throttles, string(kind)+"|"+key)
+	return nil
+}
+
+func (m *mockLoginThrottleRepository) ListLocked(ctx context.Context, now time.Time) ([]entity.LoginThrottle, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var locked []entity.LoginThrottle
+	for _, throttle := range m.throttles {
+		if throttle.LockedUntil != nil && throttle.LockedUntil.After(now) {
+			locked = append(locked, *throttle)
+		}
+	}
+	return locked, nil
+}
+
+func (m *mockLoginThrottleRepository) PurgeThrottles(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// Mock MFA Repository cho testing
+type mockMFARepository struct {
+	mu       sync.Mutex
+	totp     map[uuid.UUID]*entity.UserTOTP
+	recovery map[uuid.UUID]map[string]bool // user -> code hash -> đã dùng
+}
+
+func newMockMFARepository() *mockMFARepository {
+	return &mockMFARepository{
+		totp:     make(map[uuid.UUID]*entity.UserTOTP),
+		recovery: make(map[uuid.UUID]map[string]bool),
+	}
+}
+
+func (m *mockMFARepository) GetTOTP(ctx context.Context, userID uuid.UUID) (*entity.UserTOTP, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	totp, ok := m.totp[userID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *totp
+	return &copied, nil
+}
+
+func (m *mockMFARepository) SaveTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.totp[userID]; ok && existing.EnabledAt != nil {
+		return false, nil
+	}
+	m.totp[userID] = &entity.UserTOTP{UserID: userID, Secret: secret, CreatedAt: time.Now()}
+	return true, nil
+}
+
+func (m *mockMFARepository) EnableTOTP(ctx context.Context, userID uuid.UUID, step int64, recoveryCodeHashes []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	totp, ok := m.totp[userID]
+	if !ok || totp.EnabledAt != nil {
+		return sql.ErrNoRows
+	}
+	now := time.Now()
+	totp.EnabledAt = &now
+	totp.LastUsedStep = step
+	m.setRecovery(userID, recoveryCodeHashes)
+	return nil
+}
+
+func (m *mockMFARepository) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	totp, ok := m.totp[userID]
+	if !ok || totp.EnabledAt == nil || totp.LastUsedStep >= step {
+		return false, nil
+	}
+	totp.LastUsedStep = step
+	return true, nil
+}
+
+func (m *mockMFARepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	used, ok := m.recovery[userID][codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	m.recovery[userID][codeHash] = true
+	return true, nil
+}
+
+func (m *mockMFARepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.setRecovery(userID, codeHashes)
+	return nil
+}
+
+func (m *mockMFARepository) DeleteTOTP(ctx context.Context, userID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.totp, userID)
+	delete(m.recovery, userID)
+	return nil
+}
+
+func (m *mockMFARepository) setRecovery(userID uuid.UUID, codeHashes []string) {
+	m.recovery[userID] = make(map[string]bool, len(codeHashes))
+	for _, hash := range codeHashes {
+		m.recovery[userID][hash] = false
+	}
+}
+
+// Mock Mailer ghi lại email đã gửi
+type mockMailer struct {
+	mu   sync.Mutex
+	sent []sentMail
+}
+
+type sentMail struct {
+	To, Subject, Body string
+}
+
+func (m *mockMailer) Send(ctx context.Context, to string, subject string, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, sentMail{To: to, Subject: subject, Body: body})
+	return nil
+}
+
+var mailTokenPattern = regexp.MustCompile(`token=([A-Za-z0-9_-]+)`)
+
+// lastToken trả về token trong link của email gần nhất gửi tới địa chỉ to.
+func (m *mockMailer) lastToken(t *testing.T, to string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.sent) - 1; i >= 0; i-- {
+		if m.sent[i].To == to {
+			if match := mailTokenPattern.FindStringSubmatch(m.sent[i].Body); match != nil {
+				return match[1]
+			}
+		}
+	}
+	t.Fatalf("No email with token sent to %s", to)
+	return ""
+}
+
+func (m *mockTokenRepository) PurgeExpiredTokens(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// testJWTKeys là khóa ký tạm dùng chung cho các test auth
+var testJWTKeys = func() *auth.KeySet {
+	keys, err := auth.GenerateEphemeralKeySet(15 * time.Minute)
+	if err != nil {
+		panic(err)
+	}
+	return keys
+}()
+
+// testAuthConfig là cấu hình auth dùng chung cho test (2FA không bắt buộc)
+var testAuthConfig = service.AuthConfig{
+	AccessTTL:  15 * time.Minute,
+	RefreshTTL: time.Hour,
+	AppBaseURL: "https://app.example.com",
+	MFAIssuer:  "Ticketing",
+}
+
+// newTestAuthService tạo auth service với một user (mật khẩu "secret123"), token repo và mailer trong bộ nhớ.
+func newTestAuthService(t *testing.T) (port.AuthServicePort, *mockTokenRepository, *mockMailer, *entity.User) {
+	hash, err := auth.HashPassword("secret123")
+	if err != nil {
+		t.Fatalf("HashPassword failed: %v", err)
+	}
+	user := &entity.User{ID: uuid.New(), Username: "alice", Email: "alice@example.com", PasswordHash: hash, Role: entity.RoleUser}
+	users := &mockUserRepository{users: map[uuid.UUID]*entity.User{user.ID: user}}
+	tokens := newMockTokenRepository()
+	mailer := &mockMailer{}
+	return service.NewAuthService(users, tokens, newMockLoginThrottleRepository(), newMockMFARepository(), mailer, testJWTKeys, testAuthConfig), tokens, mailer, user
+}
+
+func TestLogin_IssuesShortLivedAccessAndRefreshToken(t *testing.T) {
+	svc, tokens, _, user := newTestAuthService(t)
+
+	pair, err := svc.Login(context.Background(), entity.LoginRequest{Email: user.Email, Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if pair.RefreshToken == "" || pair.ExpiresIn != int64((15*time.Minute).Seconds()) {
+		t.Fatalf("Unexpected token pair: %+v", pair)
+	}
+
+	claims, err := auth.ValidateToken(pair.AccessToken, testJWTKeys)
+	if err != nil {
+		t.Fatalf("ValidateToken failed: %v", err)
+	}
+	if claims.UserID != user.ID.String() || claims.ID == "" {
+		t.Errorf("Expected claims with user id and jti, got %+v", claims)
+	}
+
+	stored, ok := tokens.refresh[auth.HashToken(pair.RefreshToken)]
+	if !ok {
+		t.Fatal("Expected refresh token stored by hash")
+	}
+	if stored.TokenHash == pair.RefreshToken {
+		t.Error("Refresh token must not be stored in plain text")
+	}
+}
+
+func TestRefresh_RotatesAndDetectsReuse(t *testing.T) {
+	svc, _, _, user := newTestAuthService(t)
+	ctx := context.Background()
+
+	first, err := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	second, err := svc.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("Expected a new refresh token after rotation")
+	}
+
+	// Dùng lại token đã xoay: bị từ chối và cả họ token bị thu hồi
+	if _, err := svc.Refresh(ctx, first.RefreshToken); err == nil {
+		t.Fatal("Expected error when reusing a rotated refresh token")
+	}
+	if _, err := svc.Refresh(ctx, second.RefreshToken); err == nil {
+		t.Error("Expected whole token family revoked after reuse")
+	}
+
+	// Lần đăng nhập khác (họ khác) không bị ảnh hưởng
+	other, err := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if _, err := svc.Refresh(ctx, other.RefreshToken); err != nil {
+		t.Errorf("Expected other session to refresh, got %v", err)
+	}
+}
+
+func TestLogout_RevokesAccessTokenAndSession(t *testing.T) {
+	svc, _, _, user := newTestAuthService(t)
+	ctx := context.Background()
+
+	pair, err := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	claims, _ := auth.ValidateToken(pair.AccessToken, testJWTKeys)
+
+	if err := svc.Logout(ctx, user.ID, claims.ID, claims.ExpiresAt.Time, pair.RefreshToken); err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+
+	revoked, err := svc.IsTokenRevoked(ctx, claims.ID)
+	if err != nil || !revoked {
+		t.Errorf("Expected access token revoked, got %v %v", revoked, err)
+	}
+	if _, err := svc.ValidateToken(ctx, pair.AccessToken); err == nil {
+		t.Error("Expected revoked access token to fail validation")
+	}
+	if _, err := svc.Refresh(ctx, pair.RefreshToken); err == nil {
+		t.Error("Expected refresh token revoked after logout")
+	}
+}
+
+func TestValidateToken_RejectsOtherSigningMethod(t *testing.T) {
+	// Token "alg: none" không được chấp nhận
+	const noneToken = "eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0.eyJ1c2VyX2lkIjoieCIsInJvbGUiOiJhZG1pbiJ9."
+	if _, err := auth.ValidateToken(noneToken, testJWTKeys); err == nil {
+		t.Error("Expected error for unsigned token")
+	}
+}
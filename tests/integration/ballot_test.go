@@ -0,0 +1,134 @@
+package integration
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/yourname/ticketing-system/internal/adapter/repository"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+func TestBallotRank_DeterministicAndSeedBound(t *testing.T) {
+	entryIDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+
+	order := func(seed string) []uuid.UUID {
+		ids := append([]uuid.UUID(nil), entryIDs...)
+		sort.Slice(ids, func(i, j int) bool {
+			return service.BallotRank(seed, ids[i]) < service.BallotRank(seed, ids[j])
+		})
+		return ids
+	}
+
+	first, again := order("seed-a"), order("seed-a")
+	for i := range first {
+		if first[i] != again[i] {
+			t.Fatal("Expected the same seed to reproduce the same draw order")
+		}
+	}
+
+	if service.BallotRank("seed-a", entryIDs[0]) == service.BallotRank("seed-b", entryIDs[0]) {
+		t.Error("Expected rank to depend on the seed")
+	}
+}
+
+// Cần schema đầy đủ từ scripts/db/init.sql
+func TestBallotDrawAndRedraw_DB(t *testing.T) {
+	db := setupDB()
+	ctx := context.Background()
+
+	eventID := uuid.New()
+	if err := db.Exec("INSERT INTO events (id, name, slug, start_time, end_time) VALUES (?, ?, ?, ?, ?)",
+		eventID, "Ballot Event", "ballot-"+eventID.String()[:8], time.Now().Add(time.Hour), time.Now().Add(2*time.Hour)).Error; err != nil {
+		t.Fatalf("Failed to seed event: %v", err)
+	}
+
+	ticketTypeID := uuid.New()
+	if err := db.Create(&entity.TicketType{
+		ID:                ticketTypeID,
+		EventID:           eventID,
+		Name:              "Ballot GA",
+		Price:             decimal.NewFromInt(100),
+		InitialQuantity:   2,
+		RemainingQuantity: 2,
+	}).Error; err != nil {
+		t.Fatalf("Failed to seed ticket type: %v", err)
+	}
+
+	orderRepo := repository.NewOrderRepository(db)
+	orders := service.NewOrderService(db, orderRepo, nil, nil)
+	ballots := service.NewBallotService(db, repository.NewBallotRepository(db), orderRepo, orders, &recordingNotifier{})
+
+	ballot, err := ballots.CreateBallot(ctx, entity.CreateBallotRequest{
+		TicketTypeID:         ticketTypeID,
+		EntryOpensAt:         time.Now().Add(-time.Minute),
+		EntryClosesAt:        time.Now().Add(time.Second),
+		MaxPerEntry:          2,
+		PaymentWindowMinutes: 30,
+	})
+	if err != nil {
+		t.Fatalf("CreateBallot failed: %v", err)
+	}
+	if ballot.Seed != "" || ballot.SeedHash == "" {
+		t.Error("Expected only seed hash to be published before the draw")
+	}
+
+	// Không mua trực tiếp được loại vé bốc thăm
+	if _, err := orders.PlaceOrder(ctx, uuid.New(), []service.RequestItem{{TicketTypeID: ticketTypeID, Quantity: 1}}); err == nil {
+		t.Error("Expected direct purchase of ballot ticket type to fail")
+	}
+
+	for i := 0; i < 3; i++ {
+		userID := uuid.New()
+		name := userID.String()[:8]
+		if err := db.Exec("INSERT INTO users (id, username, email, password_hash) VALUES (?, ?, ?, ?)",
+			userID, "ballot-"+name, name+"@example.com", "hash").Error; err != nil {
+			t.Fatalf("Failed to seed user: %v", err)
+		}
+		if _, err := ballots.Enter(ctx, userID, ballot.ID, entity.EnterBallotRequest{Quantity: 2}); err != nil {
+			t.Fatalf("Enter failed: %v", err)
+		}
+	}
+
+	time.Sleep(1100 * time.Millisecond) // đợi đóng đăng ký
+
+	result, err := ballots.Draw(ctx, ballot.ID)
+	if err != nil {
+		t.Fatalf("Draw failed: %v", err)
+	}
+	if result.Winners != 1 || result.NotSelected != 2 {
+		t.Fatalf("Expected 1 winner and 2 not selected, got %+v", result)
+	}
+
+	entries, err := ballots.Results(ctx, ballot.ID)
+	if err != nil {
+		t.Fatalf("Results failed: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.DrawRank != service.BallotRank(result.Ballot.Seed, entry.ID) {
+			t.Errorf("Entry %s rank does not match published seed", entry.ID)
+		}
+	}
+
+	// Người trúng không thanh toán (hủy đơn) -> bốc bổ sung cho người kế tiếp
+	winner := entries[0]
+	if winner.Status != entity.BallotEntryStatusWon {
+		t.Fatalf("Expected first ranked entry to win, got %s", winner.Status)
+	}
+	if err := db.Model(&entity.BallotEntry{}).Where("id = ?", winner.ID).Update("payment_deadline", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatalf("Failed to expire deadline: %v", err)
+	}
+
+	redraw, err := ballots.Redraw(ctx, ballot.ID)
+	if err != nil {
+		t.Fatalf("Redraw failed: %v", err)
+	}
+	if redraw.Unpaid != 1 || redraw.Winners != 1 {
+		t.Errorf("Expected 1 unpaid and 1 new winner, got %+v", redraw)
+	}
+}
@@ -0,0 +1,91 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/yourname/ticketing-system/internal/adapter/repository"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+func TestBoxOfficeOrder_RejectsUnknownPaymentMethod(t *testing.T) {
+	svc := service.NewOrderService(nil, nil, nil, nil)
+	_, err := svc.PlaceBoxOfficeOrder(context.Background(), uuid.New(),
+		entity.BoxOfficeSale{PaymentMethod: "VOUCHER"},
+		[]service.RequestItem{{TicketTypeID: uuid.New(), Quantity: 1}})
+	if err == nil {
+		t.Fatal("Expected error for unknown payment method")
+	}
+}
+
+func TestBoxOfficeSaleAndShiftReport_DB(t *testing.T) {
+	db := setupDB()
+	ctx := context.Background()
+
+	cashierID := uuid.New()
+	name := cashierID.String()[:8]
+	if err := db.Exec("INSERT INTO users (id, username, email, password_hash, role) VALUES (?, ?, ?, ?, ?)",
+		cashierID, "cashier-"+name, name+"@example.com", "hash", entity.RoleBoxOffice).Error; err != nil {
+		t.Fatalf("Failed to seed cashier: %v", err)
+	}
+
+	eventID := uuid.New()
+	if err := db.Exec("INSERT INTO events (id, name, slug, start_time, end_time) VALUES (?, ?, ?, ?, ?)",
+		eventID, "Box Office Event", "box-office-"+eventID.String()[:8], time.Now().Add(time.Hour), time.Now().Add(2*time.Hour)).Error; err != nil {
+		t.Fatalf("Failed to seed event: %v", err)
+	}
+
+	ticketTypeID := uuid.New()
+	if err := db.Create(&entity.TicketType{
+		ID:                ticketTypeID,
+		EventID:           eventID,
+		Name:              "GA",
+		Price:             decimal.NewFromInt(200),
+		InitialQuantity:   10,
+		RemainingQuantity: 10,
+	}).Error; err != nil {
+		t.Fatalf("Failed to seed ticket type: %v", err)
+	}
+
+	orders := service.NewOrderService(db, repository.NewOrderRepository(db), nil, nil)
+	start := time.Now().Add(-time.Minute)
+
+	order, err := orders.PlaceBoxOfficeOrder(ctx, cashierID,
+		entity.BoxOfficeSale{PaymentMethod: entity.PaymentMethodCash, CustomerName: "Khách lẻ"},
+		[]service.RequestItem{{TicketTypeID: ticketTypeID, Quantity: 2}})
+	if err != nil {
+		t.Fatalf("PlaceBoxOfficeOrder failed: %v", err)
+	}
+	if order.Status != entity.OrderStatusPaid || order.UserID != nil || order.Channel != entity.OrderChannelBoxOffice {
+		t.Errorf("Expected PAID box office order without user, got status=%s user=%v channel=%s", order.Status, order.UserID, order.Channel)
+	}
+	if len(order.Tickets) != 2 || order.Tickets[0].OwnerName != "Khách lẻ" {
+		t.Errorf("Expected 2 tickets printed for the customer, got %+v", order.Tickets)
+	}
+
+	if _, err := orders.PlaceBoxOfficeOrder(ctx, cashierID,
+		entity.BoxOfficeSale{PaymentMethod: entity.PaymentMethodCard},
+		[]service.RequestItem{{TicketTypeID: ticketTypeID, Quantity: 1}}); err != nil {
+		t.Fatalf("PlaceBoxOfficeOrder (card) failed: %v", err)
+	}
+
+	report, err := orders.ShiftReport(ctx, start, time.Now().Add(time.Minute), &cashierID)
+	if err != nil {
+		t.Fatalf("ShiftReport failed: %v", err)
+	}
+	if len(report.Cashiers) != 1 {
+		t.Fatalf("Expected 1 cashier in report, got %d", len(report.Cashiers))
+	}
+	shift := report.Cashiers[0]
+	if shift.Orders != 2 || shift.Tickets != 3 {
+		t.Errorf("Expected 2 orders / 3 tickets, got %d / %d", shift.Orders, shift.Tickets)
+	}
+	if !shift.TotalAmount.Equal(decimal.NewFromInt(600)) || !shift.CashAmount.Equal(decimal.NewFromInt(400)) {
+		t.Errorf("Expected total 600 / cash 400, got %s / %s", shift.TotalAmount, shift.CashAmount)
+	}
+}
@@ -0,0 +1,117 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+	"gorm.io/gorm"
+)
+
+// Mock Bundle Repository cho testing
+type mockBundleRepository struct {
+	bundles map[uuid.UUID]*entity.Bundle
+}
+
+func (m *mockBundleRepository) CreateBundle(ctx context.Context, bundle *entity.Bundle) error {
+	m.bundles[bundle.ID] = bundle
+	return nil
+}
+
+func (m *mockBundleRepository) GetBundleByID(ctx context.Context, id uuid.UUID) (*entity.Bundle, error) {
+	if bundle, ok := m.bundles[id]; ok {
+		return bundle, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockBundleRepository) ListBundles(ctx context.Context, limit int, offset int) ([]entity.Bundle, error) {
+	bundles := make([]entity.Bundle, 0, len(m.bundles))
+	for _, bundle := range m.bundles {
+		bundles = append(bundles, *bundle)
+	}
+	return bundles, nil
+}
+
+func addTicketType(repo *mockEventRepository, name string, reserved bool) uuid.UUID {
+	id := uuid.New()
+	repo.ticketTypes[id] = &entity.TicketType{
+		ID:                id,
+		Name:              name,
+		Price:             decimal.NewFromInt(500000),
+		InitialQuantity:   100,
+		RemainingQuantity: 100,
+		Reserved:          reserved,
+	}
+	return id
+}
+
+func TestCreateBundle_Success(t *testing.T) {
+	eventRepo := NewMockEventRepository()
+	day1 := addTicketType(eventRepo, "Ngày 1", false)
+	day2 := addTicketType(eventRepo, "Ngày 2", false)
+	day3 := addTicketType(eventRepo, "Ngày 3", false)
+
+	bundleRepo := &mockBundleRepository{bundles: make(map[uuid.UUID]*entity.Bundle)}
+	svc := service.NewBundleService(bundleRepo, eventRepo)
+
+	bundle, err := svc.CreateBundle(context.Background(), entity.CreateBundleRequest{
+		Name:            "Vé 3 ngày",
+		Price:           decimal.NewFromInt(1200000),
+		InitialQuantity: 50,
+		Items: []entity.CreateBundleItemRequest{
+			{TicketTypeID: day1},
+			{TicketTypeID: day2},
+			{TicketTypeID: day3},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateBundle failed: %v", err)
+	}
+
+	if len(bundle.Items) != 3 {
+		t.Fatalf("Expected 3 bundle items, got %d", len(bundle.Items))
+	}
+	for _, item := range bundle.Items {
+		if item.Quantity != 1 {
+			t.Errorf("Expected default quantity 1, got %d", item.Quantity)
+		}
+	}
+	if bundle.RemainingQuantity != 50 {
+		t.Errorf("Expected remaining 50, got %d", bundle.RemainingQuantity)
+	}
+}
+
+func TestCreateBundle_RejectsInvalidComponents(t *testing.T) {
+	eventRepo := NewMockEventRepository()
+	ga := addTicketType(eventRepo, "GA", false)
+	seated := addTicketType(eventRepo, "Ghế A", true)
+
+	bundleRepo := &mockBundleRepository{bundles: make(map[uuid.UUID]*entity.Bundle)}
+	svc := service.NewBundleService(bundleRepo, eventRepo)
+
+	cases := map[string][]entity.CreateBundleItemRequest{
+		"reserved":  {{TicketTypeID: ga}, {TicketTypeID: seated}},
+		"duplicate": {{TicketTypeID: ga}, {TicketTypeID: ga}},
+		"missing":   {{TicketTypeID: ga}, {TicketTypeID: uuid.New()}},
+	}
+
+	for name, items := range cases {
+		_, err := svc.CreateBundle(context.Background(), entity.CreateBundleRequest{
+			Name:            "Combo",
+			Price:           decimal.NewFromInt(900000),
+			InitialQuantity: 10,
+			Items:           items,
+		})
+		if err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+
+	if len(bundleRepo.bundles) != 0 {
+		t.Errorf("Expected no bundle to be saved, got %d", len(bundleRepo.bundles))
+	}
+}
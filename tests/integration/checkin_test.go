@@ -0,0 +1,107 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/yourname/ticketing-system/internal/adapter/repository"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+func TestCheckIn_RejectsEmptyCode(t *testing.T) {
+	svc := service.NewCheckInService(nil, nil)
+	if _, err := svc.CheckIn(context.Background(), uuid.New(), entity.CheckInRequest{TicketCode: "  "}); err == nil {
+		t.Fatal("Expected error for empty ticket code")
+	}
+}
+
+func TestCheckInAndRefund_DB(t *testing.T) {
+	db := setupDB()
+	ctx := context.Background()
+
+	cashierID := uuid.New()
+	name := cashierID.String()[:8]
+	if err := db.Exec("INSERT INTO users (id, username, email, password_hash, role) VALUES (?, ?, ?, ?, ?)",
+		cashierID, "scan-"+name, name+"@example.com", "hash", entity.RoleBoxOffice).Error; err != nil {
+		t.Fatalf("Failed to seed cashier: %v", err)
+	}
+
+	eventID, otherEventID := uuid.New(), uuid.New()
+	for _, id := range []uuid.UUID{eventID, otherEventID} {
+		if err := db.Exec("INSERT INTO events (id, name, slug, start_time, end_time) VALUES (?, ?, ?, ?, ?)",
+			id, "Check-in Event", "check-in-"+id.String()[:8], time.Now().Add(time.Hour), time.Now().Add(2*time.Hour)).Error; err != nil {
+			t.Fatalf("Failed to seed event: %v", err)
+		}
+	}
+
+	ticketTypeID := uuid.New()
+	if err := db.Create(&entity.TicketType{
+		ID:                ticketTypeID,
+		EventID:           eventID,
+		Name:              "GA",
+		Price:             decimal.NewFromInt(100),
+		InitialQuantity:   5,
+		RemainingQuantity: 5,
+	}).Error; err != nil {
+		t.Fatalf("Failed to seed ticket type: %v", err)
+	}
+
+	orders := service.NewOrderService(db, repository.NewOrderRepository(db), nil, nil)
+	checkIn := service.NewCheckInService(db, repository.NewTransferRepository(db))
+	sale := entity.BoxOfficeSale{PaymentMethod: entity.PaymentMethodCash}
+
+	order, err := orders.PlaceBoxOfficeOrder(ctx, cashierID, sale, []service.RequestItem{{TicketTypeID: ticketTypeID, Quantity: 2}})
+	if err != nil {
+		t.Fatalf("PlaceBoxOfficeOrder failed: %v", err)
+	}
+	code := order.Tickets[0].TicketCode
+
+	if _, err := checkIn.CheckIn(ctx, otherEventID, entity.CheckInRequest{TicketCode: code}); err == nil {
+		t.Error("Expected error when scanning ticket at another event")
+	}
+	ticket, err := checkIn.CheckIn(ctx, eventID, entity.CheckInRequest{TicketCode: code})
+	if err != nil {
+		t.Fatalf("CheckIn failed: %v", err)
+	}
+	if ticket.Status != entity.TicketStatusUsed {
+		t.Errorf("Expected ticket USED, got %s", ticket.Status)
+	}
+	if _, err := checkIn.CheckIn(ctx, eventID, entity.CheckInRequest{TicketCode: code}); err == nil {
+		t.Error("Expected error when scanning the same ticket twice")
+	}
+
+	// Đơn có vé đã soát không hoàn được
+	if err := orders.RefundOrder(ctx, order.ID); err == nil {
+		t.Error("Expected error when refunding an order with a scanned ticket")
+	}
+
+	refundable, err := orders.PlaceBoxOfficeOrder(ctx, cashierID, sale, []service.RequestItem{{TicketTypeID: ticketTypeID, Quantity: 2}})
+	if err != nil {
+		t.Fatalf("PlaceBoxOfficeOrder failed: %v", err)
+	}
+	if err := orders.RefundOrder(ctx, refundable.ID); err != nil {
+		t.Fatalf("RefundOrder failed: %v", err)
+	}
+	if err := orders.RefundOrder(ctx, refundable.ID); err == nil {
+		t.Error("Expected error when refunding twice")
+	}
+
+	var refunded entity.Order
+	db.First(&refunded, "id = ?", refundable.ID)
+	if refunded.Status != entity.OrderStatusRefunded {
+		t.Errorf("Expected REFUNDED, got %s", refunded.Status)
+	}
+	var remaining int
+	db.Model(&entity.TicketType{}).Where("id = ?", ticketTypeID).Select("remaining_quantity").Scan(&remaining)
+	if remaining != 3 {
+		t.Errorf("Expected refunded tickets back in stock (3), got %d", remaining)
+	}
+	if _, err := checkIn.CheckIn(ctx, eventID, entity.CheckInRequest{TicketCode: refundable.Tickets[0].TicketCode}); err == nil {
+		t.Error("Expected refunded ticket to be rejected at the gate")
+	}
+}
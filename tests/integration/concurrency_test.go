@@ -36,14 +36,15 @@ func TestConcurrentOrderPlacement(t *testing.T) {
 	db := setupDB()
 
 	// Clean up schema to remove any zombie columns from previous runs
-	db.Migrator().DropTable(&entity.OrderItem{}, &entity.Order{}, &entity.TicketType{})
+	db.Migrator().DropTable(&entity.Ticket{}, &entity.OrderItem{}, &entity.Order{}, &entity.TicketType{})
 
 	// Migration
-	if err := db.AutoMigrate(&entity.TicketType{}, &entity.Order{}, &entity.OrderItem{}); err != nil {
+	if err := db.AutoMigrate(&entity.TicketType{}, &entity.Order{}, &entity.OrderItem{}, &entity.Ticket{}); err != nil {
 		t.Fatalf("Failed to migrate: %v", err)
 	}
 
 	// Clean up previous test data
+	db.Exec("DELETE FROM tickets")
 	db.Exec("DELETE FROM order_items")
 	db.Exec("DELETE FROM orders")
 	db.Exec("DELETE FROM ticket_types")
@@ -82,7 +83,7 @@ func TestConcurrentOrderPlacement(t *testing.T) {
 
 	// Initialize Service
 	repo := repository.NewOrderRepository(db)
-	svc := service.NewOrderService(db, repo)
+	svc := service.NewOrderService(db, repo, nil, nil)
 
 	// Simulation: 20 concurrenct requests, each buying 1 ticket.
 	// Only 10 should succeed.
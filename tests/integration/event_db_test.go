@@ -43,7 +43,7 @@ func cleanupEvents(t *testing.T, db *gorm.DB, slugs ...string) {
 func TestCreateEvent_DB_Success(t *testing.T) {
 	db := setupTestDB(t)
 	eventRepo := repository.NewEventRepository(db)
-	svc := service.NewEventService(eventRepo)
+	svc := service.NewEventService(eventRepo, repository.NewVenueRepository(db))
 	ctx := context.Background()
 
 	slug := fmt.Sprintf("concert-db-success-%d", time.Now().UnixNano())
@@ -93,7 +93,7 @@ func TestCreateEvent_DB_Success(t *testing.T) {
 func TestCreateEventWithTickets_DB_Success(t *testing.T) {
 	db := setupTestDB(t)
 	eventRepo := repository.NewEventRepository(db)
-	svc := service.NewEventService(eventRepo)
+	svc := service.NewEventService(eventRepo, repository.NewVenueRepository(db))
 	ctx := context.Background()
 
 	slug := fmt.Sprintf("festival-db-success-%d", time.Now().UnixNano())
@@ -190,7 +190,7 @@ func TestCreateEventWithTickets_DB_Success(t *testing.T) {
 func TestGetEventBySlug_DB(t *testing.T) {
 	db := setupTestDB(t)
 	eventRepo := repository.NewEventRepository(db)
-	svc := service.NewEventService(eventRepo)
+	svc := service.NewEventService(eventRepo, repository.NewVenueRepository(db))
 	ctx := context.Background()
 
 	slug := fmt.Sprintf("cinema-night-%d", time.Now().UnixNano())
@@ -230,7 +230,7 @@ func TestGetEventBySlug_DB(t *testing.T) {
 func TestListEvents_DB(t *testing.T) {
 	db := setupTestDB(t)
 	eventRepo := repository.NewEventRepository(db)
-	svc := service.NewEventService(eventRepo)
+	svc := service.NewEventService(eventRepo, repository.NewVenueRepository(db))
 	ctx := context.Background()
 
 	// Create 3 events
@@ -257,10 +257,11 @@ func TestListEvents_DB(t *testing.T) {
 	defer cleanupEvents(t, db, slugs...)
 
 	// List events
-	events, err := svc.ListEvents(ctx, 10, 0)
+	page, err := svc.ListEvents(ctx, entity.EventFilter{Limit: 100})
 	if err != nil {
 		t.Fatalf("ListEvents failed: %v", err)
 	}
+	events := page.Events
 
 	if len(events) == 0 {
 		t.Fatal("Expected events to be returned")
@@ -285,7 +286,7 @@ func TestListEvents_DB(t *testing.T) {
 func TestCreateEvent_DB_DuplicateSlug(t *testing.T) {
 	db := setupTestDB(t)
 	eventRepo := repository.NewEventRepository(db)
-	svc := service.NewEventService(eventRepo)
+	svc := service.NewEventService(eventRepo, repository.NewVenueRepository(db))
 	ctx := context.Background()
 
 	slug := fmt.Sprintf("duplicate-slug-%d", time.Now().UnixNano())
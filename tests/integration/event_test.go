@@ -2,12 +2,14 @@ package integration
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
 	"github.com/yourname/ticketing-system/internal/core/service"
 	"gorm.io/gorm"
 )
@@ -17,6 +19,8 @@ type mockEventRepository struct {
 	events      map[uuid.UUID]*entity.Event
 	ticketTypes map[uuid.UUID]*entity.TicketType
 	slugs       map[string]uuid.UUID
+	orders      map[uuid.UUID][]entity.Order // event -> đơn có item của event
+	orgID       *uuid.UUID                   // Khác nil: bản sao chỉ thấy event của tổ chức
 }
 
 func NewMockEventRepository() *mockEventRepository {
@@ -24,17 +28,32 @@ func NewMockEventRepository() *mockEventRepository {
 		events:      make(map[uuid.UUID]*entity.Event),
 		ticketTypes: make(map[uuid.UUID]*entity.TicketType),
 		slugs:       make(map[string]uuid.UUID),
+		orders:      make(map[uuid.UUID][]entity.Order),
 	}
 }
 
+func (m *mockEventRepository) ForOrganization(orgID uuid.UUID) port.EventRepositoryPort {
+	scoped := *m
+	scoped.orgID = &orgID
+	return &scoped
+}
+
+// inTenant cho biết event có nằm trong tổ chức của bản sao không
+func (m *mockEventRepository) inTenant(event *entity.Event) bool {
+	return m.orgID == nil || (event.OrganizationID != nil && *event.OrganizationID == *m.orgID)
+}
+
 func (m *mockEventRepository) CreateEvent(ctx context.Context, event *entity.Event) error {
+	if m.orgID != nil {
+		event.OrganizationID = m.orgID
+	}
 	m.events[event.ID] = event
 	m.slugs[event.Slug] = event.ID
 	return nil
 }
 
 func (m *mockEventRepository) GetEventByID(ctx context.Context, id uuid.UUID) (*entity.Event, error) {
-	if event, ok := m.events[id]; ok {
+	if event, ok := m.events[id]; ok && m.inTenant(event) {
 		return event, nil
 	}
 	return nil, gorm.ErrRecordNotFound
@@ -42,19 +61,34 @@ func (m *mockEventRepository) GetEventByID(ctx context.Context, id uuid.UUID) (*
 
 func (m *mockEventRepository) GetEventBySlug(ctx context.Context, slug string) (*entity.Event, error) {
 	if id, ok := m.slugs[slug]; ok {
-		if event, ok := m.events[id]; ok {
+		if event, ok := m.events[id]; ok && m.inTenant(event) {
 			return event, nil
 		}
 	}
 	return nil, gorm.ErrRecordNotFound
 }
 
-func (m *mockEventRepository) ListEvents(ctx context.Context, limit int, offset int) ([]entity.Event, error) {
+func (m *mockEventRepository) ListEvents(ctx context.Context, filter entity.EventFilter) ([]entity.Event, int64, error) {
 	events := make([]entity.Event, 0)
 	for _, event := range m.events {
+		if filter.Status != "" && event.Status != filter.Status {
+			continue
+		}
+		if !m.inTenant(event) {
+			continue
+		}
 		events = append(events, *event)
 	}
-	return events, nil
+	total := int64(len(events))
+
+	if filter.Offset >= len(events) {
+		return []entity.Event{}, total, nil
+	}
+	events = events[filter.Offset:]
+	if filter.Limit > 0 && filter.Limit < len(events) {
+		events = events[:filter.Limit]
+	}
+	return events, total, nil
 }
 
 func (m *mockEventRepository) UpdateEvent(ctx context.Context, event *entity.Event) error {
@@ -79,11 +113,42 @@ func (m *mockEventRepository) CreateTicketTypes(ctx context.Context, ticketTypes
 	return nil
 }
 
+func (m *mockEventRepository) ListTicketTypesByEvent(ctx context.Context, eventID uuid.UUID) ([]entity.TicketType, error) {
+	ticketTypes := make([]entity.TicketType, 0)
+	for _, tt := range m.ticketTypes {
+		if tt.EventID == eventID {
+			ticketTypes = append(ticketTypes, *tt)
+		}
+	}
+	return ticketTypes, nil
+}
+
+func (m *mockEventRepository) GetTicketTypesByIDs(ctx context.Context, ids []uuid.UUID) ([]entity.TicketType, error) {
+	ticketTypes := make([]entity.TicketType, 0, len(ids))
+	for _, id := range ids {
+		if tt, ok := m.ticketTypes[id]; ok {
+			ticketTypes = append(ticketTypes, *tt)
+		}
+	}
+	return ticketTypes, nil
+}
+
+func (m *mockEventRepository) ListOrdersByEvent(ctx context.Context, eventID uuid.UUID, cursor *entity.PageCursor, limit int) ([]entity.Order, error) {
+	if _, err := m.GetEventByID(ctx, eventID); err != nil {
+		return nil, err
+	}
+	orders := m.orders[eventID]
+	if limit < len(orders) {
+		orders = orders[:limit]
+	}
+	return orders, nil
+}
+
 // Tests
 func TestCreateEvent_Success(t *testing.T) {
 	// Arrange
 	mockRepo := NewMockEventRepository()
-	svc := service.NewEventService(mockRepo)
+	svc := service.NewEventService(mockRepo, NewMockVenueRepository())
 	ctx := context.Background()
 
 	req := entity.CreateEventRequest{
@@ -126,7 +191,7 @@ func TestCreateEvent_Success(t *testing.T) {
 
 func TestCreateEvent_EmptyName(t *testing.T) {
 	mockRepo := NewMockEventRepository()
-	svc := service.NewEventService(mockRepo)
+	svc := service.NewEventService(mockRepo, NewMockVenueRepository())
 	ctx := context.Background()
 
 	req := entity.CreateEventRequest{
@@ -151,7 +216,7 @@ func TestCreateEvent_EmptyName(t *testing.T) {
 
 func TestCreateEvent_InvalidTimeRange(t *testing.T) {
 	mockRepo := NewMockEventRepository()
-	svc := service.NewEventService(mockRepo)
+	svc := service.NewEventService(mockRepo, NewMockVenueRepository())
 	ctx := context.Background()
 
 	startTime := time.Now().Add(25 * time.Hour)
@@ -179,7 +244,7 @@ func TestCreateEvent_InvalidTimeRange(t *testing.T) {
 
 func TestCreateEvent_DuplicateSlug(t *testing.T) {
 	mockRepo := NewMockEventRepository()
-	svc := service.NewEventService(mockRepo)
+	svc := service.NewEventService(mockRepo, NewMockVenueRepository())
 	ctx := context.Background()
 
 	req1 := entity.CreateEventRequest{
@@ -218,7 +283,7 @@ func TestCreateEvent_DuplicateSlug(t *testing.T) {
 
 func TestCreateEventWithTickets_Success(t *testing.T) {
 	mockRepo := NewMockEventRepository()
-	svc := service.NewEventService(mockRepo)
+	svc := service.NewEventService(mockRepo, NewMockVenueRepository())
 	ctx := context.Background()
 
 	eventReq := entity.CreateEventRequest{
@@ -302,7 +367,7 @@ func TestCreateEventWithTickets_Success(t *testing.T) {
 
 func TestCreateEventWithTickets_InvalidTicketPrice(t *testing.T) {
 	mockRepo := NewMockEventRepository()
-	svc := service.NewEventService(mockRepo)
+	svc := service.NewEventService(mockRepo, NewMockVenueRepository())
 	ctx := context.Background()
 
 	eventReq := entity.CreateEventRequest{
@@ -335,7 +400,7 @@ func TestCreateEventWithTickets_InvalidTicketPrice(t *testing.T) {
 
 func TestCreateEventWithTickets_InvalidTicketQuantity(t *testing.T) {
 	mockRepo := NewMockEventRepository()
-	svc := service.NewEventService(mockRepo)
+	svc := service.NewEventService(mockRepo, NewMockVenueRepository())
 	ctx := context.Background()
 
 	eventReq := entity.CreateEventRequest{
@@ -368,7 +433,7 @@ func TestCreateEventWithTickets_InvalidTicketQuantity(t *testing.T) {
 
 func TestGetEvent_Success(t *testing.T) {
 	mockRepo := NewMockEventRepository()
-	svc := service.NewEventService(mockRepo)
+	svc := service.NewEventService(mockRepo, NewMockVenueRepository())
 	ctx := context.Background()
 
 	// Create event first
@@ -397,7 +462,7 @@ func TestGetEvent_Success(t *testing.T) {
 
 func TestGetEventBySlug_Success(t *testing.T) {
 	mockRepo := NewMockEventRepository()
-	svc := service.NewEventService(mockRepo)
+	svc := service.NewEventService(mockRepo, NewMockVenueRepository())
 	ctx := context.Background()
 
 	// Create event first
@@ -430,7 +495,7 @@ func TestGetEventBySlug_Success(t *testing.T) {
 
 func TestListEvents(t *testing.T) {
 	mockRepo := NewMockEventRepository()
-	svc := service.NewEventService(mockRepo)
+	svc := service.NewEventService(mockRepo, NewMockVenueRepository())
 	ctx := context.Background()
 
 	// Create multiple events
@@ -447,13 +512,73 @@ func TestListEvents(t *testing.T) {
 	}
 
 	// List events
-	events, err := svc.ListEvents(ctx, 10, 0)
+	page, err := svc.ListEvents(ctx, entity.EventFilter{Limit: 10})
+
+	if err != nil {
+		t.Fatalf("ListEvents failed: %v", err)
+	}
+
+	if len(page.Events) != 5 {
+		t.Errorf("Expected 5 events, got %d", len(page.Events))
+	}
+
+	if page.Total != 5 {
+		t.Errorf("Expected total 5, got %d", page.Total)
+	}
+}
+
+func TestListEvents_PaginationKeepsTotal(t *testing.T) {
+	mockRepo := NewMockEventRepository()
+	svc := service.NewEventService(mockRepo, NewMockVenueRepository())
+	ctx := context.Background()
 
+	for i := 1; i <= 5; i++ {
+		req := entity.CreateEventRequest{
+			Name:      fmt.Sprintf("Paged Event %d", i),
+			Slug:      fmt.Sprintf("paged-event-%d", i),
+			Location:  "Nhà hát Lớn",
+			StartTime: time.Now().Add(24 * time.Hour),
+			EndTime:   time.Now().Add(25 * time.Hour),
+		}
+		if _, err := svc.CreateEvent(ctx, req); err != nil {
+			t.Fatalf("CreateEvent failed: %v", err)
+		}
+	}
+
+	page, err := svc.ListEvents(ctx, entity.EventFilter{Limit: 2, Offset: 4})
 	if err != nil {
 		t.Fatalf("ListEvents failed: %v", err)
 	}
 
-	if len(events) != 5 {
-		t.Errorf("Expected 5 events, got %d", len(events))
+	if len(page.Events) != 1 {
+		t.Errorf("Expected 1 event on last page, got %d", len(page.Events))
+	}
+
+	if page.Total != 5 {
+		t.Errorf("Expected total 5, got %d", page.Total)
+	}
+}
+
+func TestListEvents_InvalidFilter(t *testing.T) {
+	mockRepo := NewMockEventRepository()
+	svc := service.NewEventService(mockRepo, NewMockVenueRepository())
+	ctx := context.Background()
+
+	from := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(-24 * time.Hour)
+	minPrice := decimal.NewFromInt(500000)
+	maxPrice := decimal.NewFromInt(100000)
+
+	cases := map[string]entity.EventFilter{
+		"unknown sort":        {Sort: "cheapest"},
+		"unknown status":      {Status: "SOLD_OUT"},
+		"reversed date range": {StartFrom: &from, StartTo: &to},
+		"reversed price":      {MinPrice: &minPrice, MaxPrice: &maxPrice},
+	}
+
+	for name, filter := range cases {
+		if _, err := svc.ListEvents(ctx, filter); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
 	}
 }
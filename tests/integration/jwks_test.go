@@ -0,0 +1,176 @@
+package integration
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/yourname/ticketing-system/pkg/auth"
+)
+
+type testKeyEntry struct {
+	ID             string     `json:"kid"`
+	Algorithm      string     `json:"alg"`
+	PrivateKeyFile string     `json:"private_key_file"`
+	ActiveFrom     time.Time  `json:"active_from"`
+	RetireAt       *time.Time `json:"retire_at,omitempty"`
+}
+
+// writeTestKeys sinh một khóa RSA ("old") và một khóa Ed25519 ("new") dạng PEM PKCS#8 trong dir.
+func writeTestKeys(t *testing.T, dir string) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	for name, key := range map[string]interface{}{"old.pem": rsaKey, "new.pem": edKey} {
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			t.Fatalf("MarshalPKCS8PrivateKey failed: %v", err)
+		}
+		data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatalf("WriteFile failed: %v", err)
+		}
+	}
+}
+
+// loadTestKeySet ghi manifest với lịch xoay vòng cho trước rồi nạp lại qua auth.LoadKeySet.
+func loadTestKeySet(t *testing.T, dir string, entries []testKeyEntry) *auth.KeySet {
+	data, err := json.Marshal(map[string]interface{}{"keys": entries})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	manifest := filepath.Join(dir, "keys.json")
+	if err := os.WriteFile(manifest, data, 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	keys, err := auth.LoadKeySet(manifest, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("LoadKeySet failed: %v", err)
+	}
+	return keys
+}
+
+func TestKeySet_RotationKeepsRetiringKeyUntilTokensExpire(t *testing.T) {
+	dir := t.TempDir()
+	writeTestKeys(t, dir)
+	now := time.Now()
+	later := now.Add(time.Hour)
+
+	// Trước khi xoay: khóa RSA đang ký, khóa Ed25519 đã lên lịch nhưng chưa tới giờ
+	before := loadTestKeySet(t, dir, []testKeyEntry{
+		{ID: "old", Algorithm: auth.AlgRS256, PrivateKeyFile: "old.pem", ActiveFrom: now.Add(-24 * time.Hour), RetireAt: &later},
+		{ID: "new", Algorithm: auth.AlgEdDSA, PrivateKeyFile: "new.pem", ActiveFrom: later},
+	})
+	token, _, err := auth.GenerateToken("user-1", "user", before, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateToken failed: %v", err)
+	}
+	if len(before.JWKS(now).Keys) != 2 {
+		t.Error("Expected JWKS to publish the scheduled key ahead of rotation")
+	}
+
+	// Vừa xoay: khóa mới ký, token cũ ký bằng RSA vẫn hợp lệ tới khi hết hạn
+	retired := now.Add(-time.Minute)
+	after := loadTestKeySet(t, dir, []testKeyEntry{
+		{ID: "old", Algorithm: auth.AlgRS256, PrivateKeyFile: "old.pem", ActiveFrom: now.Add(-24 * time.Hour), RetireAt: &retired},
+		{ID: "new", Algorithm: auth.AlgEdDSA, PrivateKeyFile: "new.pem", ActiveFrom: retired},
+	})
+	if key, err := after.SigningKey(now); err != nil || key.ID != "new" {
+		t.Fatalf("Expected new key to sign after rotation, got %v %v", key, err)
+	}
+	if _, err := auth.ValidateToken(token, after); err != nil {
+		t.Errorf("Expected token signed by retiring key to validate, got %v", err)
+	}
+	fresh, _, err := auth.GenerateToken("user-1", "user", after, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateToken failed: %v", err)
+	}
+	if parsed, _, _ := jwt.NewParser().ParseUnverified(fresh, &auth.Claims{}); parsed.Header["kid"] != "new" || parsed.Method.Alg() != auth.AlgEdDSA {
+		t.Errorf("Expected token signed with kid new/EdDSA, got %v", parsed.Header)
+	}
+
+	// Quá RetireAt + TTL token dài nhất: khóa cũ bị gỡ khỏi JWKS và không còn kiểm tra được
+	long := now.Add(-time.Hour)
+	expired := loadTestKeySet(t, dir, []testKeyEntry{
+		{ID: "old", Algorithm: auth.AlgRS256, PrivateKeyFile: "old.pem", ActiveFrom: now.Add(-24 * time.Hour), RetireAt: &long},
+		{ID: "new", Algorithm: auth.AlgEdDSA, PrivateKeyFile: "new.pem", ActiveFrom: long},
+	})
+	if _, err := auth.ValidateToken(token, expired); err == nil {
+		t.Error("Expected key to stop verifying once its tokens have expired")
+	}
+	jwks := expired.JWKS(now)
+	if len(jwks.Keys) != 1 || jwks.Keys[0].KeyID != "new" || jwks.Keys[0].KeyType != "OKP" || jwks.Keys[0].X == "" {
+		t.Errorf("Unexpected JWKS: %+v", jwks)
+	}
+}
+
+func TestKeySet_JWKSPublishesRSAKey(t *testing.T) {
+	dir := t.TempDir()
+	writeTestKeys(t, dir)
+	keys := loadTestKeySet(t, dir, []testKeyEntry{
+		{ID: "old", Algorithm: auth.AlgRS256, PrivateKeyFile: "old.pem", ActiveFrom: time.Now().Add(-time.Hour)},
+	})
+
+	jwks := keys.JWKS(time.Now())
+	if len(jwks.Keys) != 1 {
+		t.Fatalf("Expected 1 key, got %d", len(jwks.Keys))
+	}
+	k := jwks.Keys[0]
+	if k.KeyType != "RSA" || k.Algorithm != auth.AlgRS256 || k.Use != "sig" || k.N == "" || k.E != "AQAB" {
+		t.Errorf("Unexpected RSA JWK: %+v", k)
+	}
+}
+
+func TestLoadKeySet_RejectsAlgorithmMismatch(t *testing.T) {
+	dir := t.TempDir()
+	writeTestKeys(t, dir)
+	data, _ := json.Marshal(map[string]interface{}{"keys": []testKeyEntry{
+		{ID: "old", Algorithm: auth.AlgEdDSA, PrivateKeyFile: "old.pem", ActiveFrom: time.Now()},
+	}})
+	manifest := filepath.Join(dir, "keys.json")
+	if err := os.WriteFile(manifest, data, 0o600); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if _, err := auth.LoadKeySet(manifest, 15*time.Minute); err == nil {
+		t.Error("Expected error when RSA key is declared as EdDSA")
+	}
+}
+
+func TestValidateToken_RejectsUnknownKidAndHMAC(t *testing.T) {
+	other, err := auth.GenerateEphemeralKeySet(15 * time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateEphemeralKeySet failed: %v", err)
+	}
+	token, _, err := auth.GenerateToken("user-1", "user", other, 15*time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateToken failed: %v", err)
+	}
+	if _, err := auth.ValidateToken(token, testJWTKeys); err == nil {
+		t.Error("Expected token from unknown key to be rejected")
+	}
+
+	// Token HS256 mang kid hợp lệ (tấn công đổi alg) vẫn bị từ chối
+	kid := testJWTKeys.JWKS(time.Now()).Keys[0].KeyID
+	hmac := jwt.NewWithClaims(jwt.SigningMethodHS256, &auth.Claims{UserID: "user-1", Role: "admin"})
+	hmac.Header["kid"] = kid
+	signed, err := hmac.SignedString([]byte("guessed-secret"))
+	if err != nil {
+		t.Fatalf("SignedString failed: %v", err)
+	}
+	if _, err := auth.ValidateToken(signed, testJWTKeys); err == nil {
+		t.Error("Expected HS256 token to be rejected")
+	}
+}
@@ -0,0 +1,138 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+func TestLogin_LocksAccountAfterRepeatedFailures(t *testing.T) {
+	svc, _, _, user := newTestAuthService(t)
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		_, err := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "wrong", IP: "10.0.0.1"})
+		var locked *service.LoginLockedError
+		if err == nil || errors.As(err, &locked) {
+			t.Fatalf("Attempt %d: expected invalid credentials, got %v", i+1, err)
+		}
+	}
+
+	// Lần sai thứ 5 chạm ngưỡng: khóa 30s
+	if _, err := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "wrong", IP: "10.0.0.1"}); err == nil {
+		t.Fatal("Expected invalid credentials")
+	}
+
+	// Đang khóa thì mật khẩu đúng cũng bị từ chối, kể cả từ IP khác và email viết hoa
+	_, err := svc.Login(ctx, entity.LoginRequest{Email: "  ALICE@example.com", Password: "secret123", IP: "10.0.0.2"})
+	var locked *service.LoginLockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("Expected LoginLockedError, got %v", err)
+	}
+	if locked.RetryAfter <= 0 || locked.RetryAfter > 30*time.Second {
+		t.Errorf("Expected lock up to 30s, got %s", locked.RetryAfter)
+	}
+
+	lockouts, _ := svc.ListLockouts(ctx)
+	if len(lockouts) != 1 || lockouts[0].Kind != entity.LoginThrottleAccount || lockouts[0].Key != "alice@example.com" {
+		t.Fatalf("Expected account lockout listed, got %+v", lockouts)
+	}
+
+	// Admin mở khóa thì đăng nhập được ngay
+	if err := svc.ClearLockout(ctx, entity.LoginThrottleAccount, "Alice@example.com"); err != nil {
+		t.Fatalf("ClearLockout failed: %v", err)
+	}
+	if _, err := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "secret123", IP: "10.0.0.2"}); err != nil {
+		t.Errorf("Expected login after clearing lockout, got %v", err)
+	}
+	if err := svc.ClearLockout(ctx, "USER", "x"); err == nil {
+		t.Error("Expected error for unknown lockout kind")
+	}
+}
+
+func TestLogin_UnknownEmailBehavesLikeWrongPassword(t *testing.T) {
+	svc, _, _, user := newTestAuthService(t)
+	ctx := context.Background()
+
+	_, unknownErr := svc.Login(ctx, entity.LoginRequest{Email: "ghost@example.com", Password: "wrong"})
+	_, wrongErr := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "wrong"})
+	if unknownErr == nil || wrongErr == nil || unknownErr.Error() != wrongErr.Error() {
+		t.Fatalf("Expected identical errors, got %v / %v", unknownErr, wrongErr)
+	}
+
+	// Email không tồn tại cũng bị khóa như email thật
+	for i := 0; i < 4; i++ {
+		svc.Login(ctx, entity.LoginRequest{Email: "ghost@example.com", Password: "wrong"})
+	}
+	_, err := svc.Login(ctx, entity.LoginRequest{Email: "ghost@example.com", Password: "wrong"})
+	var locked *service.LoginLockedError
+	if !errors.As(err, &locked) {
+		t.Errorf("Expected unknown email to be locked as well, got %v", err)
+	}
+}
+
+func TestLogin_IPLockCoversAllAccounts(t *testing.T) {
+	svc, _, _, user := newTestAuthService(t)
+	ctx := context.Background()
+
+	// Một IP dò nhiều email khác nhau (mỗi email dưới ngưỡng riêng)
+	for i := 0; i < 20; i++ {
+		email := string(rune('a'+i)) + "@example.com"
+		svc.Login(ctx, entity.LoginRequest{Email: email, Password: "wrong", IP: "203.0.113.9"})
+	}
+
+	_, err := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "secret123", IP: "203.0.113.9"})
+	var locked *service.LoginLockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("Expected IP to be locked, got %v", err)
+	}
+	if _, err := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "secret123", IP: "198.51.100.1"}); err != nil {
+		t.Errorf("Expected other IP to log in, got %v", err)
+	}
+}
+
+func TestLogin_SuccessResetsAccountFailures(t *testing.T) {
+	svc, _, _, user := newTestAuthService(t)
+	ctx := context.Background()
+
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 4; i++ {
+			svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "wrong"})
+		}
+		if _, err := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "secret123"}); err != nil {
+			t.Fatalf("Round %d: expected login to succeed, got %v", round, err)
+		}
+	}
+}
+
+func TestLogin_LockoutDoublesOnEachFurtherFailure(t *testing.T) {
+	_, _, _, user := newTestAuthService(t)
+	users := &mockUserRepository{users: map[uuid.UUID]*entity.User{user.ID: user}}
+	throttles := newMockLoginThrottleRepository()
+	svc := service.NewAuthService(users, newMockTokenRepository(), throttles, newMockMFARepository(), &mockMailer{}, testJWTKeys, testAuthConfig)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "wrong"})
+	}
+
+	for _, want := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute} {
+		// Hết thời gian khóa trước, thử sai tiếp
+		throttles.SetLockedUntil(ctx, entity.LoginThrottleAccount, user.Email, time.Now().Add(-time.Second))
+		svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "wrong"})
+
+		_, err := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "secret123"})
+		var locked *service.LoginLockedError
+		if !errors.As(err, &locked) {
+			t.Fatalf("Expected LoginLockedError, got %v", err)
+		}
+		if locked.RetryAfter <= want-time.Second || locked.RetryAfter > want {
+			t.Errorf("Expected lock of %s, got %s", want, locked.RetryAfter)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+	"github.com/yourname/ticketing-system/internal/core/service"
+	"github.com/yourname/ticketing-system/pkg/auth"
+)
+
+// newTestMFAService tạo auth service có một user với role cho trước (mật khẩu "secret123").
+func newTestMFAService(t *testing.T, role string, requireAdminMFA bool) (port.AuthServicePort, *entity.User) {
+	hash, err := auth.HashPassword("secret123")
+	if err != nil {
+		t.Fatalf("HashPassword failed: %v", err)
+	}
+	user := &entity.User{ID: uuid.New(), Username: "root", Email: "root@example.com", PasswordHash: hash, Role: role}
+	users := &mockUserRepository{users: map[uuid.UUID]*entity.User{user.ID: user}}
+	cfg := testAuthConfig
+	cfg.RequireAdminMFA = requireAdminMFA
+	return service.NewAuthService(users, newMockTokenRepository(), newMockLoginThrottleRepository(), newMockMFARepository(), &mockMailer{}, testJWTKeys, cfg), user
+}
+
+func totpAt(t *testing.T, secret string, offset time.Duration) string {
+	code, err := auth.TOTPCode(secret, time.Now().Add(offset))
+	if err != nil {
+		t.Fatalf("TOTPCode failed: %v", err)
+	}
+	return code
+}
+
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	// Secret ASCII "12345678901234567890" (RFC 6238 phụ lục B), 6 chữ số cuối của mã 8 số
+	const secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+	for unix, want := range map[int64]string{59: "287082", 1111111109: "081804", 1234567890: "005924", 2000000000: "279037"} {
+		got, err := auth.TOTPCode(secret, time.Unix(unix, 0))
+		if err != nil || got != want {
+			t.Errorf("TOTPCode(%d) = %s, %v; want %s", unix, got, err, want)
+		}
+	}
+
+	if _, ok := auth.ValidateTOTP(secret, "287082", time.Unix(59+30, 0)); !ok {
+		t.Error("Expected previous step to be accepted (clock skew)")
+	}
+	if _, ok := auth.ValidateTOTP(secret, "287082", time.Unix(59+90, 0)); ok {
+		t.Error("Expected code outside skew window to be rejected")
+	}
+}
+
+func TestMFA_OptionalEnrollmentAndTwoStepLogin(t *testing.T) {
+	svc, user := newTestMFAService(t, entity.RoleUser, false)
+	ctx := context.Background()
+
+	enrollment, err := svc.BeginMFAEnrollment(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("BeginMFAEnrollment failed: %v", err)
+	}
+	if len(enrollment.QRCodePNG) == 0 || enrollment.OTPAuthURI == "" || enrollment.Secret == "" {
+		t.Fatalf("Incomplete enrollment: %+v", enrollment)
+	}
+
+	if _, err := svc.ConfirmMFAEnrollment(ctx, user.ID, "000000"); err == nil {
+		t.Fatal("Expected wrong code to be rejected")
+	}
+	codes, err := svc.ConfirmMFAEnrollment(ctx, user.ID, totpAt(t, enrollment.Secret, 0))
+	if err != nil {
+		t.Fatalf("ConfirmMFAEnrollment failed: %v", err)
+	}
+	if len(codes) != 10 {
+		t.Fatalf("Expected 10 recovery codes, got %d", len(codes))
+	}
+	if _, err := svc.BeginMFAEnrollment(ctx, user.ID); err == nil {
+		t.Error("Expected error re-enrolling with 2FA enabled")
+	}
+
+	// Đăng nhập giờ cần bước 2
+	result, err := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if !result.MFARequired || result.MFAEnrollmentRequired || result.TokenPair != nil || result.ChallengeToken == "" {
+		t.Fatalf("Expected MFA challenge only, got %+v", result)
+	}
+
+	// Mã đã dùng lúc bật 2FA không dùng lại được
+	if _, err := svc.VerifyMFALogin(ctx, entity.MFALoginRequest{ChallengeToken: result.ChallengeToken, Code: totpAt(t, enrollment.Secret, 0)}); err == nil {
+		t.Fatal("Expected replayed TOTP code to be rejected")
+	}
+	session, err := svc.VerifyMFALogin(ctx, entity.MFALoginRequest{ChallengeToken: result.ChallengeToken, Code: totpAt(t, enrollment.Secret, 30*time.Second)})
+	if err != nil {
+		t.Fatalf("VerifyMFALogin failed: %v", err)
+	}
+	if session.AccessToken == "" || session.RefreshToken == "" {
+		t.Fatalf("Expected token pair, got %+v", session)
+	}
+
+	// Challenge chỉ dùng một lần
+	if _, err := svc.VerifyMFALogin(ctx, entity.MFALoginRequest{ChallengeToken: result.ChallengeToken, RecoveryCode: codes[0]}); err == nil {
+		t.Error("Expected used challenge to be rejected")
+	}
+
+	// Mã khôi phục (gõ hoa, bỏ gạch) dùng được đúng một lần
+	for i, wantOK := range []bool{true, false} {
+		result, _ := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "secret123"})
+		_, err := svc.VerifyMFALogin(ctx, entity.MFALoginRequest{ChallengeToken: result.ChallengeToken, RecoveryCode: "  " + strings.ToUpper(strings.ReplaceAll(codes[1], "-", ""))})
+		if (err == nil) != wantOK {
+			t.Errorf("Recovery attempt %d: expected ok=%v, got %v", i+1, wantOK, err)
+		}
+	}
+}
+
+func TestMFA_DisableRequiresCurrentCode(t *testing.T) {
+	svc, user := newTestMFAService(t, entity.RoleUser, false)
+	ctx := context.Background()
+
+	enrollment, _ := svc.BeginMFAEnrollment(ctx, user.ID)
+	if _, err := svc.ConfirmMFAEnrollment(ctx, user.ID, totpAt(t, enrollment.Secret, -30*time.Second)); err != nil {
+		t.Fatalf("ConfirmMFAEnrollment failed: %v", err)
+	}
+
+	if err := svc.DisableMFA(ctx, user.ID, "123456"); err == nil {
+		t.Fatal("Expected wrong code to be rejected")
+	}
+	if err := svc.DisableMFA(ctx, user.ID, totpAt(t, enrollment.Secret, 0)); err != nil {
+		t.Fatalf("DisableMFA failed: %v", err)
+	}
+
+	result, err := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "secret123"})
+	if err != nil || result.MFARequired || result.TokenPair == nil {
+		t.Errorf("Expected single-step login after disabling 2FA, got %+v %v", result, err)
+	}
+}
+
+func TestMFA_MandatoryForAdmin(t *testing.T) {
+	svc, admin := newTestMFAService(t, entity.RoleAdmin, true)
+	ctx := context.Background()
+
+	result, err := svc.Login(ctx, entity.LoginRequest{Email: admin.Email, Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if !result.MFARequired || !result.MFAEnrollmentRequired || result.TokenPair != nil {
+		t.Fatalf("Expected enrollment challenge for admin, got %+v", result)
+	}
+
+	// Chưa lấy secret thì chưa xác nhận được
+	if _, err := svc.VerifyMFALogin(ctx, entity.MFALoginRequest{ChallengeToken: result.ChallengeToken, Code: "123456"}); err == nil {
+		t.Fatal("Expected error before enrollment")
+	}
+
+	enrollment, err := svc.BeginMFAEnrollmentWithChallenge(ctx, result.ChallengeToken)
+	if err != nil {
+		t.Fatalf("BeginMFAEnrollmentWithChallenge failed: %v", err)
+	}
+	session, err := svc.VerifyMFALogin(ctx, entity.MFALoginRequest{ChallengeToken: result.ChallengeToken, Code: totpAt(t, enrollment.Secret, 0)})
+	if err != nil {
+		t.Fatalf("VerifyMFALogin failed: %v", err)
+	}
+	if session.AccessToken == "" || len(session.RecoveryCodes) != 10 {
+		t.Fatalf("Expected tokens and recovery codes, got %+v", session)
+	}
+
+	if err := svc.DisableMFA(ctx, admin.ID, totpAt(t, enrollment.Secret, 30*time.Second)); err == nil {
+		t.Error("Expected admin to be unable to disable mandatory 2FA")
+	}
+}
+
+func TestMFA_AdminWithoutEnrollmentCannotRefresh(t *testing.T) {
+	// Phiên admin tạo khi chưa bắt buộc 2FA
+	hash, _ := auth.HashPassword("secret123")
+	admin := &entity.User{ID: uuid.New(), Username: "root", Email: "root@example.com", PasswordHash: hash, Role: entity.RoleAdmin}
+	users := &mockUserRepository{users: map[uuid.UUID]*entity.User{admin.ID: admin}}
+	tokens := newMockTokenRepository()
+	mfa := newMockMFARepository()
+	ctx := context.Background()
+
+	before := service.NewAuthService(users, tokens, newMockLoginThrottleRepository(), mfa, &mockMailer{}, testJWTKeys, testAuthConfig)
+	result, err := before.Login(ctx, entity.LoginRequest{Email: admin.Email, Password: "secret123"})
+	if err != nil || result.TokenPair == nil {
+		t.Fatalf("Expected plain login while 2FA optional, got %+v %v", result, err)
+	}
+
+	cfg := testAuthConfig
+	cfg.RequireAdminMFA = true
+	after := service.NewAuthService(users, tokens, newMockLoginThrottleRepository(), mfa, &mockMailer{}, testJWTKeys, cfg)
+	if _, err := after.Refresh(ctx, result.RefreshToken); err == nil {
+		t.Error("Expected refresh to fail until admin enrolls in 2FA")
+	}
+}
+
+func TestMFA_WrongCodesLockAccount(t *testing.T) {
+	svc, user := newTestMFAService(t, entity.RoleUser, false)
+	ctx := context.Background()
+
+	enrollment, _ := svc.BeginMFAEnrollment(ctx, user.ID)
+	if _, err := svc.ConfirmMFAEnrollment(ctx, user.ID, totpAt(t, enrollment.Secret, 0)); err != nil {
+		t.Fatalf("ConfirmMFAEnrollment failed: %v", err)
+	}
+	result, _ := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "secret123"})
+
+	for i := 0; i < 5; i++ {
+		svc.VerifyMFALogin(ctx, entity.MFALoginRequest{ChallengeToken: result.ChallengeToken, Code: "000000"})
+	}
+	_, err := svc.VerifyMFALogin(ctx, entity.MFALoginRequest{ChallengeToken: result.ChallengeToken, Code: totpAt(t, enrollment.Secret, 30*time.Second)})
+	var locked *service.LoginLockedError
+	if !errors.As(err, &locked) {
+		t.Errorf("Expected LoginLockedError after repeated wrong codes, got %v", err)
+	}
+}
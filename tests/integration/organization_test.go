@@ -0,0 +1,323 @@
+package integration
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourname/ticketing-system/internal/adapter/handler"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+	"github.com/yourname/ticketing-system/internal/core/service"
+	"gorm.io/gorm"
+)
+
+type mockOrganizationRepository struct {
+	orgs  map[uuid.UUID]*entity.Organization
+	roles *mockRoleRepository // Thành viên nằm trong role_assignments
+}
+
+func (m *mockOrganizationRepository) CreateOrganization(ctx context.Context, org *entity.Organization) error {
+	m.orgs[org.ID] = org
+	m.roles.orgs[org.ID] = true
+	return nil
+}
+
+func (m *mockOrganizationRepository) GetOrganizationByID(ctx context.Context, id uuid.UUID) (*entity.Organization, error) {
+	if org, ok := m.orgs[id]; ok {
+		return org, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockOrganizationRepository) GetOrganizationBySlug(ctx context.Context, slug string) (*entity.Organization, error) {
+	for _, org := range m.orgs {
+		if org.Slug == slug {
+			return org, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockOrganizationRepository) ListOrganizations(ctx context.Context) ([]entity.Organization, error) {
+	orgs := []entity.Organization{}
+	for _, org := range m.orgs {
+		orgs = append(orgs, *org)
+	}
+	sort.Slice(orgs, func(i, j int) bool { return orgs[i].Name < orgs[j].Name })
+	return orgs, nil
+}
+
+func (m *mockOrganizationRepository) ListOrganizationsByMember(ctx context.Context, userID uuid.UUID) ([]entity.Organization, error) {
+	seen := map[uuid.UUID]bool{}
+	orgs := []entity.Organization{}
+	for _, a := range m.roles.assignments {
+		if a.UserID != userID || a.ScopeType != entity.ScopeOrganization || seen[*a.ScopeID] {
+			continue
+		}
+		seen[*a.ScopeID] = true
+		orgs = append(orgs, *m.orgs[*a.ScopeID])
+	}
+	return orgs, nil
+}
+
+func (m *mockOrganizationRepository) UpdateOrganization(ctx context.Context, org *entity.Organization) error {
+	m.orgs[org.ID] = org
+	return nil
+}
+
+type orgTestEnv struct {
+	svc    port.OrganizationServicePort
+	access port.AccessServicePort
+	events *mockEventRepository
+	roles  *mockRoleRepository
+	users  *mockUserRepository
+	admin  *entity.User
+}
+
+func newTestOrganizationService(t *testing.T) *orgTestEnv {
+	t.Helper()
+	access, roles, users := newTestAccessService(t)
+	events := NewMockEventRepository()
+	orgs := &mockOrganizationRepository{orgs: map[uuid.UUID]*entity.Organization{}, roles: roles}
+	svc := service.NewOrganizationService(orgs, events, NewMockVenueRepository(), roles, users, access)
+	return &orgTestEnv{svc: svc, access: access, events: events, roles: roles, users: users, admin: addTestUser(users, entity.RoleAdmin)}
+}
+
+// createEvent tạo event của tổ chức và đồng bộ sang mock role repository để tra tổ chức của event
+func (env *orgTestEnv) createEvent(t *testing.T, orgID uuid.UUID, slug string) *entity.Event {
+	t.Helper()
+	event, err := env.svc.CreateEvent(context.Background(), orgID, entity.CreateEventRequest{
+		Name:      "Sự kiện " + slug,
+		Slug:      slug,
+		Location:  "Nhà hát lớn",
+		StartTime: time.Now().Add(24 * time.Hour),
+		EndTime:   time.Now().Add(27 * time.Hour),
+	}, nil)
+	if err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+	env.roles.events[event.ID] = true
+	env.roles.eventOrgs[event.ID] = *event.OrganizationID
+	return event
+}
+
+func TestOrganization_CreateWithOwnerAndTenantIsolation(t *testing.T) {
+	env := newTestOrganizationService(t)
+	ctx := context.Background()
+	owner := addTestUser(env.users, entity.RoleUser)
+	other := addTestUser(env.users, entity.RoleUser)
+
+	orgA, err := env.svc.CreateOrganization(ctx, env.admin.ID, entity.CreateOrganizationRequest{
+		Name: "Live Nation VN", Slug: "live-nation-vn", OwnerEmail: "  " + owner.Email,
+	})
+	if err != nil {
+		t.Fatalf("CreateOrganization failed: %v", err)
+	}
+	if orgA.Settings.Currency != "VND" {
+		t.Errorf("Expected default currency VND, got %q", orgA.Settings.Currency)
+	}
+	orgB, err := env.svc.CreateOrganization(ctx, env.admin.ID, entity.CreateOrganizationRequest{
+		Name: "Other Promoter", Slug: "other-promoter", OwnerEmail: other.Email,
+	})
+	if err != nil {
+		t.Fatalf("CreateOrganization failed: %v", err)
+	}
+	if _, err := env.svc.CreateOrganization(ctx, env.admin.ID, entity.CreateOrganizationRequest{Name: "Dup", Slug: "live-nation-vn"}); err == nil {
+		t.Error("Expected duplicate slug to fail")
+	}
+
+	eventA := env.createEvent(t, orgA.ID, "show-a")
+	eventB := env.createEvent(t, orgB.ID, "show-b")
+	if eventA.OrganizationID == nil || *eventA.OrganizationID != orgA.ID {
+		t.Fatalf("Expected event owned by org A, got %v", eventA.OrganizationID)
+	}
+
+	// Slug là duy nhất toàn hệ thống, kể cả khi trùng với event của tổ chức khác
+	if _, err := env.svc.CreateEvent(ctx, orgA.ID, entity.CreateEventRequest{
+		Name: "Trùng slug", Slug: "show-b", Location: "X",
+		StartTime: time.Now().Add(time.Hour), EndTime: time.Now().Add(2 * time.Hour),
+	}, nil); err == nil {
+		t.Error("Expected slug used by another organization to be rejected")
+	}
+
+	// Tổ chức A chỉ thấy event của mình
+	page, err := env.svc.ListEvents(ctx, orgA.ID, entity.EventFilter{})
+	if err != nil {
+		t.Fatalf("ListEvents failed: %v", err)
+	}
+	if page.Total != 1 || len(page.Events) != 1 || page.Events[0].ID != eventA.ID {
+		t.Fatalf("Expected only org A event, got %+v", page.Events)
+	}
+
+	// Đơn hàng của event tổ chức khác không lộ ra
+	env.events.orders[eventB.ID] = []entity.Order{{ID: uuid.New(), TotalAmount: decimal.NewFromInt(100), CreatedAt: time.Now()}}
+	if _, err := env.svc.ListEventOrders(ctx, orgA.ID, eventB.ID, nil, 10); err == nil {
+		t.Error("Expected orders of another organization's event to be hidden")
+	}
+	orders, err := env.svc.ListEventOrders(ctx, orgB.ID, eventB.ID, nil, 10)
+	if err != nil || len(orders.Orders) != 1 {
+		t.Fatalf("Expected org B to see its order, got %+v, %v", orders, err)
+	}
+
+	// Owner là organizer của tổ chức: sửa được event của tổ chức, không sửa được event tổ chức khác
+	scopeA := entity.PermissionScope{Type: entity.ScopeEvent, ID: eventA.ID}
+	scopeB := entity.PermissionScope{Type: entity.ScopeEvent, ID: eventB.ID}
+	if ok, _ := env.access.HasPermission(ctx, owner.ID, owner.Role, entity.PermEventsWrite, scopeA); !ok {
+		t.Error("Expected org organizer to write own org's event")
+	}
+	if ok, _ := env.access.HasPermission(ctx, owner.ID, owner.Role, entity.PermEventsWrite, scopeB); ok {
+		t.Error("Expected org organizer not to write another org's event")
+	}
+	if ok, _ := env.access.HasPermission(ctx, owner.ID, owner.Role, entity.PermOrgManage, entity.PermissionScope{Type: entity.ScopeOrganization, ID: orgB.ID}); ok {
+		t.Error("Expected org organizer not to manage another organization")
+	}
+
+	mine, _ := env.svc.ListOrganizations(ctx, owner.ID, owner.Role)
+	if len(mine) != 1 || mine[0].ID != orgA.ID {
+		t.Errorf("Expected owner to list only org A, got %+v", mine)
+	}
+	all, _ := env.svc.ListOrganizations(ctx, env.admin.ID, env.admin.Role)
+	if len(all) != 2 {
+		t.Errorf("Expected admin to list all organizations, got %d", len(all))
+	}
+}
+
+func TestOrganization_OrganizerForbiddenOnOtherOrgEvents(t *testing.T) {
+	env := newTestOrganizationService(t)
+	ctx := context.Background()
+	owner := addTestUser(env.users, entity.RoleUser)
+	other := addTestUser(env.users, entity.RoleUser)
+
+	orgA, err := env.svc.CreateOrganization(ctx, env.admin.ID, entity.CreateOrganizationRequest{Name: "Org A", Slug: "org-a", OwnerEmail: owner.Email})
+	if err != nil {
+		t.Fatalf("CreateOrganization failed: %v", err)
+	}
+	orgB, err := env.svc.CreateOrganization(ctx, env.admin.ID, entity.CreateOrganizationRequest{Name: "Org B", Slug: "org-b", OwnerEmail: other.Email})
+	if err != nil {
+		t.Fatalf("CreateOrganization failed: %v", err)
+	}
+	eventA := env.createEvent(t, orgA.ID, "org-a-show")
+	eventB := env.createEvent(t, orgB.ID, "org-b-show")
+
+	// Route /events/:id/* dùng repo không lọc tenant nên ranh giới nằm ở kiểm tra quyền theo event
+	guard := handler.RequirePermission(env.access, entity.PermEventsWrite, handler.EventScope("id"))
+	app := permissionApp(owner, fiber.MethodPut, "/events/:id/resale", guard)
+	if status := doJSON(t, app, fiber.MethodPut, "/events/"+eventA.ID.String()+"/resale", `{}`); status != fiber.StatusOK {
+		t.Errorf("Expected organizer of org A allowed on own event, got %d", status)
+	}
+	if status := doJSON(t, app, fiber.MethodPut, "/events/"+eventB.ID.String()+"/resale", `{}`); status != fiber.StatusForbidden {
+		t.Errorf("Expected organizer of org A forbidden on org B event, got %d", status)
+	}
+
+	// Role gốc organizer (dữ liệu cũ) không cho quyền toàn hệ thống
+	legacy := addTestUser(env.users, entity.RoleOrganizer)
+	app = permissionApp(legacy, fiber.MethodPut, "/events/:id/resale", guard)
+	if status := doJSON(t, app, fiber.MethodPut, "/events/"+eventB.ID.String()+"/resale", `{}`); status != fiber.StatusForbidden {
+		t.Errorf("Expected root-role organizer forbidden on org B event, got %d", status)
+	}
+	app = permissionApp(legacy, fiber.MethodPost, "/events", handler.RequirePermission(env.access, entity.PermEventsWrite, handler.GlobalScope))
+	if status := doJSON(t, app, fiber.MethodPost, "/events", `{}`); status != fiber.StatusForbidden {
+		t.Errorf("Expected root-role organizer unable to create platform events, got %d", status)
+	}
+}
+
+func TestOrganization_Members(t *testing.T) {
+	env := newTestOrganizationService(t)
+	ctx := context.Background()
+	org, err := env.svc.CreateOrganization(ctx, env.admin.ID, entity.CreateOrganizationRequest{Name: "Saigon Shows", Slug: "saigon-shows"})
+	if err != nil {
+		t.Fatalf("CreateOrganization failed: %v", err)
+	}
+	event := env.createEvent(t, org.ID, "saigon-night")
+	staff := addTestUser(env.users, entity.RoleUser)
+
+	for _, role := range []string{entity.RoleScanner, entity.RoleBoxOffice} {
+		if _, err := env.svc.AddMember(ctx, env.admin.ID, org.ID, entity.AddMemberRequest{Email: staff.Email, Role: role}); err != nil {
+			t.Fatalf("AddMember %s failed: %v", role, err)
+		}
+	}
+	if _, err := env.svc.AddMember(ctx, env.admin.ID, org.ID, entity.AddMemberRequest{Email: "nobody@example.com", Role: entity.RoleScanner}); err == nil {
+		t.Error("Expected unknown email to fail")
+	}
+	if _, err := env.svc.AddMember(ctx, env.admin.ID, org.ID, entity.AddMemberRequest{Email: staff.Email, Role: entity.RoleAdmin}); err == nil {
+		t.Error("Expected admin role to be rejected")
+	}
+
+	members, err := env.svc.ListMembers(ctx, org.ID)
+	if err != nil {
+		t.Fatalf("ListMembers failed: %v", err)
+	}
+	if len(members) != 1 || members[0].UserID != staff.ID || len(members[0].Roles) != 2 {
+		t.Fatalf("Expected one member with two roles, got %+v", members)
+	}
+
+	scope := entity.PermissionScope{Type: entity.ScopeEvent, ID: event.ID}
+	if ok, _ := env.access.HasPermission(ctx, staff.ID, staff.Role, entity.PermTicketsScan, scope); !ok {
+		t.Error("Expected org scanner to scan org's event")
+	}
+	if ok, _ := env.access.HasPermission(ctx, staff.ID, staff.Role, entity.PermEventsWrite, scope); ok {
+		t.Error("Expected org scanner not to write events")
+	}
+
+	// Thu hồi một vai trò rồi toàn bộ
+	if err := env.svc.RemoveMember(ctx, org.ID, staff.ID, entity.RoleBoxOffice); err != nil {
+		t.Fatalf("RemoveMember role failed: %v", err)
+	}
+	if ok, _ := env.access.HasPermission(ctx, staff.ID, staff.Role, entity.PermTicketsScan, scope); !ok {
+		t.Error("Expected scanner role to remain")
+	}
+	if err := env.svc.RemoveMember(ctx, org.ID, staff.ID, ""); err != nil {
+		t.Fatalf("RemoveMember failed: %v", err)
+	}
+	if ok, _ := env.access.HasPermission(ctx, staff.ID, staff.Role, entity.PermTicketsScan, scope); ok {
+		t.Error("Expected no permission after removal")
+	}
+	if err := env.svc.RemoveMember(ctx, org.ID, staff.ID, ""); err == nil {
+		t.Error("Expected error removing non-member")
+	}
+}
+
+func TestOrganization_UpdateSettingsValidation(t *testing.T) {
+	env := newTestOrganizationService(t)
+	ctx := context.Background()
+	org, err := env.svc.CreateOrganization(ctx, env.admin.ID, entity.CreateOrganizationRequest{Name: "Hanoi Arts", Slug: "hanoi-arts"})
+	if err != nil {
+		t.Fatalf("CreateOrganization failed: %v", err)
+	}
+
+	updated, err := env.svc.UpdateSettings(ctx, org.ID, entity.OrganizationSettings{
+		Currency:          "usd",
+		ServiceFeePercent: decimal.NewFromFloat(2.5),
+		ServiceFeeFixed:   decimal.NewFromInt(1),
+		Branding: entity.OrganizationBranding{
+			LogoURL: "https://cdn.example.com/logo.png", PrimaryColor: "#FF6600", SupportEmail: "Help@Example.com",
+		},
+	})
+	if err != nil {
+		t.Fatalf("UpdateSettings failed: %v", err)
+	}
+	if updated.Settings.Currency != "USD" || updated.Settings.Branding.SupportEmail != "help@example.com" {
+		t.Errorf("Expected normalized settings, got %+v", updated.Settings)
+	}
+
+	invalid := map[string]entity.OrganizationSettings{
+		"currency":     {Currency: "DONG"},
+		"fee > 100%":   {ServiceFeePercent: decimal.NewFromInt(101)},
+		"negative fee": {ServiceFeeFixed: decimal.NewFromInt(-1)},
+		"logo scheme":  {Branding: entity.OrganizationBranding{LogoURL: "javascript:alert(1)"}},
+		"color":        {Branding: entity.OrganizationBranding{PrimaryColor: "orange"}},
+	}
+	for name, settings := range invalid {
+		if _, err := env.svc.UpdateSettings(ctx, org.ID, settings); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+	if _, err := env.svc.UpdateSettings(ctx, uuid.New(), entity.OrganizationSettings{}); err == nil {
+		t.Error("Expected unknown organization to fail")
+	}
+}
@@ -0,0 +1,177 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+	"github.com/yourname/ticketing-system/pkg/cursor"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	in := entity.PageCursor{
+		Sort:  "start_time",
+		Value: time.Date(2026, 7, 1, 19, 0, 0, 0, time.UTC).Format(time.RFC3339Nano),
+		ID:    uuid.New(),
+	}
+
+	token, err := cursor.Encode(in, "cursor-secret")
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	var out entity.PageCursor
+	if err := cursor.Decode(token, "cursor-secret", &out); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	if out != in {
+		t.Errorf("Expected %+v, got %+v", in, out)
+	}
+}
+
+func TestCursor_RejectsTamperedToken(t *testing.T) {
+	token, err := cursor.Encode(entity.PageCursor{Sort: "start_time", ID: uuid.New()}, "cursor-secret")
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	var out entity.PageCursor
+	if err := cursor.Decode(token, "another-secret", &out); err == nil {
+		t.Error("Expected error when decoding with wrong secret")
+	}
+
+	if err := cursor.Decode("x"+token, "cursor-secret", &out); err == nil {
+		t.Error("Expected error when decoding modified token")
+	}
+}
+
+func TestListEvents_ReturnsNextCursor(t *testing.T) {
+	mockRepo := NewMockEventRepository()
+	svc := service.NewEventService(mockRepo, NewMockVenueRepository())
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		req := entity.CreateEventRequest{
+			Name:      fmt.Sprintf("Cursor Event %d", i),
+			Slug:      fmt.Sprintf("cursor-event-%d", i),
+			Location:  "Nhà hát Lớn",
+			StartTime: time.Now().Add(time.Duration(i) * time.Hour),
+			EndTime:   time.Now().Add(time.Duration(i+1) * time.Hour),
+		}
+		if _, err := svc.CreateEvent(ctx, req); err != nil {
+			t.Fatalf("CreateEvent failed: %v", err)
+		}
+	}
+
+	page, err := svc.ListEvents(ctx, entity.EventFilter{Limit: 2})
+	if err != nil {
+		t.Fatalf("ListEvents failed: %v", err)
+	}
+
+	if len(page.Events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(page.Events))
+	}
+
+	if page.NextCursor == nil {
+		t.Fatal("Expected next cursor when more events remain")
+	}
+
+	if page.NextCursor.ID != page.Events[1].ID {
+		t.Errorf("Expected next cursor to point at last event %s, got %s", page.Events[1].ID, page.NextCursor.ID)
+	}
+
+	if page.PrevCursor != nil {
+		t.Error("Expected no prev cursor on first page")
+	}
+
+	// Cursor chỉ dùng được với đúng bộ lọc đã cấp nó
+	if _, err := svc.ListEvents(ctx, entity.EventFilter{Limit: 2, Cursor: page.NextCursor}); err != nil {
+		t.Errorf("Expected cursor accepted with same filter, got %v", err)
+	}
+	if _, err := svc.ListEvents(ctx, entity.EventFilter{Limit: 2, Query: "rock", Cursor: page.NextCursor}); err == nil {
+		t.Error("Expected cursor rejected with a different query")
+	}
+}
+
+func TestListEvents_CursorSortMismatch(t *testing.T) {
+	mockRepo := NewMockEventRepository()
+	svc := service.NewEventService(mockRepo, NewMockVenueRepository())
+
+	filter := entity.EventFilter{
+		Sort:   entity.EventSortNewest,
+		Cursor: &entity.PageCursor{Sort: string(entity.EventSortStartTime), ID: uuid.New()},
+	}
+
+	if _, err := svc.ListEvents(context.Background(), filter); err == nil {
+		t.Error("Expected error when cursor sort differs from requested sort")
+	}
+}
+
+// failingEventRepository giả lập DB lỗi khi liệt kê event
+type failingEventRepository struct {
+	*mockEventRepository
+}
+
+func (m failingEventRepository) ListEvents(ctx context.Context, filter entity.EventFilter) ([]entity.Event, int64, error) {
+	return nil, 0, errors.New("connection refused")
+}
+
+func TestListEvents_DistinguishesFilterAndRepositoryErrors(t *testing.T) {
+	ctx := context.Background()
+	var filterErr *service.FilterError
+
+	svc := service.NewEventService(NewMockEventRepository(), NewMockVenueRepository())
+	if _, err := svc.ListEvents(ctx, entity.EventFilter{Sort: "price"}); !errors.As(err, &filterErr) {
+		t.Errorf("Expected FilterError for invalid sort, got %v", err)
+	}
+
+	failing := service.NewEventService(failingEventRepository{NewMockEventRepository()}, NewMockVenueRepository())
+	_, err := failing.ListEvents(ctx, entity.EventFilter{})
+	if err == nil || errors.As(err, &filterErr) {
+		t.Errorf("Expected repository error not to be a FilterError, got %v", err)
+	}
+}
+
+func TestListEvents_PopularityUsesOffsetOnly(t *testing.T) {
+	mockRepo := NewMockEventRepository()
+	svc := service.NewEventService(mockRepo, NewMockVenueRepository())
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		req := entity.CreateEventRequest{
+			Name:      fmt.Sprintf("Popular Event %d", i),
+			Slug:      fmt.Sprintf("popular-event-%d", i),
+			Location:  "Nhà hát Lớn",
+			StartTime: time.Now().Add(time.Duration(i) * time.Hour),
+			EndTime:   time.Now().Add(time.Duration(i+1) * time.Hour),
+		}
+		if _, err := svc.CreateEvent(ctx, req); err != nil {
+			t.Fatalf("CreateEvent failed: %v", err)
+		}
+	}
+
+	// Số vé bán đổi giữa các trang nên không cấp cursor, client dùng offset
+	page, err := svc.ListEvents(ctx, entity.EventFilter{Sort: entity.EventSortPopularity, Limit: 2})
+	if err != nil {
+		t.Fatalf("ListEvents failed: %v", err)
+	}
+	if page.NextCursor != nil || page.PrevCursor != nil {
+		t.Error("Expected no cursors for popularity sort")
+	}
+	if _, err := svc.ListEvents(ctx, entity.EventFilter{Sort: entity.EventSortPopularity, Limit: 2, Offset: 2}); err != nil {
+		t.Errorf("Expected offset paging for popularity sort, got %v", err)
+	}
+
+	var filterErr *service.FilterError
+	filter := entity.EventFilter{Sort: entity.EventSortPopularity, Limit: 2}
+	filter.Cursor = &entity.PageCursor{Sort: string(entity.EventSortPopularity), Value: "5", ID: uuid.New()}
+	if _, err := svc.ListEvents(ctx, filter); !errors.As(err, &filterErr) {
+		t.Errorf("Expected FilterError for popularity cursor, got %v", err)
+	}
+}
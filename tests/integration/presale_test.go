@@ -0,0 +1,146 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+	"gorm.io/gorm"
+)
+
+// Mock Presale Repository cho testing
+type mockPresaleRepository struct {
+	codes    []entity.PresaleCode
+	redeemed map[uuid.UUID]bool // key = user id
+}
+
+func (m *mockPresaleRepository) CreateCode(ctx context.Context, code *entity.PresaleCode) error {
+	m.codes = append(m.codes, *code)
+	return nil
+}
+
+func (m *mockPresaleRepository) ListCodesByEvent(ctx context.Context, eventID uuid.UUID) ([]entity.PresaleCode, error) {
+	codes := make([]entity.PresaleCode, 0)
+	for _, code := range m.codes {
+		if code.EventID == eventID {
+			codes = append(codes, code)
+		}
+	}
+	return codes, nil
+}
+
+func (m *mockPresaleRepository) GetCodeByValue(ctx context.Context, eventID uuid.UUID, code string) (*entity.PresaleCode, error) {
+	for i := range m.codes {
+		if m.codes[i].EventID == eventID && m.codes[i].Code == code {
+			return &m.codes[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockPresaleRepository) HasRedeemed(ctx context.Context, codeID, userID uuid.UUID) (bool, error) {
+	return m.redeemed[userID], nil
+}
+
+func TestGetEvent_HidesNonPublicTicketTypes(t *testing.T) {
+	eventRepo := NewMockEventRepository()
+	eventID, publicID := newTestEventWithTicketType(t, eventRepo)
+
+	hiddenID := addTicketType(eventRepo, "Fan club", false)
+	eventRepo.ticketTypes[hiddenID].EventID = eventID
+	eventRepo.ticketTypes[hiddenID].Visibility = entity.TicketVisibilityCode
+
+	svc := service.NewEventService(eventRepo, NewMockVenueRepository())
+	event, err := svc.GetEvent(context.Background(), eventID)
+	if err != nil {
+		t.Fatalf("GetEvent failed: %v", err)
+	}
+
+	if len(event.TicketTypes) != 1 || event.TicketTypes[0].ID != publicID {
+		t.Fatalf("Expected only the public ticket type, got %+v", event.TicketTypes)
+	}
+}
+
+func TestPresaleUnlock(t *testing.T) {
+	eventRepo := NewMockEventRepository()
+	eventID, _ := newTestEventWithTicketType(t, eventRepo)
+
+	hiddenID := addTicketType(eventRepo, "Fan club", false)
+	eventRepo.ticketTypes[hiddenID].EventID = eventID
+	eventRepo.ticketTypes[hiddenID].Visibility = entity.TicketVisibilityCode
+
+	presaleRepo := &mockPresaleRepository{redeemed: map[uuid.UUID]bool{}}
+	svc := service.NewPresaleService(presaleRepo, eventRepo)
+	ctx := context.Background()
+
+	code, err := svc.CreateCode(ctx, eventID, entity.CreatePresaleCodeRequest{
+		Code:          " fanclub2026 ",
+		MaxUses:       2,
+		TicketTypeIDs: []uuid.UUID{hiddenID},
+	})
+	if err != nil {
+		t.Fatalf("CreateCode failed: %v", err)
+	}
+	if code.Code != "FANCLUB2026" {
+		t.Errorf("Expected normalized code FANCLUB2026, got %s", code.Code)
+	}
+
+	t.Run("valid code unlocks hidden type", func(t *testing.T) {
+		unlocked, err := svc.Unlock(ctx, uuid.New(), eventID, "FanClub2026")
+		if err != nil {
+			t.Fatalf("Unlock failed: %v", err)
+		}
+		if len(unlocked) != 1 || unlocked[0].ID != hiddenID {
+			t.Errorf("Expected hidden ticket type unlocked, got %+v", unlocked)
+		}
+	})
+
+	t.Run("unknown code", func(t *testing.T) {
+		if _, err := svc.Unlock(ctx, uuid.New(), eventID, "WRONG"); err == nil {
+			t.Error("Expected error for unknown code")
+		}
+	})
+
+	t.Run("code already used by user", func(t *testing.T) {
+		userID := uuid.New()
+		presaleRepo.redeemed[userID] = true
+		if _, err := svc.Unlock(ctx, userID, eventID, "FANCLUB2026"); err == nil {
+			t.Error("Expected error for already used code")
+		}
+	})
+
+	t.Run("code exhausted", func(t *testing.T) {
+		presaleRepo.codes[0].UsedCount = 2
+		defer func() { presaleRepo.codes[0].UsedCount = 0 }()
+		if _, err := svc.Unlock(ctx, uuid.New(), eventID, "FANCLUB2026"); err == nil {
+			t.Error("Expected error for exhausted code")
+		}
+	})
+
+	t.Run("code expired", func(t *testing.T) {
+		ended := time.Now().Add(-time.Hour)
+		presaleRepo.codes[0].EndsAt = &ended
+		defer func() { presaleRepo.codes[0].EndsAt = nil }()
+		if _, err := svc.Unlock(ctx, uuid.New(), eventID, "FANCLUB2026"); err == nil {
+			t.Error("Expected error for expired code")
+		}
+	})
+}
+
+func TestCreatePresaleCode_RejectsForeignTicketType(t *testing.T) {
+	eventRepo := NewMockEventRepository()
+	eventID, _ := newTestEventWithTicketType(t, eventRepo)
+	otherID := addTicketType(eventRepo, "Sự kiện khác", false)
+
+	svc := service.NewPresaleService(&mockPresaleRepository{}, eventRepo)
+	_, err := svc.CreateCode(context.Background(), eventID, entity.CreatePresaleCodeRequest{
+		Code:          "FANCLUB",
+		TicketTypeIDs: []uuid.UUID{otherID},
+	})
+	if err == nil {
+		t.Fatal("Expected error for ticket type of another event")
+	}
+}
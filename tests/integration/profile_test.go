@@ -0,0 +1,126 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestUpdateProfile_ValidatesAndMergesFields(t *testing.T) {
+	svc, _, _, user := newTestAuthService(t)
+	ctx := context.Background()
+	user.ProfileData = map[string]interface{}{"newsletter": true}
+
+	invalid := []entity.UpdateProfileRequest{
+		{Phone: strPtr("12ab")},
+		{BirthDate: strPtr("01/02/1990")},
+		{BirthDate: strPtr(time.Now().AddDate(0, 0, 1).Format("2006-01-02"))},
+		{AvatarURL: strPtr("javascript:alert(1)")},
+	}
+	for _, req := range invalid {
+		if _, err := svc.UpdateProfile(ctx, user.ID, req); err == nil {
+			t.Errorf("Expected validation error for %+v", req)
+		}
+	}
+
+	updated, err := svc.UpdateProfile(ctx, user.ID, entity.UpdateProfileRequest{
+		FullName:  strPtr("  Nguyễn Văn A "),
+		Phone:     strPtr("+84 912-345.678"),
+		BirthDate: strPtr("1990-05-20"),
+		AvatarURL: strPtr("https://cdn.example.com/a.png"),
+	})
+	if err != nil {
+		t.Fatalf("UpdateProfile failed: %v", err)
+	}
+	if updated.ProfileData[entity.ProfileFullName] != "Nguyễn Văn A" || updated.ProfileData[entity.ProfilePhone] != "+84912345678" {
+		t.Errorf("Expected normalized name and phone, got %v", updated.ProfileData)
+	}
+	if updated.ProfileData["newsletter"] != true {
+		t.Error("Expected unrelated profile keys to be kept")
+	}
+
+	// Chuỗi rỗng xóa trường, trường bỏ qua giữ nguyên
+	updated, err = svc.UpdateProfile(ctx, user.ID, entity.UpdateProfileRequest{AvatarURL: strPtr("")})
+	if err != nil {
+		t.Fatalf("UpdateProfile failed: %v", err)
+	}
+	if _, ok := updated.ProfileData[entity.ProfileAvatarURL]; ok {
+		t.Error("Expected avatar_url cleared")
+	}
+	if updated.ProfileData[entity.ProfileBirthDate] != "1990-05-20" {
+		t.Error("Expected omitted birth_date kept")
+	}
+}
+
+func TestChangePassword_RequiresCurrentPasswordAndRevokesSessions(t *testing.T) {
+	svc, _, mails, user := newTestAuthService(t)
+	ctx := context.Background()
+
+	session, err := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "secret123"})
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	if err := svc.ChangePassword(ctx, user.ID, entity.ChangePasswordRequest{CurrentPassword: "wrong", NewPassword: "new-secret"}); err == nil {
+		t.Fatal("Expected wrong current password rejected")
+	}
+	if err := svc.ChangePassword(ctx, user.ID, entity.ChangePasswordRequest{CurrentPassword: "secret123", NewPassword: "new-secret"}); err != nil {
+		t.Fatalf("ChangePassword failed: %v", err)
+	}
+
+	if _, err := svc.Login(ctx, entity.LoginRequest{Email: user.Email, Password: "new-secret"}); err != nil {
+		t.Errorf("Expected login with new password, got %v", err)
+	}
+	if _, err := svc.Refresh(ctx, session.RefreshToken); err == nil {
+		t.Error("Expected existing sessions revoked after password change")
+	}
+	if len(mails.sent) == 0 || mails.sent[len(mails.sent)-1].To != user.Email {
+		t.Error("Expected password change notification email")
+	}
+}
+
+func TestChangeEmail_RequiresConfirmationFromNewAddress(t *testing.T) {
+	svc, _, mails, user := newTestAuthService(t)
+	ctx := context.Background()
+
+	if _, err := svc.Register(ctx, entity.RegisterRequest{Username: "bob", Email: "bob@example.com", Password: "secret123"}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if err := svc.RequestEmailChange(ctx, user.ID, entity.ChangeEmailRequest{NewEmail: "bob@example.com", Password: "secret123"}); err == nil {
+		t.Error("Expected email already in use rejected")
+	}
+	if err := svc.RequestEmailChange(ctx, user.ID, entity.ChangeEmailRequest{NewEmail: "alice.new@example.com", Password: "wrong"}); err == nil {
+		t.Error("Expected wrong password rejected")
+	}
+
+	if err := svc.RequestEmailChange(ctx, user.ID, entity.ChangeEmailRequest{NewEmail: " Alice.New@Example.com", Password: "secret123"}); err != nil {
+		t.Fatalf("RequestEmailChange failed: %v", err)
+	}
+	if user.Email != "alice@example.com" {
+		t.Fatal("Expected email unchanged before confirmation")
+	}
+	token := mails.lastToken(t, "alice.new@example.com")
+
+	// Token đổi email không dùng để xác thực email được
+	if err := svc.VerifyEmail(ctx, token); err == nil {
+		t.Error("Expected email change token rejected for email verification")
+	}
+	if err := svc.ConfirmEmailChange(ctx, token); err != nil {
+		t.Fatalf("ConfirmEmailChange failed: %v", err)
+	}
+
+	profile, err := svc.GetProfile(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GetProfile failed: %v", err)
+	}
+	if profile.Email != "alice.new@example.com" || profile.PendingEmail != nil || profile.EmailVerifiedAt == nil {
+		t.Errorf("Expected verified new email, got %+v", profile)
+	}
+	if err := svc.ConfirmEmailChange(ctx, token); err == nil {
+		t.Error("Expected email change token to be single-use")
+	}
+}
@@ -0,0 +1,230 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/yourname/ticketing-system/internal/adapter/repository"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+// recordingPayout ghi lại các lệnh chi tiền, lỗi nếu fail được đặt.
+type recordingPayout struct {
+	mu      sync.Mutex
+	fail    bool
+	amounts map[uuid.UUID]decimal.Decimal // theo reference
+}
+
+func (p *recordingPayout) Payout(ctx context.Context, userID uuid.UUID, reference uuid.UUID, amount decimal.Decimal) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.fail {
+		return errors.New("payout gateway down")
+	}
+	if p.amounts == nil {
+		p.amounts = make(map[uuid.UUID]decimal.Decimal)
+	}
+	p.amounts[reference] = p.amounts[reference].Add(amount)
+	return nil
+}
+
+func TestResaleListing_RejectsNonPositivePrice(t *testing.T) {
+	svc := service.NewResaleService(nil, nil, nil, &recordingNotifier{}, &recordingPayout{}, decimal.NewFromInt(10))
+	_, err := svc.CreateListing(context.Background(), uuid.New(), entity.CreateResaleListingRequest{TicketID: uuid.New(), Price: decimal.Zero})
+	if err == nil {
+		t.Fatal("Expected error for zero resale price")
+	}
+}
+
+func TestResaleSetEventCap_RejectsNegative(t *testing.T) {
+	svc := service.NewResaleService(nil, nil, nil, &recordingNotifier{}, &recordingPayout{}, decimal.NewFromInt(10))
+	if err := svc.SetEventCap(context.Background(), uuid.New(), -1); err == nil {
+		t.Fatal("Expected error for negative resale cap")
+	}
+}
+
+func TestResaleMarketplace_DB(t *testing.T) {
+	db := setupDB()
+	ctx := context.Background()
+
+	sellerID, buyerID, otherID := uuid.New(), uuid.New(), uuid.New()
+	for _, id := range []uuid.UUID{sellerID, buyerID, otherID} {
+		if err := db.Exec("INSERT INTO users (id, username, email, password_hash) VALUES (?, ?, ?, ?)",
+			id, "rs-"+id.String()[:8], "rs-"+id.String()[:8]+"@example.com", "hash").Error; err != nil {
+			t.Fatalf("Failed to seed user: %v", err)
+		}
+	}
+
+	eventID := uuid.New()
+	if err := db.Exec("INSERT INTO events (id, name, slug, start_time, end_time) VALUES (?, ?, ?, ?, ?)",
+		eventID, "Resale Event", "resale-"+eventID.String()[:8], time.Now().Add(time.Hour), time.Now().Add(2*time.Hour)).Error; err != nil {
+		t.Fatalf("Failed to seed event: %v", err)
+	}
+
+	ticketTypeID := uuid.New()
+	if err := db.Create(&entity.TicketType{
+		ID:                ticketTypeID,
+		EventID:           eventID,
+		Name:              "GA",
+		Price:             decimal.NewFromInt(100),
+		InitialQuantity:   5,
+		RemainingQuantity: 5,
+	}).Error; err != nil {
+		t.Fatalf("Failed to seed ticket type: %v", err)
+	}
+
+	transferRepo := repository.NewTransferRepository(db)
+	payouts := &recordingPayout{}
+	resale := service.NewResaleService(db, repository.NewResaleRepository(db), transferRepo, &recordingNotifier{}, payouts, decimal.NewFromInt(10))
+	transfers := service.NewTransferService(db, transferRepo, nil, &recordingNotifier{})
+	orders := service.NewOrderService(db, repository.NewOrderRepository(db), nil, resale)
+
+	order, err := orders.PlaceOrder(ctx, sellerID, []service.RequestItem{{TicketTypeID: ticketTypeID, Quantity: 1}})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	db.Model(&entity.Order{}).Where("id = ?", order.ID).Update("status", entity.OrderStatusPaid)
+	ticket := order.Tickets[0]
+
+	// Event chưa mở bán lại
+	if _, err := resale.CreateListing(ctx, sellerID, entity.CreateResaleListingRequest{TicketID: ticket.ID, Price: decimal.NewFromInt(100)}); err == nil {
+		t.Error("Expected error when resale is disabled for event")
+	}
+	if err := resale.SetEventCap(ctx, eventID, 110); err != nil {
+		t.Fatalf("SetEventCap failed: %v", err)
+	}
+
+	// Vượt trần 110% giá gốc
+	if _, err := resale.CreateListing(ctx, sellerID, entity.CreateResaleListingRequest{TicketID: ticket.ID, Price: decimal.NewFromInt(111)}); err == nil {
+		t.Error("Expected error when price exceeds cap")
+	}
+	// Không phải chủ vé
+	if _, err := resale.CreateListing(ctx, otherID, entity.CreateResaleListingRequest{TicketID: ticket.ID, Price: decimal.NewFromInt(110)}); err == nil {
+		t.Error("Expected error when listing someone else's ticket")
+	}
+
+	listing, err := resale.CreateListing(ctx, sellerID, entity.CreateResaleListingRequest{TicketID: ticket.ID, Price: decimal.NewFromInt(110)})
+	if err != nil {
+		t.Fatalf("CreateListing failed: %v", err)
+	}
+	if _, err := resale.CreateListing(ctx, sellerID, entity.CreateResaleListingRequest{TicketID: ticket.ID, Price: decimal.NewFromInt(105)}); err == nil {
+		t.Error("Expected error when ticket is already listed")
+	}
+
+	// Người bán không tự mua được
+	if _, err := orders.PlaceOrder(ctx, sellerID, []service.RequestItem{{ResaleID: &listing.ID, Quantity: 1}}); err == nil {
+		t.Error("Expected error when seller buys own listing")
+	}
+
+	buyerOrder, err := orders.PlaceOrder(ctx, buyerID, []service.RequestItem{{ResaleID: &listing.ID, Quantity: 1}})
+	if err != nil {
+		t.Fatalf("PlaceOrder resale failed: %v", err)
+	}
+	if !buyerOrder.TotalAmount.Equal(decimal.NewFromInt(110)) {
+		t.Errorf("Expected total 110, got %s", buyerOrder.TotalAmount)
+	}
+	if len(buyerOrder.Tickets) != 0 {
+		t.Fatalf("Expected no tickets before payment, got %+v", buyerOrder.Tickets)
+	}
+
+	// Chưa thanh toán: vé vẫn của người bán với mã cũ, người mua chưa thấy vé
+	var held entity.Ticket
+	db.First(&held, "id = ?", ticket.ID)
+	if held.OwnerID == nil || *held.OwnerID != sellerID || held.TicketCode != ticket.TicketCode {
+		t.Errorf("Expected ticket to stay with seller until payment, got owner=%v", held.OwnerID)
+	}
+	if mine, err := transfers.ListMyTickets(ctx, buyerID); err != nil || len(mine) != 0 {
+		t.Errorf("Expected buyer to hold no tickets before payment, got %d (%v)", len(mine), err)
+	}
+
+	// Không bán được lần hai, người bán cũng không gỡ tin được khi đang chờ thanh toán
+	if _, err := orders.PlaceOrder(ctx, otherID, []service.RequestItem{{ResaleID: &listing.ID, Quantity: 1}}); err == nil {
+		t.Error("Expected error when buying a reserved listing")
+	}
+	if err := resale.CancelListing(ctx, sellerID, listing.ID); err == nil {
+		t.Error("Expected error when cancelling a reserved listing")
+	}
+
+	var sold entity.ResaleListing
+	db.First(&sold, "id = ?", listing.ID)
+	if sold.Status != entity.ResaleListingStatusReserved || !sold.Fee.Equal(decimal.NewFromInt(11)) || !sold.SellerPayout.Equal(decimal.NewFromInt(99)) {
+		t.Errorf("Expected RESERVED with fee 11 and payout 99, got %s fee=%s payout=%s", sold.Status, sold.Fee, sold.SellerPayout)
+	}
+
+	// Người mua hủy đơn: tin rao tiếp, vé không đổi
+	if err := orders.CancelOrder(ctx, buyerID, buyerOrder.ID); err != nil {
+		t.Fatalf("CancelOrder failed: %v", err)
+	}
+	db.First(&held, "id = ?", ticket.ID)
+	if held.OwnerID == nil || *held.OwnerID != sellerID || held.TicketCode != ticket.TicketCode {
+		t.Errorf("Expected ticket unchanged with seller, got owner=%v", held.OwnerID)
+	}
+	db.First(&sold, "id = ?", listing.ID)
+	if sold.Status != entity.ResaleListingStatusActive || sold.BuyerID != nil {
+		t.Errorf("Expected listing ACTIVE again, got %s", sold.Status)
+	}
+
+	// Thanh toán: vé sang tên người mua với mã mới, người bán được chi tiền (cổng lỗi thì chi lại sau)
+	buyerOrder, err = orders.PlaceOrder(ctx, buyerID, []service.RequestItem{{ResaleID: &listing.ID, Quantity: 1}})
+	if err != nil {
+		t.Fatalf("PlaceOrder resale again failed: %v", err)
+	}
+	payouts.fail = true
+	if err := orders.ConfirmPayment(ctx, buyerOrder.ID); err != nil {
+		t.Fatalf("ConfirmPayment failed: %v", err)
+	}
+	db.First(&held, "id = ?", ticket.ID)
+	if held.OwnerID == nil || *held.OwnerID != buyerID || held.TicketCode == ticket.TicketCode {
+		t.Errorf("Expected ticket re-issued to buyer with new code, got owner=%v", held.OwnerID)
+	}
+	db.First(&sold, "id = ?", listing.ID)
+	if sold.Status != entity.ResaleListingStatusSold || sold.PaidOutAt != nil {
+		t.Errorf("Expected SOLD and not yet paid out, got %s paid_out_at=%v", sold.Status, sold.PaidOutAt)
+	}
+
+	payouts.fail = false
+	if _, err := resale.RetryPayouts(ctx); err != nil {
+		t.Fatalf("RetryPayouts failed: %v", err)
+	}
+	if _, err := resale.RetryPayouts(ctx); err != nil {
+		t.Fatalf("RetryPayouts failed: %v", err)
+	}
+	db.First(&sold, "id = ?", listing.ID)
+	if sold.PaidOutAt == nil || !payouts.amounts[listing.ID].Equal(decimal.NewFromInt(99)) {
+		t.Errorf("Expected seller paid 99 exactly once, got %s", payouts.amounts[listing.ID])
+	}
+	if err := orders.ConfirmPayment(ctx, buyerOrder.ID); err == nil {
+		t.Error("Expected error when confirming a paid order twice")
+	}
+
+	// Tin khác để kiểm tra gỡ tin
+	second, err := orders.PlaceOrder(ctx, sellerID, []service.RequestItem{{TicketTypeID: ticketTypeID, Quantity: 1}})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if err := orders.ConfirmPayment(ctx, second.ID); err != nil {
+		t.Fatalf("ConfirmPayment failed: %v", err)
+	}
+	listing, err = resale.CreateListing(ctx, sellerID, entity.CreateResaleListingRequest{TicketID: second.Tickets[0].ID, Price: decimal.NewFromInt(100)})
+	if err != nil {
+		t.Fatalf("CreateListing failed: %v", err)
+	}
+
+	if err := resale.CancelListing(ctx, sellerID, listing.ID); err != nil {
+		t.Fatalf("CancelListing failed: %v", err)
+	}
+	active, err := resale.ListEventListings(ctx, eventID)
+	if err != nil {
+		t.Fatalf("ListEventListings failed: %v", err)
+	}
+	if len(active) != 0 {
+		t.Errorf("Expected no active listings, got %d", len(active))
+	}
+}
@@ -0,0 +1,142 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+// Mock Seat Repository cho testing
+type mockSeatRepository struct {
+	seats []entity.Seat
+}
+
+func (m *mockSeatRepository) CreateSeats(ctx context.Context, seats []entity.Seat) error {
+	m.seats = append(m.seats, seats...)
+	return nil
+}
+
+func (m *mockSeatRepository) ListSeatsByEvent(ctx context.Context, eventID uuid.UUID) ([]entity.Seat, error) {
+	seats := make([]entity.Seat, 0)
+	for _, seat := range m.seats {
+		if seat.EventID == eventID {
+			seats = append(seats, seat)
+		}
+	}
+	return seats, nil
+}
+
+func (m *mockSeatRepository) CountSeatsByTicketType(ctx context.Context, ticketTypeID uuid.UUID) (int, error) {
+	count := 0
+	for _, seat := range m.seats {
+		if seat.TicketTypeID == ticketTypeID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// buildRow tạo một hàng ghế 1..n, cách nhau 1 đơn vị theo trục X
+func buildRow(ticketTypeID uuid.UUID, label string, y float64, n int) []entity.Seat {
+	seats := make([]entity.Seat, 0, n)
+	for i := 1; i <= n; i++ {
+		seats = append(seats, entity.Seat{
+			ID:           uuid.New(),
+			TicketTypeID: ticketTypeID,
+			RowLabel:     label,
+			Number:       i,
+			X:            float64(i),
+			Y:            y,
+			Status:       entity.SeatStatusAvailable,
+		})
+	}
+	return seats
+}
+
+func TestFindBestSeats_PrefersFrontRowCenter(t *testing.T) {
+	ticketTypeID := uuid.New()
+	seats := append(buildRow(ticketTypeID, "B", 2, 10), buildRow(ticketTypeID, "A", 1, 10)...)
+
+	best := service.FindBestSeats(seats, ticketTypeID, 2)
+
+	if len(best) != 2 {
+		t.Fatalf("Expected 2 seats, got %d", len(best))
+	}
+
+	if best[0].RowLabel != "A" {
+		t.Errorf("Expected front row A, got %s", best[0].RowLabel)
+	}
+
+	// Hàng 10 ghế, tâm ở giữa ghế 5 và 6
+	if best[0].Number != 5 || best[1].Number != 6 {
+		t.Errorf("Expected seats 5-6, got %d-%d", best[0].Number, best[1].Number)
+	}
+}
+
+func TestFindBestSeats_SkipsSoldAndFallsBack(t *testing.T) {
+	ticketTypeID := uuid.New()
+	front := buildRow(ticketTypeID, "A", 1, 4)
+	front[1].Status = entity.SeatStatusSold // A2 đã bán, hàng A không còn 3 ghế liền nhau
+	seats := append(front, buildRow(ticketTypeID, "B", 2, 4)...)
+
+	best := service.FindBestSeats(seats, ticketTypeID, 3)
+
+	if len(best) != 3 {
+		t.Fatalf("Expected 3 seats, got %d", len(best))
+	}
+
+	if best[0].RowLabel != "B" {
+		t.Errorf("Expected row B, got %s", best[0].RowLabel)
+	}
+}
+
+func TestFindBestSeats_IgnoresOtherTicketTypes(t *testing.T) {
+	vip := uuid.New()
+	standard := uuid.New()
+	seats := buildRow(vip, "A", 1, 6)
+
+	if best := service.FindBestSeats(seats, standard, 2); best != nil {
+		t.Errorf("Expected no seats for another ticket type, got %d", len(best))
+	}
+}
+
+func TestCreateSeats_ExceedsTicketQuantity(t *testing.T) {
+	eventRepo := NewMockEventRepository()
+	venueRepo := NewMockVenueRepository()
+	eventSvc := service.NewEventService(eventRepo, venueRepo)
+	seatSvc := service.NewSeatService(&mockSeatRepository{}, eventRepo, venueRepo)
+	ctx := context.Background()
+
+	event, err := eventSvc.CreateEventWithTickets(ctx, entity.CreateEventRequest{
+		Name:      "Kịch Sân khấu Nhỏ",
+		Slug:      "kich-san-khau-nho",
+		Location:  "Sân khấu kịch Idecaf",
+		StartTime: time.Now().Add(24 * time.Hour),
+		EndTime:   time.Now().Add(26 * time.Hour),
+	}, []entity.CreateTicketTypeRequest{
+		{Name: "Orchestra", Price: decimal.NewFromInt(300000), InitialQuantity: 2},
+	})
+	if err != nil {
+		t.Fatalf("CreateEventWithTickets failed: %v", err)
+	}
+
+	var ticketTypeID uuid.UUID
+	for id := range eventRepo.ticketTypes {
+		ticketTypeID = id
+	}
+
+	req := []entity.CreateSeatRequest{
+		{TicketTypeID: ticketTypeID, Row: "A", Number: 1},
+		{TicketTypeID: ticketTypeID, Row: "A", Number: 2},
+		{TicketTypeID: ticketTypeID, Row: "A", Number: 3},
+	}
+
+	if _, err := seatSvc.CreateSeats(ctx, event.ID, req); err == nil {
+		t.Fatal("Expected error when seats exceed ticket quantity, got nil")
+	}
+}
@@ -0,0 +1,77 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+func TestExpandRecurrence_DailyCount(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Ho_Chi_Minh")
+	start := time.Date(2026, 11, 1, 19, 30, 0, 0, loc)
+
+	times, err := service.ExpandRecurrence(start, entity.RecurrenceRule{
+		Frequency: entity.RecurrenceDaily,
+		Count:     14,
+	})
+	if err != nil {
+		t.Fatalf("ExpandRecurrence failed: %v", err)
+	}
+
+	if len(times) != 14 {
+		t.Fatalf("Expected 14 sessions, got %d", len(times))
+	}
+
+	last := times[len(times)-1]
+	if last.Day() != 14 || last.Hour() != 19 || last.Minute() != 30 {
+		t.Errorf("Expected last session on Nov 14 at 19:30, got %v", last)
+	}
+}
+
+func TestExpandRecurrence_WeeklyUntil(t *testing.T) {
+	// 2026-11-02 là thứ Hai
+	start := time.Date(2026, 11, 2, 20, 0, 0, 0, time.UTC)
+	until := time.Date(2026, 11, 15, 23, 59, 0, 0, time.UTC)
+
+	times, err := service.ExpandRecurrence(start, entity.RecurrenceRule{
+		Frequency: entity.RecurrenceWeekly,
+		Weekdays:  []time.Weekday{time.Saturday, time.Friday},
+		Until:     &until,
+	})
+	if err != nil {
+		t.Fatalf("ExpandRecurrence failed: %v", err)
+	}
+
+	// Thứ Sáu 6, thứ Bảy 7, thứ Sáu 13, thứ Bảy 14
+	expected := []int{6, 7, 13, 14}
+	if len(times) != len(expected) {
+		t.Fatalf("Expected %d sessions, got %d", len(expected), len(times))
+	}
+	for i, day := range expected {
+		if times[i].Day() != day {
+			t.Errorf("Session %d: expected day %d, got %d", i, day, times[i].Day())
+		}
+	}
+}
+
+func TestExpandRecurrence_RequiresBound(t *testing.T) {
+	_, err := service.ExpandRecurrence(time.Now(), entity.RecurrenceRule{Frequency: entity.RecurrenceDaily})
+	if err == nil {
+		t.Fatal("Expected error when rule has neither count nor until, got nil")
+	}
+}
+
+func TestExpandRecurrence_SingleSession(t *testing.T) {
+	start := time.Now()
+
+	times, err := service.ExpandRecurrence(start, entity.RecurrenceRule{})
+	if err != nil {
+		t.Fatalf("ExpandRecurrence failed: %v", err)
+	}
+
+	if len(times) != 1 || !times[0].Equal(start) {
+		t.Errorf("Expected single session at start time, got %v", times)
+	}
+}
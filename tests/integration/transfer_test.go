@@ -0,0 +1,130 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/yourname/ticketing-system/internal/adapter/repository"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+func TestTransferInitiate_RejectsInvalidEmail(t *testing.T) {
+	svc := service.NewTransferService(nil, nil, nil, &recordingNotifier{})
+	_, err := svc.Initiate(context.Background(), uuid.New(), uuid.New(), entity.CreateTicketTransferRequest{Email: "not-an-email"})
+	if err == nil {
+		t.Fatal("Expected error for invalid recipient email")
+	}
+}
+
+func TestTransferAccept_RequiresVerifiedEmail(t *testing.T) {
+	recipient := &entity.User{ID: uuid.New(), Username: "friend", Email: "friend@example.com", Role: entity.RoleUser}
+	users := &mockUserRepository{users: map[uuid.UUID]*entity.User{recipient.ID: recipient}}
+	svc := service.NewTransferService(nil, nil, users, &recordingNotifier{})
+
+	if _, err := svc.Accept(context.Background(), recipient.ID, uuid.New()); err == nil {
+		t.Fatal("Expected unverified recipient rejected")
+	}
+}
+
+func TestTicketTransfer_DB(t *testing.T) {
+	db := setupDB()
+	ctx := context.Background()
+
+	ownerID, friendID := uuid.New(), uuid.New()
+	friendEmail := "friend-" + friendID.String()[:8] + "@example.com"
+	for id, email := range map[uuid.UUID]string{ownerID: "owner-" + ownerID.String()[:8] + "@example.com", friendID: friendEmail} {
+		if err := db.Exec("INSERT INTO users (id, username, email, password_hash) VALUES (?, ?, ?, ?)",
+			id, "tr-"+id.String()[:8], email, "hash").Error; err != nil {
+			t.Fatalf("Failed to seed user: %v", err)
+		}
+	}
+
+	eventID := uuid.New()
+	if err := db.Exec("INSERT INTO events (id, name, slug, start_time, end_time) VALUES (?, ?, ?, ?, ?)",
+		eventID, "Transfer Event", "transfer-"+eventID.String()[:8], time.Now().Add(time.Hour), time.Now().Add(2*time.Hour)).Error; err != nil {
+		t.Fatalf("Failed to seed event: %v", err)
+	}
+
+	ticketTypeID := uuid.New()
+	if err := db.Create(&entity.TicketType{
+		ID:                ticketTypeID,
+		EventID:           eventID,
+		Name:              "GA",
+		Price:             decimal.NewFromInt(100),
+		InitialQuantity:   5,
+		RemainingQuantity: 5,
+	}).Error; err != nil {
+		t.Fatalf("Failed to seed ticket type: %v", err)
+	}
+
+	orders := service.NewOrderService(db, repository.NewOrderRepository(db), nil, nil)
+	order, err := orders.PlaceOrder(ctx, ownerID, []service.RequestItem{{TicketTypeID: ticketTypeID, Quantity: 1}})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	ticket := order.Tickets[0]
+
+	sqlDB, _ := db.DB()
+	transfers := service.NewTransferService(db, repository.NewTransferRepository(db), repository.NewUserRepository(sqlDB), &recordingNotifier{})
+
+	// Đơn chưa thanh toán thì chưa chuyển được
+	if _, err := transfers.Initiate(ctx, ownerID, ticket.ID, entity.CreateTicketTransferRequest{Email: friendEmail}); err == nil {
+		t.Error("Expected error when transferring ticket of unpaid order")
+	}
+	db.Model(&entity.Order{}).Where("id = ?", order.ID).Update("status", entity.OrderStatusPaid)
+
+	// Ban tổ chức tắt chuyển nhượng loại vé
+	if err := transfers.SetTicketTypeTransfers(ctx, ticketTypeID, true); err != nil {
+		t.Fatalf("SetTicketTypeTransfers failed: %v", err)
+	}
+	if _, err := transfers.Initiate(ctx, ownerID, ticket.ID, entity.CreateTicketTransferRequest{Email: friendEmail}); err == nil {
+		t.Error("Expected error when transfers are disabled")
+	}
+	if err := transfers.SetTicketTypeTransfers(ctx, ticketTypeID, false); err != nil {
+		t.Fatalf("SetTicketTypeTransfers failed: %v", err)
+	}
+
+	transfer, err := transfers.Initiate(ctx, ownerID, ticket.ID, entity.CreateTicketTransferRequest{Email: friendEmail})
+	if err != nil {
+		t.Fatalf("Initiate failed: %v", err)
+	}
+	if _, err := transfers.Accept(ctx, ownerID, transfer.ID); err == nil {
+		t.Error("Expected error when someone other than the recipient accepts")
+	}
+
+	// Người nhận phải xác thực email trước
+	if _, err := transfers.Accept(ctx, friendID, transfer.ID); err == nil {
+		t.Error("Expected error when recipient email is not verified")
+	}
+	db.Exec("UPDATE users SET email_verified_at = NOW() WHERE id = ?", friendID)
+
+	accepted, err := transfers.Accept(ctx, friendID, transfer.ID)
+	if err != nil {
+		t.Fatalf("Accept failed: %v", err)
+	}
+	if accepted.OwnerID == nil || *accepted.OwnerID != friendID {
+		t.Errorf("Expected ticket owned by recipient, got %v", accepted.OwnerID)
+	}
+	if accepted.TicketCode == ticket.TicketCode {
+		t.Error("Expected a new ticket code after transfer")
+	}
+
+	var oldCodeCount int64
+	db.Model(&entity.Ticket{}).Where("ticket_code = ?", ticket.TicketCode).Count(&oldCodeCount)
+	if oldCodeCount != 0 {
+		t.Error("Expected old ticket code to be invalidated")
+	}
+
+	history, err := transfers.TicketHistory(ctx, friendID, ticket.ID)
+	if err != nil {
+		t.Fatalf("TicketHistory failed: %v", err)
+	}
+	if len(history) != 1 || history[0].Status != entity.TicketTransferStatusAccepted {
+		t.Errorf("Expected one accepted transfer in history, got %+v", history)
+	}
+}
@@ -0,0 +1,206 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/port"
+	"github.com/yourname/ticketing-system/internal/core/service"
+	"gorm.io/gorm"
+)
+
+// Mock Venue Repository cho testing
+type mockVenueRepository struct {
+	venues map[uuid.UUID]*entity.Venue
+}
+
+func NewMockVenueRepository() *mockVenueRepository {
+	return &mockVenueRepository{
+		venues: make(map[uuid.UUID]*entity.Venue),
+	}
+}
+
+func (m *mockVenueRepository) CreateVenue(ctx context.Context, venue *entity.Venue) error {
+	m.venues[venue.ID] = venue
+	return nil
+}
+
+func (m *mockVenueRepository) GetVenueByID(ctx context.Context, id uuid.UUID) (*entity.Venue, error) {
+	if venue, ok := m.venues[id]; ok {
+		return venue, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockVenueRepository) ListVenues(ctx context.Context, limit int, offset int) ([]entity.Venue, error) {
+	venues := make([]entity.Venue, 0)
+	for _, venue := range m.venues {
+		venues = append(venues, *venue)
+	}
+	return venues, nil
+}
+
+func (m *mockVenueRepository) UpdateVenue(ctx context.Context, venue *entity.Venue) error {
+	m.venues[venue.ID] = venue
+	return nil
+}
+
+func (m *mockVenueRepository) DeleteVenue(ctx context.Context, id uuid.UUID) error {
+	delete(m.venues, id)
+	return nil
+}
+
+func (m *mockVenueRepository) CreateSection(ctx context.Context, section *entity.VenueSection) error {
+	venue, ok := m.venues[section.VenueID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	venue.Sections = append(venue.Sections, *section)
+	return nil
+}
+
+func (m *mockVenueRepository) MaxEventAllocation(ctx context.Context, venueID uuid.UUID, sectionID *uuid.UUID) (int, error) {
+	return 0, nil
+}
+
+func newTestVenue(t *testing.T, svc port.VenueServicePort) *entity.Venue {
+	venue, err := svc.CreateVenue(context.Background(), entity.CreateVenueRequest{
+		Name:      "Nhà hát Hòa Bình",
+		Address:   "240 Đường 3/2, Quận 10, TP.HCM",
+		Latitude:  10.7683,
+		Longitude: 106.6658,
+		Capacity:  500,
+		Sections: []entity.CreateVenueSectionRequest{
+			{Name: "Tầng trệt", Capacity: 300},
+			{Name: "Ban công", Capacity: 200},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateVenue failed: %v", err)
+	}
+	return venue
+}
+
+func TestCreateVenue_Success(t *testing.T) {
+	venueSvc := service.NewVenueService(NewMockVenueRepository())
+
+	venue := newTestVenue(t, venueSvc)
+
+	if venue.Timezone != "Asia/Ho_Chi_Minh" {
+		t.Errorf("Expected default timezone Asia/Ho_Chi_Minh, got %q", venue.Timezone)
+	}
+
+	if len(venue.Sections) != 2 {
+		t.Errorf("Expected 2 sections, got %d", len(venue.Sections))
+	}
+}
+
+func TestCreateVenue_SectionsExceedCapacity(t *testing.T) {
+	venueSvc := service.NewVenueService(NewMockVenueRepository())
+
+	_, err := venueSvc.CreateVenue(context.Background(), entity.CreateVenueRequest{
+		Name:     "Sân khấu nhỏ",
+		Capacity: 100,
+		Sections: []entity.CreateVenueSectionRequest{
+			{Name: "Khu A", Capacity: 80},
+			{Name: "Khu B", Capacity: 80},
+		},
+	})
+
+	if err == nil {
+		t.Fatal("Expected error when sections exceed venue capacity, got nil")
+	}
+}
+
+func TestCreateVenue_InvalidTimezone(t *testing.T) {
+	venueSvc := service.NewVenueService(NewMockVenueRepository())
+
+	_, err := venueSvc.CreateVenue(context.Background(), entity.CreateVenueRequest{
+		Name:     "Sân khấu nhỏ",
+		Timezone: "Mars/Olympus",
+		Capacity: 100,
+	})
+
+	if err == nil {
+		t.Fatal("Expected error for unknown timezone, got nil")
+	}
+}
+
+func TestCreateEventWithTickets_ExceedsVenueCapacity(t *testing.T) {
+	venueRepo := NewMockVenueRepository()
+	venue := newTestVenue(t, service.NewVenueService(venueRepo))
+	mockRepo := NewMockEventRepository()
+	svc := service.NewEventService(mockRepo, venueRepo)
+
+	eventReq := entity.CreateEventRequest{
+		Name:      "Vở kịch cuối tuần",
+		Slug:      "vo-kich-cuoi-tuan",
+		VenueID:   &venue.ID,
+		StartTime: time.Now().Add(24 * time.Hour),
+		EndTime:   time.Now().Add(26 * time.Hour),
+	}
+	tickets := []entity.CreateTicketTypeRequest{
+		{Name: "Standard", Price: decimal.NewFromInt(200000), InitialQuantity: 400},
+		{Name: "VIP Seat", Price: decimal.NewFromInt(500000), InitialQuantity: 150},
+	}
+
+	_, err := svc.CreateEventWithTickets(context.Background(), eventReq, tickets)
+	if err == nil {
+		t.Fatal("Expected error when tickets exceed venue capacity, got nil")
+	}
+
+	if len(mockRepo.events) != 0 {
+		t.Errorf("Expected no event to be created, got %d", len(mockRepo.events))
+	}
+}
+
+func TestCreateEventWithTickets_ExceedsSectionCapacity(t *testing.T) {
+	venueRepo := NewMockVenueRepository()
+	venue := newTestVenue(t, service.NewVenueService(venueRepo))
+	svc := service.NewEventService(NewMockEventRepository(), venueRepo)
+
+	balcony := venue.Sections[1].ID
+	eventReq := entity.CreateEventRequest{
+		Name:      "Vở kịch cuối tuần",
+		Slug:      "vo-kich-cuoi-tuan",
+		VenueID:   &venue.ID,
+		StartTime: time.Now().Add(24 * time.Hour),
+		EndTime:   time.Now().Add(26 * time.Hour),
+	}
+	tickets := []entity.CreateTicketTypeRequest{
+		{Name: "Balcony", Price: decimal.NewFromInt(200000), InitialQuantity: 250, SectionID: &balcony},
+	}
+
+	if _, err := svc.CreateEventWithTickets(context.Background(), eventReq, tickets); err == nil {
+		t.Fatal("Expected error when tickets exceed section capacity, got nil")
+	}
+}
+
+func TestCreateEvent_WithVenueDefaultsLocation(t *testing.T) {
+	venueRepo := NewMockVenueRepository()
+	venue := newTestVenue(t, service.NewVenueService(venueRepo))
+	svc := service.NewEventService(NewMockEventRepository(), venueRepo)
+
+	event, err := svc.CreateEvent(context.Background(), entity.CreateEventRequest{
+		Name:      "Hòa nhạc thính phòng",
+		Slug:      "hoa-nhac-thinh-phong",
+		VenueID:   &venue.ID,
+		StartTime: time.Now().Add(24 * time.Hour),
+		EndTime:   time.Now().Add(26 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent failed: %v", err)
+	}
+
+	if event.Location != venue.Name {
+		t.Errorf("Expected location %q, got %q", venue.Name, event.Location)
+	}
+
+	if event.VenueID == nil || *event.VenueID != venue.ID {
+		t.Errorf("Expected venue_id %s, got %v", venue.ID, event.VenueID)
+	}
+}
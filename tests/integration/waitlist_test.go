@@ -0,0 +1,233 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/yourname/ticketing-system/internal/adapter/repository"
+	"github.com/yourname/ticketing-system/internal/core/entity"
+	"github.com/yourname/ticketing-system/internal/core/service"
+)
+
+// recordingNotifier ghi lại các thông báo đã gửi để test kiểm tra
+type recordingNotifier struct {
+	mu      sync.Mutex
+	userIDs []uuid.UUID
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, userID uuid.UUID, subject string, message string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.userIDs = append(n.userIDs, userID)
+	return nil
+}
+
+func TestSoldOutError_KeepsMessage(t *testing.T) {
+	err := error(&service.SoldOutError{TicketTypeID: uuid.New(), Name: "VIP", Remaining: 0, Requested: 2})
+
+	if !strings.HasPrefix(err.Error(), "hết vé rồi bro!") {
+		t.Errorf("Unexpected message: %s", err.Error())
+	}
+
+	var soldOut *service.SoldOutError
+	if !errors.As(err, &soldOut) || soldOut.Name != "VIP" {
+		t.Error("Expected errors.As to unwrap SoldOutError")
+	}
+}
+
+// Cần schema đầy đủ từ scripts/db/init.sql
+func TestWaitlistOfferAfterCancel_DB(t *testing.T) {
+	db := setupDB()
+	ctx := context.Background()
+
+	buyerID, waiterID := uuid.New(), uuid.New()
+	for i, id := range []uuid.UUID{buyerID, waiterID} {
+		name := id.String()[:8]
+		if err := db.Exec("INSERT INTO users (id, username, email, password_hash) VALUES (?, ?, ?, ?)",
+			id, "wl-"+name, name+"@example.com", "hash").Error; err != nil {
+			t.Fatalf("Failed to seed user %d: %v", i, err)
+		}
+	}
+
+	eventID := uuid.New()
+	if err := db.Exec("INSERT INTO events (id, name, slug, start_time, end_time) VALUES (?, ?, ?, ?, ?)",
+		eventID, "Waitlist Event", "waitlist-"+eventID.String()[:8], time.Now().Add(time.Hour), time.Now().Add(2*time.Hour)).Error; err != nil {
+		t.Fatalf("Failed to seed event: %v", err)
+	}
+
+	ticketTypeID := uuid.New()
+	if err := db.Create(&entity.TicketType{
+		ID:                ticketTypeID,
+		EventID:           eventID,
+		Name:              "Last Ticket",
+		Price:             decimal.NewFromInt(100),
+		InitialQuantity:   1,
+		RemainingQuantity: 1,
+	}).Error; err != nil {
+		t.Fatalf("Failed to seed ticket type: %v", err)
+	}
+
+	notify := &recordingNotifier{}
+	orderRepo := repository.NewOrderRepository(db)
+	waitlist := service.NewWaitlistService(db, repository.NewWaitlistRepository(db), orderRepo, notify, 10*time.Minute)
+	orders := service.NewOrderService(db, orderRepo, waitlist, nil)
+
+	order, err := orders.PlaceOrder(ctx, buyerID, []service.RequestItem{{TicketTypeID: ticketTypeID, Quantity: 1}})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+
+	_, err = orders.PlaceOrder(ctx, waiterID, []service.RequestItem{{TicketTypeID: ticketTypeID, Quantity: 1}})
+	var soldOut *service.SoldOutError
+	if !errors.As(err, &soldOut) {
+		t.Fatalf("Expected SoldOutError, got %v", err)
+	}
+
+	entry, err := waitlist.Join(ctx, waiterID, entity.JoinWaitlistRequest{TicketTypeID: ticketTypeID, Quantity: 1})
+	if err != nil {
+		t.Fatalf("Join failed: %v", err)
+	}
+
+	if err := orders.CancelOrder(ctx, buyerID, order.ID); err != nil {
+		t.Fatalf("CancelOrder failed: %v", err)
+	}
+
+	// Vé trả lại phải được giữ cho người chờ, không quay về kho công khai
+	var ticketType entity.TicketType
+	db.First(&ticketType, "id = ?", ticketTypeID)
+	if ticketType.RemainingQuantity != 0 {
+		t.Errorf("Expected released ticket to be held for waitlist, remaining = %d", ticketType.RemainingQuantity)
+	}
+	if len(notify.userIDs) != 1 || notify.userIDs[0] != waiterID {
+		t.Errorf("Expected one notification to waiter, got %v", notify.userIDs)
+	}
+
+	claimed, err := waitlist.Claim(ctx, waiterID, entry.ID)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if len(claimed.Tickets) != 1 {
+		t.Errorf("Expected 1 ticket issued on claim, got %d", len(claimed.Tickets))
+	}
+}
+
+// Cần schema đầy đủ từ scripts/db/init.sql
+func TestTopUpStock_RespectsVenueCapacity_DB(t *testing.T) {
+	db := setupDB()
+	ctx := context.Background()
+
+	venueID, eventID := uuid.New(), uuid.New()
+	if err := db.Exec("INSERT INTO venues (id, name, capacity) VALUES (?, ?, ?)", venueID, "Top-up Hall", 10).Error; err != nil {
+		t.Fatalf("Failed to seed venue: %v", err)
+	}
+	if err := db.Exec("INSERT INTO events (id, name, slug, venue_id, start_time, end_time) VALUES (?, ?, ?, ?, ?, ?)",
+		eventID, "Top-up Event", "top-up-"+eventID.String()[:8], venueID, time.Now().Add(time.Hour), time.Now().Add(2*time.Hour)).Error; err != nil {
+		t.Fatalf("Failed to seed event: %v", err)
+	}
+
+	ticketTypeID := uuid.New()
+	if err := db.Create(&entity.TicketType{
+		ID:                ticketTypeID,
+		EventID:           eventID,
+		Name:              "Standard",
+		Price:             decimal.NewFromInt(100),
+		InitialQuantity:   8,
+		RemainingQuantity: 8,
+	}).Error; err != nil {
+		t.Fatalf("Failed to seed ticket type: %v", err)
+	}
+
+	orders := service.NewOrderService(db, repository.NewOrderRepository(db), nil, nil)
+	if err := orders.TopUpStock(ctx, ticketTypeID, 3); err == nil {
+		t.Error("Expected top-up beyond venue capacity rejected")
+	}
+	if err := orders.TopUpStock(ctx, ticketTypeID, 2); err != nil {
+		t.Fatalf("TopUpStock failed: %v", err)
+	}
+
+	var ticketType entity.TicketType
+	if err := db.First(&ticketType, "id = ?", ticketTypeID).Error; err != nil {
+		t.Fatalf("Failed to reload ticket type: %v", err)
+	}
+	if ticketType.InitialQuantity != 10 || ticketType.RemainingQuantity != 10 {
+		t.Errorf("Expected 10/10 after top-up, got %d/%d", ticketType.InitialQuantity, ticketType.RemainingQuantity)
+	}
+}
+
+// Cần schema đầy đủ từ scripts/db/init.sql
+func TestExpirePendingOrders_SkipsFailingOrder_DB(t *testing.T) {
+	db := setupDB()
+	ctx := context.Background()
+
+	userID, eventID := uuid.New(), uuid.New()
+	if err := db.Exec("INSERT INTO users (id, username, email, password_hash) VALUES (?, ?, ?, ?)",
+		userID, "exp-"+userID.String()[:8], userID.String()[:8]+"@example.com", "hash").Error; err != nil {
+		t.Fatalf("Failed to seed user: %v", err)
+	}
+	if err := db.Exec("INSERT INTO events (id, name, slug, start_time, end_time) VALUES (?, ?, ?, ?, ?)",
+		eventID, "Expiry Event", "expiry-"+eventID.String()[:8], time.Now().Add(time.Hour), time.Now().Add(2*time.Hour)).Error; err != nil {
+		t.Fatalf("Failed to seed event: %v", err)
+	}
+	ticketTypeID := uuid.New()
+	if err := db.Create(&entity.TicketType{
+		ID:                ticketTypeID,
+		EventID:           eventID,
+		Name:              "Standard",
+		Price:             decimal.NewFromInt(100),
+		InitialQuantity:   1,
+		RemainingQuantity: 1,
+	}).Error; err != nil {
+		t.Fatalf("Failed to seed ticket type: %v", err)
+	}
+
+	// Đơn quá hạn cũ nhất không hủy được: item bán lại trong khi service không bật sàn bán lại
+	failingID := uuid.New()
+	if err := db.Exec("INSERT INTO orders (id, user_id, total_amount, status, created_at) VALUES (?, ?, 100, 'PENDING', ?)",
+		failingID, userID, time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)).Error; err != nil {
+		t.Fatalf("Failed to seed failing order: %v", err)
+	}
+	defer db.Exec("DELETE FROM orders WHERE id = ?", failingID)
+	if err := db.Exec("INSERT INTO order_items (order_id, kind, quantity, price) VALUES (?, 'RESALE', 1, 100)", failingID).Error; err != nil {
+		t.Fatalf("Failed to seed failing order item: %v", err)
+	}
+
+	orders := service.NewOrderService(db, repository.NewOrderRepository(db), nil, nil)
+	order, err := orders.PlaceOrder(ctx, userID, []service.RequestItem{{TicketTypeID: ticketTypeID, Quantity: 1}})
+	if err != nil {
+		t.Fatalf("PlaceOrder failed: %v", err)
+	}
+	if err := db.Exec("UPDATE orders SET created_at = ? WHERE id = ?", time.Date(1990, 1, 2, 0, 0, 0, 0, time.UTC), order.ID).Error; err != nil {
+		t.Fatalf("Failed to age order: %v", err)
+	}
+
+	if _, err := orders.ExpirePendingOrders(ctx, time.Minute); err != nil {
+		t.Fatalf("ExpirePendingOrders failed: %v", err)
+	}
+
+	var statuses []struct {
+		ID     uuid.UUID
+		Status entity.OrderStatus
+	}
+	db.Raw("SELECT id, status FROM orders WHERE id IN ?", []uuid.UUID{failingID, order.ID}).Scan(&statuses)
+	for _, row := range statuses {
+		if row.ID == failingID && row.Status != entity.OrderStatusPending {
+			t.Errorf("Expected failing order left PENDING, got %s", row.Status)
+		}
+		if row.ID == order.ID && row.Status != entity.OrderStatusCancelled {
+			t.Errorf("Expected later order cancelled despite earlier failure, got %s", row.Status)
+		}
+	}
+
+	var ticketType entity.TicketType
+	db.First(&ticketType, "id = ?", ticketTypeID)
+	if ticketType.RemainingQuantity != 1 {
+		t.Errorf("Expected stock released by expiry, remaining = %d", ticketType.RemainingQuantity)
+	}
+}